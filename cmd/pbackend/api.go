@@ -114,6 +114,10 @@ func api() {
 	metricsRouter.Get("/", s.WelcomeService)
 	metricsRouter.Handle(config.Prometheus.Path, promhttp.Handler())
 
+	// Routes for operators, only started when enabled
+	adminRouter := chi.NewRouter()
+	routes.MountInternal(adminRouter, config.Admin.PSK)
+
 	log.Info().Msgf("Starting new instance on port %d with prometheus on %d", config.Application.Port, config.Prometheus.Port)
 	apiServer := http.Server{
 		Addr:    fmt.Sprintf(":%d", config.Application.Port),
@@ -125,6 +129,11 @@ func api() {
 		Handler: metricsRouter,
 	}
 
+	adminServer := http.Server{
+		Addr:    fmt.Sprintf(":%d", config.Admin.Port),
+		Handler: adminRouter,
+	}
+
 	waitForSignal := make(chan struct{})
 	go func() {
 		sigint := make(chan os.Signal, 1)
@@ -136,6 +145,11 @@ func api() {
 		if err := metricsServer.Shutdown(context.Background()); err != nil {
 			log.Warn().Err(err).Msg("Metrics service shutdown error")
 		}
+		if config.Admin.Port != 0 {
+			if err := adminServer.Shutdown(context.Background()); err != nil {
+				log.Warn().Err(err).Msg("Admin service shutdown error")
+			}
+		}
 		close(waitForSignal)
 	}()
 
@@ -150,6 +164,15 @@ func api() {
 		}
 	}()
 
+	if config.Admin.Port != 0 {
+		log.Info().Msgf("Starting admin endpoints on port %d", config.Admin.Port)
+		go func() {
+			if err := adminServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Warn().Err(err).Msg("Admin service listen error")
+			}
+		}()
+	}
+
 	if err := apiServer.ListenAndServe(); err != nil {
 		if !errors.Is(err, http.ErrServerClosed) {
 			log.Warn().Err(err).Msg("Main service listen error")
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/RHEnVision/provisioning-backend/internal/config"
+	"github.com/RHEnVision/provisioning-backend/internal/logging"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/RHEnVision/provisioning-backend/internal/queue"
+	"github.com/RHEnVision/provisioning-backend/internal/queue/jq"
+	jobqueue "github.com/RHEnVision/provisioning-backend/pkg/worker"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+func jobsUsage() {
+	fmt.Println("Usage: pbackend jobs list [-type TYPE] [-account-id ID] [-older-than DURATION]")
+	fmt.Println("       pbackend jobs [show|delete|requeue] JOB_ID")
+	fmt.Println("       pbackend jobs drain")
+	os.Exit(1)
+}
+
+// jobs inspects and manages the Redis job queue, job arguments are printed with redacted
+// authentication.
+func jobs() {
+	if len(os.Args) < 3 {
+		jobsUsage()
+	}
+
+	config.Initialize("config/api.env")
+	logging.InitializeStdout()
+	logger := log.Logger
+	ctx := logger.WithContext(context.Background())
+
+	if config.Worker.Queue != "redis" {
+		logger.Fatal().Msgf("Job inspection requires the redis job queue, configured: %s", config.Worker.Queue)
+	}
+
+	err := jq.Initialize(ctx, &logger)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Error initializing job queue")
+	}
+
+	// job argument types must be registered to decode jobs
+	jq.RegisterJobs(&logger)
+	wk := queue.GetWorker(ctx)
+
+	switch os.Args[2] {
+	case "list":
+		fs := flag.NewFlagSet("jobs list", flag.ExitOnError)
+		jobType := fs.String("type", "", "job type, for example launch_instances_aws")
+		accountID := fs.Int64("account-id", 0, "account ID (database ID, not the account number)")
+		olderThan := fs.Duration("older-than", 0, "only jobs enqueued at least this long ago, for example 15m")
+		_ = fs.Parse(os.Args[3:])
+
+		infos, err := wk.ListJobs(ctx, &jobqueue.JobFilter{
+			Type:      jobqueue.JobType(*jobType),
+			AccountID: *accountID,
+			OlderThan: *olderThan,
+		})
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Unable to list jobs")
+		}
+		printJSON(payloads.NewJobListResponse(infos))
+	case "show":
+		info, err := wk.GetJob(ctx, jobIDArg())
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Unable to get job")
+		}
+		printJSON(payloads.NewJobResponse(info))
+	case "delete":
+		err := wk.DeleteJob(ctx, jobIDArg())
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Unable to delete job")
+		}
+	case "requeue":
+		err := wk.RequeueJob(ctx, jobIDArg())
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Unable to requeue job")
+		}
+	case "drain":
+		drained, err := wk.DrainQueue(ctx)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Unable to drain queue")
+		}
+		printJSON(payloads.NewJobDrainResponse(drained))
+	default:
+		jobsUsage()
+	}
+}
+
+func jobIDArg() uuid.UUID {
+	if len(os.Args) < 4 {
+		jobsUsage()
+	}
+	id, err := uuid.Parse(os.Args[3])
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid job ID")
+	}
+	return id
+}
+
+func printJSON(v any) {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Fatal().Err(err).Msg("Unable to marshal output")
+	}
+	fmt.Println(string(out))
+}
@@ -42,6 +42,8 @@ func main() {
 		statuser()
 	case "stats":
 		stats()
+	case "jobs":
+		jobs()
 	case "version":
 		ver()
 	default:
@@ -50,7 +52,7 @@ func main() {
 }
 
 func usage() {
-	fmt.Println("Usage: pbackend [migrate|api|worker|statuser|stats|version]")
+	fmt.Println("Usage: pbackend [migrate|api|worker|statuser|stats|jobs|version]")
 	os.Exit(1)
 }
 
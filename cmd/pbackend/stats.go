@@ -12,8 +12,10 @@ import (
 	"github.com/RHEnVision/provisioning-backend/internal/background"
 	"github.com/RHEnVision/provisioning-backend/internal/config"
 	"github.com/RHEnVision/provisioning-backend/internal/db"
+	"github.com/RHEnVision/provisioning-backend/internal/kafka"
 	"github.com/RHEnVision/provisioning-backend/internal/logging"
 	"github.com/RHEnVision/provisioning-backend/internal/metrics"
+	"github.com/RHEnVision/provisioning-backend/internal/notifications"
 	"github.com/RHEnVision/provisioning-backend/internal/queue/jq"
 	"github.com/RHEnVision/provisioning-backend/internal/telemetry"
 	"github.com/go-chi/chi/v5"
@@ -34,12 +36,15 @@ func stats() {
 	tel := telemetry.Initialize(ctx, &log.Logger)
 	defer tel.Close(ctx)
 
-	// initialize the job queue but don't register any workers
+	// initialize the job queue but don't start any workers
 	err := jq.Initialize(ctx, &logger)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Error initializing job queue")
 	}
 
+	// job argument types must be registered, the reservation scheduler enqueues launch jobs
+	jq.RegisterJobs(&logger)
+
 	// metrics
 	logger.Info().Msgf("Starting new instance on port %d with prometheus on %d", config.Application.Port, config.Prometheus.Port)
 	metricsRouter := chi.NewRouter()
@@ -81,6 +86,18 @@ func stats() {
 	}
 	defer db.Close()
 
+	// the stale reservation reaper sends launch notifications
+	if config.Kafka.Enabled {
+		err = kafka.InitializeKafkaBroker(ctx)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Unable to initialize the platform kafka")
+		}
+
+		if config.Application.Notifications.Enabled {
+			notifications.Initialize(ctx)
+		}
+	}
+
 	// initialize background goroutines
 	bgCtx, bgCancel := context.WithCancel(ctx)
 	background.InitializeStats(bgCtx)
@@ -12,6 +12,9 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/RHEnVision/provisioning-backend/internal/background"
+	"github.com/RHEnVision/provisioning-backend/internal/breaker"
+	"github.com/RHEnVision/provisioning-backend/internal/cache"
 	"github.com/RHEnVision/provisioning-backend/internal/clients"
 	"github.com/RHEnVision/provisioning-backend/internal/db"
 	"github.com/RHEnVision/provisioning-backend/internal/models"
@@ -44,6 +47,7 @@ const ChannelBuffer = 32
 type SourceInfo struct {
 	MessageContext      context.Context // Carries logger and identity
 	Authentication      clients.Authentication
+	SourceID            string
 	SourceApplicationID string
 }
 
@@ -57,10 +61,53 @@ var (
 	senderWG     = sync.WaitGroup{}
 )
 
+// Circuit breakers of Sources and provider availability checks, open breakers skip checks instead
+// of reporting sources as unavailable during an outage.
+var (
+	sourcesBreaker *breaker.Breaker
+	awsBreaker     *breaker.Breaker
+	azureBreaker   *breaker.Breaker
+	gcpBreaker     *breaker.Breaker
+)
+
 func init() {
 	random.SeedGlobal()
 }
 
+func newAvailabilityBreaker(name string) *breaker.Breaker {
+	return breaker.New(name, breaker.Settings{
+		Window:         config.Breakers.Window,
+		MinRequests:    config.Breakers.MinRequests,
+		FailureRate:    config.Breakers.FailureRate,
+		OpenTimeout:    config.Breakers.OpenTimeout,
+		MaxOpenTimeout: config.Breakers.MaxOpenTimeout,
+		MaxBackoff:     config.Breakers.MaxBackoff,
+	})
+}
+
+// recordCheck reports outcome of a check to the breaker. It returns false when the check failed
+// because of an outage, such result must not be reported to Sources.
+func recordCheck(b *breaker.Breaker, err error) bool {
+	if clients.IsOutage(err) {
+		b.Failure()
+		return false
+	}
+	b.Success()
+	return true
+}
+
+// sendResult sends the result to Sources and caches it for repeated requests of the source.
+func sendResult(s SourceInfo, sr kafka.SourceResult) {
+	chSend <- sr
+
+	if config.Debounce.ResultTTL > 0 {
+		err := cache.SetExpires(sr.MessageContext, s.SourceID, kafka.NewCachedSourceResult(sr), config.Debounce.ResultTTL)
+		if err != nil {
+			zerolog.Ctx(sr.MessageContext).Warn().Err(err).Msg("Unable to cache availability check result")
+		}
+	}
+}
+
 func processMessage(msgCtx context.Context, message *kafka.GenericMessage) {
 	logger := zerolog.Ctx(msgCtx)
 
@@ -77,6 +124,17 @@ func processMessage(msgCtx context.Context, message *kafka.GenericMessage) {
 	ctx := logger.WithContext(msgCtx)
 	logger.Trace().Msgf("Sources availability check for %s", sourceId)
 
+	// Reuse result of a source which was checked recently
+	if config.Debounce.ResultTTL > 0 {
+		cached := kafka.CachedSourceResult{}
+		if cache.Find(ctx, sourceId, &cached) == nil {
+			logger.Trace().Msgf("Reusing cached availability status of %s", sourceId)
+			metrics.IncAvailabilityCheckSkipped("cached")
+			chSend <- cached.SourceResult(ctx)
+			return
+		}
+	}
+
 	// Get sources client
 	sourcesClient, err := clients.GetSourcesClient(ctx)
 	if err != nil {
@@ -84,8 +142,14 @@ func processMessage(msgCtx context.Context, message *kafka.GenericMessage) {
 		return
 	}
 
+	if !sourcesBreaker.Allow() {
+		logger.Debug().Msg("Skipping availability check, sources circuit breaker is open")
+		return
+	}
+
 	// Fetch authentication from Sources
 	authentication, err := sourcesClient.GetAuthentication(ctx, sourceId)
+	recordCheck(sourcesBreaker, err)
 	if err != nil {
 		metrics.IncTotalInvalidAvailabilityCheckReqs()
 		if errors.Is(err, clients.ErrNotFound) {
@@ -103,6 +167,7 @@ func processMessage(msgCtx context.Context, message *kafka.GenericMessage) {
 	s := SourceInfo{
 		MessageContext:      ctx,
 		Authentication:      *authentication,
+		SourceID:            sourceId,
 		SourceApplicationID: authentication.SourceApplictionID,
 	}
 
@@ -131,6 +196,11 @@ func checkSourceAvailabilityAzure(cancelCtx context.Context) {
 			metrics.IncTotalSentAvailabilityCheckReqs(models.ProviderTypeAzure.String(), "skipped", nil)
 			continue
 		}
+		if !azureBreaker.Allow() {
+			logger.Debug().Msgf("Skipping Azure source availability status %s, circuit breaker is open", s.SourceApplicationID)
+			metrics.IncTotalSentAvailabilityCheckReqs(models.ProviderTypeAzure.String(), "skipped", nil)
+			continue
+		}
 
 		logger.Trace().Msgf("Checking Azure source availability status %s", s.SourceApplicationID)
 		metrics.ObserveAvailabilityCheckReqsDuration(models.ProviderTypeAzure.String(), func() error {
@@ -147,8 +217,8 @@ func checkSourceAvailabilityAzure(cancelCtx context.Context) {
 				sr.UserError = "We could not log into this Azure account"
 				logger.Warn().Err(err).Msg("Failed to create Azure client")
 			} else {
-				_, verifyErr := azureClient.TenantId(ctx)
-				if verifyErr != nil {
+				_, err = azureClient.TenantId(ctx)
+				if err != nil {
 					sr.Status = kafka.StatusUnavailable
 					sr.Err = err
 					sr.UserError = "Red Hat HCC provisioning service account can not connect to your subscription"
@@ -157,7 +227,12 @@ func checkSourceAvailabilityAzure(cancelCtx context.Context) {
 					sr.Status = kafka.StatusAvailable
 				}
 			}
-			chSend <- sr
+			if !recordCheck(azureBreaker, err) {
+				logger.Warn().Err(err).Msg("Availability check failed due to an outage, status not reported")
+				metrics.IncTotalSentAvailabilityCheckReqs(models.ProviderTypeAzure.String(), "unknown", err)
+				return fmt.Errorf("error during check: %w", err)
+			}
+			sendResult(s, sr)
 			metrics.IncTotalSentAvailabilityCheckReqs(models.ProviderTypeAzure.String(), sr.Status.String(), nil)
 
 			return fmt.Errorf("error during check: %w", err)
@@ -167,7 +242,7 @@ func checkSourceAvailabilityAzure(cancelCtx context.Context) {
 			break
 		}
 
-		time.Sleep(config.Azure.AvailabilityDelay)
+		time.Sleep(azureBreaker.Backoff(config.Azure.AvailabilityDelay))
 	}
 }
 
@@ -183,6 +258,11 @@ func checkSourceAvailabilityAWS(cancelCtx context.Context) {
 			metrics.IncTotalSentAvailabilityCheckReqs(models.ProviderTypeAWS.String(), "skipped", nil)
 			continue
 		}
+		if !awsBreaker.Allow() {
+			logger.Debug().Msgf("Skipping AWS source availability status %s, circuit breaker is open", s.SourceApplicationID)
+			metrics.IncTotalSentAvailabilityCheckReqs(models.ProviderTypeAWS.String(), "skipped", nil)
+			continue
+		}
 
 		logger.Trace().Msgf("Checking AWS source availability status %s", s.SourceApplicationID)
 		metrics.ObserveAvailabilityCheckReqsDuration(models.ProviderTypeAWS.String(), func() error {
@@ -218,7 +298,12 @@ func checkSourceAvailabilityAWS(cancelCtx context.Context) {
 					}
 				}
 			}
-			chSend <- sr
+			if !recordCheck(awsBreaker, err) {
+				logger.Warn().Err(err).Msg("Availability check failed due to an outage, status not reported")
+				metrics.IncTotalSentAvailabilityCheckReqs(models.ProviderTypeAWS.String(), "unknown", err)
+				return fmt.Errorf("error during check: %w", err)
+			}
+			sendResult(s, sr)
 			metrics.IncTotalSentAvailabilityCheckReqs(models.ProviderTypeAWS.String(), sr.Status.String(), err)
 			return fmt.Errorf("error during check: %w", err)
 		})
@@ -227,7 +312,7 @@ func checkSourceAvailabilityAWS(cancelCtx context.Context) {
 			break
 		}
 
-		time.Sleep(config.AWS.AvailabilityDelay)
+		time.Sleep(awsBreaker.Backoff(config.AWS.AvailabilityDelay))
 	}
 }
 
@@ -243,6 +328,11 @@ func checkSourceAvailabilityGCP(cancelCtx context.Context) {
 			metrics.IncTotalSentAvailabilityCheckReqs(models.ProviderTypeGCP.String(), "skipped", nil)
 			continue
 		}
+		if !gcpBreaker.Allow() {
+			logger.Debug().Msgf("Skipping GCP source availability status %s, circuit breaker is open", s.SourceApplicationID)
+			metrics.IncTotalSentAvailabilityCheckReqs(models.ProviderTypeGCP.String(), "skipped", nil)
+			continue
+		}
 
 		logger.Trace().Msgf("Checking GCP source availability status %s", s.SourceApplicationID)
 		metrics.ObserveAvailabilityCheckReqsDuration(models.ProviderTypeGCP.String(), func() error {
@@ -258,19 +348,23 @@ func checkSourceAvailabilityGCP(cancelCtx context.Context) {
 				sr.Err = err
 				sr.UserError = "Could not list log into GCP account"
 				logger.Warn().Err(err).Msg("Could not get gcp client")
-				chSend <- sr
-			}
-			_, err = gcpClient.ListAllRegions(ctx)
-			if err != nil {
-				sr.Status = kafka.StatusUnavailable
-				sr.Err = err
-				sr.UserError = "Could not list gcp regions using the provided source"
-				logger.Warn().Err(err).Msg("Could not list gcp regions")
-				chSend <- sr
 			} else {
-				sr.Status = kafka.StatusAvailable
-				chSend <- sr
+				_, err = gcpClient.ListAllRegions(ctx)
+				if err != nil {
+					sr.Status = kafka.StatusUnavailable
+					sr.Err = err
+					sr.UserError = "Could not list gcp regions using the provided source"
+					logger.Warn().Err(err).Msg("Could not list gcp regions")
+				} else {
+					sr.Status = kafka.StatusAvailable
+				}
+			}
+			if !recordCheck(gcpBreaker, err) {
+				logger.Warn().Err(err).Msg("Availability check failed due to an outage, status not reported")
+				metrics.IncTotalSentAvailabilityCheckReqs(models.ProviderTypeGCP.String(), "unknown", err)
+				return fmt.Errorf("error during check: %w", err)
 			}
+			sendResult(s, sr)
 			metrics.IncTotalSentAvailabilityCheckReqs(models.ProviderTypeGCP.String(), sr.Status.String(), err)
 
 			return fmt.Errorf("error during check: %w", err)
@@ -280,7 +374,7 @@ func checkSourceAvailabilityGCP(cancelCtx context.Context) {
 			break
 		}
 
-		time.Sleep(config.GCP.AvailabilityDelay)
+		time.Sleep(gcpBreaker.Backoff(config.GCP.AvailabilityDelay))
 	}
 }
 
@@ -396,6 +490,12 @@ func statuser() {
 		}
 	}()
 
+	// circuit breakers of availability checks
+	sourcesBreaker = newAvailabilityBreaker("sources")
+	awsBreaker = newAvailabilityBreaker(models.ProviderTypeAWS.String())
+	azureBreaker = newAvailabilityBreaker(models.ProviderTypeAzure.String())
+	gcpBreaker = newAvailabilityBreaker(models.ProviderTypeGCP.String())
+
 	// start the consumer
 	receiverWG.Add(1)
 	cancelCtx, consumerCancelFunc := context.WithCancel(ctx)
@@ -416,6 +516,14 @@ func statuser() {
 	}
 	defer db.Close()
 
+	// initialize cache
+	cache.Initialize()
+
+	// initialize background goroutines
+	bgCtx, bgCancel := context.WithCancel(ctx)
+	background.InitializeStatuser(bgCtx)
+	defer bgCancel()
+
 	// start processing goroutines
 	processingWG.Add(3)
 
@@ -93,9 +93,6 @@ func worker() {
 		sigint := make(chan os.Signal, 1)
 		signal.Notify(sigint, syscall.SIGINT, syscall.SIGTERM)
 		<-sigint
-		if err := metricsServer.Shutdown(context.Background()); err != nil {
-			logger.Warn().Err(err).Msg("Metrics service shutdown error")
-		}
 		close(signalNotify)
 	}()
 
@@ -115,5 +112,10 @@ func worker() {
 
 	logger.Info().Msg("Graceful shutdown initiated - waiting for jobs to finish")
 	jq.StopDequeueLoop(ctx)
+
+	// metrics are served until jobs are stopped, so the shutdown sequence can be observed
+	if err := metricsServer.Shutdown(context.Background()); err != nil {
+		logger.Warn().Err(err).Msg("Metrics service shutdown error")
+	}
 	logger.Info().Msg("Graceful shutdown finished - exiting")
 }
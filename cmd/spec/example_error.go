@@ -30,3 +30,11 @@ var ResponseErrorUserFriendlyExample = payloads.ResponseError{
 	Version:   "df8a489",
 	BuildTime: "2023-04-14_17:15:02",
 }
+
+var ResponseForbiddenErrorExample = payloads.ResponseError{
+	Message:   "launch policy violation, allowed_regions: region \"eu-west-3\" is not allowed for aws",
+	TraceId:   "b57f7b78c",
+	Error:     "launch policy: allowed_regions: region \"eu-west-3\" is not allowed for aws",
+	Version:   "df8a489",
+	BuildTime: "2023-04-14_17:15:02",
+}
@@ -109,6 +109,10 @@ var AwsReservationResponsePayloadDoneExample = payloads.AWSReservationResponse{
 	AWSReservationID: "r-3743243324231",
 	Name:             "my-instance",
 	PowerOff:         false,
+	EstimatedCost: &payloads.CostEstimateResponse{
+		Hourly:  0.0208,
+		Monthly: 15.18,
+	},
 	Instances: []payloads.InstanceResponse{
 		{InstanceID: "i-2324343212", Detail: models.ReservationInstanceDetail{
 			PublicDNS:   "ec2-184-73-141-211.compute-1.amazonaws.com",
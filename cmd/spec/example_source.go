@@ -5,6 +5,7 @@ import (
 	"github.com/RHEnVision/provisioning-backend/internal/models"
 	"github.com/RHEnVision/provisioning-backend/internal/page"
 	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/RHEnVision/provisioning-backend/internal/ptr"
 )
 
 var SourceListResponse = payloads.SourceListResponse{
@@ -43,3 +44,49 @@ var SourceUploadInfoAzureResponse = payloads.SourceUploadInfoResponse{
 		ResourceGroups: []string{"MyGroup 1", "MyGroup 42"},
 	},
 }
+
+var SourceRegionListAWSResponse = payloads.SourceRegionListResponse{
+	Provider: "aws",
+	Data: []*payloads.SourceRegionResponse{
+		{
+			Name:    "af-south-1",
+			Enabled: false,
+		}, {
+			Name:    "eu-central-1",
+			Enabled: true,
+			Zones:   []string{"eu-central-1a", "eu-central-1b", "eu-central-1c"},
+		}, {
+			Name:    "us-east-1",
+			Enabled: true,
+			Zones:   []string{"us-east-1a", "us-east-1b", "us-east-1c", "us-east-1d", "us-east-1e", "us-east-1f"},
+		},
+	},
+}
+
+var SourceImageListAWSResponse = payloads.SourceImageListResponse{
+	Provider: "aws",
+	Data: []*payloads.SourceImageResponse{
+		{
+			ID:           "f1a3b6b4-9b8f-4e2a-8d5e-6c1e2f4a9b7d",
+			Name:         "my-rhel-9",
+			Origin:       "image-builder",
+			Architecture: "x86_64",
+			Region:       "us-east-1",
+			Compatible:   ptr.To(true),
+		}, {
+			ID:           "ami-0c830793775595d4b",
+			Name:         "rhel-9-custom",
+			Origin:       "account",
+			Architecture: "x86_64",
+			Region:       "us-east-1",
+			Compatible:   ptr.To(true),
+		}, {
+			ID:           "ami-0e1d7ed4d9f3b5a2c",
+			Name:         "rhel-9-arm-shared",
+			Origin:       "shared",
+			Architecture: "arm64",
+			Region:       "us-east-1",
+			Compatible:   ptr.To(false),
+		},
+	},
+}
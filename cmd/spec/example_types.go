@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/RHEnVision/provisioning-backend/internal/clients"
 	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/RHEnVision/provisioning-backend/internal/ptr"
 )
 
 var InstanceTypesAWSResponse = payloads.InstanceTypeListResponse{
@@ -16,6 +17,7 @@ var InstanceTypesAWSResponse = payloads.InstanceTypeListResponse{
 			Supported:          true,
 			Architecture:       "x86_64",
 			AzureDetail:        nil,
+			PricePerHour:       ptr.To(1.232),
 		},
 	},
 }
@@ -51,3 +53,64 @@ var InstanceTypesGCPResponse = payloads.InstanceTypeListResponse{
 		},
 	},
 }
+
+var InstanceTypeRecommendationResponse = payloads.InstanceTypeRecommendationListResponse{
+	Data: []*payloads.InstanceTypeRecommendationResponse{
+		{
+			Provider: "aws",
+			Region:   "us-east-1",
+			InstanceTypes: []*payloads.RecommendedInstanceTypeResponse{
+				{
+					InstanceType: &payloads.InstanceTypeResponse{
+						Name:               "m5.xlarge",
+						VCPUs:              4,
+						Cores:              2,
+						MemoryMiB:          16384,
+						EphemeralStorageGB: 0,
+						Supported:          true,
+						Architecture:       "x86_64",
+					},
+					Distance: 0,
+				},
+			},
+		},
+		{
+			Provider: "azure",
+			InstanceTypes: []*payloads.RecommendedInstanceTypeResponse{
+				{
+					InstanceType: &payloads.InstanceTypeResponse{
+						Name:               "Standard_D4s_v5",
+						VCPUs:              4,
+						Cores:              2,
+						MemoryMiB:          16384,
+						EphemeralStorageGB: 0,
+						Supported:          true,
+						Architecture:       "x86_64",
+						AzureDetail: &clients.InstanceTypeDetailAzure{
+							GenV1: true,
+							GenV2: true,
+						},
+					},
+					Distance: 0,
+				},
+			},
+		},
+		{
+			Provider: "gcp",
+			InstanceTypes: []*payloads.RecommendedInstanceTypeResponse{
+				{
+					InstanceType: &payloads.InstanceTypeResponse{
+						Name:               "n2-standard-4",
+						VCPUs:              4,
+						Cores:              2,
+						MemoryMiB:          16384,
+						EphemeralStorageGB: 0,
+						Supported:          true,
+						Architecture:       "x86_64",
+					},
+					Distance: 0,
+				},
+			},
+		},
+	},
+}
@@ -27,16 +27,30 @@ func addPayloads(gen *APISchemaGen) {
 	gen.addSchema("v1.AzureReservationResponse", &payloads.AzureReservationResponse{})
 	gen.addSchema("v1.GCPReservationRequest", &payloads.GCPReservationRequest{})
 	gen.addSchema("v1.GCPReservationResponse", &payloads.GCPReservationResponse{})
+	gen.addSchema("v1.ReservationRetryRequest", &payloads.ReservationRetryRequest{})
+	gen.addSchema("v1.ScheduleResponse", &payloads.ScheduleResponse{})
+	gen.addSchema("v1.ApprovalRuleRequest", &payloads.ApprovalRuleRequest{})
+	gen.addSchema("v1.ApprovalRuleResponse", &payloads.ApprovalRuleResponse{})
+	gen.addSchema("v1.ApprovalDecisionRequest", &payloads.ApprovalDecisionRequest{})
+	gen.addSchema("v1.ApprovalResponse", &payloads.ApprovalResponse{})
+	gen.addSchema("v1.JobExecutionResponse", &payloads.JobExecutionResponse{})
+	gen.addSchema("v1.PolicyRequest", &payloads.PolicyRequest{})
+	gen.addSchema("v1.PolicyResponse", &payloads.PolicyResponse{})
 	gen.addSchema("v1.AvailabilityStatusRequest", &payloads.AvailabilityStatusRequest{})
 	gen.addSchema("v1.AccountIDTypeResponse", &payloads.AccountIdentityResponse{})
 	gen.addSchema("v1.SourceUploadInfoResponse", &payloads.SourceUploadInfoResponse{})
 	gen.addSchema("v1.LaunchTemplatesResponse", &payloads.LaunchTemplateResponse{})
 
 	gen.addSchema("v1.ListSourceResponse", &payloads.SourceListResponse{})
+	gen.addSchema("v1.ListSourceRegionResponse", &payloads.SourceRegionListResponse{})
+	gen.addSchema("v1.ListSourceImageResponse", &payloads.SourceImageListResponse{})
 	gen.addSchema("v1.ListPubkeyResponse", &payloads.PubkeyListResponse{})
+	gen.addSchema("v1.ListApprovalRuleResponse", &payloads.ApprovalRuleListResponse{})
+	gen.addSchema("v1.ListJobExecutionResponse", &payloads.JobExecutionListResponse{})
 	gen.addSchema("v1.ListInstaceTypeResponse", &payloads.InstanceTypeListResponse{})
 	gen.addSchema("v1.ListGenericReservationResponse", &payloads.GenericReservationListResponse{})
 	gen.addSchema("v1.ListLaunchTemplateResponse", &payloads.LaunchTemplateListResponse{})
+	gen.addSchema("v1.ListInstanceTypeRecommendationResponse", &payloads.InstanceTypeRecommendationListResponse{})
 }
 
 func addExamples(gen *APISchemaGen) {
@@ -46,6 +60,8 @@ func addExamples(gen *APISchemaGen) {
 	gen.addExample("v1.SourceListResponseExample", SourceListResponse)
 	gen.addExample("v1.SourceUploadInfoAWSResponse", SourceUploadInfoAWSResponse)
 	gen.addExample("v1.SourceUploadInfoAzureResponse", SourceUploadInfoAzureResponse)
+	gen.addExample("v1.SourceRegionListAWSResponse", SourceRegionListAWSResponse)
+	gen.addExample("v1.SourceImageListAWSResponse", SourceImageListAWSResponse)
 	gen.addExample("v1.LaunchTemplateListResponse", LaunchTemplateListResponse)
 	gen.addExample("v1.AvailabilityStatusRequest", AvailabilityStatusRequest)
 	gen.addExample("v1.GenericReservationResponsePayloadSuccessExample", GenericReservationResponsePayloadSuccessExample)
@@ -65,6 +81,7 @@ func addExamples(gen *APISchemaGen) {
 	gen.addExample("v1.InstanceTypesAWSResponse", InstanceTypesAWSResponse)
 	gen.addExample("v1.InstanceTypesAzureResponse", InstanceTypesAzureResponse)
 	gen.addExample("v1.InstanceTypesGCPResponse", InstanceTypesGCPResponse)
+	gen.addExample("v1.InstanceTypeRecommendationResponse", InstanceTypeRecommendationResponse)
 }
 
 func addParameters(gen *APISchemaGen) {
@@ -82,6 +99,7 @@ func addErrorSchemas(gen *APISchemaGen) {
 	gen.addResponse("NotFound", "The requested resource was not found", "#/components/schemas/v1.ResponseError", ResponseNotFoundErrorExample)
 	gen.addResponse("InternalError", "The server encountered an internal error", "#/components/schemas/v1.ResponseError", ResponseErrorGenericExample)
 	gen.addResponse("BadRequest", "The request's parameters are not valid", "#/components/schemas/v1.ResponseError", ResponseBadRequestErrorExample)
+	gen.addResponse("Forbidden", "The request was denied, for example by the launch policy of the account", "#/components/schemas/v1.ResponseError", ResponseForbiddenErrorExample)
 }
 
 type APISchemaGen struct {
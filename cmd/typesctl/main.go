@@ -3,6 +3,8 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
+	"slices"
 	"strings"
 
 	"github.com/RHEnVision/provisioning-backend/cmd/typesctl/providers"
@@ -29,8 +31,19 @@ func main() {
 	printRegionFlag := flag.String("region", "", "print instance type names for a region (or 'all')")
 	printZoneFlag := flag.String("zone", "", "print instance type names for a zone (region is needed too)")
 	generateFlag := flag.Bool("generate", false, "generate new type information")
+	pricesFlag := flag.String("prices", "", "generate prices from a price list export file (AWS Pricing CSV, Azure Retail Prices JSON or GCP Billing Catalog JSON) for preloaded types, can be combined with -generate")
+	diffFlag := flag.Bool("diff", false, "report added, removed and changed types and availability between live and preloaded data")
+	validateFlag := flag.Bool("validate", false, "check integrity of preloaded data, exits with non-zero code on error (all providers when -provider is not set)")
 	flag.Parse()
 
+	if *validateFlag && *providerFlag == "" {
+		slices.Sort(validProviders)
+		if !validate(validProviders...) {
+			os.Exit(1)
+		}
+		return
+	}
+
 	provider, ok := providers.TypeProviders[strings.ToLower(*providerFlag)]
 	if !ok {
 		fmt.Println("Unknown or unspecified provider, use -provider")
@@ -38,7 +51,16 @@ func main() {
 		return
 	}
 
-	if *printAllFlag {
+	if *validateFlag {
+		if !validate(strings.ToLower(*providerFlag)) {
+			os.Exit(1)
+		}
+	} else if *diffFlag {
+		err := provider.DiffTypes()
+		if err != nil {
+			panic(err)
+		}
+	} else if *printAllFlag {
 		provider.PrintRegisteredTypes("")
 		provider.PrintRegionalAvailability("", "")
 	} else if *printTypeFlag == "all" {
@@ -49,12 +71,37 @@ func main() {
 		(*printRegionFlag != "" && *printZoneFlag == "") ||
 		(*printRegionFlag == "all" && *printZoneFlag == "") {
 		provider.PrintRegionalAvailability(*printRegionFlag, *printZoneFlag)
-	} else if *generateFlag {
-		err := provider.GenerateTypes()
-		if err != nil {
-			panic(err)
+	} else if *generateFlag || *pricesFlag != "" {
+		if *generateFlag {
+			err := provider.GenerateTypes()
+			if err != nil {
+				panic(err)
+			}
+		}
+		if *pricesFlag != "" {
+			err := provider.GeneratePrices(*pricesFlag)
+			if err != nil {
+				panic(err)
+			}
 		}
 	} else {
 		flag.Usage()
 	}
 }
+
+// validate prints integrity errors of preloaded data and returns false when there are any.
+func validate(names ...string) bool {
+	valid := true
+	for _, name := range names {
+		errs := providers.TypeProviders[name].ValidateTypes()
+		for _, err := range errs {
+			fmt.Printf("%s: %s\n", name, err.Error())
+		}
+		if len(errs) > 0 {
+			valid = false
+		} else {
+			fmt.Printf("%s: OK\n", name)
+		}
+	}
+	return valid
+}
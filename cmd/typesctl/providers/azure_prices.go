@@ -0,0 +1,74 @@
+package providers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/preload"
+)
+
+// azureRetailPage is a page of the Azure Retail Prices API response
+type azureRetailPage struct {
+	Items []struct {
+		CurrencyCode  string  `json:"currencyCode"`
+		RetailPrice   float64 `json:"retailPrice"`
+		ArmRegionName string  `json:"armRegionName"`
+		ArmSkuName    string  `json:"armSkuName"`
+		ProductName   string  `json:"productName"`
+		SkuName       string  `json:"skuName"`
+		ServiceName   string  `json:"serviceName"`
+		UnitOfMeasure string  `json:"unitOfMeasure"`
+		Type          string  `json:"type"`
+	} `json:"Items"`
+}
+
+// generatePricesAzure reads on-demand Linux prices from the Azure Retail Prices API export. The file
+// contains one or more concatenated response pages of:
+// https://prices.azure.com/api/retail/prices?$filter=serviceName eq 'Virtual Machines' and priceType eq 'Consumption'
+func generatePricesAzure(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("unable to open price list: %w", err)
+	}
+	defer file.Close()
+
+	prices := clients.NewRegionalPrices()
+	decoder := json.NewDecoder(file)
+	for {
+		var page azureRetailPage
+		err = decoder.Decode(&page)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("unable to decode price list: %w", err)
+		}
+
+		for _, item := range page.Items {
+			if item.Type != "Consumption" || item.ServiceName != "Virtual Machines" ||
+				item.UnitOfMeasure != "1 Hour" || item.CurrencyCode != "USD" ||
+				strings.Contains(item.ProductName, "Windows") ||
+				strings.Contains(item.SkuName, "Spot") || strings.Contains(item.SkuName, "Low Priority") {
+				continue
+			}
+
+			name := clients.InstanceTypeName(item.ArmSkuName)
+			if preload.AzureInstanceType.FindInstanceType(name) == nil || item.RetailPrice == 0 {
+				continue
+			}
+			prices.Set(strings.ToLower(item.ArmRegionName), name, item.RetailPrice)
+		}
+	}
+
+	fmt.Printf("Found %d prices\n", prices.Len())
+	err = prices.Save("internal/preload/azure_prices.yaml")
+	if err != nil {
+		return fmt.Errorf("unable to save prices: %w", err)
+	}
+
+	return nil
+}
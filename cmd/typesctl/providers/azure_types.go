@@ -13,6 +13,9 @@ func init() {
 		PrintRegisteredTypes:      printRegisteredTypesAzure,
 		PrintRegionalAvailability: printRegionalAvailabilityAzure,
 		GenerateTypes:             generateTypesAzure,
+		GeneratePrices:            generatePricesAzure,
+		DiffTypes:                 diffTypesAzure,
+		ValidateTypes:             validateTypesAzure,
 	}
 	TypeProviders["azure"] = provider
 }
@@ -26,29 +29,55 @@ func printRegionalAvailabilityAzure(region, zone string) {
 }
 
 func generateTypesAzure() error {
-	instanceTypes := clients.NewRegisteredInstanceTypes()
-	regionalTypes := clients.NewRegionalInstanceTypes()
-
-	ctx := context.Background()
-	sc, err := clients.GetServiceAzureClient(ctx)
+	info, err := fetchTypesAzure()
 	if err != nil {
-		return fmt.Errorf("unable to generate types: %w", err)
+		return err
 	}
 
-	err = sc.RegisterInstanceTypes(ctx, instanceTypes, regionalTypes)
+	err = info.RegisteredTypes.Save("internal/preload/azure_types.yaml")
 	if err != nil {
 		return fmt.Errorf("unable to generate types: %w", err)
 	}
 
-	err = instanceTypes.Save("internal/preload/azure_types.yaml")
+	err = info.RegionalAvailability.Save("internal/preload/azure_availability")
 	if err != nil {
 		return fmt.Errorf("unable to generate types: %w", err)
 	}
 
-	err = regionalTypes.Save("internal/preload/azure_availability")
+	return nil
+}
+
+func diffTypesAzure() error {
+	info, err := fetchTypesAzure()
 	if err != nil {
-		return fmt.Errorf("unable to generate types: %w", err)
+		return err
 	}
 
+	fmt.Print(preload.AzureInstanceType.Diff(info).String())
 	return nil
 }
+
+func validateTypesAzure() []error {
+	return preload.AzureInstanceType.Validate()
+}
+
+func fetchTypesAzure() (*clients.InstanceTypeInfo, error) {
+	instanceTypes := clients.NewRegisteredInstanceTypes()
+	regionalTypes := clients.NewRegionalInstanceTypes()
+
+	ctx := context.Background()
+	sc, err := clients.GetServiceAzureClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate types: %w", err)
+	}
+
+	err = sc.RegisterInstanceTypes(ctx, instanceTypes, regionalTypes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate types: %w", err)
+	}
+
+	return &clients.InstanceTypeInfo{
+		RegisteredTypes:      *instanceTypes,
+		RegionalAvailability: *regionalTypes,
+	}, nil
+}
@@ -0,0 +1,105 @@
+package providers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/preload"
+)
+
+var ErrMissingColumn = errors.New("missing column in price list header")
+
+// Required columns of the AWS Pricing bulk CSV (AmazonEC2 offer file)
+var ec2PriceColumns = []string{
+	"TermType", "Unit", "PricePerUnit", "Currency", "Instance Type", "Tenancy",
+	"Operating System", "Pre Installed S/W", "CapacityStatus", "License Model", "Region Code",
+}
+
+// generatePricesEC2 reads on-demand Linux prices of shared tenancy instances from the AWS Pricing
+// bulk CSV export available at:
+// https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonEC2/current/index.csv
+func generatePricesEC2(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("unable to open price list: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	// the file starts with a preamble of a different number of fields
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	prices := clients.NewRegionalPrices()
+	var columns map[string]int
+	for {
+		record, readErr := reader.Read()
+		if errors.Is(readErr, io.EOF) {
+			break
+		} else if readErr != nil {
+			return fmt.Errorf("unable to read price list: %w", readErr)
+		}
+
+		if columns == nil {
+			if len(record) > 0 && record[0] == "SKU" {
+				columns, err = csvColumns(record, ec2PriceColumns)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		field := func(name string) string {
+			return record[columns[name]]
+		}
+		if field("TermType") != "OnDemand" || field("Unit") != "Hrs" || field("Currency") != "USD" ||
+			field("Tenancy") != "Shared" || field("Operating System") != "Linux" ||
+			field("Pre Installed S/W") != "NA" || field("CapacityStatus") != "Used" ||
+			field("License Model") != "No License required" {
+			continue
+		}
+
+		name := clients.InstanceTypeName(field("Instance Type"))
+		if preload.EC2InstanceType.FindInstanceType(name) == nil {
+			continue
+		}
+
+		price, parseErr := strconv.ParseFloat(field("PricePerUnit"), 64)
+		if parseErr != nil || price == 0 {
+			continue
+		}
+		prices.Set(field("Region Code"), name, price)
+	}
+
+	if columns == nil {
+		return fmt.Errorf("%w: SKU", ErrMissingColumn)
+	}
+
+	fmt.Printf("Found %d prices\n", prices.Len())
+	err = prices.Save("internal/preload/ec2_prices.yaml")
+	if err != nil {
+		return fmt.Errorf("unable to save prices: %w", err)
+	}
+
+	return nil
+}
+
+// csvColumns returns indexes of required columns from a CSV header.
+func csvColumns(header []string, required []string) (map[string]int, error) {
+	columns := make(map[string]int, len(required))
+	for i, name := range header {
+		columns[name] = i
+	}
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingColumn, name)
+		}
+	}
+	return columns, nil
+}
@@ -13,6 +13,9 @@ func init() {
 		PrintRegisteredTypes:      printRegisteredTypesEC2,
 		PrintRegionalAvailability: printRegionalAvailabilityEC2,
 		GenerateTypes:             generateTypesEC2,
+		GeneratePrices:            generatePricesEC2,
+		DiffTypes:                 diffTypesEC2,
+		ValidateTypes:             validateTypesEC2,
 	}
 	TypeProviders["ec2"] = provider
 }
@@ -26,19 +29,52 @@ func printRegionalAvailabilityEC2(region, zone string) {
 }
 
 func generateTypesEC2() error {
+	info, err := fetchTypesEC2()
+	if err != nil {
+		return err
+	}
+
+	err = info.RegisteredTypes.Save("internal/preload/ec2_types.yaml")
+	if err != nil {
+		return fmt.Errorf("unable to generate types: %w", err)
+	}
+
+	err = info.RegionalAvailability.Save("internal/preload/ec2_availability")
+	if err != nil {
+		return fmt.Errorf("unable to generate types: %w", err)
+	}
+
+	return nil
+}
+
+func diffTypesEC2() error {
+	info, err := fetchTypesEC2()
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(preload.EC2InstanceType.Diff(info).String())
+	return nil
+}
+
+func validateTypesEC2() []error {
+	return preload.EC2InstanceType.Validate()
+}
+
+func fetchTypesEC2() (*clients.InstanceTypeInfo, error) {
 	instanceTypes := clients.NewRegisteredInstanceTypes()
 	regionalTypes := clients.NewRegionalInstanceTypes()
 	ctx := context.Background()
 
 	defaultClient, err := clients.GetServiceEC2Client(ctx, "")
 	if err != nil {
-		return fmt.Errorf("unable to get default EC2 client: %w", err)
+		return nil, fmt.Errorf("unable to get default EC2 client: %w", err)
 	}
 
 	fmt.Println("Warning: Account must have all regions enabled, otherwise this will return 4xx")
 	regions, err := defaultClient.ListAllRegions(ctx)
 	if err != nil {
-		return fmt.Errorf("unable to list EC2 regions: %w", err)
+		return nil, fmt.Errorf("unable to list EC2 regions: %w", err)
 	}
 
 	// This will throw AuthFailure "AWS was not able to validate the provided access credentials" unless all regions
@@ -55,7 +91,7 @@ func generateTypesEC2() error {
 		fmt.Printf("Generating for region %s\n", region)
 		client, regionErr := clients.GetServiceEC2Client(ctx, region.String())
 		if regionErr != nil {
-			return fmt.Errorf("unable to get regional EC2 client: %w", regionErr)
+			return nil, fmt.Errorf("unable to get regional EC2 client: %w", regionErr)
 		}
 		instTypes, regionErr := client.ListInstanceTypes(ctx)
 		if regionErr != nil {
@@ -72,15 +108,8 @@ func generateTypesEC2() error {
 		}
 	}
 
-	err = instanceTypes.Save("internal/preload/ec2_types.yaml")
-	if err != nil {
-		return fmt.Errorf("unable to generate types: %w", err)
-	}
-
-	err = regionalTypes.Save("internal/preload/ec2_availability")
-	if err != nil {
-		return fmt.Errorf("unable to generate types: %w", err)
-	}
-
-	return nil
+	return &clients.InstanceTypeInfo{
+		RegisteredTypes:      *instanceTypes,
+		RegionalAvailability: *regionalTypes,
+	}, nil
 }
@@ -0,0 +1,152 @@
+package providers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/preload"
+)
+
+// gcpCatalogPage is a page of the Cloud Billing Catalog API SKU list response
+type gcpCatalogPage struct {
+	Skus []struct {
+		Description string `json:"description"`
+		Category    struct {
+			ResourceFamily string `json:"resourceFamily"`
+			UsageType      string `json:"usageType"`
+		} `json:"category"`
+		ServiceRegions []string `json:"serviceRegions"`
+		PricingInfo    []struct {
+			PricingExpression struct {
+				TieredRates []struct {
+					UnitPrice struct {
+						CurrencyCode string `json:"currencyCode"`
+						Units        string `json:"units"`
+						Nanos        int64  `json:"nanos"`
+					} `json:"unitPrice"`
+				} `json:"tieredRates"`
+			} `json:"pricingExpression"`
+		} `json:"pricingInfo"`
+	} `json:"skus"`
+}
+
+// gcpResourcePrice holds per vCPU and per GiB hourly prices of a machine family in a region
+type gcpResourcePrice struct {
+	core, ram float64
+}
+
+// gcpSkuFamily returns lowercase machine family and resource ("Core" or "Ram") from a SKU
+// description like "N2 Instance Core running in Americas" or "N1 Predefined Instance Ram running in Frankfurt".
+func gcpSkuFamily(description string) (string, string, bool) {
+	prefix, _, found := strings.Cut(description, " running in ")
+	if !found {
+		return "", "", false
+	}
+
+	for _, resource := range []string{"Core", "Ram"} {
+		family, found := strings.CutSuffix(prefix, " Instance "+resource)
+		if !found {
+			continue
+		}
+		family = strings.TrimSuffix(family, " Predefined")
+		if strings.Contains(family, " ") {
+			// custom, sole tenancy, commitments etc.
+			return "", "", false
+		}
+		return strings.ToLower(family), resource, true
+	}
+
+	return "", "", false
+}
+
+// generatePricesGCP reads on-demand vCPU and memory prices from the Cloud Billing Catalog API export
+// of Compute Engine SKUs and calculates prices for preloaded machine types. The file contains one or
+// more concatenated response pages of:
+// https://cloudbilling.googleapis.com/v1/services/6F81-5844-456A/skus
+func generatePricesGCP(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("unable to open price list: %w", err)
+	}
+	defer file.Close()
+
+	// region -> family -> price
+	resources := make(map[string]map[string]*gcpResourcePrice)
+	decoder := json.NewDecoder(file)
+	for {
+		var page gcpCatalogPage
+		err = decoder.Decode(&page)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("unable to decode price list: %w", err)
+		}
+
+		for _, sku := range page.Skus {
+			if sku.Category.ResourceFamily != "Compute" || sku.Category.UsageType != "OnDemand" || len(sku.PricingInfo) == 0 {
+				continue
+			}
+			family, resource, ok := gcpSkuFamily(sku.Description)
+			if !ok {
+				continue
+			}
+			rates := sku.PricingInfo[0].PricingExpression.TieredRates
+			if len(rates) == 0 || rates[len(rates)-1].UnitPrice.CurrencyCode != "USD" {
+				continue
+			}
+			// the last tier is the regular price, first tiers can be free
+			unitPrice := rates[len(rates)-1].UnitPrice
+			units, parseErr := strconv.ParseInt(unitPrice.Units, 10, 64)
+			if parseErr != nil {
+				continue
+			}
+			price := float64(units) + float64(unitPrice.Nanos)/1e9
+
+			for _, region := range sku.ServiceRegions {
+				if _, ok := resources[region]; !ok {
+					resources[region] = make(map[string]*gcpResourcePrice)
+				}
+				if _, ok := resources[region][family]; !ok {
+					resources[region][family] = &gcpResourcePrice{}
+				}
+				if resource == "Core" {
+					resources[region][family].core = price
+				} else {
+					resources[region][family].ram = price
+				}
+			}
+		}
+	}
+
+	prices := clients.NewRegionalPrices()
+	for region, families := range resources {
+		types, typesErr := preload.GCPInstanceType.InstanceTypesForRegion(region)
+		if typesErr != nil {
+			// region without preloaded availability
+			continue
+		}
+		for _, it := range types {
+			rp, ok := families[strings.ToLower(it.Family())]
+			if !ok || rp.core == 0 || rp.ram == 0 {
+				continue
+			}
+			price := float64(it.VCPUs)*rp.core + float64(it.MemoryMiB)/1024*rp.ram
+			prices.Set(region, it.Name, math.Round(price*1e6)/1e6)
+		}
+	}
+
+	fmt.Printf("Found %d prices\n", prices.Len())
+	err = prices.Save("internal/preload/gcp_prices.yaml")
+	if err != nil {
+		return fmt.Errorf("unable to save prices: %w", err)
+	}
+
+	return nil
+}
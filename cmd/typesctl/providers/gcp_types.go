@@ -13,6 +13,9 @@ func init() {
 		PrintRegisteredTypes:      printRegisteredTypesGCP,
 		PrintRegionalAvailability: printRegionalAvailabilityGCP,
 		GenerateTypes:             generateTypesGCP,
+		GeneratePrices:            generatePricesGCP,
+		DiffTypes:                 diffTypesGCP,
+		ValidateTypes:             validateTypesGCP,
 	}
 	TypeProviders["gcp"] = provider
 }
@@ -26,29 +29,55 @@ func printRegionalAvailabilityGCP(region, zone string) {
 }
 
 func generateTypesGCP() error {
-	instanceTypes := clients.NewRegisteredInstanceTypes()
-	regionalTypes := clients.NewRegionalInstanceTypes()
-	ctx := context.Background()
-
-	gcpClient, err := clients.GetServiceGCPClient(ctx)
+	info, err := fetchTypesGCP()
 	if err != nil {
-		return fmt.Errorf("unable to get GCP client: %w", err)
+		return err
 	}
 
-	err = gcpClient.RegisterInstanceTypes(ctx, instanceTypes, regionalTypes)
+	err = info.RegisteredTypes.Save("internal/preload/gcp_types.yaml")
 	if err != nil {
-		return fmt.Errorf("unable to generate types: %w", err)
+		return fmt.Errorf("unable to save types: %w", err)
 	}
 
-	err = instanceTypes.Save("internal/preload/gcp_types.yaml")
+	err = info.RegionalAvailability.Save("internal/preload/gcp_availability")
 	if err != nil {
-		return fmt.Errorf("unable to save types: %w", err)
+		return fmt.Errorf("unable to save regional types: %w", err)
 	}
 
-	err = regionalTypes.Save("internal/preload/gcp_availability")
+	return nil
+}
+
+func diffTypesGCP() error {
+	info, err := fetchTypesGCP()
 	if err != nil {
-		return fmt.Errorf("unable to save regional types: %w", err)
+		return err
 	}
 
+	fmt.Print(preload.GCPInstanceType.Diff(info).String())
 	return nil
 }
+
+func validateTypesGCP() []error {
+	return preload.GCPInstanceType.Validate()
+}
+
+func fetchTypesGCP() (*clients.InstanceTypeInfo, error) {
+	instanceTypes := clients.NewRegisteredInstanceTypes()
+	regionalTypes := clients.NewRegionalInstanceTypes()
+	ctx := context.Background()
+
+	gcpClient, err := clients.GetServiceGCPClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get GCP client: %w", err)
+	}
+
+	err = gcpClient.RegisterInstanceTypes(ctx, instanceTypes, regionalTypes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate types: %w", err)
+	}
+
+	return &clients.InstanceTypeInfo{
+		RegisteredTypes:      *instanceTypes,
+		RegionalAvailability: *regionalTypes,
+	}, nil
+}
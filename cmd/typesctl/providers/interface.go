@@ -6,6 +6,9 @@ type TypeProvider struct {
 	PrintRegisteredTypes      func(string)
 	PrintRegionalAvailability func(string, string)
 	GenerateTypes             func() error
+	GeneratePrices            func(string) error
+	DiffTypes                 func() error
+	ValidateTypes             func() []error
 }
 
 var TypeProviders = make(map[string]TypeProvider)
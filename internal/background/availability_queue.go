@@ -13,13 +13,20 @@ import (
 	"go.opentelemetry.io/otel/codes"
 )
 
+// availabilityRequest is an enqueued message with source ID used for deduplication.
+type availabilityRequest struct {
+	sourceID string
+	message  *kafka.GenericMessage
+}
+
 // buffered channel for incoming requests: the length is bigger than the batch size to have room
 // for additional messages when first batch is processed.
-var kafkaAvailabilityRequest = make(chan *kafka.GenericMessage, 5*availabilityStatusBatchSize)
+var kafkaAvailabilityRequest = make(chan availabilityRequest, 5*availabilityStatusBatchSize)
 
 // EnqueueAvailabilityStatusRequest prepares a status request check to be sent in the next
-// batch to the platform kafka. Messages can be delayed up to several seconds until sent.
-// The function can block if the enqueueing channel is full.
+// batch to the platform kafka. Messages can be delayed up to several seconds until sent and
+// requests of a source which was enqueued recently are dropped. The function can block if the
+// enqueueing channel is full.
 func EnqueueAvailabilityStatusRequest(ctx context.Context, asm *kafka.AvailabilityStatusMessage) error {
 	ctx, span := telemetry.StartSpan(ctx, "EnqueueAvailabilityStatusRequest")
 	defer span.End()
@@ -30,7 +37,7 @@ func EnqueueAvailabilityStatusRequest(ctx context.Context, asm *kafka.Availabili
 		return fmt.Errorf("cannot create message: %w", err)
 	}
 
-	kafkaAvailabilityRequest <- &msg
+	kafkaAvailabilityRequest <- availabilityRequest{sourceID: asm.SourceID, message: &msg}
 	zerolog.Ctx(ctx).Trace().Str("source_id", asm.SourceID).Msgf("Enqueued source id %s availability check", asm.SourceID)
 	return nil
 }
@@ -60,16 +67,54 @@ func send(ctx context.Context, method string, messages ...*kafka.GenericMessage)
 	}()
 }
 
-// main sending loop: takes messages enqueued via EnqueueAvailabilityStatusRequest and sends them to the kafka
-func sendAvailabilityRequestMessages(ctx context.Context, batchSize int, tickDuration time.Duration) {
+// sourceDebouncer remembers when sources were last enqueued, it is not safe for concurrent use.
+type sourceDebouncer struct {
+	window   time.Duration
+	lastSeen map[string]time.Time
+}
+
+func newSourceDebouncer(window time.Duration) *sourceDebouncer {
+	return &sourceDebouncer{window: window, lastSeen: make(map[string]time.Time)}
+}
+
+// allow returns false when the source was already allowed within the window.
+func (d *sourceDebouncer) allow(sourceID string, now time.Time) bool {
+	if d.window <= 0 {
+		return true
+	}
+	if last, ok := d.lastSeen[sourceID]; ok && now.Sub(last) < d.window {
+		return false
+	}
+	d.lastSeen[sourceID] = now
+	return true
+}
+
+// prune forgets sources which are out of the window.
+func (d *sourceDebouncer) prune(now time.Time) {
+	for sourceID, last := range d.lastSeen {
+		if now.Sub(last) >= d.window {
+			delete(d.lastSeen, sourceID)
+		}
+	}
+}
+
+// main sending loop: takes messages enqueued via EnqueueAvailabilityStatusRequest and sends them to the kafka,
+// repeated requests of the same source within the debounce window are dropped
+func sendAvailabilityRequestMessages(ctx context.Context, batchSize int, tickDuration, debounceWindow time.Duration) {
 	ticker := time.NewTicker(tickDuration)
 	messageBuffer := make([]*kafka.GenericMessage, 0, batchSize)
+	debouncer := newSourceDebouncer(debounceWindow)
 	defer sendWG.Wait()
 
 	for {
 		select {
-		case msg := <-kafkaAvailabilityRequest:
-			messageBuffer = append(messageBuffer, msg)
+		case req := <-kafkaAvailabilityRequest:
+			if !debouncer.allow(req.sourceID, time.Now()) {
+				zerolog.Ctx(ctx).Trace().Str("source_id", req.sourceID).Msgf("Dropping duplicate source id %s availability check", req.sourceID)
+				metrics.IncAvailabilityCheckSkipped("duplicate")
+				continue
+			}
+			messageBuffer = append(messageBuffer, req.message)
 			length := len(messageBuffer)
 
 			if length >= batchSize {
@@ -77,6 +122,7 @@ func sendAvailabilityRequestMessages(ctx context.Context, batchSize int, tickDur
 				messageBuffer = messageBuffer[:0]
 			}
 		case <-ticker.C:
+			debouncer.prune(time.Now())
 			length := len(messageBuffer)
 
 			if length > 0 {
@@ -24,7 +24,7 @@ func TestQueueNormalSend(t *testing.T) {
 	wg.Add(2)
 	cct, cancel := context.WithCancel(ctx)
 	defer cancel()
-	go sendAvailabilityRequestMessages(cct, 8, 10*time.Millisecond)
+	go sendAvailabilityRequestMessages(cct, 8, 10*time.Millisecond, 0)
 	go kafka.Consume(cct, kafka.AvailabilityStatusRequestTopic, time.Now(), func(ctx context.Context, msg *kafka.GenericMessage) {
 		asm, _ := kafka.NewAvailabilityStatusMessage(msg)
 		require.EqualValues(t, "1", asm.SourceID)
@@ -49,7 +49,7 @@ func TestFullQueueSend(t *testing.T) {
 	consumeCtx, consumeCancel := context.WithCancel(ctx)
 	senderCtx, senderCancel := context.WithCancel(ctx)
 	defer consumeCancel()
-	go sendAvailabilityRequestMessages(senderCtx, 2, time.Second)
+	go sendAvailabilityRequestMessages(senderCtx, 2, time.Second, 0)
 	go kafka.Consume(consumeCtx, kafka.AvailabilityStatusRequestTopic, time.Now(), func(ctx context.Context, msg *kafka.GenericMessage) {
 		asm, _ := kafka.NewAvailabilityStatusMessage(msg)
 		require.EqualValues(t, "1", asm.SourceID)
@@ -81,7 +81,7 @@ func TestQueueCancelSend(t *testing.T) {
 	wg.Add(2)
 	// start sending messages
 	senderCtx, senderCancel := context.WithCancel(ctx)
-	go sendAvailabilityRequestMessages(senderCtx, 2, 5*time.Second)
+	go sendAvailabilityRequestMessages(senderCtx, 2, 5*time.Second, 0)
 
 	// allow the other goroutine to put the message into the buffer
 	runtime.Gosched()
@@ -106,3 +106,51 @@ func TestQueueCancelSend(t *testing.T) {
 	// wait until the message is consumed
 	wg.Wait()
 }
+
+func TestQueueDuplicateSend(t *testing.T) {
+	ctx := context.Background()
+	ctx = identity.WithIdentity(t, ctx)
+	_ = kafka.InitializeStubBroker(16)
+
+	var mu sync.Mutex
+	var received []string
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	cct, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go sendAvailabilityRequestMessages(cct, 8, 10*time.Millisecond, time.Minute)
+	go kafka.Consume(cct, kafka.AvailabilityStatusRequestTopic, time.Now(), func(ctx context.Context, msg *kafka.GenericMessage) {
+		asm, _ := kafka.NewAvailabilityStatusMessage(msg)
+		mu.Lock()
+		received = append(received, asm.SourceID)
+		mu.Unlock()
+		wg.Done()
+	})
+
+	for _, id := range []string{"1", "1", "2"} {
+		err := EnqueueAvailabilityStatusRequest(ctx, &kafka.AvailabilityStatusMessage{SourceID: id})
+		require.NoError(t, err)
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.ElementsMatch(t, []string{"1", "2"}, received)
+}
+
+func TestSourceDebouncer(t *testing.T) {
+	now := time.Now()
+	d := newSourceDebouncer(time.Minute)
+
+	require.True(t, d.allow("1", now))
+	require.False(t, d.allow("1", now.Add(30*time.Second)), "duplicate within window was allowed")
+	require.True(t, d.allow("2", now.Add(30*time.Second)))
+	require.True(t, d.allow("1", now.Add(time.Minute)))
+
+	d.prune(now.Add(90 * time.Second))
+	require.Len(t, d.lastSeen, 1, "source out of window was not pruned")
+
+	disabled := newSourceDebouncer(0)
+	require.True(t, disabled.allow("1", now))
+	require.True(t, disabled.allow("1", now))
+}
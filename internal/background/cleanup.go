@@ -39,3 +39,37 @@ func cleanupReservations(ctx context.Context) {
 		logger.Error().Err(err).Msg("Error while performing reservation cleanup")
 	}
 }
+
+func jobHistoryCleanup(ctx context.Context, sleep, lifetime time.Duration) {
+	logger := zerolog.Ctx(ctx)
+	logger.Debug().Msgf("Started job history cleanup %s", sleep.String())
+	defer func() {
+		logger.Debug().Msgf("Job history cleanup routine exited")
+	}()
+
+	ticker := time.NewTicker(sleep)
+
+	cleanupJobHistory(ctx, lifetime)
+
+	for {
+		select {
+		case <-ticker.C:
+			cleanupJobHistory(ctx, lifetime)
+
+		case <-ctx.Done():
+			ticker.Stop()
+			return
+		}
+	}
+}
+
+func cleanupJobHistory(ctx context.Context, lifetime time.Duration) {
+	logger := zerolog.Ctx(ctx)
+	exDao := dao.GetJobExecutionDao(ctx)
+	deleted, err := exDao.UnscopedDeleteBefore(ctx, lifetime)
+	if err != nil {
+		logger.Error().Err(err).Msg("Error while performing job history cleanup")
+		return
+	}
+	logger.Debug().Int64("deleted", deleted).Msg("Deleted old job execution records")
+}
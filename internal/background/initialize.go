@@ -21,13 +21,34 @@ func InitializeApi(ctx context.Context) {
 	ctx = logger.WithContext(ctx)
 
 	// start availability request batch sender
-	go sendAvailabilityRequestMessages(ctx, availabilityStatusBatchSize, 2*time.Second)
+	go sendAvailabilityRequestMessages(ctx, availabilityStatusBatchSize, 2*time.Second, config.Debounce.Window)
+
+	// reload instance types from an external directory
+	if config.Application.InstanceTypes.Path != "" {
+		go instanceTypesRefreshLoop(ctx, config.Application.InstanceTypes.Path, config.Application.InstanceTypes.RefreshInterval)
+	}
 }
 
 // InitializeWorker starts background goroutines for worker processes.
 // Use context cancellation to stop them.
 func InitializeWorker(ctx context.Context) {
-	// no background goroutines at the moment
+	logger := zerolog.Ctx(ctx).With().Bool("background", true).Logger()
+	ctx = logger.WithContext(ctx)
+
+	// reload instance types from an external directory
+	if config.Application.InstanceTypes.Path != "" {
+		go instanceTypesRefreshLoop(ctx, config.Application.InstanceTypes.Path, config.Application.InstanceTypes.RefreshInterval)
+	}
+}
+
+// InitializeStatuser starts background goroutines for the statuser process.
+// Use context cancellation to stop them.
+func InitializeStatuser(ctx context.Context) {
+	logger := zerolog.Ctx(ctx).With().Bool("background", true).Logger()
+	ctx = logger.WithContext(ctx)
+
+	// start sources event-stream consumer (orphaned data cleanup)
+	go sourcesEventLoop(ctx)
 }
 
 // InitializeStats starts background goroutines for the statuser process.
@@ -46,4 +67,19 @@ func InitializeStats(ctx context.Context) {
 	if config.Reservation.CleanupEnabled {
 		go dbCleanup(ctx, config.Reservation.CleanupInterval)
 	}
+
+	// delete old job execution records
+	if config.Worker.HistoryLifetime > 0 {
+		go jobHistoryCleanup(ctx, config.Reservation.CleanupInterval, config.Worker.HistoryLifetime)
+	}
+
+	// launch scheduled and recurring reservations
+	if config.Reservation.SchedulerEnabled {
+		go reservationSchedulerLoop(ctx, config.Reservation.SchedulerInterval)
+	}
+
+	// finish reservations of lost launch jobs
+	if config.Reservation.ReaperEnabled {
+		go staleReservationReaper(ctx, config.Reservation.ReaperInterval)
+	}
 }
@@ -0,0 +1,61 @@
+package background
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/preload"
+	"github.com/rs/zerolog"
+)
+
+// instanceTypesRefreshLoop reloads instance type data from a directory on start, on SIGHUP and
+// periodically when interval is not zero.
+func instanceTypesRefreshLoop(ctx context.Context, dir string, interval time.Duration) {
+	logger := zerolog.Ctx(ctx)
+	logger.Debug().Msgf("Started instance types refresh routine from %s with interval %.2f seconds", dir, interval.Seconds())
+	defer func() {
+		logger.Debug().Msgf("Instance types refresh routine exited")
+	}()
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	// override embedded data immediately
+	instanceTypesRefreshTick(ctx, dir)
+
+	for {
+		select {
+		case <-tick:
+			instanceTypesRefreshTick(ctx, dir)
+
+		case <-hup:
+			logger.Info().Msg("Received SIGHUP, reloading instance types")
+			instanceTypesRefreshTick(ctx, dir)
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func instanceTypesRefreshTick(ctx context.Context, dir string) {
+	logger := zerolog.Ctx(ctx)
+	err := preload.Reload(dir)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to reload instance types, keeping previous data")
+		return
+	}
+
+	logger.Debug().Msgf("Reloaded instance types from %s", dir)
+}
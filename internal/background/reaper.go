@@ -0,0 +1,308 @@
+package background
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/config"
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/identity"
+	"github.com/RHEnVision/provisioning-backend/internal/jobs"
+	"github.com/RHEnVision/provisioning-backend/internal/metrics"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/notifications"
+	"github.com/RHEnVision/provisioning-backend/internal/queue"
+	"github.com/RHEnVision/provisioning-backend/pkg/worker"
+	"github.com/rs/zerolog"
+)
+
+var ErrLaunchJobLost = errors.New("launch job did not finish and no instances were found")
+
+// maximum amount of reservations checked in a single round
+const reaperBatchSize = 50
+
+// staleReservationReaper finishes reservations which are pending longer than the worker timeout
+// plus a margin. Their launch job was lost, for example when a worker was killed, so they would
+// be pending forever. Instances tagged with the reservation ID are looked up on the cloud
+// provider, the reservation succeeds when there are any and fails otherwise.
+func staleReservationReaper(ctx context.Context, sleep time.Duration) {
+	logger := zerolog.Ctx(ctx)
+	logger.Debug().Msgf("Started stale reservation reaper %s", sleep.String())
+	defer func() {
+		logger.Debug().Msgf("Stale reservation reaper routine exited")
+	}()
+
+	ticker := time.NewTicker(sleep)
+
+	reapStaleReservations(ctx, time.Now())
+
+	for {
+		select {
+		case <-ticker.C:
+			reapStaleReservations(ctx, time.Now())
+
+		case <-ctx.Done():
+			ticker.Stop()
+			return
+		}
+	}
+}
+
+func reapStaleReservations(ctx context.Context, now time.Time) {
+	logger := zerolog.Ctx(ctx)
+	age := config.Worker.Timeout + config.Reservation.ReaperMargin
+	reservations, err := dao.GetReservationDao(ctx).UnscopedListStale(ctx, age, reaperBatchSize)
+	if err != nil {
+		logger.Error().Err(err).Msg("Error while listing stale reservations")
+		return
+	}
+	if len(reservations) == 0 {
+		return
+	}
+
+	queued, err := queuedReservationIDs(ctx, now)
+	if err != nil {
+		logger.Error().Err(err).Msg("Error while listing queued launch jobs")
+		return
+	}
+
+	for _, reservation := range reservations {
+		if queued[reservation.ID] {
+			logger.Debug().Int64("reservation_id", reservation.ID).Msg("Stale reservation still has a queued job")
+			continue
+		}
+		reapReservation(ctx, reservation)
+	}
+}
+
+// queuedReservationIDs returns IDs of reservations with a delayed, queued or in-flight launch job.
+// In-flight jobs started longer than the worker timeout ago were lost together with their worker,
+// their records are removed and the reservations are reaped.
+func queuedReservationIDs(ctx context.Context, now time.Time) (map[int64]bool, error) {
+	logger := zerolog.Ctx(ctx)
+	wk := queue.GetWorker(ctx)
+	infos, err := wk.ListJobs(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list jobs: %w", err)
+	}
+
+	result := make(map[int64]bool, len(infos))
+	for _, info := range infos {
+		id, ok := jobs.LaunchReservationID(info.Job.Args)
+		if !ok {
+			continue
+		}
+		if info.State == worker.JobStateInFlight && now.Sub(info.StartedAt) > config.Worker.Timeout {
+			logger.Warn().Int64("reservation_id", id).Str("job_id", info.Job.ID.String()).
+				Time("started_at", info.StartedAt).Msg("Removing in-flight record of a lost launch job")
+			if err := wk.DeleteJob(ctx, info.Job.ID); err != nil {
+				logger.Error().Err(err).Str("job_id", info.Job.ID.String()).Msg("Unable to remove in-flight record of a lost job")
+			}
+			continue
+		}
+		result[id] = true
+	}
+	return result, nil
+}
+
+func reapReservation(ctx context.Context, reservation *models.Reservation) {
+	provider := reservation.Provider.String()
+	logger := zerolog.Ctx(ctx).With().
+		Int64("reservation_id", reservation.ID).
+		Int64("account_id", reservation.AccountID).
+		Str("provider", provider).
+		Logger()
+	ctx = logger.WithContext(ctx)
+
+	ctx, err := withReservationAccount(ctx, reservation.AccountID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to load account of stale reservation")
+		metrics.IncStaleReservation(provider, "error")
+		return
+	}
+
+	instances, err := findReservationInstances(ctx, reservation)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Unable to look up instances of stale reservation, will retry")
+		metrics.IncStaleReservation(provider, "error")
+		return
+	}
+
+	if len(instances) == 0 {
+		failReservation(ctx, reservation)
+		return
+	}
+
+	err = storeMissingInstances(ctx, reservation.ID, instances)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to store instances of stale reservation")
+		metrics.IncStaleReservation(provider, "error")
+		return
+	}
+
+	finished, err := dao.GetReservationDao(ctx).UnscopedFinishPending(ctx, reservation.ID, true, "")
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to finish stale reservation")
+		metrics.IncStaleReservation(provider, "error")
+		return
+	}
+	if !finished {
+		logger.Debug().Msg("Stale reservation was finished meanwhile")
+		return
+	}
+
+	logger.Warn().Int("instances", len(instances)).Msg("Finished stale reservation with instances found")
+	metrics.IncStaleReservation(provider, "success")
+	notifications.GetNotificationClient(ctx).SuccessfulLaunch(ctx, reservation.ID)
+}
+
+func failReservation(ctx context.Context, reservation *models.Reservation) {
+	logger := zerolog.Ctx(ctx)
+	provider := reservation.Provider.String()
+
+	finished, err := dao.GetReservationDao(ctx).UnscopedFinishPending(ctx, reservation.ID, false, ErrLaunchJobLost.Error())
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to fail stale reservation")
+		metrics.IncStaleReservation(provider, "error")
+		return
+	}
+	if !finished {
+		logger.Debug().Msg("Stale reservation was finished meanwhile")
+		return
+	}
+
+	logger.Warn().Msg("Failed stale reservation without instances")
+	metrics.IncStaleReservation(provider, "failure")
+	notifications.GetNotificationClient(ctx).FailedLaunch(ctx, reservation.ID, ErrLaunchJobLost)
+}
+
+// withReservationAccount returns context with identity and account of the reservation, calls to
+// sources and notifications are made on behalf of the account.
+func withReservationAccount(ctx context.Context, accountID int64) (context.Context, error) {
+	account, err := dao.GetAccountDao(ctx).GetById(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get account: %w", err)
+	}
+
+	principal := identity.Principal{}
+	principal.Identity.OrgID = account.OrgID
+	if account.AccountNumber.Valid {
+		principal.Identity.AccountNumber = account.AccountNumber.String
+	}
+	ctx = identity.WithIdentity(ctx, principal)
+	ctx = identity.WithAccountId(ctx, accountID)
+	return ctx, nil
+}
+
+// findReservationInstances returns instances tagged or labeled with the reservation.
+func findReservationInstances(ctx context.Context, reservation *models.Reservation) ([]*clients.InstanceDescription, error) {
+	rDao := dao.GetReservationDao(ctx)
+	tag := config.EnvironmentPrefix("r", strconv.FormatInt(reservation.ID, 10))
+
+	switch reservation.Provider {
+	case models.ProviderTypeAWS:
+		awsReservation, err := rDao.GetAWSById(ctx, reservation.ID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get AWS reservation: %w", err)
+		}
+		auth, err := jobs.SourceAuthentication(ctx, awsReservation.SourceID, models.ProviderTypeAWS)
+		if err != nil {
+			return nil, err
+		}
+		ec2Client, err := clients.GetEC2Client(ctx, auth, awsReservation.Detail.Region)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get EC2 client: %w", err)
+		}
+		instances, err := ec2Client.ListInstancesByTag(ctx, "rh-rid", tag)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list EC2 instances: %w", err)
+		}
+		return instances, nil
+	case models.ProviderTypeAzure:
+		azureReservation, err := rDao.GetAzureById(ctx, reservation.ID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get Azure reservation: %w", err)
+		}
+		auth, err := jobs.SourceAuthentication(ctx, azureReservation.SourceID, models.ProviderTypeAzure)
+		if err != nil {
+			return nil, err
+		}
+		azureClient, err := clients.GetAzureClient(ctx, auth)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get Azure client: %w", err)
+		}
+		vms, err := azureClient.ListVMsByTag(ctx, "rh-rid", tag)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list Azure virtual machines: %w", err)
+		}
+		instances := make([]*clients.InstanceDescription, len(vms))
+		for i := range vms {
+			instances[i] = &vms[i]
+		}
+		return instances, nil
+	case models.ProviderTypeGCP:
+		gcpReservation, err := rDao.GetGCPById(ctx, reservation.ID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get GCP reservation: %w", err)
+		}
+		if gcpReservation.Detail == nil || gcpReservation.Detail.UUID == "" {
+			return nil, nil
+		}
+		auth, err := jobs.SourceAuthentication(ctx, gcpReservation.SourceID, models.ProviderTypeGCP)
+		if err != nil {
+			return nil, err
+		}
+		gcpClient, err := clients.GetGCPClient(ctx, auth)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get GCP client: %w", err)
+		}
+		ids, err := gcpClient.ListInstancesIDsByLabel(ctx, gcpReservation.Detail.UUID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list GCP instances: %w", err)
+		}
+		instances := make([]*clients.InstanceDescription, 0, len(ids))
+		for _, id := range ids {
+			instances = append(instances, &clients.InstanceDescription{ID: *id})
+		}
+		return instances, nil
+	case models.ProviderTypeNoop, models.ProviderTypeUnknown:
+		// nothing is launched
+	}
+	return nil, nil
+}
+
+// storeMissingInstances stores found instances which the lost job did not store.
+func storeMissingInstances(ctx context.Context, reservationID int64, instances []*clients.InstanceDescription) error {
+	rDao := dao.GetReservationDao(ctx)
+	existing, err := rDao.ListInstances(ctx, reservationID)
+	if err != nil {
+		return fmt.Errorf("unable to list reservation instances: %w", err)
+	}
+	stored := make(map[string]bool, len(existing))
+	for _, instance := range existing {
+		stored[instance.InstanceID] = true
+	}
+
+	for _, instance := range instances {
+		if stored[instance.ID] {
+			continue
+		}
+		err = rDao.CreateInstance(ctx, &models.ReservationInstance{
+			ReservationID: reservationID,
+			InstanceID:    instance.ID,
+			Detail: models.ReservationInstanceDetail{
+				PublicIPv4:  instance.IPv4,
+				PublicDNS:   instance.DNS,
+				PrivateIPv4: instance.PrivateIPv4,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("unable to store instance %s: %w", instance.ID, err)
+		}
+	}
+	return nil
+}
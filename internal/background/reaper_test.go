@@ -0,0 +1,161 @@
+package background
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	clientStubs "github.com/RHEnVision/provisioning-backend/internal/clients/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/config"
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	daoStubs "github.com/RHEnVision/provisioning-backend/internal/dao/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/identity"
+	"github.com/RHEnVision/provisioning-backend/internal/jobs"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/queue"
+	tidentity "github.com/RHEnVision/provisioning-backend/internal/testing/identity"
+	"github.com/RHEnVision/provisioning-backend/pkg/worker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func prepareStaleReservation(t *testing.T, ctx context.Context, queued bool) int64 {
+	t.Helper()
+
+	reservation := &models.AWSReservation{
+		SourceID: "1",
+		ImageID:  "ami-random",
+		Detail: &models.AWSDetail{
+			Region:       "us-east-1",
+			InstanceType: "t1.micro",
+			Amount:       1,
+		},
+	}
+	reservation.AccountID = identity.AccountId(ctx)
+	reservation.Provider = models.ProviderTypeAWS
+	reservation.CreatedAt = time.Now().Add(-24 * time.Hour)
+	if queued {
+		reservation.QueuedAt = sql.NullTime{Time: reservation.CreatedAt, Valid: true}
+	}
+	err := daoStubs.AddAWSReservation(ctx, reservation)
+	require.NoError(t, err)
+
+	return reservation.ID
+}
+
+func TestReapStaleReservations(t *testing.T) {
+	ctx := daoStubs.WithAccountDaoOne(context.Background())
+	ctx = tidentity.WithTenant(t, ctx)
+	ctx = daoStubs.WithReservationDao(ctx)
+	ctx = clientStubs.WithSourcesClient(ctx)
+	ctx = clientStubs.WithEC2Client(ctx)
+
+	wk := worker.NewMemoryClient()
+	getWorker := queue.GetWorker
+	queue.GetWorker = func(_ context.Context) worker.JobWorker {
+		return wk
+	}
+	t.Cleanup(func() {
+		queue.GetWorker = getWorker
+	})
+
+	launchedID := prepareStaleReservation(t, ctx, true)
+	lostID := prepareStaleReservation(t, ctx, true)
+	queuedID := prepareStaleReservation(t, ctx, true)
+	waitingID := prepareStaleReservation(t, ctx, false)
+
+	tag := config.EnvironmentPrefix("r", strconv.FormatInt(launchedID, 10))
+	err := clientStubs.AddStubbedEC2TaggedInstance(ctx, "rh-rid", tag, &clients.InstanceDescription{ID: "i-1", IPv4: "10.0.0.1"})
+	require.NoError(t, err)
+
+	// the memory worker is not started, a delayed job is listed without blocking
+	err = wk.EnqueueAfter(ctx, &worker.Job{
+		Type: jobs.TypeLaunchInstanceAws,
+		Args: jobs.LaunchInstanceAWSTaskArgs{ReservationID: queuedID},
+	}, time.Hour)
+	require.NoError(t, err)
+
+	reapStaleReservations(ctx, time.Now())
+
+	rDao := dao.GetReservationDao(ctx)
+	launched, err := rDao.GetById(ctx, launchedID)
+	require.NoError(t, err)
+	assert.True(t, launched.Success.Valid && launched.Success.Bool)
+	instances, err := rDao.ListInstances(ctx, launchedID)
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, "i-1", instances[0].InstanceID)
+	assert.Equal(t, "10.0.0.1", instances[0].Detail.PublicIPv4)
+
+	lost, err := rDao.GetById(ctx, lostID)
+	require.NoError(t, err)
+	assert.True(t, lost.Success.Valid && !lost.Success.Bool)
+	assert.Equal(t, ErrLaunchJobLost.Error(), lost.Error)
+
+	queued, err := rDao.GetById(ctx, queuedID)
+	require.NoError(t, err)
+	assert.False(t, queued.Success.Valid, "reservation with a queued job must stay pending")
+
+	waiting, err := rDao.GetById(ctx, waitingID)
+	require.NoError(t, err)
+	assert.False(t, waiting.Success.Valid, "reservation which was never queued must stay pending")
+}
+
+func TestReapStaleReservationsLostInFlightJob(t *testing.T) {
+	ctx := daoStubs.WithAccountDaoOne(context.Background())
+	ctx = tidentity.WithTenant(t, ctx)
+	ctx = daoStubs.WithReservationDao(ctx)
+	ctx = clientStubs.WithSourcesClient(ctx)
+	ctx = clientStubs.WithEC2Client(ctx)
+
+	timeout := config.Worker.Timeout
+	config.Worker.Timeout = time.Hour
+	wk := worker.NewMemoryClient()
+	getWorker := queue.GetWorker
+	queue.GetWorker = func(_ context.Context) worker.JobWorker {
+		return wk
+	}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	wk.RegisterHandler(jobs.TypeLaunchInstanceAws, func(_ context.Context, _ *worker.Job) {
+		started <- struct{}{}
+		<-release
+	}, nil)
+	wk.DequeueLoop(ctx)
+	t.Cleanup(func() {
+		close(release)
+		wk.Stop(ctx)
+		queue.GetWorker = getWorker
+		config.Worker.Timeout = timeout
+	})
+
+	lostID := prepareStaleReservation(t, ctx, true)
+	job := &worker.Job{
+		Type: jobs.TypeLaunchInstanceAws,
+		Args: jobs.LaunchInstanceAWSTaskArgs{ReservationID: lostID},
+	}
+	err := wk.Enqueue(ctx, job)
+	require.NoError(t, err)
+	<-started
+
+	// the in-flight job is not older than the worker timeout yet
+	reapStaleReservations(ctx, time.Now())
+
+	rDao := dao.GetReservationDao(ctx)
+	running, err := rDao.GetById(ctx, lostID)
+	require.NoError(t, err)
+	assert.False(t, running.Success.Valid, "reservation with a running job must stay pending")
+
+	reapStaleReservations(ctx, time.Now().Add(2*time.Hour))
+
+	_, err = wk.GetJob(ctx, job.ID)
+	require.ErrorIs(t, err, worker.ErrJobNotFound, "in-flight record of the lost job must be removed")
+
+	lost, err := rDao.GetById(ctx, lostID)
+	require.NoError(t, err)
+	assert.True(t, lost.Success.Valid && !lost.Success.Bool)
+	assert.Equal(t, ErrLaunchJobLost.Error(), lost.Error)
+}
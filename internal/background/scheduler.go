@@ -0,0 +1,224 @@
+package background
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/cron"
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/identity"
+	"github.com/RHEnVision/provisioning-backend/internal/jobs"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/pkg/worker"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+var ErrUnexpectedLaunchArgs = errors.New("unexpected launch job arguments")
+
+// reservationSchedulerLoop enqueues launch jobs of scheduled reservations. Multiple stats processes
+// can run it, only the one holding the database lock launches reservations in each round.
+func reservationSchedulerLoop(ctx context.Context, sleep time.Duration) {
+	logger := zerolog.Ctx(ctx)
+	logger.Debug().Msgf("Started reservation scheduler %s", sleep.String())
+	defer func() {
+		logger.Debug().Msgf("Reservation scheduler routine exited")
+	}()
+
+	ticker := time.NewTicker(sleep)
+
+	runSchedules(ctx, time.Now())
+
+	for {
+		select {
+		case <-ticker.C:
+			runSchedules(ctx, time.Now())
+
+		case <-ctx.Done():
+			ticker.Stop()
+			return
+		}
+	}
+}
+
+func runSchedules(ctx context.Context, now time.Time) {
+	logger := zerolog.Ctx(ctx)
+	schedules, locked, err := dao.GetScheduleDao(ctx).UnscopedClaimDue(ctx, now.UTC(), nextScheduleRun)
+	if err != nil {
+		logger.Error().Err(err).Msg("Error while claiming scheduled reservations")
+		return
+	}
+	if !locked {
+		logger.Trace().Msg("Reservation scheduler is running in a different process")
+		return
+	}
+
+	// schedules were already moved to the next run, a failed launch is not repeated
+	for _, schedule := range schedules {
+		launchSchedule(ctx, schedule)
+	}
+}
+
+// nextScheduleRun returns the next run time of a recurring schedule, or zero time for one-time
+// schedules which are deleted.
+func nextScheduleRun(ctx context.Context, schedule *models.ReservationSchedule) time.Time {
+	if !schedule.Recurring() {
+		return time.Time{}
+	}
+
+	cronSchedule, err := cron.Parse(schedule.Cron)
+	if err != nil {
+		// the expression was validated on creation, the schedule is dropped
+		zerolog.Ctx(ctx).Error().Err(err).Int64("reservation_id", schedule.ReservationID).Msgf("Invalid schedule %q", schedule.Cron)
+		return time.Time{}
+	}
+	return cronSchedule.Next(time.Now())
+}
+
+// launchSchedule enqueues the launch job of a schedule. One-time schedules launch the reservation
+// itself, recurring schedules launch a copy of the reservation each time.
+func launchSchedule(ctx context.Context, schedule *models.ReservationSchedule) {
+	ctx = identity.WithIdentity(ctx, schedule.JobIdentity)
+	ctx = identity.WithAccountId(ctx, schedule.AccountID)
+	logger := zerolog.Ctx(ctx).With().
+		Int64("reservation_id", schedule.ReservationID).
+		Int64("account_id", schedule.AccountID).
+		Logger()
+	ctx = logger.WithContext(ctx)
+
+	reservationID, err := enqueueScheduledLaunch(ctx, schedule)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to launch scheduled reservation")
+		if !schedule.Recurring() {
+			finishErr := dao.GetReservationDao(ctx).FinishWithError(ctx, schedule.ReservationID, err.Error())
+			if finishErr != nil {
+				logger.Error().Err(finishErr).Msg("Unable to finish scheduled reservation")
+			}
+		}
+		return
+	}
+
+	logger.Info().Int64("launched_reservation_id", reservationID).Msgf("Launched scheduled reservation %d", reservationID)
+}
+
+// enqueueScheduledLaunch enqueues the stored launch job with authentication and image resolved
+// and returns ID of the launched reservation.
+func enqueueScheduledLaunch(ctx context.Context, schedule *models.ReservationSchedule) (int64, error) {
+	jobType := worker.JobType(schedule.JobType)
+	args, err := jobs.DecodeLaunchArgs(jobType, schedule.JobArgs)
+	if err != nil {
+		return 0, fmt.Errorf("unable to decode launch job: %w", err)
+	}
+
+	reservationID := schedule.ReservationID
+	if schedule.Recurring() {
+		args, reservationID, err = cloneScheduledReservation(ctx, schedule.ReservationID, args)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	args, err = jobs.ResolveLaunchArgs(ctx, args)
+	if err != nil {
+		return 0, fmt.Errorf("unable to resolve launch job: %w", err)
+	}
+
+	job := worker.Job{
+		Type:      jobType,
+		AccountID: schedule.AccountID,
+		Identity:  schedule.JobIdentity,
+		EdgeID:    schedule.JobEdgeID,
+		Args:      args,
+	}
+	err = jobs.EnqueueLaunch(ctx, &job)
+	if err != nil {
+		return 0, fmt.Errorf("unable to launch scheduled reservation: %w", err)
+	}
+
+	return reservationID, nil
+}
+
+// cloneScheduledReservation creates a new reservation from the template of a recurring schedule
+// and returns job arguments updated for the new reservation.
+func cloneScheduledReservation(ctx context.Context, templateID int64, args any) (any, int64, error) {
+	rDao := dao.GetReservationDao(ctx)
+	template, err := rDao.GetById(ctx, templateID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to get reservation template: %w", err)
+	}
+
+	reservation := models.Reservation{
+		Provider:   template.Provider,
+		AccountID:  template.AccountID,
+		Steps:      template.Steps,
+		StepTitles: template.StepTitles,
+		Status:     "Created",
+		ParentID:   &template.ID,
+	}
+
+	switch typedArgs := args.(type) {
+	case jobs.LaunchInstanceAWSTaskArgs:
+		awsTemplate, err := rDao.GetAWSById(ctx, templateID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("unable to get AWS reservation template: %w", err)
+		}
+		awsReservation := &models.AWSReservation{
+			Reservation: reservation,
+			PubkeyID:    awsTemplate.PubkeyID,
+			SourceID:    awsTemplate.SourceID,
+			ImageID:     awsTemplate.ImageID,
+			Detail:      awsTemplate.Detail,
+		}
+		err = rDao.CreateAWS(ctx, awsReservation)
+		if err != nil {
+			return nil, 0, fmt.Errorf("unable to create AWS reservation: %w", err)
+		}
+		typedArgs.ReservationID = awsReservation.ID
+		typedArgs.Detail = awsReservation.Detail
+		return typedArgs, awsReservation.ID, nil
+	case jobs.LaunchInstanceAzureTaskArgs:
+		azureTemplate, err := rDao.GetAzureById(ctx, templateID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("unable to get Azure reservation template: %w", err)
+		}
+		azureReservation := &models.AzureReservation{
+			Reservation: reservation,
+			PubkeyID:    azureTemplate.PubkeyID,
+			SourceID:    azureTemplate.SourceID,
+			ImageID:     azureTemplate.ImageID,
+			Detail:      azureTemplate.Detail,
+		}
+		err = rDao.CreateAzure(ctx, azureReservation)
+		if err != nil {
+			return nil, 0, fmt.Errorf("unable to create Azure reservation: %w", err)
+		}
+		typedArgs.ReservationID = azureReservation.ID
+		return typedArgs, azureReservation.ID, nil
+	case jobs.LaunchInstanceGCPTaskArgs:
+		gcpTemplate, err := rDao.GetGCPById(ctx, templateID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("unable to get GCP reservation template: %w", err)
+		}
+		// instances are found by the UUID label, each launch needs its own
+		detail := *gcpTemplate.Detail
+		detail.UUID = uuid.New().String()
+		gcpReservation := &models.GCPReservation{
+			Reservation: reservation,
+			PubkeyID:    gcpTemplate.PubkeyID,
+			SourceID:    gcpTemplate.SourceID,
+			ImageID:     gcpTemplate.ImageID,
+			Detail:      &detail,
+		}
+		err = rDao.CreateGCP(ctx, gcpReservation)
+		if err != nil {
+			return nil, 0, fmt.Errorf("unable to create GCP reservation: %w", err)
+		}
+		typedArgs.ReservationID = gcpReservation.ID
+		typedArgs.Detail = gcpReservation.Detail
+		return typedArgs, gcpReservation.ID, nil
+	default:
+		return nil, 0, fmt.Errorf("%w: %T", ErrUnexpectedLaunchArgs, args)
+	}
+}
@@ -0,0 +1,175 @@
+package background
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	clientStubs "github.com/RHEnVision/provisioning-backend/internal/clients/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	daoStubs "github.com/RHEnVision/provisioning-backend/internal/dao/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/identity"
+	"github.com/RHEnVision/provisioning-backend/internal/jobs"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/queue"
+	tidentity "github.com/RHEnVision/provisioning-backend/internal/testing/identity"
+	"github.com/RHEnVision/provisioning-backend/pkg/worker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errQueueDown = errors.New("queue is down")
+
+// the package imports the job queue which replaces the stubbed enqueuer
+type recordingEnqueuer struct {
+	enqueued []*worker.Job
+	err      error
+}
+
+func (e *recordingEnqueuer) Enqueue(_ context.Context, job *worker.Job) error {
+	if e.err != nil {
+		return e.err
+	}
+	e.enqueued = append(e.enqueued, job)
+	return nil
+}
+
+func (e *recordingEnqueuer) EnqueueAt(ctx context.Context, job *worker.Job, _ time.Time) error {
+	return e.Enqueue(ctx, job)
+}
+
+func (e *recordingEnqueuer) EnqueueAfter(ctx context.Context, job *worker.Job, _ time.Duration) error {
+	return e.Enqueue(ctx, job)
+}
+
+func withRecordingEnqueuer(t *testing.T) *recordingEnqueuer {
+	t.Helper()
+	enqueuer := &recordingEnqueuer{}
+	getEnqueuer := queue.GetEnqueuer
+	queue.GetEnqueuer = func(_ context.Context) worker.JobEnqueuer {
+		return enqueuer
+	}
+	t.Cleanup(func() {
+		queue.GetEnqueuer = getEnqueuer
+	})
+	return enqueuer
+}
+
+func prepareSchedule(t *testing.T, ctx context.Context, cron string, nextRunAt time.Time) int64 {
+	t.Helper()
+
+	reservation := &models.AWSReservation{
+		SourceID: "1",
+		ImageID:  "ami-random",
+		Detail: &models.AWSDetail{
+			Region:       "us-east-1",
+			InstanceType: "t1.micro",
+			Amount:       1,
+		},
+	}
+	reservation.AccountID = identity.AccountId(ctx)
+	reservation.Provider = models.ProviderTypeAWS
+	reservation.Steps = 3
+	err := daoStubs.AddAWSReservation(ctx, reservation)
+	require.NoError(t, err)
+
+	args, err := json.Marshal(jobs.LaunchInstanceAWSTaskArgs{
+		ReservationID: reservation.ID,
+		Region:        reservation.Detail.Region,
+		Detail:        reservation.Detail,
+	})
+	require.NoError(t, err)
+
+	err = daoStubs.AddSchedule(ctx, &models.ReservationSchedule{
+		ReservationID: reservation.ID,
+		Cron:          cron,
+		NextRunAt:     nextRunAt,
+		JobType:       jobs.TypeLaunchInstanceAws.String(),
+		JobIdentity:   identity.Identity(ctx),
+		JobArgs:       args,
+	})
+	require.NoError(t, err)
+
+	return reservation.ID
+}
+
+func TestRunSchedules(t *testing.T) {
+	ctx := daoStubs.WithAccountDaoOne(context.Background())
+	ctx = tidentity.WithTenant(t, ctx)
+	ctx = daoStubs.WithReservationDao(ctx)
+	ctx = daoStubs.WithScheduleDao(ctx)
+	ctx = clientStubs.WithSourcesClient(ctx)
+	enqueuer := withRecordingEnqueuer(t)
+
+	now := time.Now()
+	oneTimeID := prepareSchedule(t, ctx, "", now.Add(-time.Minute))
+	recurringID := prepareSchedule(t, ctx, "0 2 * * *", now.Add(-time.Minute))
+	prepareSchedule(t, ctx, "", now.Add(time.Hour))
+
+	runSchedules(ctx, now)
+
+	enqueued := enqueuer.enqueued
+	require.Len(t, enqueued, 2)
+	oneTimeArgs, ok := enqueued[0].Args.(jobs.LaunchInstanceAWSTaskArgs)
+	require.True(t, ok)
+	assert.Equal(t, oneTimeID, oneTimeArgs.ReservationID)
+
+	// authentication and image are not stored with the schedule, they are resolved on launch
+	require.NotNil(t, oneTimeArgs.ARN)
+	assert.Equal(t, "arn:aws:iam::230214684733:role/Test", oneTimeArgs.ARN.Payload)
+	assert.Equal(t, "ami-random", oneTimeArgs.AMI)
+
+	// launched reservations are queued from now on, the recurring template never is
+	oneTime, err := dao.GetReservationDao(ctx).GetById(ctx, oneTimeID)
+	require.NoError(t, err)
+	assert.True(t, oneTime.QueuedAt.Valid)
+	template, err := dao.GetReservationDao(ctx).GetById(ctx, recurringID)
+	require.NoError(t, err)
+	assert.False(t, template.QueuedAt.Valid)
+
+	// recurring schedule launches a copy of the reservation
+	recurringArgs, ok := enqueued[1].Args.(jobs.LaunchInstanceAWSTaskArgs)
+	require.True(t, ok)
+	launchedID := recurringArgs.ReservationID
+	assert.Equal(t, 4, daoStubs.AWSReservationStubCount(ctx))
+	launched, err := dao.GetReservationDao(ctx).GetAWSById(ctx, launchedID)
+	require.NoError(t, err)
+	require.NotNil(t, launched.ParentID)
+	assert.Equal(t, recurringID, *launched.ParentID)
+	assert.Equal(t, "t1.micro", launched.Detail.InstanceType)
+
+	// one-time schedule is removed, recurring is moved to the next run
+	assert.Equal(t, 2, daoStubs.ScheduleStubCount(ctx))
+	schedule, err := dao.GetScheduleDao(ctx).GetByReservationId(ctx, recurringID)
+	require.NoError(t, err)
+	assert.True(t, schedule.NextRunAt.After(now))
+	assert.Equal(t, 2, schedule.NextRunAt.Hour())
+	assert.True(t, schedule.LastRunAt.Valid)
+}
+
+func TestRunSchedulesFailedLaunch(t *testing.T) {
+	ctx := daoStubs.WithAccountDaoOne(context.Background())
+	ctx = tidentity.WithTenant(t, ctx)
+	ctx = daoStubs.WithReservationDao(ctx)
+	ctx = daoStubs.WithScheduleDao(ctx)
+	ctx = clientStubs.WithSourcesClient(ctx)
+	enqueuer := withRecordingEnqueuer(t)
+	enqueuer.err = errQueueDown
+
+	now := time.Now()
+	recurringID := prepareSchedule(t, ctx, "0 2 * * *", now.Add(-time.Minute))
+
+	runSchedules(ctx, now)
+	require.Empty(t, enqueuer.enqueued)
+
+	// the schedule was moved before the launch, the next round does not launch it again
+	schedule, err := dao.GetScheduleDao(ctx).GetByReservationId(ctx, recurringID)
+	require.NoError(t, err)
+	assert.True(t, schedule.NextRunAt.After(now))
+
+	enqueuer.err = nil
+	runSchedules(ctx, now)
+	require.Empty(t, enqueuer.enqueued)
+}
@@ -0,0 +1,158 @@
+package background
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/RHEnVision/provisioning-backend/internal/cache"
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	httpClients "github.com/RHEnVision/provisioning-backend/internal/clients/http"
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/kafka"
+	"github.com/RHEnVision/provisioning-backend/internal/metrics"
+	"github.com/RHEnVision/provisioning-backend/internal/ptr"
+	"github.com/rs/zerolog"
+)
+
+// error stored into pending reservations which source was deleted
+const sourceDeletedReservationError = "source or its provisioning authentication was deleted"
+
+// sourcesEventLoop consumes the sources event-stream topic and cleans up data associated with
+// deleted sources. The consumer group offset is committed, events published while no statuser
+// was running are processed after start. Blocking call, use context cancellation to stop it.
+func sourcesEventLoop(ctx context.Context) {
+	logger := zerolog.Ctx(ctx)
+	logger.Debug().Msgf("Started sources event-stream consumer on topic %s", kafka.SourcesEventStreamTopic)
+	defer func() {
+		logger.Debug().Msgf("Sources event-stream consumer exited")
+	}()
+
+	kafka.ConsumeGroup(ctx, kafka.SourcesEventStreamTopic, kafka.SourcesEventStreamGroup, processSourcesEvent)
+}
+
+func processSourcesEvent(msgCtx context.Context, message *kafka.GenericMessage) {
+	logger := zerolog.Ctx(msgCtx)
+
+	sem, err := kafka.NewSourcesEventMessage(message)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Could not parse sources event message")
+		metrics.IncSourcesEvent("invalid", "error")
+		return
+	}
+
+	switch sem.EventType {
+	case kafka.SourceDestroyEvent,
+		kafka.ApplicationUpdateEvent,
+		kafka.ApplicationDestroyEvent,
+		kafka.AuthenticationCreateEvent,
+		kafka.AuthenticationUpdateEvent,
+		kafka.AuthenticationDestroyEvent:
+	default:
+		// all other events are not interesting
+		return
+	}
+
+	sourceId, err := sem.AffectedSourceID()
+	if err != nil {
+		logger.Warn().Err(err).Msg("Could not get source id from sources event message")
+		metrics.IncSourcesEvent(sem.EventType.String(), "error")
+		return
+	}
+
+	logger = ptr.To(logger.With().Str("source_id", sourceId).Str("event_type", sem.EventType.String()).Logger())
+	ctx := logger.WithContext(msgCtx)
+	logger.Trace().Msgf("Processing sources event %s for source %s", sem.EventType, sourceId)
+
+	switch sem.EventType {
+	case kafka.SourceDestroyEvent:
+		invalidateSourceCache(ctx, sourceId)
+		err = cleanupSource(ctx, sourceId)
+	case kafka.ApplicationDestroyEvent, kafka.AuthenticationDestroyEvent:
+		invalidateSourceCache(ctx, sourceId)
+		if provisioningAuthenticationExists(ctx, sourceId) {
+			logger.Debug().Msg("Source still has provisioning authentication, skipping cleanup")
+			break
+		}
+		err = cleanupSource(ctx, sourceId)
+	case kafka.AuthenticationCreateEvent, kafka.AuthenticationUpdateEvent, kafka.ApplicationUpdateEvent:
+		invalidateSourceCache(ctx, sourceId)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to clean up data of a deleted source")
+		metrics.IncSourcesEvent(sem.EventType.String(), "error")
+		return
+	}
+	metrics.IncSourcesEvent(sem.EventType.String(), "processed")
+}
+
+// provisioningAuthenticationExists returns false only when Sources confirm the source does not
+// have provisioning authentication anymore. Other events (e.g. other application deleted) or
+// errors when contacting Sources must not lead to data removal.
+func provisioningAuthenticationExists(ctx context.Context, sourceId string) bool {
+	logger := zerolog.Ctx(ctx)
+
+	sourcesClient, err := clients.GetSourcesClient(ctx)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Could not get sources client")
+		return true
+	}
+
+	_, err = sourcesClient.GetAuthentication(ctx, sourceId)
+	if err == nil {
+		return true
+	}
+
+	if errors.Is(err, clients.ErrNotFound) ||
+		errors.Is(err, httpClients.ErrApplicationRead) ||
+		errors.Is(err, httpClients.ErrAuthenticationForSourcesNotFound) {
+		return false
+	}
+
+	logger.Warn().Err(err).Msg("Could not verify source authentication, skipping cleanup")
+	return true
+}
+
+// cleanupSource removes pubkey resources of a source and finishes its pending reservations.
+// Uploaded keys cannot be removed from the cloud because the authentication is no longer available.
+func cleanupSource(ctx context.Context, sourceId string) error {
+	logger := zerolog.Ctx(ctx)
+
+	deleted, err := dao.GetPubkeyDao(ctx).UnscopedDeleteResourcesBySourceId(ctx, sourceId)
+	if err != nil {
+		return fmt.Errorf("cannot delete pubkey resources: %w", err)
+	}
+
+	failed, err := dao.GetReservationDao(ctx).UnscopedFailPendingBySourceId(ctx, sourceId, sourceDeletedReservationError)
+	if err != nil {
+		return fmt.Errorf("cannot finish pending reservations: %w", err)
+	}
+
+	logger.Info().Int64("pubkey_resources", deleted).Int64("reservations", failed).
+		Msgf("Deleted %d pubkey resource(s) and failed %d pending reservation(s) of source %s", deleted, failed, sourceId)
+	return nil
+}
+
+// invalidateSourceCache drops all application cache items keyed by a source ID.
+func invalidateSourceCache(ctx context.Context, sourceId string) {
+	logger := zerolog.Ctx(ctx)
+
+	if err := cache.Invalidate(ctx, sourceId, &clients.AccountDetailsAWS{}); err != nil {
+		logger.Warn().Err(err).Msg("Unable to invalidate AWS account details in cache")
+	}
+
+	var tenantId clients.AzureTenantId
+	if err := cache.Invalidate(ctx, sourceId, &tenantId); err != nil {
+		logger.Warn().Err(err).Msg("Unable to invalidate Azure tenant id in cache")
+	}
+
+	if err := cache.Invalidate(ctx, sourceId, &clients.SourceRegions{}); err != nil {
+		logger.Warn().Err(err).Msg("Unable to invalidate source regions in cache")
+	}
+
+	// fixed credentials must not be reported with the last availability status
+	if err := cache.Invalidate(ctx, sourceId, &kafka.CachedSourceResult{}); err != nil {
+		logger.Warn().Err(err).Msg("Unable to invalidate source availability status in cache")
+	}
+}
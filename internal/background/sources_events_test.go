@@ -0,0 +1,136 @@
+package background
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/cache"
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/clients/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/config"
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	daoStubs "github.com/RHEnVision/provisioning-backend/internal/dao/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/kafka"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/testing/identity"
+	"github.com/stretchr/testify/require"
+)
+
+func prepareSourcesEventContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx := daoStubs.WithAccountDaoOne(context.Background())
+	ctx = identity.WithTenant(t, ctx)
+	ctx = stubs.WithSourcesClient(ctx)
+	ctx = daoStubs.WithPubkeyDao(ctx)
+	ctx = daoStubs.WithReservationDao(ctx)
+
+	pkDao := dao.GetPubkeyDao(ctx)
+	for _, sourceId := range []string{"2", "2", "3"} {
+		err := pkDao.UnscopedCreateResource(ctx, &models.PubkeyResource{
+			PubkeyID: 1,
+			Provider: models.ProviderTypeAWS,
+			SourceID: sourceId,
+			Handle:   "handle",
+			Region:   "us-east-1",
+		})
+		require.NoError(t, err)
+	}
+
+	err := dao.GetReservationDao(ctx).CreateAWS(ctx, &models.AWSReservation{
+		Reservation: models.Reservation{AccountID: 1},
+		SourceID:    "2",
+	})
+	require.NoError(t, err)
+
+	return ctx
+}
+
+func withMemoryCache(t *testing.T) {
+	t.Helper()
+	cacheType, cacheSize := config.Application.Cache.Type, config.Application.Cache.Memory.Size
+	config.Application.Cache.Type = "memory"
+	config.Application.Cache.Memory.Size = 100
+	cache.Initialize()
+	t.Cleanup(func() {
+		config.Application.Cache.Type, config.Application.Cache.Memory.Size = cacheType, cacheSize
+		cache.Initialize()
+	})
+}
+
+func sourcesEvent(eventType, value string) *kafka.GenericMessage {
+	return &kafka.GenericMessage{
+		Value:   []byte(value),
+		Headers: kafka.GenericHeaders("event_type", eventType),
+	}
+}
+
+func TestSourceDestroyCleanup(t *testing.T) {
+	ctx := prepareSourcesEventContext(t)
+
+	processSourcesEvent(ctx, sourcesEvent("Source.destroy", `{"id":"2"}`))
+
+	resources, err := dao.GetPubkeyDao(ctx).UnscopedListResourcesByPubkeyId(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	require.Equal(t, "3", resources[0].SourceID)
+
+	reservation, err := dao.GetReservationDao(ctx).GetAWSById(ctx, 1)
+	require.NoError(t, err)
+	require.True(t, reservation.Success.Valid)
+	require.False(t, reservation.Success.Bool)
+	require.Equal(t, sourceDeletedReservationError, reservation.Error)
+}
+
+func TestApplicationDestroyWithAuthenticationSkipsCleanup(t *testing.T) {
+	ctx := prepareSourcesEventContext(t)
+	source, err := stubs.AddSource(ctx, models.ProviderTypeAWS)
+	require.NoError(t, err)
+	require.Equal(t, "2", source.ID)
+
+	processSourcesEvent(ctx, sourcesEvent("Application.destroy", `{"id":"10","source_id":"2"}`))
+
+	resources, err := dao.GetPubkeyDao(ctx).UnscopedListResourcesByPubkeyId(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, resources, 3)
+}
+
+func TestAuthenticationUpdateKeepsData(t *testing.T) {
+	ctx := prepareSourcesEventContext(t)
+
+	processSourcesEvent(ctx, sourcesEvent("Authentication.update", `{"id":"5","source_id":"2"}`))
+
+	resources, err := dao.GetPubkeyDao(ctx).UnscopedListResourcesByPubkeyId(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, resources, 3)
+
+	reservation, err := dao.GetReservationDao(ctx).GetAWSById(ctx, 1)
+	require.NoError(t, err)
+	require.False(t, reservation.Success.Valid)
+}
+
+func TestAuthenticationUpdateInvalidatesCache(t *testing.T) {
+	ctx := prepareSourcesEventContext(t)
+	withMemoryCache(t)
+
+	regions := clients.SourceRegions{{Name: "us-east-1", Enabled: true}}
+	require.NoError(t, cache.SetExpires(ctx, "2", &regions, time.Hour))
+	require.NoError(t, cache.Find(ctx, "2", &clients.SourceRegions{}))
+
+	processSourcesEvent(ctx, sourcesEvent("Authentication.update", `{"id":"5","source_id":"2"}`))
+
+	require.ErrorIs(t, cache.Find(ctx, "2", &clients.SourceRegions{}), cache.ErrNotFound)
+}
+
+func TestApplicationUpdateInvalidatesStatus(t *testing.T) {
+	ctx := prepareSourcesEventContext(t)
+	withMemoryCache(t)
+
+	status := kafka.CachedSourceResult{ResourceID: "10", ResourceType: "Application", Status: kafka.StatusUnavailable}
+	require.NoError(t, cache.SetExpires(ctx, "2", &status, time.Hour))
+	require.NoError(t, cache.Find(ctx, "2", &kafka.CachedSourceResult{}))
+
+	processSourcesEvent(ctx, sourcesEvent("Application.update", `{"id":"10","source_id":"2"}`))
+
+	require.ErrorIs(t, cache.Find(ctx, "2", &kafka.CachedSourceResult{}), cache.ErrNotFound)
+}
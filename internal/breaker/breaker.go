@@ -0,0 +1,242 @@
+// Package breaker implements circuit breakers which stop calls to a degraded backend service
+// and let a single probe through after a timeout.
+package breaker
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/metrics"
+)
+
+// Open timeout stops doubling after this many failed probes, so it never overflows.
+const maxDoublings = 16
+
+// State of a circuit breaker, the numeric value is exported as a metric.
+type State int
+
+const (
+	// Closed breaker allows all calls.
+	Closed State = iota
+
+	// HalfOpen breaker allows a single probe, its outcome closes or opens the breaker.
+	HalfOpen
+
+	// Open breaker rejects all calls until the open timeout passes.
+	Open
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case HalfOpen:
+		return "half-open"
+	case Open:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// Settings of a circuit breaker.
+type Settings struct {
+	// Number of last outcomes used to calculate the failure rate.
+	Window int
+
+	// Minimum number of outcomes in the window before the breaker can open.
+	MinRequests int
+
+	// Failure rate (0.0-1.0) which opens the breaker, zero disables the breaker.
+	FailureRate float64
+
+	// Duration of the first open state, it doubles every time the probe fails.
+	OpenTimeout time.Duration
+
+	// Maximum duration of the open state.
+	MaxOpenTimeout time.Duration
+
+	// Maximum delay returned by Backoff.
+	MaxBackoff time.Duration
+}
+
+// Breaker is a circuit breaker safe for concurrent use. Outcomes are reported via Success and
+// Failure after every call which was allowed.
+type Breaker struct {
+	name     string
+	settings Settings
+	now      func() time.Time
+
+	mu        sync.Mutex
+	state     State
+	outcomes  []bool
+	next      int
+	failures  int
+	trips     int
+	openUntil time.Time
+	probing   bool
+}
+
+// New creates a closed circuit breaker, the name is used as a metric label.
+func New(name string, settings Settings) *Breaker {
+	if settings.Window < 1 {
+		settings.Window = 1
+	}
+	b := &Breaker{
+		name:     name,
+		settings: settings,
+		now:      time.Now,
+		outcomes: make([]bool, 0, settings.Window),
+	}
+	metrics.SetCircuitBreakerState(name, int(Closed))
+	return b
+}
+
+// Name returns the breaker name.
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// State returns the current state, open breaker with passed timeout is reported as half-open.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == Open && !b.now().Before(b.openUntil) {
+		return HalfOpen
+	}
+	return b.state
+}
+
+// Allow returns false when the call must be skipped. When it returns true, the outcome of the call
+// must be reported.
+func (b *Breaker) Allow() bool {
+	if b.settings.FailureRate <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Closed:
+		return true
+	case Open:
+		if b.now().Before(b.openUntil) {
+			return false
+		}
+		b.setState(HalfOpen)
+		b.probing = true
+		return true
+	case HalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// Success reports a successful call.
+func (b *Breaker) Success() {
+	b.record(true)
+}
+
+// Failure reports a call which failed because the backend service is degraded.
+func (b *Breaker) Failure() {
+	b.record(false)
+}
+
+func (b *Breaker) record(success bool) {
+	if b.settings.FailureRate <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case HalfOpen:
+		b.probing = false
+		if success {
+			b.trips = 0
+			b.reset()
+			b.setState(Closed)
+		} else {
+			b.trip()
+		}
+	case Closed:
+		b.push(success)
+		if len(b.outcomes) >= b.settings.MinRequests && b.failureRate() >= b.settings.FailureRate {
+			b.trip()
+		}
+	case Open:
+		// late outcome of a call allowed before the breaker opened
+	}
+}
+
+// Backoff returns the delay between calls increased exponentially with the failure rate: the delay
+// is returned as is when all calls succeed and MaxBackoff when all calls fail.
+func (b *Breaker) Backoff(delay time.Duration) time.Duration {
+	if b.settings.FailureRate <= 0 || delay <= 0 || b.settings.MaxBackoff <= delay {
+		return delay
+	}
+
+	b.mu.Lock()
+	rate := b.failureRate()
+	b.mu.Unlock()
+
+	factor := math.Pow(float64(b.settings.MaxBackoff)/float64(delay), rate)
+	return time.Duration(float64(delay) * factor)
+}
+
+func (b *Breaker) trip() {
+	b.trips++
+	timeout := b.settings.MaxOpenTimeout
+	if b.trips <= maxDoublings {
+		if t := b.settings.OpenTimeout << (b.trips - 1); t < timeout {
+			timeout = t
+		}
+	}
+	b.openUntil = b.now().Add(timeout)
+	b.setState(Open)
+}
+
+func (b *Breaker) setState(state State) {
+	if b.state == state {
+		return
+	}
+	b.state = state
+	metrics.SetCircuitBreakerState(b.name, int(state))
+	metrics.IncCircuitBreakerTransition(b.name, state.String())
+}
+
+func (b *Breaker) push(success bool) {
+	if len(b.outcomes) < cap(b.outcomes) {
+		b.outcomes = append(b.outcomes, success)
+	} else {
+		if !b.outcomes[b.next] {
+			b.failures--
+		}
+		b.outcomes[b.next] = success
+		b.next = (b.next + 1) % len(b.outcomes)
+	}
+	if !success {
+		b.failures++
+	}
+}
+
+func (b *Breaker) reset() {
+	b.outcomes = b.outcomes[:0]
+	b.next = 0
+	b.failures = 0
+}
+
+func (b *Breaker) failureRate() float64 {
+	if len(b.outcomes) == 0 {
+		return 0
+	}
+	return float64(b.failures) / float64(len(b.outcomes))
+}
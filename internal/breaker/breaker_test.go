@@ -0,0 +1,108 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSettings = Settings{
+	Window:         4,
+	MinRequests:    4,
+	FailureRate:    0.5,
+	OpenTimeout:    time.Minute,
+	MaxOpenTimeout: 3 * time.Minute,
+	MaxBackoff:     16 * time.Second,
+}
+
+func newTestBreaker(now *time.Time) *Breaker {
+	b := New("test", testSettings)
+	b.now = func() time.Time {
+		return *now
+	}
+	return b
+}
+
+func TestBreakerStates(t *testing.T) {
+	now := time.Now()
+	b := newTestBreaker(&now)
+
+	b.Success()
+	b.Success()
+	b.Failure()
+	require.Equal(t, Closed, b.State(), "opened before minimum requests")
+	b.Failure()
+	require.Equal(t, Open, b.State())
+	assert.False(t, b.Allow())
+
+	now = now.Add(time.Minute)
+	require.True(t, b.Allow(), "probe was not allowed")
+	assert.False(t, b.Allow(), "second probe was allowed")
+	b.Failure()
+	require.Equal(t, Open, b.State())
+
+	now = now.Add(time.Minute)
+	assert.False(t, b.Allow(), "open timeout was not doubled")
+	now = now.Add(time.Minute)
+	require.True(t, b.Allow())
+	b.Success()
+	require.Equal(t, Closed, b.State())
+	assert.True(t, b.Allow())
+
+	// the window was reset when the breaker closed
+	b.Failure()
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestBreakerWindow(t *testing.T) {
+	now := time.Now()
+	b := newTestBreaker(&now)
+
+	b.Failure()
+	for i := 0; i < 4; i++ {
+		b.Success()
+	}
+	b.Failure()
+	assert.Equal(t, Closed, b.State(), "failure outside of the window was counted")
+	b.Failure()
+	assert.Equal(t, Open, b.State())
+}
+
+func TestBreakerMaxOpenTimeout(t *testing.T) {
+	now := time.Now()
+	b := newTestBreaker(&now)
+
+	for i := 0; i < 4; i++ {
+		b.Failure()
+	}
+	for i := 0; i < 20; i++ {
+		now = now.Add(testSettings.MaxOpenTimeout)
+		require.True(t, b.Allow(), "probe %d was not allowed", i)
+		b.Failure()
+	}
+}
+
+func TestBreakerBackoff(t *testing.T) {
+	now := time.Now()
+	b := newTestBreaker(&now)
+
+	assert.Equal(t, time.Second, b.Backoff(time.Second))
+	b.Success()
+	b.Failure()
+	assert.Equal(t, 4*time.Second, b.Backoff(time.Second))
+	b.Failure()
+	b.Failure()
+	assert.Equal(t, 8*time.Second, b.Backoff(time.Second))
+	assert.Equal(t, 60*time.Second, b.Backoff(time.Minute))
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	b := New("disabled", Settings{})
+	for i := 0; i < 10; i++ {
+		b.Failure()
+	}
+	assert.True(t, b.Allow())
+	assert.Equal(t, time.Second, b.Backoff(time.Second))
+}
@@ -0,0 +1,165 @@
+// Package cache provides application cache based on Redis or in-memory store. This feature
+// can be turned off via configuration and in that case function Find return ErrNotFound and
+// functions Set do nothing.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/config"
+	"github.com/RHEnVision/provisioning-backend/internal/metrics"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/telemetry"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrNotFound = errors.New("not found in cache")
+	ErrNilValue = errors.New("value is nil")
+
+	// the backend, nil when cache is disabled
+	store backend
+)
+
+// Forever is used for items that should be cached "forever". Expiration of 30 days
+// is used to allow cleanup of unused items.
+const Forever time.Duration = 24 * time.Hour * 30
+
+type Cacheable interface {
+	CacheKeyName() string
+}
+
+// backend stores serialized items. Implementations must return ErrNotFound on cache miss.
+type backend interface {
+	get(ctx context.Context, key string) ([]byte, error)
+	set(ctx context.Context, key string, value []byte, expiration time.Duration) error
+	del(ctx context.Context, key string) error
+	delPrefix(ctx context.Context, prefix string) error
+}
+
+// Initialize creates new cache backend if allowed by application config, or does nothing.
+func Initialize() {
+	// register all Cacheable types
+	gob.Register(&models.Account{})
+	gob.Register(&clients.AccountDetailsAWS{})
+	gob.Register(&clients.AccessList{})
+
+	publisher = nil
+	switch config.Application.Cache.Type {
+	case "redis":
+		log.Logger.Info().Bool("cache", true).Msg("Initializing redis application cache")
+		store = newRedisBackend()
+	case "memory":
+		log.Logger.Info().Bool("cache", true).Msgf("Initializing in-memory application cache with size %d",
+			config.Application.Cache.Memory.Size)
+		store = newMemoryBackend(config.Application.Cache.Memory.Size, config.Application.Cache.Memory.CleanupInterval)
+
+		if config.Application.Cache.Memory.PubSub {
+			log.Logger.Info().Bool("cache", true).Msg("Broadcasting cache invalidations via redis pub/sub")
+			publisher = newRedisClient()
+			go subscribeInvalidations(context.Background(), publisher)
+		}
+	default:
+		log.Logger.Info().Bool("cache", true).Msg("No application cache in use")
+		store = nil
+	}
+}
+
+// Find returns an item from cache. ErrNotFound is returned on cache miss or when
+// the item cannot be deserialized
+func Find(ctx context.Context, key string, value Cacheable) error {
+	if store == nil {
+		return ErrNotFound
+	}
+
+	if value == nil {
+		return ErrNilValue
+	}
+
+	return find(ctx, value.CacheKeyName(), value.CacheKeyName()+key, value)
+}
+
+// SetExpires calls Set with specific expiration.
+func SetExpires(ctx context.Context, key string, value Cacheable, expiration time.Duration) error {
+	if store == nil {
+		return nil
+	}
+
+	if value == nil {
+		return ErrNilValue
+	}
+
+	return set(ctx, value.CacheKeyName(), value.CacheKeyName()+key, value, expiration)
+}
+
+// find loads and decodes an item with full key, metric label is the type prefix.
+func find(ctx context.Context, prefix, fullKey string, value any) error {
+	ctx, span := telemetry.StartSpan(ctx, "Find")
+	defer span.End()
+
+	buf, err := store.get(ctx, fullKey)
+	if errors.Is(err, ErrNotFound) {
+		metrics.IncCacheHit(prefix, "miss")
+		return ErrNotFound
+	} else if err != nil {
+		metrics.IncCacheHit(prefix, "err")
+		return err //nolint:wrapcheck
+	}
+
+	dec := gob.NewDecoder(bytes.NewReader(buf))
+
+	err = dec.Decode(value)
+	if err != nil {
+		// decode error can be thrown if previous cache entry was JSON-encoded, return not found to overwrite it
+		zerolog.Ctx(ctx).Warn().Err(err).Bool("cache", true).Msgf("Cache decode error: %s", err.Error())
+		metrics.IncCacheHit(prefix, "err")
+		return ErrNotFound
+	}
+
+	metrics.IncCacheHit(prefix, "hit")
+	zerolog.Ctx(ctx).Trace().Bool("cache", true).Msgf("Cache hit for key '%s' type %T", fullKey, value)
+	return nil
+}
+
+// set encodes and stores an item with full key, metric label is the type prefix.
+func set(ctx context.Context, prefix, fullKey string, value any, expiration time.Duration) error {
+	ctx, span := telemetry.StartSpan(ctx, "Set")
+	defer span.End()
+
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+
+	err := enc.Encode(value)
+	if err != nil {
+		metrics.IncCacheHit(prefix, "err")
+		return fmt.Errorf("unable to encode for cache: %w", err)
+	}
+
+	err = store.set(ctx, fullKey, buf.Bytes(), expiration)
+	if err != nil {
+		metrics.IncCacheHit(prefix, "err")
+		return err //nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// SetForever calls Set with Forever expiration duration.
+// nolint: wrapcheck
+func SetForever(ctx context.Context, key string, value Cacheable) error {
+	return SetExpires(ctx, key, value, Forever)
+}
+
+// Set creates or updates existing cache entry. It uses the default expiration duration
+// specified in the application configuration.
+// nolint: wrapcheck
+func Set(ctx context.Context, key string, value Cacheable) error {
+	return SetExpires(ctx, key, value, config.Application.Cache.Expiration)
+}
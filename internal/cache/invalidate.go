@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"context"
+	"strings"
+
+	"github.com/RHEnVision/provisioning-backend/internal/metrics"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// invalidationChannel is the Redis pub/sub channel used to broadcast invalidations to other
+// replicas with in-memory cache
+const invalidationChannel = "provisioning-cache-invalidation"
+
+// message payload prefixes
+const (
+	invalidateKeyMsg    = "key:"
+	invalidatePrefixMsg = "prefix:"
+)
+
+// publisher is set when invalidations are broadcast, nil otherwise
+var publisher *redis.Client
+
+// Invalidate removes an item from cache. When in-memory cache with pub/sub is configured, the
+// invalidation is broadcast to all replicas. Invalidating a key which is not present is not an error.
+func Invalidate(ctx context.Context, key string, value Cacheable) error {
+	if store == nil {
+		return nil
+	}
+
+	if value == nil {
+		return ErrNilValue
+	}
+
+	return invalidate(ctx, value.CacheKeyName(), invalidateKeyMsg+value.CacheKeyName()+key)
+}
+
+// InvalidatePrefix removes all items of the value type which keys start with keyPrefix. Empty
+// keyPrefix removes all items of the type. See Invalidate for more details.
+func InvalidatePrefix(ctx context.Context, keyPrefix string, value Cacheable) error {
+	if store == nil {
+		return nil
+	}
+
+	if value == nil {
+		return ErrNilValue
+	}
+
+	return invalidate(ctx, value.CacheKeyName(), invalidatePrefixMsg+value.CacheKeyName()+keyPrefix)
+}
+
+func invalidate(ctx context.Context, prefix, msg string) error {
+	err := applyInvalidation(ctx, msg)
+	if err != nil {
+		metrics.IncCacheHit(prefix, "err")
+		return err
+	}
+
+	if publisher != nil {
+		cmd := publisher.Publish(ctx, invalidationChannel, msg)
+		if cmd.Err() != nil {
+			metrics.IncCacheHit(prefix, "err")
+			zerolog.Ctx(ctx).Warn().Err(cmd.Err()).Bool("cache", true).Msg("Unable to broadcast cache invalidation")
+		}
+	}
+
+	return nil
+}
+
+// applyInvalidation removes item(s) from the local store according to the message payload.
+func applyInvalidation(ctx context.Context, msg string) error {
+	if key, ok := strings.CutPrefix(msg, invalidateKeyMsg); ok {
+		return store.del(ctx, key) //nolint:wrapcheck
+	}
+
+	if keyPrefix, ok := strings.CutPrefix(msg, invalidatePrefixMsg); ok {
+		return store.delPrefix(ctx, keyPrefix) //nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// subscribeInvalidations applies invalidations broadcast by other replicas, blocking call.
+func subscribeInvalidations(ctx context.Context, client *redis.Client) {
+	logger := log.Logger.With().Bool("cache", true).Logger()
+	ctx = logger.WithContext(ctx)
+
+	sub := client.Subscribe(ctx, invalidationChannel)
+	defer sub.Close()
+
+	for msg := range sub.Channel() {
+		if err := applyInvalidation(ctx, msg.Payload); err != nil {
+			logger.Warn().Err(err).Msgf("Unable to apply cache invalidation %s", msg.Payload)
+		}
+	}
+}
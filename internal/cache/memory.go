@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryBackend is an in-process cache with size bound and least-recently-used eviction.
+// Expired items are removed lazily on access and periodically by a cleanup goroutine.
+type memoryBackend struct {
+	mu      sync.Mutex
+	size    int
+	items   map[string]*list.Element
+	lru     *list.List
+	nowFunc func() time.Time
+}
+
+type memoryItem struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+func (i *memoryItem) expired(now time.Time) bool {
+	return !i.expires.IsZero() && now.After(i.expires)
+}
+
+func newMemoryBackend(size int, cleanupInterval time.Duration) *memoryBackend {
+	if size < 1 {
+		size = 1
+	}
+
+	b := &memoryBackend{
+		size:    size,
+		items:   make(map[string]*list.Element, size),
+		lru:     list.New(),
+		nowFunc: time.Now,
+	}
+
+	if cleanupInterval > 0 {
+		go b.cleanupLoop(cleanupInterval)
+	}
+
+	return b
+}
+
+func (b *memoryBackend) get(_ context.Context, key string) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	elem, ok := b.items[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	item := itemOf(elem)
+	if item.expired(b.nowFunc()) {
+		b.removeElement(elem)
+		return nil, ErrNotFound
+	}
+
+	b.lru.MoveToFront(elem)
+	return item.value, nil
+}
+
+func (b *memoryBackend) set(_ context.Context, key string, value []byte, expiration time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// zero expiration means the item does not expire, same as in Redis
+	var expires time.Time
+	if expiration > 0 {
+		expires = b.nowFunc().Add(expiration)
+	}
+	if elem, ok := b.items[key]; ok {
+		item := itemOf(elem)
+		item.value = value
+		item.expires = expires
+		b.lru.MoveToFront(elem)
+		return nil
+	}
+
+	b.items[key] = b.lru.PushFront(&memoryItem{key: key, value: value, expires: expires})
+	for b.lru.Len() > b.size {
+		b.removeElement(b.lru.Back())
+	}
+
+	return nil
+}
+
+func (b *memoryBackend) del(_ context.Context, key string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if elem, ok := b.items[key]; ok {
+		b.removeElement(elem)
+	}
+
+	return nil
+}
+
+func (b *memoryBackend) delPrefix(_ context.Context, prefix string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for key, elem := range b.items {
+		if strings.HasPrefix(key, prefix) {
+			b.removeElement(elem)
+		}
+	}
+
+	return nil
+}
+
+// cleanup removes all expired items.
+func (b *memoryBackend) cleanup() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.nowFunc()
+	for elem := b.lru.Back(); elem != nil; {
+		prev := elem.Prev()
+		if itemOf(elem).expired(now) {
+			b.removeElement(elem)
+		}
+		elem = prev
+	}
+}
+
+func (b *memoryBackend) cleanupLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		b.cleanup()
+	}
+}
+
+func itemOf(elem *list.Element) *memoryItem {
+	item, _ := elem.Value.(*memoryItem)
+	return item
+}
+
+// removeElement must be called with the lock held.
+func (b *memoryBackend) removeElement(elem *list.Element) {
+	b.lru.Remove(elem)
+	delete(b.items, itemOf(elem).key)
+}
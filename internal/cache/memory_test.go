@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMemoryBackend(size int) (*memoryBackend, *time.Time) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newMemoryBackend(size, 0)
+	b.nowFunc = func() time.Time { return now }
+	return b, &now
+}
+
+func TestMemorySetGet(t *testing.T) {
+	ctx := context.Background()
+	b, _ := newTestMemoryBackend(10)
+
+	err := b.set(ctx, "a", []byte("1"), time.Minute)
+	require.NoError(t, err)
+
+	value, err := b.get(ctx, "a")
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), value)
+
+	_, err = b.get(ctx, "b")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMemoryExpiration(t *testing.T) {
+	ctx := context.Background()
+	b, now := newTestMemoryBackend(10)
+
+	require.NoError(t, b.set(ctx, "a", []byte("1"), time.Minute))
+	require.NoError(t, b.set(ctx, "b", []byte("2"), time.Hour))
+
+	*now = now.Add(2 * time.Minute)
+	_, err := b.get(ctx, "a")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	*now = now.Add(2 * time.Hour)
+	b.cleanup()
+	require.Empty(t, b.items)
+	require.Zero(t, b.lru.Len())
+}
+
+func TestMemoryEviction(t *testing.T) {
+	ctx := context.Background()
+	b, _ := newTestMemoryBackend(2)
+
+	require.NoError(t, b.set(ctx, "a", []byte("1"), time.Minute))
+	require.NoError(t, b.set(ctx, "b", []byte("2"), time.Minute))
+
+	// "a" becomes most recently used, "b" is evicted
+	_, err := b.get(ctx, "a")
+	require.NoError(t, err)
+	require.NoError(t, b.set(ctx, "c", []byte("3"), time.Minute))
+
+	_, err = b.get(ctx, "b")
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = b.get(ctx, "a")
+	require.NoError(t, err)
+	_, err = b.get(ctx, "c")
+	require.NoError(t, err)
+}
+
+func TestMemoryDelete(t *testing.T) {
+	ctx := context.Background()
+	b, _ := newTestMemoryBackend(10)
+
+	require.NoError(t, b.set(ctx, "a", []byte("1"), time.Minute))
+	require.NoError(t, b.del(ctx, "a"))
+	require.NoError(t, b.del(ctx, "missing"))
+
+	_, err := b.get(ctx, "a")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMemoryFindSet(t *testing.T) {
+	ctx := context.Background()
+	store = newMemoryBackend(10, 0)
+	defer func() { store = nil }()
+
+	value := models.Account{ID: 42, OrgID: "442"}
+	require.NoError(t, Set(ctx, "42", &value))
+
+	result := models.Account{}
+	require.NoError(t, Find(ctx, "42", &result))
+	require.Equal(t, value, result)
+
+	// must not share the stored value
+	result.OrgID = "1"
+	require.NoError(t, Find(ctx, "42", &result))
+	require.Equal(t, "442", result.OrgID)
+
+	require.NoError(t, Invalidate(ctx, "42", &value))
+	require.ErrorIs(t, Find(ctx, "42", &result), ErrNotFound)
+}
@@ -58,3 +58,33 @@ func TestPrefix(t *testing.T) {
 
 	require.Equal(t, value1, result)
 }
+
+func TestInvalidatePrefix(t *testing.T) {
+	for _, key := range []string{"p1-a", "p1-b", "p2-a"} {
+		err := cache.Set(context.Background(), key, &models.Account{OrgID: key})
+		require.NoError(t, err)
+	}
+
+	err := cache.InvalidatePrefix(context.Background(), "p1-", &models.Account{})
+	require.NoError(t, err)
+
+	result := models.Account{}
+	require.ErrorIs(t, cache.Find(context.Background(), "p1-a", &result), cache.ErrNotFound)
+	require.ErrorIs(t, cache.Find(context.Background(), "p1-b", &result), cache.ErrNotFound)
+	require.NoError(t, cache.Find(context.Background(), "p2-a", &result))
+}
+
+func TestGetOrLoad(t *testing.T) {
+	loader := func(ctx context.Context) (models.Account, error) {
+		return models.Account{ID: 7, OrgID: "7"}, nil
+	}
+
+	value, err := cache.GetOrLoad(context.Background(), "7", cache.LoadOptions{}, loader)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), value.ID)
+
+	result := models.Account{}
+	err = cache.Find(context.Background(), "7", &result)
+	require.NoError(t, err)
+	require.Equal(t, value, result)
+}
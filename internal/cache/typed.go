@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/config"
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/singleflight"
+)
+
+// defaultLoadTimeout limits loads without LoadOptions.Timeout
+const defaultLoadTimeout = 30 * time.Second
+
+// loads in flight, keyed by full cache key
+var group singleflight.Group
+
+// Loader fetches an item on cache miss.
+type Loader[T Cacheable] func(ctx context.Context) (T, error)
+
+// LoadOptions configure GetOrLoad, zero value is a valid configuration.
+type LoadOptions struct {
+	// Expiration of loaded items, the application cache expiration is used when zero.
+	Expiration time.Duration
+
+	// Timeout of the loader, 30 seconds when zero. The load is shared by all waiting callers, so
+	// it is not cancelled together with the context of the caller which started it.
+	Timeout time.Duration
+}
+
+func (o LoadOptions) timeout() time.Duration {
+	if o.Timeout == 0 {
+		return defaultLoadTimeout
+	}
+	return o.Timeout
+}
+
+// GetOrLoad returns an item from cache, or calls the loader on cache miss and stores the result.
+// Concurrent misses of the same key are collapsed into a single loader call, even when the cache is
+// turned off, and all waiting callers receive the same value which must be treated as read-only.
+// The loader runs with the values of the first caller's context but not its cancellation, callers
+// stop waiting when their own context is done. T must be a value type (e.g. models.Account)
+// because its zero value is used to get the cache key prefix.
+func GetOrLoad[T Cacheable](ctx context.Context, key string, opts LoadOptions, loader Loader[T]) (T, error) {
+	var zero T
+	prefix := zero.CacheKeyName()
+	fullKey := prefix + key
+
+	if store != nil {
+		var result T
+		err := find(ctx, prefix, fullKey, &result)
+		if err == nil {
+			return result, nil
+		} else if !errors.Is(err, ErrNotFound) {
+			zerolog.Ctx(ctx).Warn().Err(err).Bool("cache", true).Msgf("Cache find error for key '%s', loading", fullKey)
+		}
+	}
+
+	ch := group.DoChan(fullKey, func() (any, error) {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), opts.timeout())
+		defer cancel()
+		return load(loadCtx, prefix, fullKey, opts, loader)
+	})
+
+	select {
+	case <-ctx.Done():
+		return zero, fmt.Errorf("load of key '%s' was not awaited: %w", fullKey, context.Cause(ctx))
+	case res := <-ch:
+		if res.Shared {
+			zerolog.Ctx(ctx).Trace().Bool("cache", true).Msgf("Shared load result for key '%s'", fullKey)
+		}
+		if res.Err != nil {
+			return zero, res.Err //nolint:wrapcheck
+		}
+
+		result, _ := res.Val.(T)
+		return result, nil
+	}
+}
+
+func load[T Cacheable](ctx context.Context, prefix, fullKey string, opts LoadOptions, loader Loader[T]) (T, error) {
+	var zero T
+	logger := zerolog.Ctx(ctx)
+
+	result, err := loader(ctx)
+	if err != nil {
+		return zero, err
+	}
+
+	if store != nil {
+		expiration := opts.Expiration
+		if expiration == 0 {
+			expiration = config.Application.Cache.Expiration
+		}
+
+		err = set(ctx, prefix, fullKey, result, expiration)
+		if err != nil {
+			// loaded value is still valid
+			logger.Warn().Err(err).Bool("cache", true).Msgf("Unable to store key '%s' to cache", fullKey)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func withMemoryStore(t *testing.T) {
+	t.Helper()
+	store = newMemoryBackend(100, 0)
+	t.Cleanup(func() { store = nil })
+}
+
+func TestGetOrLoadCaches(t *testing.T) {
+	ctx := context.Background()
+	withMemoryStore(t)
+	var calls int32
+
+	loader := func(ctx context.Context) (models.Account, error) {
+		atomic.AddInt32(&calls, 1)
+		return models.Account{ID: 1, OrgID: "1"}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		result, err := GetOrLoad(ctx, "1", LoadOptions{Expiration: time.Minute}, loader)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), result.ID)
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	require.NoError(t, Invalidate(ctx, "1", &models.Account{}))
+	_, err := GetOrLoad(ctx, "1", LoadOptions{Expiration: time.Minute}, loader)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestGetOrLoadCoalesces(t *testing.T) {
+	ctx := context.Background()
+	var calls int32
+	release := make(chan struct{})
+
+	// cache is turned off, concurrent calls must be still collapsed
+	loader := func(ctx context.Context) (models.Account, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return models.Account{ID: 2}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := GetOrLoad(ctx, "2", LoadOptions{}, loader)
+			require.NoError(t, err)
+			require.Equal(t, int64(2), result.ID)
+		}()
+	}
+
+	// give goroutines a chance to join the flight
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestGetOrLoadCallerCancelled(t *testing.T) {
+	withMemoryStore(t)
+	opts := LoadOptions{Expiration: time.Minute, Timeout: time.Minute}
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+
+	loader := func(ctx context.Context) (models.Account, error) {
+		_, hasDeadline := ctx.Deadline()
+		require.True(t, hasDeadline, "loader must run with its own timeout")
+		started <- struct{}{}
+		<-release
+		return models.Account{ID: 3}, ctx.Err()
+	}
+
+	// the first caller gives up, the shared load continues for the other caller
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := GetOrLoad(ctx, "3", opts, loader)
+		firstErr <- err
+	}()
+	<-started
+
+	secondResult := make(chan models.Account, 1)
+	go func() {
+		result, err := GetOrLoad(context.Background(), "3", opts, loader)
+		require.NoError(t, err)
+		secondResult <- result
+	}()
+
+	cancel()
+	require.ErrorIs(t, <-firstErr, context.Canceled)
+	close(release)
+	require.Equal(t, int64(3), (<-secondResult).ID)
+}
+
+func TestInvalidatePrefix(t *testing.T) {
+	ctx := context.Background()
+	withMemoryStore(t)
+
+	for _, key := range []string{"org1-a", "org1-b", "org2-a"} {
+		require.NoError(t, SetExpires(ctx, key, &models.Account{OrgID: key}, time.Minute))
+	}
+
+	require.NoError(t, InvalidatePrefix(ctx, "org1-", &models.Account{}))
+
+	result := models.Account{}
+	require.ErrorIs(t, Find(ctx, "org1-a", &result), ErrNotFound)
+	require.ErrorIs(t, Find(ctx, "org1-b", &result), ErrNotFound)
+	require.NoError(t, Find(ctx, "org2-a", &result))
+}
+
+func TestApplyInvalidationMessage(t *testing.T) {
+	ctx := context.Background()
+	withMemoryStore(t)
+
+	require.NoError(t, SetExpires(ctx, "5", &models.Account{ID: 5}, time.Minute))
+	require.NoError(t, applyInvalidation(ctx, invalidateKeyMsg+"account5"))
+
+	result := models.Account{}
+	require.ErrorIs(t, Find(ctx, "5", &result), ErrNotFound)
+
+	// unknown messages are ignored
+	require.NoError(t, applyInvalidation(ctx, "unknown"))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
@@ -11,6 +12,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
 	"github.com/RHEnVision/provisioning-backend/internal/clients"
 	"github.com/RHEnVision/provisioning-backend/internal/config"
+	"github.com/RHEnVision/provisioning-backend/internal/ptr"
 	"github.com/RHEnVision/provisioning-backend/internal/telemetry"
 )
 
@@ -52,6 +54,22 @@ func (c *client) newImagesClient(ctx context.Context) (*armcompute.ImagesClient,
 	return vmClient, nil
 }
 
+func (c *client) newGalleriesClient(ctx context.Context) (*armcompute.GalleriesClient, error) {
+	galleriesClient, err := armcompute.NewGalleriesClient(c.subscriptionID, c.credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Gallery Azure client: %w", err)
+	}
+	return galleriesClient, nil
+}
+
+func (c *client) newGalleryImagesClient(ctx context.Context) (*armcompute.GalleryImagesClient, error) {
+	imagesClient, err := armcompute.NewGalleryImagesClient(c.subscriptionID, c.credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Gallery Image Azure client: %w", err)
+	}
+	return imagesClient, nil
+}
+
 func (c *client) newVirtualMachinesClient(ctx context.Context) (*armcompute.VirtualMachinesClient, error) {
 	vmClient, err := armcompute.NewVirtualMachinesClient(c.subscriptionID, c.credential, nil)
 	if err != nil {
@@ -155,6 +173,32 @@ func (c *client) ListResourceGroups(ctx context.Context) ([]string, error) {
 	return list, nil
 }
 
+func (c *client) ListVMsByTag(ctx context.Context, key, value string) ([]clients.InstanceDescription, error) {
+	ctx, span := telemetry.StartSpan(ctx, "ListVMsByTag")
+	defer span.End()
+
+	var list []clients.InstanceDescription
+	vmClient, err := c.newVirtualMachinesClient(ctx)
+	if err != nil {
+		return list, err
+	}
+
+	pager := vmClient.NewListAllPager(nil)
+	for pager.More() {
+		page, pagerErr := pager.NextPage(ctx)
+		if pagerErr != nil {
+			return list, fmt.Errorf("failed to fetch virtual machines: %w", pagerErr)
+		}
+		for _, vm := range page.Value {
+			if tag, ok := vm.Tags[key]; ok && ptr.FromOrEmpty(tag) == value {
+				list = append(list, clients.InstanceDescription{ID: ptr.FromOrEmpty(vm.ID)})
+			}
+		}
+	}
+
+	return list, nil
+}
+
 func (c *client) TenantId(ctx context.Context) (clients.AzureTenantId, error) {
 	ctx, span := telemetry.StartSpan(ctx, "TenantId")
 	defer span.End()
@@ -170,3 +214,101 @@ func (c *client) TenantId(ctx context.Context) (clients.AzureTenantId, error) {
 
 	return clients.AzureTenantId(*response.TenantID), nil
 }
+
+func (c *client) ListRegionDetails(ctx context.Context) ([]clients.RegionDetail, error) {
+	ctx, span := telemetry.StartSpan(ctx, "ListRegionDetails")
+	defer span.End()
+
+	subClient, err := c.newSubscriptionsClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []clients.RegionDetail
+	pager := subClient.NewListLocationsPager(c.subscriptionID, nil)
+	for pager.More() {
+		page, pagerErr := pager.NextPage(ctx)
+		if pagerErr != nil {
+			return nil, fmt.Errorf("failed to fetch locations: %w", pagerErr)
+		}
+		for _, location := range page.Value {
+			// logical locations (e.g. "europe") cannot be used for resources
+			if location.Name == nil || location.Metadata == nil ||
+				ptr.From(location.Metadata.RegionType) != armsubscriptions.RegionTypePhysical {
+				continue
+			}
+
+			zones := make([]clients.Zone, 0, len(location.AvailabilityZoneMappings))
+			for _, mapping := range location.AvailabilityZoneMappings {
+				if mapping.LogicalZone != nil {
+					zones = append(zones, clients.Zone(*location.Name+"_"+*mapping.LogicalZone))
+				}
+			}
+			result = append(result, clients.RegionDetail{
+				Name:    clients.Region(*location.Name),
+				Enabled: true,
+				Zones:   zones,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+func (c *client) ListImages(ctx context.Context) ([]*clients.Image, error) {
+	ctx, span := telemetry.StartSpan(ctx, "ListImages")
+	defer span.End()
+
+	galleriesClient, err := c.newGalleriesClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	imagesClient, err := c.newGalleryImagesClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*clients.Image
+	pager := galleriesClient.NewListPager(nil)
+	for pager.More() {
+		page, pagerErr := pager.NextPage(ctx)
+		if pagerErr != nil {
+			return nil, fmt.Errorf("failed to fetch galleries: %w", pagerErr)
+		}
+		for _, gallery := range page.Value {
+			if gallery.ID == nil || gallery.Name == nil {
+				continue
+			}
+			resourceID, parseErr := arm.ParseResourceID(*gallery.ID)
+			if parseErr != nil {
+				return nil, fmt.Errorf("unable to parse gallery ID: %w", parseErr)
+			}
+
+			imagesPager := imagesClient.NewListByGalleryPager(resourceID.ResourceGroupName, *gallery.Name, nil)
+			for imagesPager.More() {
+				imagesPage, imagesErr := imagesPager.NextPage(ctx)
+				if imagesErr != nil {
+					return nil, fmt.Errorf("failed to fetch gallery images: %w", imagesErr)
+				}
+				for _, image := range imagesPage.Value {
+					if image.ID == nil {
+						continue
+					}
+					// definitions without architecture are x64
+					arch := clients.ArchitectureTypeX86_64
+					if image.Properties != nil && ptr.From(image.Properties.Architecture) == armcompute.ArchitectureArm64 {
+						arch = clients.ArchitectureTypeArm64
+					}
+					result = append(result, &clients.Image{
+						ID:           *image.ID,
+						Name:         *gallery.Name + "/" + ptr.From(image.Name),
+						Origin:       clients.ImageOriginAccount,
+						Architecture: arch,
+					})
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
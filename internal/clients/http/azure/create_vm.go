@@ -18,6 +18,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
 	"github.com/RHEnVision/provisioning-backend/internal/ptr"
 	"github.com/RHEnVision/provisioning-backend/internal/telemetry"
 	"go.opentelemetry.io/otel/codes"
@@ -420,6 +421,53 @@ func (c *client) createNetworkInterface(ctx context.Context, location string, re
 	return &resp.Interface, nil
 }
 
+// osDisk returns OS disk created from the image, root volume overrides size and type.
+func osDisk(root *models.RootVolume) *armcompute.OSDisk {
+	disk := &armcompute.OSDisk{
+		// Name:         ptr.To(vmName + "_disk1"),
+		CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesFromImage),
+		Caching:      to.Ptr(armcompute.CachingTypesReadWrite),
+		ManagedDisk: &armcompute.ManagedDiskParameters{
+			StorageAccountType: to.Ptr(armcompute.StorageAccountTypesStandardLRS), // OSDisk type Standard/Premium HDD/SSD
+		},
+		// DiskSizeGB: to.Ptr[int32](100), // default 127G
+	}
+	if root != nil {
+		if root.SizeGiB != 0 {
+			disk.DiskSizeGB = to.Ptr(root.SizeGiB)
+		}
+		if root.Type != "" {
+			disk.ManagedDisk.StorageAccountType = to.Ptr(armcompute.StorageAccountTypes(root.Type))
+		}
+	}
+	return disk
+}
+
+// dataDisks returns empty managed data disks attached as LUN 0, 1 and so on.
+func dataDisks(volumes []models.Volume) []*armcompute.DataDisk {
+	if len(volumes) == 0 {
+		return nil
+	}
+
+	disks := make([]*armcompute.DataDisk, len(volumes))
+	for i, volume := range volumes {
+		deleteOption := armcompute.DiskDeleteOptionTypesDetach
+		if volume.DeleteOnTermination {
+			deleteOption = armcompute.DiskDeleteOptionTypesDelete
+		}
+		disks[i] = &armcompute.DataDisk{
+			Lun:          to.Ptr(int32(i)),
+			CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesEmpty),
+			DiskSizeGB:   to.Ptr(volume.SizeGiB),
+			DeleteOption: to.Ptr(deleteOption),
+			ManagedDisk: &armcompute.ManagedDiskParameters{
+				StorageAccountType: to.Ptr(armcompute.StorageAccountTypes(volume.Type)),
+			},
+		}
+	}
+	return disks
+}
+
 func (c *client) prepareVirtualMachineParameters(vmParams clients.AzureInstanceParams, networkInterface *armnetwork.Interface, vmName string) *armcompute.VirtualMachine {
 	userDataEncoded := make([]byte, base64.StdEncoding.EncodedLen(len(vmParams.UserData)))
 	base64.StdEncoding.Encode(userDataEncoded, vmParams.UserData)
@@ -435,15 +483,8 @@ func (c *client) prepareVirtualMachineParameters(vmParams clients.AzureInstanceP
 				ImageReference: &armcompute.ImageReference{
 					ID: ptr.To(vmParams.ImageID),
 				},
-				OSDisk: &armcompute.OSDisk{
-					// Name:         ptr.To(vmName + "_disk1"),
-					CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesFromImage),
-					Caching:      to.Ptr(armcompute.CachingTypesReadWrite),
-					ManagedDisk: &armcompute.ManagedDiskParameters{
-						StorageAccountType: to.Ptr(armcompute.StorageAccountTypesStandardLRS), // OSDisk type Standard/Premium HDD/SSD
-					},
-					// DiskSizeGB: to.Ptr[int32](100), // default 127G
-				},
+				OSDisk:    osDisk(vmParams.RootVolume),
+				DataDisks: dataDisks(vmParams.Volumes),
 			},
 			HardwareProfile: &armcompute.HardwareProfile{
 				VMSize: to.Ptr(armcompute.VirtualMachineSizeTypes(vmParams.InstanceType)), // VM size include vCPUs,RAM,Data Disks,Temp storage.
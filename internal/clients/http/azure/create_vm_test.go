@@ -0,0 +1,35 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOSDisk(t *testing.T) {
+	disk := osDisk(nil)
+	assert.Nil(t, disk.DiskSizeGB)
+	assert.Equal(t, armcompute.StorageAccountTypesStandardLRS, *disk.ManagedDisk.StorageAccountType)
+
+	disk = osDisk(&models.RootVolume{SizeGiB: 256, Type: "Premium_LRS"})
+	assert.Equal(t, int32(256), *disk.DiskSizeGB)
+	assert.Equal(t, armcompute.StorageAccountTypesPremiumLRS, *disk.ManagedDisk.StorageAccountType)
+}
+
+func TestDataDisks(t *testing.T) {
+	assert.Nil(t, dataDisks(nil))
+
+	disks := dataDisks([]models.Volume{
+		{SizeGiB: 100, Type: "Premium_LRS", DeleteOnTermination: true},
+		{SizeGiB: 200, Type: "Standard_LRS"},
+	})
+	require.Len(t, disks, 2)
+	assert.Equal(t, int32(0), *disks[0].Lun)
+	assert.Equal(t, armcompute.DiskDeleteOptionTypesDelete, *disks[0].DeleteOption)
+	assert.Equal(t, int32(1), *disks[1].Lun)
+	assert.Equal(t, armcompute.DiskDeleteOptionTypesDetach, *disks[1].DeleteOption)
+	assert.Equal(t, armcompute.DiskCreateOptionTypesEmpty, *disks[1].CreateOption)
+}
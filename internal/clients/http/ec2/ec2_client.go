@@ -25,6 +25,7 @@ import (
 	stsTypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
 	"github.com/rs/zerolog"
 	"go.opentelemetry.io/otel/codes"
+	"golang.org/x/exp/slices"
 )
 
 type ec2Client struct {
@@ -285,6 +286,103 @@ func (c *ec2Client) ListAllZones(ctx context.Context, region clients.Region) ([]
 	return result, nil
 }
 
+func (c *ec2Client) ListRegionDetails(ctx context.Context) ([]clients.RegionDetail, error) {
+	ctx, span := telemetry.StartSpan(ctx, "ListRegionDetails")
+	defer span.End()
+
+	input := &ec2.DescribeRegionsInput{
+		AllRegions: ptr.To(true),
+	}
+
+	output, err := c.ec2.DescribeRegions(ctx, input)
+	if err != nil {
+		if isAWSUnauthorizedError(err) {
+			err = clients.ErrUnauthorized
+		}
+		return nil, fmt.Errorf("cannot list regions: %w", err)
+	}
+
+	result := make([]clients.RegionDetail, 0, len(output.Regions))
+	for _, region := range output.Regions {
+		result = append(result, clients.RegionDetail{
+			Name: clients.Region(*region.RegionName),
+			// "opt-in-not-required", "opted-in" or "not-opted-in"
+			Enabled: ptr.From(region.OptInStatus) != "not-opted-in",
+		})
+	}
+
+	return result, nil
+}
+
+func (c *ec2Client) ListImages(ctx context.Context) ([]*clients.Image, error) {
+	ctx, span := telemetry.StartSpan(ctx, "ListImages")
+	defer span.End()
+
+	owned, err := c.describeImages(ctx, &ec2.DescribeImagesInput{Owners: []string{"self"}}, clients.ImageOriginAccount)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	// images with explicit launch permission for the account, owned images are not included
+	shared, err := c.describeImages(ctx, &ec2.DescribeImagesInput{ExecutableUsers: []string{"self"}}, clients.ImageOriginShared)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(owned))
+	for _, image := range owned {
+		seen[image.ID] = struct{}{}
+	}
+	for _, image := range shared {
+		if _, ok := seen[image.ID]; !ok {
+			owned = append(owned, image)
+		}
+	}
+
+	return owned, nil
+}
+
+func (c *ec2Client) describeImages(ctx context.Context, input *ec2.DescribeImagesInput, origin clients.ImageOrigin) ([]*clients.Image, error) {
+	logger := logger(ctx)
+	region := c.ec2.Options().Region
+
+	input.Filters = []types.Filter{{Name: ptr.To("state"), Values: []string{string(types.ImageStateAvailable)}}}
+	input.MaxResults = ptr.ToInt32(1000)
+	pag := ec2.NewDescribeImagesPaginator(c.ec2, input)
+
+	result := make([]*clients.Image, 0, 32)
+	for pag.HasMorePages() {
+		resp, err := pag.NextPage(ctx)
+		if err != nil {
+			if isAWSUnauthorizedError(err) {
+				err = clients.ErrUnauthorized
+			}
+			return nil, fmt.Errorf("cannot list images: %w", err)
+		}
+		for _, awsImage := range resp.Images {
+			arch, archErr := clients.MapArchitectures(ctx, string(awsImage.Architecture))
+			if archErr != nil {
+				logger.Warn().Err(archErr).Msgf("Unknown architecture of image %s", ptr.From(awsImage.ImageId))
+			}
+			name := ptr.From(awsImage.Name)
+			if name == "" {
+				name = ptr.From(awsImage.ImageId)
+			}
+			result = append(result, &clients.Image{
+				ID:           ptr.From(awsImage.ImageId),
+				Name:         name,
+				Origin:       origin,
+				Architecture: arch,
+				Region:       region,
+			})
+		}
+	}
+
+	return result, nil
+}
+
 func (c *ec2Client) ListInstanceTypes(ctx context.Context) ([]*clients.InstanceType, error) {
 	ctx, span := telemetry.StartSpan(ctx, "ListInstanceTypes")
 	defer span.End()
@@ -338,6 +436,49 @@ func (c *ec2Client) DescribeInstanceDetails(ctx context.Context, InstanceIds []s
 	return instanceDetailList, nil
 }
 
+func (c *ec2Client) ListInstancesByTag(ctx context.Context, key, value string) ([]*clients.InstanceDescription, error) {
+	ctx, span := telemetry.StartSpan(ctx, "ListInstancesByTag")
+	defer span.End()
+
+	input := &ec2.DescribeInstancesInput{
+		Filters: []types.Filter{
+			{
+				Name:   ptr.To("tag:" + key),
+				Values: []string{value},
+			},
+			{
+				Name:   ptr.To("instance-state-name"),
+				Values: []string{"pending", "running", "stopping", "stopped"},
+			},
+		},
+	}
+
+	var list []*clients.InstanceDescription
+	paginator := ec2.NewDescribeInstancesPaginator(c.ec2, input)
+	for paginator.HasMorePages() {
+		resp, err := paginator.NextPage(ctx)
+		if err != nil {
+			if isAWSUnauthorizedError(err) {
+				err = clients.ErrUnauthorized
+			}
+			span.SetStatus(codes.Error, err.Error())
+			return nil, fmt.Errorf("cannot list instances by tag: %w", err)
+		}
+		for _, reservation := range resp.Reservations {
+			for _, instance := range reservation.Instances {
+				list = append(list, &clients.InstanceDescription{
+					ID:          *instance.InstanceId,
+					IPv4:        ptr.FromOrEmpty(instance.PublicIpAddress),
+					DNS:         ptr.FromOrEmpty(instance.PublicDnsName),
+					PrivateIPv4: ptr.FromOrEmpty(instance.PrivateIpAddress),
+					PrivateIPv6: ptr.FromOrEmpty(instance.Ipv6Address),
+				})
+			}
+		}
+	}
+	return list, nil
+}
+
 func (c *ec2Client) ListLaunchTemplates(ctx context.Context) ([]*clients.LaunchTemplate, string, error) {
 	ctx, span := telemetry.StartSpan(ctx, "ListLaunchTemplates")
 	defer span.End()
@@ -398,6 +539,22 @@ func (c *ec2Client) RunInstances(ctx context.Context, params *clients.AWSInstanc
 		KeyName:        &params.KeyName,
 		UserData:       &encodedUserData,
 	}
+	if params.ClientToken != "" {
+		input.ClientToken = ptr.To(params.ClientToken)
+	}
+
+	if params.RootVolume != nil || len(params.Volumes) > 0 {
+		var rootDevice string
+		if params.RootVolume != nil {
+			var err error
+			rootDevice, err = c.rootDeviceName(ctx, params.AMI)
+			if err != nil {
+				span.SetStatus(codes.Error, err.Error())
+				return nil, nil, err
+			}
+		}
+		input.BlockDeviceMappings = blockDeviceMappings(rootDevice, params.RootVolume, params.Volumes)
+	}
 
 	input.TagSpecifications = []types.TagSpecification{
 		{
@@ -423,6 +580,17 @@ func (c *ec2Client) RunInstances(ctx context.Context, params *clients.AWSInstanc
 		input.TagSpecifications[0].Tags = append(input.TagSpecifications[0].Tags, t)
 	}
 
+	// user tags must not override the tags above
+	for key, value := range params.Tags {
+		if slices.IndexFunc(input.TagSpecifications[0].Tags, func(t types.Tag) bool { return *t.Key == key }) >= 0 {
+			continue
+		}
+		input.TagSpecifications[0].Tags = append(input.TagSpecifications[0].Tags, types.Tag{
+			Key:   ptr.To(key),
+			Value: ptr.To(value),
+		})
+	}
+
 	resp, err := c.ec2.RunInstances(ctx, input)
 	if err != nil {
 		if isAWSUnauthorizedError(err) {
@@ -0,0 +1,61 @@
+package ec2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/ptr"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// rootDeviceName returns device name of the root volume of an image, it is needed to override
+// the root volume.
+func (c *ec2Client) rootDeviceName(ctx context.Context, ami string) (string, error) {
+	resp, err := c.ec2.DescribeImages(ctx, &ec2.DescribeImagesInput{ImageIds: []string{ami}})
+	if err != nil {
+		if isAWSUnauthorizedError(err) {
+			err = clients.ErrUnauthorized
+		}
+		return "", fmt.Errorf("cannot describe image %s: %w", ami, err)
+	}
+	if len(resp.Images) == 0 || resp.Images[0].RootDeviceName == nil {
+		return "", fmt.Errorf("image %s root device: %w", ami, clients.ErrNoResponseData)
+	}
+	return *resp.Images[0].RootDeviceName, nil
+}
+
+// blockDeviceMappings returns EBS mappings of root and data volumes. Data volumes are attached
+// as /dev/sdf to /dev/sdp as recommended for EBS volumes.
+func blockDeviceMappings(rootDevice string, root *models.RootVolume, volumes []models.Volume) []types.BlockDeviceMapping {
+	result := make([]types.BlockDeviceMapping, 0, len(volumes)+1)
+	if root != nil {
+		ebs := &types.EbsBlockDevice{}
+		if root.SizeGiB != 0 {
+			ebs.VolumeSize = ptr.To(root.SizeGiB)
+		}
+		if root.Type != "" {
+			ebs.VolumeType = types.VolumeType(root.Type)
+		}
+		result = append(result, types.BlockDeviceMapping{
+			DeviceName: ptr.To(rootDevice),
+			Ebs:        ebs,
+		})
+	}
+
+	for i, volume := range volumes {
+		result = append(result, types.BlockDeviceMapping{
+			DeviceName: ptr.To(fmt.Sprintf("/dev/sd%c", 'f'+i)),
+			Ebs: &types.EbsBlockDevice{
+				VolumeSize:          ptr.To(volume.SizeGiB),
+				VolumeType:          types.VolumeType(volume.Type),
+				Encrypted:           ptr.To(volume.Encrypted),
+				DeleteOnTermination: ptr.To(volume.DeleteOnTermination),
+			},
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,41 @@
+package ec2
+
+import (
+	"testing"
+
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockDeviceMappings(t *testing.T) {
+	t.Run("root and data volumes", func(t *testing.T) {
+		root := &models.RootVolume{SizeGiB: 50}
+		volumes := []models.Volume{
+			{SizeGiB: 100, Type: "gp3", Encrypted: true, DeleteOnTermination: true},
+			{SizeGiB: 500, Type: "st1"},
+		}
+
+		mappings := blockDeviceMappings("/dev/xvda", root, volumes)
+		require.Len(t, mappings, 3)
+
+		assert.Equal(t, "/dev/xvda", *mappings[0].DeviceName)
+		assert.Equal(t, int32(50), *mappings[0].Ebs.VolumeSize)
+		assert.Empty(t, mappings[0].Ebs.VolumeType, "root type is kept from the image")
+
+		assert.Equal(t, "/dev/sdf", *mappings[1].DeviceName)
+		assert.Equal(t, types.VolumeTypeGp3, mappings[1].Ebs.VolumeType)
+		assert.True(t, *mappings[1].Ebs.Encrypted)
+		assert.True(t, *mappings[1].Ebs.DeleteOnTermination)
+
+		assert.Equal(t, "/dev/sdg", *mappings[2].DeviceName)
+		assert.False(t, *mappings[2].Ebs.DeleteOnTermination)
+	})
+
+	t.Run("data volumes only", func(t *testing.T) {
+		mappings := blockDeviceMappings("", nil, []models.Volume{{SizeGiB: 10, Type: "gp2"}})
+		require.Len(t, mappings, 1)
+		assert.Equal(t, "/dev/sdf", *mappings[0].DeviceName)
+	})
+}
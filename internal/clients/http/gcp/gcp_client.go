@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path"
 	"strconv"
+	"strings"
 
 	"github.com/RHEnVision/provisioning-backend/internal/identity"
 	"github.com/RHEnVision/provisioning-backend/internal/telemetry"
@@ -83,6 +85,45 @@ func (c *gcpClient) ListAllRegions(ctx context.Context) ([]clients.Region, error
 	return regions, nil
 }
 
+func (c *gcpClient) ListRegionDetails(ctx context.Context) ([]clients.RegionDetail, error) {
+	ctx, span := telemetry.StartSpan(ctx, "ListRegionDetails")
+	defer span.End()
+
+	client, err := compute.NewRegionsRESTClient(ctx, c.options...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create GCP regions client: %w", err)
+	}
+	defer client.Close()
+
+	req := &computepb.ListRegionsRequest{
+		Project: c.auth.Payload,
+	}
+	iter := client.List(ctx, req)
+	regions := make([]clients.RegionDetail, 0, 32)
+	for {
+		region, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, fmt.Errorf("iterator error: %w", err)
+		}
+
+		// zones are returned as URLs ending with the zone name
+		zones := make([]clients.Zone, 0, len(region.GetZones()))
+		for _, zoneURL := range region.GetZones() {
+			zones = append(zones, clients.Zone(path.Base(zoneURL)))
+		}
+		regions = append(regions, clients.RegionDetail{
+			Name:    clients.Region(region.GetName()),
+			Enabled: region.GetStatus() == computepb.Region_UP.String(),
+			Zones:   zones,
+		})
+	}
+	return regions, nil
+}
+
 func (c *gcpClient) newInstancesClient(ctx context.Context) (*compute.InstancesClient, error) {
 	client, err := compute.NewInstancesRESTClient(ctx, c.options...)
 	if err != nil {
@@ -137,6 +178,53 @@ func (c *gcpClient) ListLaunchTemplates(ctx context.Context) ([]*clients.LaunchT
 	return templatesList, nextToken, nil
 }
 
+func (c *gcpClient) ListImages(ctx context.Context) ([]*clients.Image, error) {
+	ctx, span := telemetry.StartSpan(ctx, "ListImages")
+	defer span.End()
+	logger := logger(ctx)
+
+	client, err := compute.NewImagesRESTClient(ctx, c.options...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create GCP images client: %w", err)
+	}
+	defer client.Close()
+
+	req := &computepb.ListImagesRequest{
+		Project: c.auth.Payload,
+	}
+	iter := client.List(ctx, req)
+	images := make([]*clients.Image, 0, 32)
+	for {
+		image, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, fmt.Errorf("iterator error: %w", err)
+		}
+		if image.GetStatus() != computepb.Image_READY.String() {
+			continue
+		}
+
+		var arch clients.ArchitectureType
+		if image.GetArchitecture() != computepb.Image_ARCHITECTURE_UNSPECIFIED.String() {
+			// architecture is in upper case ("X86_64" or "ARM64")
+			arch, err = clients.MapArchitectures(ctx, strings.ToLower(image.GetArchitecture()))
+			if err != nil {
+				logger.Warn().Err(err).Msgf("Unknown architecture of image %s", image.GetName())
+			}
+		}
+		images = append(images, &clients.Image{
+			ID:           fmt.Sprintf("projects/%s/global/images/%s", c.auth.Payload, image.GetName()),
+			Name:         image.GetName(),
+			Origin:       clients.ImageOriginAccount,
+			Architecture: arch,
+		})
+	}
+	return images, nil
+}
+
 func (c *gcpClient) InsertInstances(ctx context.Context, params *clients.GCPInstanceParams, amount int64) ([]*string, *string, error) {
 	ctx, span := telemetry.StartSpan(ctx, "InsertInstances")
 	defer span.End()
@@ -174,6 +262,18 @@ func (c *gcpClient) InsertInstances(ctx context.Context, params *clients.GCPInst
 		})
 	}
 
+	labels := map[string]string{
+		"rh-rid":  config.EnvironmentPrefix("r", strconv.FormatInt(params.ReservationID, 10)),
+		"rh-uuid": params.UUID,
+		"rh-org":  identity.Identity(ctx).Identity.OrgID,
+	}
+	// user labels must not override the labels above
+	for key, value := range params.Labels {
+		if _, ok := labels[key]; !ok {
+			labels[key] = value
+		}
+	}
+
 	req := &computepb.BulkInsertInstanceRequest{
 		Project: c.auth.Payload,
 		Zone:    params.Zone,
@@ -182,11 +282,7 @@ func (c *gcpClient) InsertInstances(ctx context.Context, params *clients.GCPInst
 			Count:       &amount,
 			MinCount:    &amount,
 			InstanceProperties: &computepb.InstanceProperties{
-				Labels: map[string]string{
-					"rh-rid":  config.EnvironmentPrefix("r", strconv.FormatInt(params.ReservationID, 10)),
-					"rh-uuid": params.UUID,
-					"rh-org":  identity.Identity(ctx).Identity.OrgID,
-				},
+				Labels: labels,
 				NetworkInterfaces: []*computepb.NetworkInterface{
 					{
 						AccessConfigs: []*computepb.AccessConfig{
@@ -215,16 +311,7 @@ func (c *gcpClient) InsertInstances(ctx context.Context, params *clients.GCPInst
 	}
 
 	if params.ImageName != "" {
-		req.BulkInsertInstanceResourceResource.InstanceProperties.Disks = []*computepb.AttachedDisk{
-			{
-				InitializeParams: &computepb.AttachedDiskInitializeParams{
-					SourceImage: &params.ImageName,
-				},
-				AutoDelete: ptr.To(true),
-				Boot:       ptr.To(true),
-				Type:       ptr.To(computepb.AttachedDisk_PERSISTENT.String()),
-			},
-		}
+		req.BulkInsertInstanceResourceResource.InstanceProperties.Disks = attachedDisks(params.ImageName, params.RootVolume, params.Volumes)
 	}
 
 	op, err := client.BulkInsert(ctx, req)
@@ -250,6 +337,41 @@ func (c *gcpClient) InsertInstances(ctx context.Context, params *clients.GCPInst
 	return ids, ptr.To(op.Name()), nil
 }
 
+// attachedDisks returns boot disk created from the image followed by empty data disks.
+func attachedDisks(imageName string, root *models.RootVolume, volumes []models.Volume) []*computepb.AttachedDisk {
+	boot := &computepb.AttachedDisk{
+		InitializeParams: &computepb.AttachedDiskInitializeParams{
+			SourceImage: ptr.To(imageName),
+		},
+		AutoDelete: ptr.To(true),
+		Boot:       ptr.To(true),
+		Type:       ptr.To(computepb.AttachedDisk_PERSISTENT.String()),
+	}
+	if root != nil {
+		if root.SizeGiB != 0 {
+			boot.InitializeParams.DiskSizeGb = ptr.To(int64(root.SizeGiB))
+		}
+		if root.Type != "" {
+			boot.InitializeParams.DiskType = ptr.To(root.Type)
+		}
+	}
+
+	disks := make([]*computepb.AttachedDisk, 0, len(volumes)+1)
+	disks = append(disks, boot)
+	for _, volume := range volumes {
+		disks = append(disks, &computepb.AttachedDisk{
+			InitializeParams: &computepb.AttachedDiskInitializeParams{
+				DiskSizeGb: ptr.To(int64(volume.SizeGiB)),
+				DiskType:   ptr.To(volume.Type),
+			},
+			AutoDelete: ptr.To(volume.DeleteOnTermination),
+			Boot:       ptr.To(false),
+			Type:       ptr.To(computepb.AttachedDisk_PERSISTENT.String()),
+		})
+	}
+	return disks
+}
+
 func (c *gcpClient) ListInstancesIDsByLabel(ctx context.Context, uuid string) ([]*string, error) {
 	ctx, span := telemetry.StartSpan(ctx, "ListInstancesIDsByLabel")
 	defer span.End()
@@ -0,0 +1,33 @@
+package gcp
+
+import (
+	"testing"
+
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachedDisks(t *testing.T) {
+	t.Run("boot disk only", func(t *testing.T) {
+		disks := attachedDisks("projects/p/global/images/i", nil, nil)
+		require.Len(t, disks, 1)
+		assert.True(t, disks[0].GetBoot())
+		assert.Nil(t, disks[0].InitializeParams.DiskSizeGb)
+	})
+
+	t.Run("root override and data disks", func(t *testing.T) {
+		root := &models.RootVolume{SizeGiB: 40, Type: "pd-ssd"}
+		volumes := []models.Volume{{SizeGiB: 200, Type: "pd-balanced", DeleteOnTermination: false}}
+
+		disks := attachedDisks("projects/p/global/images/i", root, volumes)
+		require.Len(t, disks, 2)
+		assert.Equal(t, int64(40), disks[0].InitializeParams.GetDiskSizeGb())
+		assert.Equal(t, "pd-ssd", disks[0].InitializeParams.GetDiskType())
+
+		assert.False(t, disks[1].GetBoot())
+		assert.False(t, disks[1].GetAutoDelete())
+		assert.Equal(t, int64(200), disks[1].InitializeParams.GetDiskSizeGb())
+		assert.Empty(t, disks[1].InitializeParams.GetSourceImage())
+	})
+}
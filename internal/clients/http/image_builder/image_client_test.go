@@ -8,9 +8,12 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
 	httpClients "github.com/RHEnVision/provisioning-backend/internal/clients/http"
 	"github.com/RHEnVision/provisioning-backend/internal/clients/http/image_builder"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
 	"github.com/RHEnVision/provisioning-backend/internal/preload"
+	"github.com/RHEnVision/provisioning-backend/internal/ptr"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -89,3 +92,158 @@ func Test_GetAWSAmi(t *testing.T) {
 		assert.Equal(t, "ami-1234-test", ami)
 	})
 }
+
+func listImagesServer(t *testing.T, awsComposeID, gcpComposeID, cloneID uuid.UUID) *httptest.Server {
+	t.Helper()
+
+	composeRequest := func(uploadType image_builder.UploadTypes) image_builder.ComposeRequest {
+		return image_builder.ComposeRequest{
+			Distribution: image_builder.Rhel9,
+			ImageRequests: []image_builder.ImageRequest{{
+				Architecture:  image_builder.ImageRequestArchitectureAarch64,
+				UploadRequest: image_builder.UploadRequest{Type: uploadType},
+			}},
+		}
+	}
+	composes := image_builder.ComposesResponse{
+		Data: []image_builder.ComposesResponseItem{
+			{Id: awsComposeID, ImageName: ptr.To("rhel-9-arm"), Request: composeRequest(image_builder.UploadTypesAws)},
+			{Id: gcpComposeID, Request: composeRequest(image_builder.UploadTypesGcp)},
+			// compose without status fails and is skipped
+			{Id: uuid.New(), ImageName: ptr.To("rhel-9-failing"), Request: composeRequest(image_builder.UploadTypesAws)},
+		},
+	}
+
+	awsStatus := func(ami, region string) image_builder.UploadStatus {
+		options := image_builder.UploadStatus_Options{}
+		err := options.FromAWSUploadStatus(image_builder.AWSUploadStatus{Ami: ami, Region: region})
+		require.NoError(t, err)
+		return image_builder.UploadStatus{
+			Status:  image_builder.UploadStatusStatusSuccess,
+			Type:    image_builder.UploadTypesAws,
+			Options: options,
+		}
+	}
+	composeUploadStatus := awsStatus("ami-1234-test", "us-east-1")
+	composeStatus := image_builder.ComposeStatus{
+		ImageStatus: image_builder.ImageStatus{
+			Status:       image_builder.ImageStatusStatusSuccess,
+			UploadStatus: &composeUploadStatus,
+		},
+		Request: composeRequest(image_builder.UploadTypesAws),
+	}
+	clones := image_builder.ClonesResponse{
+		Data: []image_builder.ClonesResponseItem{{Id: cloneID}},
+	}
+
+	responses := map[string]interface{}{
+		"/composes":                                      composes,
+		"/composes/" + awsComposeID.String():             composeStatus,
+		"/composes/" + awsComposeID.String() + "/clones": clones,
+		"/clones/" + cloneID.String():                    awsStatus("ami-5678-test", "eu-west-1"),
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		responseString, marshalErr := json.Marshal(response)
+		require.NoError(t, marshalErr)
+		_, err := io.WriteString(w, string(responseString))
+		require.NoError(t, err, "failed to write http body for stubbed server")
+	}))
+}
+
+func Test_ListImages(t *testing.T) {
+	awsComposeID := uuid.New()
+	cloneID := uuid.New()
+	ts := listImagesServer(t, awsComposeID, uuid.New(), cloneID)
+	defer ts.Close()
+
+	ctx := context.Background()
+	client, err := image_builder.NewImageBuilderClientWithUrl(ctx, ts.URL)
+	require.NoError(t, err, "failed to initialize image builder client with test server")
+
+	images, err := client.ListImages(ctx, models.ProviderTypeAWS)
+	require.NoError(t, err)
+	require.Len(t, images, 2, "GCP and failing composes are not listed")
+
+	assert.Equal(t, awsComposeID.String(), images[0].ID)
+	assert.Equal(t, "rhel-9-arm", images[0].Name)
+	assert.Equal(t, "us-east-1", images[0].Region)
+	assert.Equal(t, "ami-1234-test", images[0].ProviderID)
+	assert.Equal(t, clients.ArchitectureTypeArm64, images[0].Architecture)
+
+	assert.Equal(t, cloneID.String(), images[1].ID)
+	assert.Equal(t, "eu-west-1", images[1].Region)
+	assert.Equal(t, "ami-5678-test", images[1].ProviderID)
+	assert.Equal(t, clients.ArchitectureTypeArm64, images[1].Architecture)
+}
+
+func Test_ListImagesPages(t *testing.T) {
+	composeID := uuid.New()
+	request := func(uploadType image_builder.UploadTypes) image_builder.ComposeRequest {
+		return image_builder.ComposeRequest{
+			Distribution: image_builder.Rhel9,
+			ImageRequests: []image_builder.ImageRequest{{
+				Architecture:  image_builder.ImageRequestArchitectureX8664,
+				UploadRequest: image_builder.UploadRequest{Type: uploadType},
+			}},
+		}
+	}
+
+	// the first page is full of Azure composes, the GCP compose is on the second page
+	firstPage := image_builder.ComposesResponse{}
+	for i := 0; i < 100; i++ {
+		firstPage.Data = append(firstPage.Data, image_builder.ComposesResponseItem{Id: uuid.New(), Request: request(image_builder.UploadTypesAzure)})
+	}
+	firstPage.Meta.Count = 101
+	secondPage := image_builder.ComposesResponse{
+		Data: []image_builder.ComposesResponseItem{{Id: composeID, ImageName: ptr.To("rhel-9-gcp"), Request: request(image_builder.UploadTypesGcp)}},
+	}
+	secondPage.Meta.Count = 101
+	composeStatus := image_builder.ComposeStatus{
+		ImageStatus: image_builder.ImageStatus{
+			Status:       image_builder.ImageStatusStatusSuccess,
+			UploadStatus: &image_builder.UploadStatus{Status: image_builder.UploadStatusStatusSuccess, Type: image_builder.UploadTypesGcp},
+		},
+	}
+
+	var offsets []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response interface{}
+		switch r.URL.Path {
+		case "/composes":
+			offset := r.URL.Query().Get("offset")
+			offsets = append(offsets, offset)
+			response = firstPage
+			if offset != "0" {
+				response = secondPage
+			}
+		case "/composes/" + composeID.String():
+			response = composeStatus
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		err := json.NewEncoder(w).Encode(response)
+		require.NoError(t, err, "failed to write http body for stubbed server")
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client, err := image_builder.NewImageBuilderClientWithUrl(ctx, ts.URL)
+	require.NoError(t, err, "failed to initialize image builder client with test server")
+
+	images, err := client.ListImages(ctx, models.ProviderTypeGCP)
+	require.NoError(t, err)
+	require.Len(t, images, 1)
+	assert.Equal(t, composeID.String(), images[0].ID)
+	assert.Equal(t, []string{"0", "100"}, offsets)
+}
@@ -0,0 +1,239 @@
+package image_builder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/cache"
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/clients/http"
+	"github.com/RHEnVision/provisioning-backend/internal/headers"
+	"github.com/RHEnVision/provisioning-backend/internal/identity"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/ptr"
+	"github.com/RHEnVision/provisioning-backend/internal/telemetry"
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// maximum concurrent requests when fetching compose and clone statuses
+	listImagesConcurrency = 8
+
+	// composes fetched per page of the compose list
+	listComposesPageSize = 100
+
+	// only the most recent composes are listed, every compose needs a status call and AWS
+	// composes also one call per clone
+	listImagesMaxComposes = 500
+
+	// only the first clones of a compose are listed
+	listClonesLimit = 100
+
+	// new composes and clones are listed after the cached list expires
+	listImagesExpiration = 5 * time.Minute
+)
+
+func uploadTypeForProvider(provider models.ProviderType) (UploadTypes, error) {
+	//nolint:exhaustive
+	switch provider {
+	case models.ProviderTypeAWS:
+		return UploadTypesAws, nil
+	case models.ProviderTypeAzure:
+		return UploadTypesAzure, nil
+	case models.ProviderTypeGCP:
+		return UploadTypesGcp, nil
+	default:
+		return "", fmt.Errorf("%w: %s", clients.ErrUnknownProvider, provider)
+	}
+}
+
+// decodeComposeRequest converts generic request from the compose list into ComposeRequest.
+func decodeComposeRequest(request interface{}) (*ComposeRequest, error) {
+	buf, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode compose request: %w", err)
+	}
+	result := ComposeRequest{}
+	err = json.Unmarshal(buf, &result)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode compose request: %w", err)
+	}
+	return &result, nil
+}
+
+func (c *ibClient) ListImages(ctx context.Context, provider models.ProviderType) ([]*clients.Image, error) {
+	ctx, span := telemetry.StartSpan(ctx, "ListImages")
+	defer span.End()
+
+	uploadType, err := uploadTypeForProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	key := identity.Identity(ctx).Identity.OrgID + "-" + provider.String()
+	result, err := cache.GetOrLoad(ctx, key, cache.LoadOptions{Expiration: listImagesExpiration},
+		func(ctx context.Context) (clients.ImageList, error) {
+			return c.loadImages(ctx, uploadType)
+		})
+	if err != nil {
+		return nil, fmt.Errorf("image list load error: %w", err)
+	}
+	return result, nil
+}
+
+// loadImages fetches images of the most recent composes with given upload type.
+func (c *ibClient) loadImages(ctx context.Context, uploadType UploadTypes) (clients.ImageList, error) {
+	logger := logger(ctx)
+
+	composes, err := c.listComposes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// each compose has its own slot to keep the order of composes, composes which cannot be
+	// fetched are logged and skipped so a single broken compose does not fail the whole list
+	slots := make([][]*clients.Image, len(composes))
+
+	group := errgroup.Group{}
+	group.SetLimit(listImagesConcurrency)
+	for i, item := range composes {
+		i, item := i, item
+		request, decodeErr := decodeComposeRequest(item.Request)
+		if decodeErr != nil {
+			logger.Warn().Err(decodeErr).Msgf("Skipping compose %s", item.Id)
+			continue
+		}
+		if len(request.ImageRequests) != 1 || request.ImageRequests[0].UploadRequest.Type != uploadType {
+			continue
+		}
+
+		group.Go(func() error {
+			images, imageErr := c.composeImages(ctx, item.Id, item.ImageName, request, uploadType)
+			if imageErr != nil {
+				logger.Warn().Err(imageErr).Msgf("Skipping compose %s", item.Id)
+				return nil
+			}
+			slots[i] = images
+			return nil
+		})
+	}
+
+	// goroutines never return an error
+	_ = group.Wait()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("unable to list images: %w", ctx.Err())
+	}
+
+	result := make(clients.ImageList, 0, len(slots))
+	for _, images := range slots {
+		result = append(result, images...)
+	}
+	return result, nil
+}
+
+// listComposes pages through the compose list, up to listImagesMaxComposes most recent composes.
+func (c *ibClient) listComposes(ctx context.Context) ([]ComposesResponseItem, error) {
+	logger := logger(ctx)
+	var result []ComposesResponseItem
+	for offset := 0; offset < listImagesMaxComposes; offset += listComposesPageSize {
+		params := &GetComposesParams{
+			Limit:  ptr.To(listComposesPageSize),
+			Offset: ptr.To(offset),
+		}
+		resp, err := c.client.GetComposesWithResponse(ctx, params, headers.AddImageBuilderIdentityHeader, headers.AddEdgeRequestIdHeader)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list composes: %w", err)
+		}
+		if resp == nil || resp.JSON200 == nil {
+			return nil, fmt.Errorf("list composes call: %w", clients.ErrUnexpectedBackendResponse)
+		}
+
+		result = append(result, resp.JSON200.Data...)
+		if len(resp.JSON200.Data) < listComposesPageSize || offset+listComposesPageSize >= resp.JSON200.Meta.Count {
+			return result, nil
+		}
+	}
+
+	logger.Warn().Msgf("Listing only %d most recent composes", listImagesMaxComposes)
+	return result, nil
+}
+
+// composeImages returns launchable images of a compose: the compose image itself and for AWS
+// also its successful clones. Nil is returned for composes which did not finish successfully.
+func (c *ibClient) composeImages(ctx context.Context, id uuid.UUID, name *string, request *ComposeRequest, uploadType UploadTypes) ([]*clients.Image, error) {
+	logger := logger(ctx)
+	composeStatus, err := c.getComposeStatus(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if composeStatus.ImageStatus.Status != ImageStatusStatusSuccess || composeStatus.ImageStatus.UploadStatus == nil {
+		return nil, nil
+	}
+
+	arch, archErr := clients.MapArchitectures(ctx, string(request.ImageRequests[0].Architecture))
+	if archErr != nil {
+		logger.Warn().Err(archErr).Msgf("Unknown architecture of compose %s", id)
+	}
+	image := &clients.Image{
+		ID:           id.String(),
+		Name:         ptr.FromOrEmpty(name),
+		Origin:       clients.ImageOriginImageBuilder,
+		Architecture: arch,
+	}
+	if uploadType != UploadTypesAws {
+		return []*clients.Image{image}, nil
+	}
+
+	// AWS images are regional and can be cloned into other regions or accounts
+	awsStatus, err := composeStatus.ImageStatus.UploadStatus.Options.AsAWSUploadStatus()
+	if err != nil {
+		return nil, fmt.Errorf("%w: not an AWS status: %s", http.ErrUploadStatus, err.Error())
+	}
+	image.Region = awsStatus.Region
+	image.ProviderID = awsStatus.Ami
+	if image.Name == "" {
+		image.Name = awsStatus.Ami
+	}
+	clones, err := c.listClones(ctx, id, image)
+	if err != nil {
+		logger.Warn().Err(err).Msgf("Unable to list clones of compose %s", id)
+	}
+	return append([]*clients.Image{image}, clones...), nil
+}
+
+// listClones returns successful clones of an AWS compose image, up to listClonesLimit clones.
+func (c *ibClient) listClones(ctx context.Context, composeUUID uuid.UUID, compose *clients.Image) ([]*clients.Image, error) {
+	resp, err := c.client.GetComposeClonesWithResponse(ctx, composeUUID, &GetComposeClonesParams{Limit: ptr.To(listClonesLimit)}, headers.AddImageBuilderIdentityHeader, headers.AddEdgeRequestIdHeader)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list clones: %w", err)
+	}
+	if resp == nil || resp.JSON200 == nil {
+		return nil, fmt.Errorf("list clones call: %w", clients.ErrUnexpectedBackendResponse)
+	}
+
+	result := make([]*clients.Image, 0, len(resp.JSON200.Data))
+	for _, clone := range resp.JSON200.Data {
+		uploadStatus, cloneErr := c.checkClone(ctx, clone.Id)
+		if cloneErr != nil {
+			// clones which are not ready or failed are not launchable
+			continue
+		}
+		awsStatus, optErr := uploadStatus.Options.AsAWSUploadStatus()
+		if optErr != nil {
+			continue
+		}
+		result = append(result, &clients.Image{
+			ID:           clone.Id.String(),
+			Name:         compose.Name,
+			Origin:       clients.ImageOriginImageBuilder,
+			Architecture: compose.Architecture,
+			Region:       awsStatus.Region,
+			ProviderID:   awsStatus.Ami,
+		})
+	}
+
+	return result, nil
+}
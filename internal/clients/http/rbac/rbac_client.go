@@ -2,7 +2,6 @@ package rbac
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"math"
 	"time"
@@ -88,74 +87,74 @@ func (c *rbac) GetPrincipalAccess(ctx context.Context) (clients.RbacAcl, error)
 	rhId := identity.Identity(ctx)
 	orgID := rhId.Identity.OrgID
 	accountNumber := rhId.Identity.AccountNumber
-	var fullRequest bool
+
+	result, err := cache.GetOrLoad(ctx, orgID+accountNumber, cache.LoadOptions{}, c.fetchPrincipalAccess)
+	if err != nil {
+		return nil, fmt.Errorf("acl load error: %w", err)
+	}
+
+	logger.Debug().Msgf("ACL: %s", result.String())
+	return result, nil
+}
+
+// fetchPrincipalAccess performs RBAC requests until all ACL records are fetched.
+func (c *rbac) fetchPrincipalAccess(ctx context.Context) (clients.AccessList, error) {
+	logger := zerolog.Ctx(ctx)
 	var result clients.AccessList
 
-	err := cache.Find(ctx, orgID+accountNumber, &result)
-	if errors.Is(err, cache.ErrNotFound) {
-		fullRequest = true
-		start := time.Now()
-		defer func() {
-			metrics.RbacAclFetchDuration.Observe(float64(time.Since(start).Nanoseconds()) / 1000000)
-		}()
-
-		records := math.MaxInt
-		offset := 0
-		maxQueries := 10
-
-		// keep fetching until we have all the records
-		for len(result) < records {
-			params := GetPrincipalAccessParams{
-				Application: "provisioning",
-				Limit:       FetchLimit,
-				Offset:      &offset,
-			}
-			resp, gpaErr := c.client.GetPrincipalAccessWithResponse(ctx, &params, headers.AddRbacIdentityHeader, headers.AddEdgeRequestIdHeader)
-			if gpaErr != nil {
-				return nil, fmt.Errorf("get principal access: %w", gpaErr)
-			}
-
-			if resp == nil {
-				return nil, fmt.Errorf("get principal access: empty response: %w", clients.ErrUnexpectedBackendResponse)
-			}
-
-			if resp.JSON200 == nil {
-				return nil, fmt.Errorf("get principal access: %w", clients.ErrUnexpectedBackendResponse)
-			}
-
-			logger.Trace().
-				Int("limit", *FetchLimit).
-				Int("offset", offset).
-				Int("length", len(result)).
-				Int("entries", len(resp.JSON200.Data)).
-				Int("return_code", resp.StatusCode()).
-				Msg("Performed get principal access RBAC call")
-
-			if resp.JSON200 == nil || resp.JSON200.Meta == nil || resp.JSON200.Meta.Count == nil {
-				return nil, ErrMetaNotPresent
-			}
-			records = int(*resp.JSON200.Meta.Count)
-
-			for _, a := range resp.JSON200.Data {
-				result = append(result, clients.NewAccess(a.Permission))
-			}
-			offset += *FetchLimit
-
-			maxQueries -= 1
-			if maxQueries <= 0 {
-				logger.Warn().Msg("Maximum amount of RBAC requests reached, giving up")
-				break
-			}
+	start := time.Now()
+	defer func() {
+		metrics.RbacAclFetchDuration.Observe(float64(time.Since(start).Nanoseconds()) / 1000000)
+	}()
+
+	records := math.MaxInt
+	offset := 0
+	maxQueries := 10
+
+	// keep fetching until we have all the records
+	for len(result) < records {
+		params := GetPrincipalAccessParams{
+			Application: "provisioning",
+			Limit:       FetchLimit,
+			Offset:      &offset,
+		}
+		resp, gpaErr := c.client.GetPrincipalAccessWithResponse(ctx, &params, headers.AddRbacIdentityHeader, headers.AddEdgeRequestIdHeader)
+		if gpaErr != nil {
+			return nil, fmt.Errorf("get principal access: %w", gpaErr)
+		}
+
+		if resp == nil {
+			return nil, fmt.Errorf("get principal access: empty response: %w", clients.ErrUnexpectedBackendResponse)
+		}
+
+		if resp.JSON200 == nil {
+			return nil, fmt.Errorf("get principal access: %w", clients.ErrUnexpectedBackendResponse)
+		}
+
+		logger.Trace().
+			Int("limit", *FetchLimit).
+			Int("offset", offset).
+			Int("length", len(result)).
+			Int("entries", len(resp.JSON200.Data)).
+			Int("return_code", resp.StatusCode()).
+			Msg("Performed get principal access RBAC call")
+
+		if resp.JSON200 == nil || resp.JSON200.Meta == nil || resp.JSON200.Meta.Count == nil {
+			return nil, ErrMetaNotPresent
+		}
+		records = int(*resp.JSON200.Meta.Count)
+
+		for _, a := range resp.JSON200.Data {
+			result = append(result, clients.NewAccess(a.Permission))
 		}
+		offset += *FetchLimit
 
-		err = cache.Set(ctx, orgID+accountNumber, &result)
-		if err != nil {
-			return nil, fmt.Errorf("acl cache set error: %w", err)
+		maxQueries -= 1
+		if maxQueries <= 0 {
+			logger.Warn().Msg("Maximum amount of RBAC requests reached, giving up")
+			break
 		}
-	} else if err != nil {
-		return nil, fmt.Errorf("acl cache get error: %w", err)
 	}
 
-	logger.Debug().Msgf("ACL (cache: %t): %s", !fullRequest, result.String())
 	return result, nil
 }
@@ -0,0 +1,45 @@
+package clients
+
+// ImageOrigin describes where a launchable image comes from.
+type ImageOrigin string
+
+const (
+	// ImageOriginImageBuilder are composes and clones built by Image Builder.
+	ImageOriginImageBuilder ImageOrigin = "image-builder"
+
+	// ImageOriginAccount are images owned by the customer account.
+	ImageOriginAccount ImageOrigin = "account"
+
+	// ImageOriginShared are images shared with the customer account by other accounts.
+	ImageOriginShared ImageOrigin = "shared"
+)
+
+// Image represents a launchable image of a hyperscaler.
+type Image struct {
+	// ID is the identifier accepted by reservations: compose or clone UUID for Image Builder,
+	// AMI for AWS EC2, image URL for GCP and resource ID for Azure.
+	ID string
+
+	// Name is a human-readable name of the image.
+	Name string
+
+	// Origin of the image.
+	Origin ImageOrigin
+
+	// Architecture of the image, empty when not known.
+	Architecture ArchitectureType
+
+	// Region the image is available in, empty for global images (GCP, Azure galleries).
+	Region string
+
+	// ProviderID is the identifier of an Image Builder image in the hyperscaler (AMI for AWS),
+	// it is used to match the image with account images. Empty when not known.
+	ProviderID string
+}
+
+// ImageList is a list of Image Builder images of an organization.
+type ImageList []*Image
+
+func (ImageList) CacheKeyName() string {
+	return "ib_images"
+}
@@ -32,6 +32,15 @@ type GCPInstanceParams struct {
 
 	// StartupScript contains metadata startup script (GCP tools must be installed on the image)
 	StartupScript string
+
+	// RootVolume overrides the image root disk, nil keeps the image disk
+	RootVolume *models.RootVolume
+
+	// Volumes are additional data volumes
+	Volumes []models.Volume
+
+	// Labels are user labels of the instances, system labels take precedence
+	Labels map[string]string
 }
 
 type AWSInstanceParams struct {
@@ -52,6 +61,19 @@ type AWSInstanceParams struct {
 
 	// UserData for the instance launch
 	UserData []byte
+
+	// RootVolume overrides the image root disk, nil keeps the image disk
+	RootVolume *models.RootVolume
+
+	// Volumes are additional data volumes
+	Volumes []models.Volume
+
+	// Tags are user tags of the instances, system tags take precedence
+	Tags map[string]string
+
+	// ClientToken makes the launch idempotent, repeated calls with the same token return the
+	// already launched instances. Blank value disables idempotency.
+	ClientToken string
 }
 
 // AzureInstanceParams define parameters for a single instance launch on Azure.
@@ -77,4 +99,10 @@ type AzureInstanceParams struct {
 
 	// Tags carries list of key-value tags
 	Tags map[string]*string
+
+	// RootVolume overrides the image root disk, nil keeps the image disk
+	RootVolume *models.RootVolume
+
+	// Volumes are additional data volumes
+	Volumes []models.Volume
 }
@@ -38,6 +38,10 @@ type InstanceType struct {
 
 	// Extra information for Azure, nil for other types
 	AzureDetail *InstanceTypeDetailAzure `json:"azure,omitempty" yaml:"azure,omitempty"`
+
+	// On-demand price in USD per hour in the listed region, nil when unknown. Prices are stored
+	// separately from types because they differ per region.
+	PricePerHour *float64 `json:"price_per_hour,omitempty" yaml:"-"`
 }
 
 // InstanceTypeDetailAzure contains specific details for Azure.
@@ -0,0 +1,143 @@
+package clients
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// InstanceTypeDiff holds differences between two instance type data sets.
+type InstanceTypeDiff struct {
+	Added   []InstanceTypeName
+	Removed []InstanceTypeName
+	Changed []InstanceTypeName
+
+	// Region or zone keys (e.g. "westeurope_1") present only in one of the sets
+	ZonesAdded   []string
+	ZonesRemoved []string
+
+	// Types added to or removed from region or zones present in both sets
+	AvailabilityAdded   map[string][]InstanceTypeName
+	AvailabilityRemoved map[string][]InstanceTypeName
+}
+
+// DiffInstanceTypeInfo compares existing (e.g. preloaded) data with new (e.g. live) data.
+func DiffInstanceTypeInfo(existing, updated *InstanceTypeInfo) *InstanceTypeDiff {
+	diff := InstanceTypeDiff{
+		AvailabilityAdded:   make(map[string][]InstanceTypeName),
+		AvailabilityRemoved: make(map[string][]InstanceTypeName),
+	}
+
+	for name, it := range updated.RegisteredTypes.types {
+		old, ok := existing.RegisteredTypes.types[name]
+		if !ok {
+			diff.Added = append(diff.Added, name)
+		} else if !reflect.DeepEqual(*old, *it) {
+			diff.Changed = append(diff.Changed, name)
+		}
+	}
+	for name := range existing.RegisteredTypes.types {
+		if _, ok := updated.RegisteredTypes.types[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+
+	for raz, names := range updated.RegionalAvailability.types {
+		oldNames, ok := existing.RegionalAvailability.types[raz]
+		if !ok {
+			diff.ZonesAdded = append(diff.ZonesAdded, raz)
+			continue
+		}
+		if added := missingNames(names, oldNames); len(added) > 0 {
+			diff.AvailabilityAdded[raz] = added
+		}
+		if removed := missingNames(oldNames, names); len(removed) > 0 {
+			diff.AvailabilityRemoved[raz] = removed
+		}
+	}
+	for raz := range existing.RegionalAvailability.types {
+		if _, ok := updated.RegionalAvailability.types[raz]; !ok {
+			diff.ZonesRemoved = append(diff.ZonesRemoved, raz)
+		}
+	}
+
+	slices.Sort(diff.Added)
+	slices.Sort(diff.Removed)
+	slices.Sort(diff.Changed)
+	slices.Sort(diff.ZonesAdded)
+	slices.Sort(diff.ZonesRemoved)
+	return &diff
+}
+
+// missingNames returns names which are not present in other.
+func missingNames(names, other []InstanceTypeName) []InstanceTypeName {
+	var result []InstanceTypeName
+	for _, name := range names {
+		if !slices.Contains(other, name) {
+			result = append(result, name)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// Empty returns true when there are no differences.
+func (d *InstanceTypeDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 &&
+		len(d.ZonesAdded) == 0 && len(d.ZonesRemoved) == 0 &&
+		len(d.AvailabilityAdded) == 0 && len(d.AvailabilityRemoved) == 0
+}
+
+func joinNames(names []InstanceTypeName) string {
+	strs := make([]string, len(names))
+	for i, name := range names {
+		strs[i] = name.String()
+	}
+	return strings.Join(strs, ", ")
+}
+
+func sortedKeys(m map[string][]InstanceTypeName) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// String returns a human-readable summary of the differences.
+func (d *InstanceTypeDiff) String() string {
+	if d.Empty() {
+		return "No differences\n"
+	}
+
+	sb := strings.Builder{}
+	line := func(format string, args ...any) {
+		sb.WriteString(fmt.Sprintf(format, args...))
+		sb.WriteString("\n")
+	}
+	if len(d.Added) > 0 {
+		line("Added types (%d): %s", len(d.Added), joinNames(d.Added))
+	}
+	if len(d.Removed) > 0 {
+		line("Removed types (%d): %s", len(d.Removed), joinNames(d.Removed))
+	}
+	if len(d.Changed) > 0 {
+		line("Changed types (%d): %s", len(d.Changed), joinNames(d.Changed))
+	}
+	if len(d.ZonesAdded) > 0 {
+		line("Added regions/zones (%d): %s", len(d.ZonesAdded), strings.Join(d.ZonesAdded, ", "))
+	}
+	if len(d.ZonesRemoved) > 0 {
+		line("Removed regions/zones (%d): %s", len(d.ZonesRemoved), strings.Join(d.ZonesRemoved, ", "))
+	}
+	for _, raz := range sortedKeys(d.AvailabilityAdded) {
+		line("Availability in '%s' added: %s", raz, joinNames(d.AvailabilityAdded[raz]))
+	}
+	for _, raz := range sortedKeys(d.AvailabilityRemoved) {
+		line("Availability in '%s' removed: %s", raz, joinNames(d.AvailabilityRemoved[raz]))
+	}
+	return sb.String()
+}
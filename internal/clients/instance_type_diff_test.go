@@ -0,0 +1,70 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTypeInfo(types ...InstanceType) *InstanceTypeInfo {
+	info := InstanceTypeInfo{
+		RegisteredTypes:      *NewRegisteredInstanceTypes(),
+		RegionalAvailability: *NewRegionalInstanceTypes(),
+	}
+	for _, it := range types {
+		info.RegisteredTypes.Register(it)
+	}
+	return &info
+}
+
+func TestDiffNoDifferences(t *testing.T) {
+	existing := newTestTypeInfo(smallType)
+	existing.RegionalAvailability.Add("region", "1", smallType)
+	updated := newTestTypeInfo(smallType)
+	updated.RegionalAvailability.Add("region", "1", smallType)
+
+	diff := DiffInstanceTypeInfo(existing, updated)
+	require.True(t, diff.Empty())
+	require.Equal(t, "No differences\n", diff.String())
+}
+
+func TestDiffTypes(t *testing.T) {
+	changed := smallType
+	changed.Name = "changed"
+	removed := smallType
+	removed.Name = "removed"
+	existing := newTestTypeInfo(smallType, changed, removed)
+
+	added := smallType
+	added.Name = "added"
+	changed.VCPUs = 2
+	updated := newTestTypeInfo(smallType, changed, added)
+
+	diff := DiffInstanceTypeInfo(existing, updated)
+	require.Equal(t, []InstanceTypeName{"added"}, diff.Added)
+	require.Equal(t, []InstanceTypeName{"removed"}, diff.Removed)
+	require.Equal(t, []InstanceTypeName{"changed"}, diff.Changed)
+}
+
+func TestDiffAvailability(t *testing.T) {
+	other := smallType
+	other.Name = "other"
+
+	existing := newTestTypeInfo(smallType, other)
+	existing.RegionalAvailability.Add("region", "1", smallType)
+	existing.RegionalAvailability.Add("region", "2", smallType)
+	updated := newTestTypeInfo(smallType, other)
+	updated.RegionalAvailability.Add("region", "1", other)
+	updated.RegionalAvailability.Add("region", "3", smallType)
+
+	diff := DiffInstanceTypeInfo(existing, updated)
+	require.Equal(t, []string{"region_3"}, diff.ZonesAdded)
+	require.Equal(t, []string{"region_2"}, diff.ZonesRemoved)
+	require.Equal(t, map[string][]InstanceTypeName{"region_1": {"other"}}, diff.AvailabilityAdded)
+	require.Equal(t, map[string][]InstanceTypeName{"region_1": {"small"}}, diff.AvailabilityRemoved)
+	require.Equal(t, `Added regions/zones (1): region_3
+Removed regions/zones (1): region_2
+Availability in 'region_1' added: other
+Availability in 'region_1' removed: small
+`, diff.String())
+}
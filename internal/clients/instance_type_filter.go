@@ -0,0 +1,163 @@
+package clients
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var ErrUnknownSortField = errors.New("unknown sort field")
+
+// InstanceTypeFilter limits listed instance types, nil or empty fields are not applied.
+type InstanceTypeFilter struct {
+	MinVCPUs     *int64
+	MaxVCPUs     *int64
+	MinCores     *int64
+	MaxCores     *int64
+	MinMemoryMiB *int64
+	MaxMemoryMiB *int64
+
+	// Minimum total size of ephemeral disks in GB, zero lists types with any ephemeral storage
+	MinStorageGB *int64
+
+	Architecture *ArchitectureType
+
+	// Azure hypervisor generation: "v1" or "v2"
+	AzureGeneration string
+
+	// Case-insensitive name prefix (e.g. "t3." or "Standard_D")
+	NamePrefix string
+
+	// Case-insensitive instance family (e.g. "t3" for AWS, "n2" for GCP, "d" for Azure)
+	Family string
+}
+
+func inRange(value int64, lower, upper *int64) bool {
+	if lower != nil && value < *lower {
+		return false
+	}
+	if upper != nil && value > *upper {
+		return false
+	}
+	return true
+}
+
+// Matches returns true when the instance type satisfies all filter conditions.
+func (f *InstanceTypeFilter) Matches(it *InstanceType) bool {
+	if !inRange(int64(it.VCPUs), f.MinVCPUs, f.MaxVCPUs) ||
+		!inRange(int64(it.Cores), f.MinCores, f.MaxCores) ||
+		!inRange(it.MemoryMiB, f.MinMemoryMiB, f.MaxMemoryMiB) {
+		return false
+	}
+
+	if f.MinStorageGB != nil && (it.EphemeralStorageGB == 0 || it.EphemeralStorageGB < *f.MinStorageGB) {
+		return false
+	}
+
+	if f.Architecture != nil && *f.Architecture != it.Architecture {
+		return false
+	}
+
+	switch strings.ToLower(f.AzureGeneration) {
+	case "v1":
+		if it.AzureDetail == nil || !it.AzureDetail.GenV1 {
+			return false
+		}
+	case "v2":
+		if it.AzureDetail == nil || !it.AzureDetail.GenV2 {
+			return false
+		}
+	}
+
+	if f.NamePrefix != "" && !strings.HasPrefix(strings.ToLower(it.Name.String()), strings.ToLower(f.NamePrefix)) {
+		return false
+	}
+
+	if f.Family != "" && !strings.EqualFold(it.Family(), f.Family) {
+		return false
+	}
+
+	return true
+}
+
+// Filter returns a new slice with instance types matching the filter.
+func (f *InstanceTypeFilter) Filter(types []*InstanceType) []*InstanceType {
+	result := make([]*InstanceType, 0, len(types))
+	for _, it := range types {
+		if f.Matches(it) {
+			result = append(result, it)
+		}
+	}
+	return result
+}
+
+// Family returns instance family which is "t3" for "t3.micro" (AWS), "n2" for "n2-standard-4" (GCP)
+// and "D" for "Standard_D2s_v3" (Azure).
+func (it *InstanceType) Family() string {
+	name := it.Name.String()
+
+	if family, _, found := strings.Cut(name, "."); found {
+		return family
+	}
+
+	for _, tier := range []string{"Standard_", "Basic_"} {
+		if series, found := strings.CutPrefix(name, tier); found {
+			return leadingLetters(series)
+		}
+	}
+
+	if family, _, found := strings.Cut(name, "-"); found {
+		return family
+	}
+
+	return name
+}
+
+func leadingLetters(s string) string {
+	end := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) })
+	if end < 0 {
+		return s
+	}
+	return s[:end]
+}
+
+// SortInstanceTypes sorts instance types in place by a field: name, vcpus, cores, memory or storage.
+// A field prefixed with "-" sorts in descending order. Ties are sorted by name.
+func SortInstanceTypes(types []*InstanceType, field string) error {
+	desc := strings.HasPrefix(field, "-")
+	field = strings.TrimPrefix(field, "-")
+
+	var key func(it *InstanceType) int64
+	switch field {
+	case "name":
+		key = func(_ *InstanceType) int64 { return 0 }
+	case "vcpus":
+		key = func(it *InstanceType) int64 { return int64(it.VCPUs) }
+	case "cores":
+		key = func(it *InstanceType) int64 { return int64(it.Cores) }
+	case "memory":
+		key = func(it *InstanceType) int64 { return it.MemoryMiB }
+	case "storage":
+		key = func(it *InstanceType) int64 { return it.EphemeralStorageGB }
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownSortField, field)
+	}
+
+	sort.SliceStable(types, func(i, j int) bool {
+		ki, kj := key(types[i]), key(types[j])
+		if ki == kj {
+			if desc && field == "name" {
+				return types[i].Name > types[j].Name
+			}
+			return types[i].Name < types[j].Name
+		}
+		if desc {
+			return ki > kj
+		}
+		return ki < kj
+	})
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/RHEnVision/provisioning-backend/internal/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testInstanceTypes() []*InstanceType {
+	return []*InstanceType{
+		{Name: "t3.micro", VCPUs: 2, Cores: 1, MemoryMiB: 1024, Architecture: ArchitectureTypeX86_64},
+		{Name: "t4g.large", VCPUs: 2, Cores: 2, MemoryMiB: 8192, Architecture: ArchitectureTypeArm64},
+		{Name: "c5d.xlarge", VCPUs: 4, Cores: 2, MemoryMiB: 8192, EphemeralStorageGB: 100, Architecture: ArchitectureTypeX86_64},
+		{Name: "Standard_D2s_v3", VCPUs: 2, Cores: 1, MemoryMiB: 8192, EphemeralStorageGB: 16, Architecture: ArchitectureTypeX86_64,
+			AzureDetail: &InstanceTypeDetailAzure{GenV1: true, GenV2: true}},
+		{Name: "Basic_A1", VCPUs: 1, Cores: 1, MemoryMiB: 1750, EphemeralStorageGB: 40, Architecture: ArchitectureTypeX86_64,
+			AzureDetail: &InstanceTypeDetailAzure{GenV1: true}},
+		{Name: "n2-standard-4", VCPUs: 4, Cores: 2, MemoryMiB: 16384, Architecture: ArchitectureTypeX86_64},
+	}
+}
+
+func names(types []*InstanceType) []InstanceTypeName {
+	result := make([]InstanceTypeName, len(types))
+	for i, it := range types {
+		result[i] = it.Name
+	}
+	return result
+}
+
+func TestInstanceType_Family(t *testing.T) {
+	expected := []string{"t3", "t4g", "c5d", "D", "A", "n2"}
+	for i, it := range testInstanceTypes() {
+		assert.Equal(t, expected[i], it.Family(), it.Name)
+	}
+}
+
+func TestInstanceTypeFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   InstanceTypeFilter
+		expected []InstanceTypeName
+	}{
+		{"empty", InstanceTypeFilter{}, names(testInstanceTypes())},
+		{"vcpus", InstanceTypeFilter{MinVCPUs: ptr.To(int64(2)), MaxVCPUs: ptr.To(int64(2))},
+			[]InstanceTypeName{"t3.micro", "t4g.large", "Standard_D2s_v3"}},
+		{"cores", InstanceTypeFilter{MinCores: ptr.To(int64(2))},
+			[]InstanceTypeName{"t4g.large", "c5d.xlarge", "n2-standard-4"}},
+		{"memory", InstanceTypeFilter{MinMemoryMiB: ptr.To(int64(2048)), MaxMemoryMiB: ptr.To(int64(8192))},
+			[]InstanceTypeName{"t4g.large", "c5d.xlarge", "Standard_D2s_v3"}},
+		{"storage", InstanceTypeFilter{MinStorageGB: ptr.To(int64(0))},
+			[]InstanceTypeName{"c5d.xlarge", "Standard_D2s_v3", "Basic_A1"}},
+		{"architecture", InstanceTypeFilter{Architecture: ptr.To(ArchitectureTypeArm64)},
+			[]InstanceTypeName{"t4g.large"}},
+		{"azure generation", InstanceTypeFilter{AzureGeneration: "v2"},
+			[]InstanceTypeName{"Standard_D2s_v3"}},
+		{"name prefix", InstanceTypeFilter{NamePrefix: "T"},
+			[]InstanceTypeName{"t3.micro", "t4g.large"}},
+		{"family", InstanceTypeFilter{Family: "d"},
+			[]InstanceTypeName{"Standard_D2s_v3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, names(tt.filter.Filter(testInstanceTypes())))
+		})
+	}
+}
+
+func TestSortInstanceTypes(t *testing.T) {
+	types := testInstanceTypes()
+	err := SortInstanceTypes(types, "-vcpus")
+	require.NoError(t, err)
+	assert.Equal(t, []InstanceTypeName{"c5d.xlarge", "n2-standard-4", "Standard_D2s_v3", "t3.micro", "t4g.large", "Basic_A1"}, names(types))
+
+	err = SortInstanceTypes(types, "memory")
+	require.NoError(t, err)
+	assert.Equal(t, []InstanceTypeName{"t3.micro", "Basic_A1", "Standard_D2s_v3", "c5d.xlarge", "t4g.large", "n2-standard-4"}, names(types))
+
+	err = SortInstanceTypes(types, "name")
+	require.NoError(t, err)
+	assert.Equal(t, InstanceTypeName("Basic_A1"), types[0].Name)
+
+	err = SortInstanceTypes(types, "price")
+	require.ErrorIs(t, err, ErrUnknownSortField)
+}
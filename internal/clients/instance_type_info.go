@@ -11,6 +11,19 @@ import (
 type InstanceTypeInfo struct {
 	RegisteredTypes      RegisteredInstanceTypes
 	RegionalAvailability RegionalTypeAvailability
+	Prices               RegionalPrices
+}
+
+// withPrice returns a copy of the instance type with price for the region or zone set, or the
+// original type when the price is unknown.
+func (iii *InstanceTypeInfo) withPrice(raz string, it *InstanceType) *InstanceType {
+	price, ok := iii.Prices.Get(raz, it.Name)
+	if !ok {
+		return it
+	}
+	priced := *it
+	priced.PricePerHour = &price
+	return &priced
 }
 
 func (iii *InstanceTypeInfo) InstanceTypesForZone(region, zone string, supported *bool) ([]*InstanceType, error) {
@@ -24,7 +37,27 @@ func (iii *InstanceTypeInfo) InstanceTypesForZone(region, zone string, supported
 		if supported != nil && *supported != rt.Supported {
 			continue
 		}
-		result = append(result, rt)
+		result = append(result, iii.withPrice(key(region, zone), rt))
+	}
+	return result, nil
+}
+
+// InstanceTypesForRegion returns instance types available in any zone of a region, or all
+// registered types when region is empty.
+func (iii *InstanceTypeInfo) InstanceTypesForRegion(region string) ([]*InstanceType, error) {
+	if region == "" {
+		return iii.RegisteredTypes.All(), nil
+	}
+
+	names, err := iii.RegionalAvailability.NamesForRegion(region)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*InstanceType, 0, len(names))
+	for _, name := range names {
+		if rt := iii.RegisteredTypes.Get(name); rt != nil {
+			result = append(result, iii.withPrice(region, rt))
+		}
 	}
 	return result, nil
 }
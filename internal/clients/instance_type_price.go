@@ -0,0 +1,82 @@
+package clients
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// HoursPerMonth is the average number of hours in a month used for monthly cost estimates.
+const HoursPerMonth = 730
+
+// RegionalPrices holds on-demand hourly prices in USD per region and instance type.
+type RegionalPrices struct {
+	prices map[string]map[InstanceTypeName]float64
+}
+
+func NewRegionalPrices() *RegionalPrices {
+	return &RegionalPrices{
+		prices: make(map[string]map[InstanceTypeName]float64),
+	}
+}
+
+// PriceRegion returns region for a region or zone key as used in the availability data: "westeurope"
+// for Azure "westeurope_1" and "us-east1" for GCP "us-east1-b". AWS regions are returned unchanged.
+func PriceRegion(raz string) string {
+	if region, _, found := strings.Cut(raz, regionSeparator); found {
+		return region
+	}
+
+	if i := strings.LastIndex(raz, "-"); i > 0 && len(raz)-i == 2 && unicode.IsLetter(rune(raz[i+1])) {
+		return raz[:i]
+	}
+
+	return raz
+}
+
+// Set stores price for a region. When a price is already present, the lower one is kept because
+// some catalogs list the same type multiple times (e.g. different license or capacity options).
+func (rp *RegionalPrices) Set(region string, name InstanceTypeName, pricePerHour float64) {
+	if rp.prices == nil {
+		rp.prices = make(map[string]map[InstanceTypeName]float64)
+	}
+	if _, ok := rp.prices[region]; !ok {
+		rp.prices[region] = make(map[InstanceTypeName]float64)
+	}
+	if existing, ok := rp.prices[region][name]; ok && existing <= pricePerHour {
+		return
+	}
+	rp.prices[region][name] = pricePerHour
+}
+
+// Get returns hourly price for a region or zone (see PriceRegion) and instance type.
+func (rp *RegionalPrices) Get(raz string, name InstanceTypeName) (float64, bool) {
+	price, ok := rp.prices[PriceRegion(raz)][name]
+	return price, ok
+}
+
+// Len returns the total amount of prices.
+func (rp *RegionalPrices) Len() int {
+	total := 0
+	for _, types := range rp.prices {
+		total += len(types)
+	}
+	return total
+}
+
+// Load existing prices from YAML buffer
+func (rp *RegionalPrices) Load(buffer []byte) error {
+	err := yaml.Unmarshal(buffer, &rp.prices)
+	if err != nil {
+		return fmt.Errorf("unable to unmarshal instance type prices: %w", err)
+	}
+
+	return nil
+}
+
+// Save prices to YAML
+func (rp *RegionalPrices) Save(filename string) error {
+	return compareAndMarshal(filename, rp.prices)
+}
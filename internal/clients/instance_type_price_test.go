@@ -0,0 +1,74 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceRegion(t *testing.T) {
+	require.Equal(t, "us-east-1", PriceRegion("us-east-1"))
+	require.Equal(t, "westeurope", PriceRegion("westeurope_1"))
+	require.Equal(t, "westeurope", PriceRegion("westeurope"))
+	require.Equal(t, "us-east1", PriceRegion("us-east1-b"))
+	require.Equal(t, "us-east1", PriceRegion("us-east1"))
+}
+
+func TestRegionalPricesSetKeepsLowest(t *testing.T) {
+	rp := NewRegionalPrices()
+	rp.Set("region", "small", 0.2)
+	rp.Set("region", "small", 0.1)
+	rp.Set("region", "small", 0.3)
+
+	price, ok := rp.Get("region", "small")
+	require.True(t, ok)
+	require.InDelta(t, 0.1, price, 0.0001)
+	require.Equal(t, 1, rp.Len())
+}
+
+func TestRegionalPricesGetZone(t *testing.T) {
+	rp := NewRegionalPrices()
+	rp.Set("us-east1", "small", 0.1)
+
+	price, ok := rp.Get("us-east1-c", "small")
+	require.True(t, ok)
+	require.InDelta(t, 0.1, price, 0.0001)
+
+	_, ok = rp.Get("us-west1-a", "small")
+	require.False(t, ok)
+}
+
+func TestRegionalPricesLoad(t *testing.T) {
+	rp := RegionalPrices{}
+	err := rp.Load([]byte("westeurope:\n    small: 0.5\n"))
+	require.NoError(t, err)
+
+	price, ok := rp.Get("westeurope_2", "small")
+	require.True(t, ok)
+	require.InDelta(t, 0.5, price, 0.0001)
+}
+
+func TestInstanceTypesForZoneWithPrice(t *testing.T) {
+	info := InstanceTypeInfo{
+		RegisteredTypes:      *NewRegisteredInstanceTypes(),
+		RegionalAvailability: *NewRegionalInstanceTypes(),
+	}
+	info.RegisteredTypes.Register(smallType)
+	info.RegionalAvailability.Add("westeurope", "1", smallType)
+	info.RegionalAvailability.Add("eastus", "1", smallType)
+	info.Prices.Set("westeurope", smallType.Name, 0.5)
+
+	types, err := info.InstanceTypesForZone("westeurope", "1", nil)
+	require.NoError(t, err)
+	require.Len(t, types, 1)
+	require.NotNil(t, types[0].PricePerHour)
+	require.InDelta(t, 0.5, *types[0].PricePerHour, 0.0001)
+
+	// registered type is not modified
+	require.Nil(t, info.RegisteredTypes.Get(smallType.Name).PricePerHour)
+
+	types, err = info.InstanceTypesForRegion("eastus")
+	require.NoError(t, err)
+	require.Len(t, types, 1)
+	require.Nil(t, types[0].PricePerHour)
+}
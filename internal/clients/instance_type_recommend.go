@@ -0,0 +1,71 @@
+package clients
+
+import (
+	"math"
+	"sort"
+)
+
+// undersizedPenalty makes instance types smaller than requested rank worse than bigger ones
+const undersizedPenalty = 2.0
+
+// InstanceTypeShape is a requested size of an instance type.
+type InstanceTypeShape struct {
+	VCPUs        int32
+	MemoryMiB    int64
+	Architecture ArchitectureType
+}
+
+// RankedInstanceType is an instance type with distance from a requested shape.
+type RankedInstanceType struct {
+	*InstanceType
+
+	// Distance from the requested shape, zero is an exact match
+	Distance float64
+}
+
+func relativeDistance(value, requested float64) float64 {
+	if requested <= 0 {
+		return 0
+	}
+	d := (value - requested) / requested
+	if d < 0 {
+		return -d * undersizedPenalty
+	}
+	return d
+}
+
+// Distance returns the sum of relative differences of vCPUs and memory from the shape. Undersized
+// attributes are penalized, so an instance type with more resources ranks better than a type with
+// the same amount of less resources.
+func (s InstanceTypeShape) Distance(it *InstanceType) float64 {
+	return relativeDistance(float64(it.VCPUs), float64(s.VCPUs)) +
+		relativeDistance(float64(it.MemoryMiB), float64(s.MemoryMiB))
+}
+
+// RankInstanceTypes returns up to limit supported instance types with matching architecture, closest
+// to the shape first. Ties are sorted by name.
+func RankInstanceTypes(types []*InstanceType, shape InstanceTypeShape, limit int) []RankedInstanceType {
+	result := make([]RankedInstanceType, 0, len(types))
+	for _, it := range types {
+		if !it.Supported || it.Architecture != shape.Architecture {
+			continue
+		}
+		result = append(result, RankedInstanceType{
+			InstanceType: it,
+			// round to avoid floating point noise in the output
+			Distance: math.Round(shape.Distance(it)*1000) / 1000,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Distance == result[j].Distance {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].Distance < result[j].Distance
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
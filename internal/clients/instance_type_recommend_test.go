@@ -0,0 +1,54 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankInstanceTypes(t *testing.T) {
+	types := []*InstanceType{
+		{Name: "exact", VCPUs: 4, MemoryMiB: 16384, Supported: true, Architecture: ArchitectureTypeX86_64},
+		{Name: "bigger", VCPUs: 8, MemoryMiB: 16384, Supported: true, Architecture: ArchitectureTypeX86_64},
+		{Name: "smaller", VCPUs: 2, MemoryMiB: 16384, Supported: true, Architecture: ArchitectureTypeX86_64},
+		{Name: "arm", VCPUs: 4, MemoryMiB: 16384, Supported: true, Architecture: ArchitectureTypeArm64},
+		{Name: "unsupported", VCPUs: 4, MemoryMiB: 16384, Supported: false, Architecture: ArchitectureTypeX86_64},
+		{Name: "also-exact", VCPUs: 4, MemoryMiB: 16384, Supported: true, Architecture: ArchitectureTypeX86_64},
+	}
+	shape := InstanceTypeShape{VCPUs: 4, MemoryMiB: 16384, Architecture: ArchitectureTypeX86_64}
+
+	ranked := RankInstanceTypes(types, shape, 0)
+	require.Len(t, ranked, 4)
+	assert.Equal(t, InstanceTypeName("also-exact"), ranked[0].Name)
+	assert.Equal(t, InstanceTypeName("exact"), ranked[1].Name)
+	assert.Zero(t, ranked[1].Distance)
+	// undersized by 50 % is worse than oversized by 100 %
+	assert.Equal(t, InstanceTypeName("bigger"), ranked[2].Name)
+	assert.Equal(t, 1.0, ranked[2].Distance)
+	assert.Equal(t, InstanceTypeName("smaller"), ranked[3].Name)
+
+	ranked = RankInstanceTypes(types, shape, 1)
+	require.Len(t, ranked, 1)
+}
+
+func TestNamesForRegion(t *testing.T) {
+	rit := NewRegionalInstanceTypes()
+	rit.Add("westeurope", "1", InstanceType{Name: "a"})
+	rit.Add("westeurope", "2", InstanceType{Name: "b"})
+	rit.Add("westeurope", "2", InstanceType{Name: "a"})
+	rit.Add("us-east1-b", "", InstanceType{Name: "c"})
+	rit.Add("us-east1-c", "", InstanceType{Name: "d"})
+	rit.Add("us-east1-extra", "", InstanceType{Name: "e"})
+
+	names, err := rit.NamesForRegion("westeurope")
+	require.NoError(t, err)
+	assert.Equal(t, []InstanceTypeName{"a", "b"}, names)
+
+	names, err = rit.NamesForRegion("us-east1")
+	require.NoError(t, err)
+	assert.Equal(t, []InstanceTypeName{"c", "d"}, names)
+
+	_, err = rit.NamesForRegion("mars")
+	require.ErrorIs(t, err, ErrUnknownRegionZoneCombination)
+}
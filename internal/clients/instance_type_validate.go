@@ -0,0 +1,54 @@
+package clients
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/exp/slices"
+)
+
+var (
+	ErrUnknownInstanceType = errors.New("unknown instance type")
+	ErrUnknownArchitecture = errors.New("unknown architecture")
+)
+
+// Validate checks referential integrity: every type in regional availability and prices must be
+// registered and every registered type must have a known architecture. All problems are returned.
+func (iii *InstanceTypeInfo) Validate() []error {
+	var errs []error
+
+	for _, name := range iii.RegisteredTypes.names() {
+		it := iii.RegisteredTypes.types[name]
+		if it.Architecture != ArchitectureTypeX86_64 && it.Architecture != ArchitectureTypeArm64 {
+			errs = append(errs, fmt.Errorf("%w '%s' of type %s", ErrUnknownArchitecture, it.Architecture, name))
+		}
+	}
+
+	razs := make([]string, 0, len(iii.RegionalAvailability.types))
+	for raz := range iii.RegionalAvailability.types {
+		razs = append(razs, raz)
+	}
+	slices.Sort(razs)
+	for _, raz := range razs {
+		for _, name := range iii.RegionalAvailability.types[raz] {
+			if iii.RegisteredTypes.Get(name) == nil {
+				errs = append(errs, fmt.Errorf("%w %s in availability of %s", ErrUnknownInstanceType, name, raz))
+			}
+		}
+	}
+
+	regions := make([]string, 0, len(iii.Prices.prices))
+	for region := range iii.Prices.prices {
+		regions = append(regions, region)
+	}
+	slices.Sort(regions)
+	for _, region := range regions {
+		for name := range iii.Prices.prices[region] {
+			if iii.RegisteredTypes.Get(name) == nil {
+				errs = append(errs, fmt.Errorf("%w %s in prices of %s", ErrUnknownInstanceType, name, region))
+			}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,39 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateValid(t *testing.T) {
+	info := newTestTypeInfo(smallType)
+	info.RegionalAvailability.Add("region", "1", smallType)
+	info.Prices.Set("region", smallType.Name, 0.1)
+
+	require.Empty(t, info.Validate())
+}
+
+func TestValidateUnknownType(t *testing.T) {
+	unknown := smallType
+	unknown.Name = "unknown"
+	info := newTestTypeInfo(smallType)
+	info.RegionalAvailability.Add("region", "1", unknown)
+	info.Prices.Set("region", unknown.Name, 0.1)
+
+	errs := info.Validate()
+	require.Len(t, errs, 2)
+	require.ErrorIs(t, errs[0], ErrUnknownInstanceType)
+	require.ErrorContains(t, errs[0], "unknown in availability of region_1")
+	require.ErrorIs(t, errs[1], ErrUnknownInstanceType)
+}
+
+func TestValidateUnknownArchitecture(t *testing.T) {
+	i386 := smallType
+	i386.Architecture = ArchitectureTypeI386
+	info := newTestTypeInfo(i386)
+
+	errs := info.Validate()
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], ErrUnknownArchitecture)
+}
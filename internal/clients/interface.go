@@ -50,6 +50,11 @@ type ImageBuilder interface {
 	// It also verifies the image is built successfully and for the right architecture.
 	GetGCPImageName(ctx context.Context, composeUUID uuid.UUID, instanceType InstanceType) (string, error)
 
+	// ListImages returns successfully built composes and clones for given provider. Only the
+	// most recent composes are listed. The result is cached per organization and provider for
+	// a few minutes and must be treated as read-only.
+	ListImages(ctx context.Context, provider models.ProviderType) ([]*Image, error)
+
 	// Ready returns readiness information
 	Ready(ctx context.Context) error
 }
@@ -76,6 +81,9 @@ type EC2 interface {
 	// ListAllZones returns list of all EC2 zones within a Region.
 	ListAllZones(ctx context.Context, region Region) ([]Zone, error)
 
+	// ListRegionDetails returns list of all EC2 regions with opt-in status, zones are not listed.
+	ListRegionDetails(ctx context.Context) ([]RegionDetail, error)
+
 	// ImportPubkey imports new ssh key-pair with given tag returning its AWS ID.
 	ImportPubkey(ctx context.Context, key *models.Pubkey, tag string) (string, error)
 
@@ -103,6 +111,12 @@ type EC2 interface {
 	CheckPermission(ctx context.Context, auth *Authentication) ([]string, error)
 
 	DescribeInstanceDetails(ctx context.Context, InstanceIds []string) ([]*InstanceDescription, error)
+
+	// ListInstancesByTag returns instances with the tag which were not terminated.
+	ListInstancesByTag(ctx context.Context, key, value string) ([]*InstanceDescription, error)
+
+	// ListImages returns available images owned by or shared with the account in the client region.
+	ListImages(ctx context.Context) ([]*Image, error)
 }
 
 // GetAzureClient returns an Azure client with customer's subscription ID.
@@ -125,6 +139,16 @@ type Azure interface {
 	CreateVMs(ctx context.Context, instanceParams AzureInstanceParams, amount int64, vmNamePrefix string) (vmIds []InstanceDescription, err error)
 
 	ListResourceGroups(ctx context.Context) ([]string, error)
+
+	// ListVMsByTag returns virtual machines of the subscription with the tag. Only instance IDs
+	// are filled in.
+	ListVMsByTag(ctx context.Context, key, value string) ([]InstanceDescription, error)
+
+	// ListRegionDetails returns list of physical locations available for the subscription with zones.
+	ListRegionDetails(ctx context.Context) ([]RegionDetail, error)
+
+	// ListImages returns image definitions from all compute galleries of the subscription.
+	ListImages(ctx context.Context) ([]*Image, error)
 }
 
 type ServiceAzure interface {
@@ -153,6 +177,9 @@ type GCP interface {
 	// ListAllRegions returns list of all GCP regions
 	ListAllRegions(ctx context.Context) ([]Region, error)
 
+	// ListRegionDetails returns list of all GCP regions with status and zones
+	ListRegionDetails(ctx context.Context) ([]RegionDetail, error)
+
 	// InsertInstances launches one or more instances and returns a list of instances ids that were created, the GCP operation name and error
 	InsertInstances(ctx context.Context, params *GCPInstanceParams, amount int64) ([]*string, *string, error)
 
@@ -163,4 +190,7 @@ type GCP interface {
 
 	// ListLaunchTemplates lists all launch templates and returns the next page token.
 	ListLaunchTemplates(ctx context.Context) ([]*LaunchTemplate, string, error)
+
+	// ListImages returns ready images of the project.
+	ListImages(ctx context.Context) ([]*Image, error)
 }
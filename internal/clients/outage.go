@@ -0,0 +1,49 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"google.golang.org/api/googleapi"
+)
+
+// IsOutage returns true for errors caused by an unavailable or degraded backend service rather
+// than by the account or the request: timeouts, network errors, throttling and 5xx responses.
+func IsOutage(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrUnexpectedBackendResponse) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// AWS SDK response errors
+	var awsErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &awsErr) {
+		return isOutageStatus(awsErr.HTTPStatusCode())
+	}
+
+	var azureErr *azcore.ResponseError
+	if errors.As(err, &azureErr) {
+		return isOutageStatus(azureErr.StatusCode)
+	}
+
+	var gcpErr *googleapi.Error
+	if errors.As(err, &gcpErr) {
+		return isOutageStatus(gcpErr.Code)
+	}
+
+	return false
+}
+
+func isOutageStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
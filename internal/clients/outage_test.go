@@ -0,0 +1,42 @@
+package clients_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+)
+
+var errAccessDenied = errors.New("access denied")
+
+func TestIsOutage(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		outage bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errAccessDenied, false},
+		{"timeout", fmt.Errorf("check: %w", context.DeadlineExceeded), true},
+		{"network", fmt.Errorf("check: %w", &net.OpError{Op: "dial", Err: errAccessDenied}), true},
+		{"sources", fmt.Errorf("sources: %w", clients.ErrUnexpectedBackendResponse), true},
+		{"not found", fmt.Errorf("sources: %w", clients.ErrNotFound), false},
+		{"azure unavailable", &azcore.ResponseError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"azure forbidden", &azcore.ResponseError{StatusCode: http.StatusForbidden}, false},
+		{"gcp throttled", fmt.Errorf("regions: %w", &googleapi.Error{Code: http.StatusTooManyRequests}), true},
+		{"gcp forbidden", &googleapi.Error{Code: http.StatusForbidden}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.outage, clients.IsOutage(tt.err))
+		})
+	}
+}
@@ -15,3 +15,22 @@ type Zone string
 func (z Zone) String() string {
 	return string(z)
 }
+
+// RegionDetail is a region with its availability for a customer account.
+type RegionDetail struct {
+	Name Region
+
+	// Enabled is false for regions which must be enabled by the account owner first (e.g. AWS
+	// opt-in regions) or which are not available (e.g. GCP regions which are down).
+	Enabled bool
+
+	// Zones of the region, can be empty when the provider does not support listing.
+	Zones []Zone
+}
+
+// SourceRegions are regions available for a source.
+type SourceRegions []RegionDetail
+
+func (SourceRegions) CacheKeyName() string {
+	return "source_regions"
+}
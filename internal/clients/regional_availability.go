@@ -2,9 +2,9 @@ package clients
 
 import (
 	"bytes"
-	"embed"
 	"errors"
 	"fmt"
+	"io/fs"
 	"path/filepath"
 	"strings"
 
@@ -49,6 +49,12 @@ func key(region, zone string) string {
 	return region + regionSeparator + zone
 }
 
+// Contains returns true when a region or zone key (e.g. "westeurope_1") is present.
+func (rit *RegionalTypeAvailability) Contains(raz string) bool {
+	_, ok := rit.types[raz]
+	return ok
+}
+
 func (rit *RegionalTypeAvailability) NamesForZone(region, zone string) ([]InstanceTypeName, error) {
 	result, ok := rit.types[key(region, zone)]
 	if !ok {
@@ -57,6 +63,35 @@ func (rit *RegionalTypeAvailability) NamesForZone(region, zone string) ([]Instan
 	return result, nil
 }
 
+// inRegion returns true when the key is the region itself or its zone. Zones are stored either
+// with the region separator (Azure "westeurope_1") or as a single letter dash suffix (GCP "us-east1-b").
+func inRegion(raz, region string) bool {
+	if raz == region || strings.HasPrefix(raz, region+regionSeparator) {
+		return true
+	}
+	zone, found := strings.CutPrefix(raz, region+"-")
+	return found && len(zone) == 1
+}
+
+// NamesForRegion returns sorted names available in any zone of a region.
+func (rit *RegionalTypeAvailability) NamesForRegion(region string) ([]InstanceTypeName, error) {
+	var result []InstanceTypeName
+	found := false
+	for raz, names := range rit.types {
+		if !inRegion(raz, region) {
+			continue
+		}
+		found = true
+		result = append(result, names...)
+	}
+	if !found {
+		return nil, ErrUnknownRegionZoneCombination
+	}
+
+	slices.Sort(result)
+	return slices.Compact(result), nil
+}
+
 func (rit *RegionalTypeAvailability) Add(region, zone string, it InstanceType) {
 	raz := key(region, zone)
 	if _, ok := rit.types[raz]; !ok {
@@ -82,10 +117,10 @@ func (rit *RegionalTypeAvailability) Save(directory string) error {
 	return nil
 }
 
-func (rit *RegionalTypeAvailability) Load(fsTypes embed.FS, path string) error {
+func (rit *RegionalTypeAvailability) Load(fsTypes fs.FS, path string) error {
 	rit.types = make(map[string]sortableInstanceTypeName)
 
-	dirEntries, err := fsTypes.ReadDir(path)
+	dirEntries, err := fs.ReadDir(fsTypes, path)
 	if err != nil {
 		return fmt.Errorf("unable to read availability dir: %w", err)
 	}
@@ -95,7 +130,7 @@ func (rit *RegionalTypeAvailability) Load(fsTypes embed.FS, path string) error {
 			continue
 		}
 		file := filepath.Join(path, dirEntry.Name())
-		buffer, err := fsTypes.ReadFile(file)
+		buffer, err := fs.ReadFile(fsTypes, file)
 		if err != nil {
 			return fmt.Errorf("unable to read availability file %s: %w", file, err)
 		}
@@ -151,22 +186,23 @@ func (rit *RegionalTypeAvailability) Sprint(fRegion, fZone string) string {
 	return sb.String()
 }
 
-func ConcatBuffers(fsTypes embed.FS, path string) []byte {
+// ConcatBuffers returns content of all files in a directory concatenated.
+func ConcatBuffers(fsTypes fs.FS, path string) ([]byte, error) {
 	result := bytes.NewBuffer(make([]byte, 0))
-	dirEntries, err := fsTypes.ReadDir(path)
+	dirEntries, err := fs.ReadDir(fsTypes, path)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("unable to read directory %s: %w", path, err)
 	}
 	for _, dirEntry := range dirEntries {
 		if dirEntry.IsDir() {
 			continue
 		}
 		file := filepath.Join(path, dirEntry.Name())
-		buffer, errBuf := fsTypes.ReadFile(file)
+		buffer, errBuf := fs.ReadFile(fsTypes, file)
 		if errBuf != nil {
-			panic(errBuf)
+			return nil, fmt.Errorf("unable to read file %s: %w", file, errBuf)
 		}
 		result.Write(buffer)
 	}
-	return result.Bytes()
+	return result.Bytes(), nil
 }
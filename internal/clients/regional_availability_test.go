@@ -1,7 +1,9 @@
 package clients
 
 import (
+	"io/fs"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/require"
 )
@@ -41,3 +43,16 @@ func TestAddSmallDifferentZones(t *testing.T) {
 	require.Equal(t, "\nRegion 'region' availability zone 'zone1': small\n", rit.Sprint("region", "zone1"))
 	require.Equal(t, "\nRegion 'region' availability zone 'zone2': small\n", rit.Sprint("region", "zone2"))
 }
+
+func TestConcatBuffers(t *testing.T) {
+	fsys := fstest.MapFS{
+		"avail/a.yaml": {Data: []byte("- a\n")},
+		"avail/b.yaml": {Data: []byte("- b\n")},
+	}
+	buf, err := ConcatBuffers(fsys, "avail")
+	require.NoError(t, err)
+	require.Equal(t, "- a\n- b\n", string(buf))
+
+	_, err = ConcatBuffers(fsys, "missing")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 
+	"golang.org/x/exp/slices"
 	"gopkg.in/yaml.v3"
 )
 
@@ -48,6 +49,25 @@ func (rit *RegisteredInstanceTypes) Get(name InstanceTypeName) *InstanceType {
 	return rit.types[name]
 }
 
+// All returns all registered instance types in random order.
+func (rit *RegisteredInstanceTypes) All() []*InstanceType {
+	result := make([]*InstanceType, 0, len(rit.types))
+	for _, it := range rit.types {
+		result = append(result, it)
+	}
+	return result
+}
+
+// names returns sorted names of all registered types.
+func (rit *RegisteredInstanceTypes) names() []InstanceTypeName {
+	result := make([]InstanceTypeName, 0, len(rit.types))
+	for name := range rit.types {
+		result = append(result, name)
+	}
+	slices.Sort(result)
+	return result
+}
+
 // Load existing instances from YAML buffer
 func (rit *RegisteredInstanceTypes) Load(buffer []byte) error {
 	err := yaml.Unmarshal(buffer, &rit.types)
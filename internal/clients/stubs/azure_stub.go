@@ -44,6 +44,16 @@ func (stub *AzureClientStub) Status(ctx context.Context) error {
 	return nil
 }
 
+func (stub *AzureClientStub) ListVMsByTag(ctx context.Context, key, value string) ([]clients.InstanceDescription, error) {
+	var list []clients.InstanceDescription
+	for _, vm := range stub.createdVms {
+		if tag, ok := vm.Tags[key]; ok && tag != nil && *tag == value {
+			list = append(list, clients.InstanceDescription{ID: *vm.ID})
+		}
+	}
+	return list, nil
+}
+
 func (stub *AzureClientStub) CreateVMs(ctx context.Context, vmParams clients.AzureInstanceParams, amount int64, vmNamePrefix string) ([]clients.InstanceDescription, error) {
 	vmIds := make([]clients.InstanceDescription, amount)
 	resumeTokens := make([]string, amount)
@@ -69,12 +79,13 @@ func (stub *AzureClientStub) CreateVMs(ctx context.Context, vmParams clients.Azu
 }
 
 func (stub *AzureClientStub) BeginCreateVM(ctx context.Context, vmParams clients.AzureInstanceParams, vmName string) (string, error) {
-	id := "with-polling-" + strconv.Itoa(len(stub.startedVms)+1)
+	id := "with-polling-" + strconv.Itoa(len(stub.startedVms)+len(stub.createdVms)+1)
 
 	vm := armcompute.VirtualMachine{
 		ID:       &id,
 		Name:     &vmName,
 		Location: &vmParams.Location,
+		Tags:     vmParams.Tags,
 	}
 	stub.startedVms = append(stub.startedVms, &vm)
 	// we use the id as a resume token
@@ -115,3 +126,21 @@ func (stub *AzureClientStub) TenantId(ctx context.Context) (clients.AzureTenantI
 func (stub *AzureClientStub) ListResourceGroups(ctx context.Context) ([]string, error) {
 	return []string{"firstGroup", "secondGroup", "test"}, nil
 }
+
+func (stub *AzureClientStub) ListRegionDetails(ctx context.Context) ([]clients.RegionDetail, error) {
+	return []clients.RegionDetail{
+		{Name: "eastus", Enabled: true, Zones: []clients.Zone{"eastus_1", "eastus_2", "eastus_3"}},
+		{Name: "westeurope", Enabled: true, Zones: []clients.Zone{"westeurope_1", "westeurope_2", "westeurope_3"}},
+	}, nil
+}
+
+func (stub *AzureClientStub) ListImages(ctx context.Context) ([]*clients.Image, error) {
+	return []*clients.Image{
+		{
+			ID:           "/subscriptions/subUUID/resourceGroups/myTestGroup/providers/Microsoft.Compute/galleries/myGallery/images/rhel-9",
+			Name:         "myGallery/rhel-9",
+			Origin:       clients.ImageOriginAccount,
+			Architecture: clients.ArchitectureTypeX86_64,
+		},
+	}, nil
+}
@@ -20,6 +20,7 @@ const ec2CtxKey ec2CtxKeyType = iota
 
 type EC2ClientStub struct {
 	Imported []*types.KeyPairInfo
+	Tagged   map[string][]*clients.InstanceDescription
 }
 
 func init() {
@@ -41,6 +42,19 @@ func AddStubbedEC2KeyPair(ctx context.Context, info *types.KeyPairInfo) error {
 	return nil
 }
 
+// AddStubbedEC2TaggedInstance adds an instance returned by ListInstancesByTag for the tag.
+func AddStubbedEC2TaggedInstance(ctx context.Context, key, value string, instance *clients.InstanceDescription) error {
+	si, err := getEC2StubFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if si.Tagged == nil {
+		si.Tagged = make(map[string][]*clients.InstanceDescription)
+	}
+	si.Tagged[key+"="+value] = append(si.Tagged[key+"="+value], instance)
+	return nil
+}
+
 func newEC2ServiceClientStubWithRegion(ctx context.Context, region string) (clients.EC2, error) {
 	return nil, nil
 }
@@ -133,6 +147,23 @@ func (mock *EC2ClientStub) ListAllZones(ctx context.Context, region clients.Regi
 	}, nil
 }
 
+func (mock *EC2ClientStub) ListRegionDetails(ctx context.Context) ([]clients.RegionDetail, error) {
+	return []clients.RegionDetail{
+		{Name: "us-east-1", Enabled: true},
+		{Name: "eu-central-1", Enabled: true},
+		{Name: "af-south-1", Enabled: false},
+	}, nil
+}
+
+func (mock *EC2ClientStub) ListImages(ctx context.Context) ([]*clients.Image, error) {
+	return []*clients.Image{
+		{ID: "ami-0c830793775595d4b-test", Name: "my-rhel-9", Origin: clients.ImageOriginAccount, Architecture: clients.ArchitectureTypeX86_64, Region: "us-east-1"},
+		{ID: "ami-0e1d7ed4d9f3b5a2c-test", Name: "shared-rhel-9-arm", Origin: clients.ImageOriginShared, Architecture: clients.ArchitectureTypeArm64, Region: "us-east-1"},
+		// AMI of the Image Builder stub image shared into the account
+		{ID: "ami-0b8e2a4c6d1f3e5a7-test", Name: "rhel-9-test", Origin: clients.ImageOriginShared, Architecture: clients.ArchitectureTypeX86_64, Region: "us-east-1"},
+	}, nil
+}
+
 func (mock *EC2ClientStub) ListInstanceTypes(ctx context.Context) ([]*clients.InstanceType, error) {
 	return []*clients.InstanceType{
 		{
@@ -186,6 +217,10 @@ func (mock *EC2ClientStub) RunInstances(ctx context.Context, details *clients.AW
 	return nil, nil, nil
 }
 
+func (mock *EC2ClientStub) ListInstancesByTag(ctx context.Context, key, value string) ([]*clients.InstanceDescription, error) {
+	return mock.Tagged[key+"="+value], nil
+}
+
 func (mock *EC2ClientStub) GetAccountId(ctx context.Context) (string, error) {
 	return "", nil
 }
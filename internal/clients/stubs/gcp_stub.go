@@ -76,6 +76,24 @@ func (mock *GCPClientStub) ListAllRegions(ctx context.Context) ([]clients.Region
 	return nil, nil
 }
 
+func (mock *GCPClientStub) ListRegionDetails(ctx context.Context) ([]clients.RegionDetail, error) {
+	return []clients.RegionDetail{
+		{Name: "us-east1", Enabled: true, Zones: []clients.Zone{"us-east1-b", "us-east1-c", "us-east1-d"}},
+		{Name: "us-west1", Enabled: true, Zones: []clients.Zone{"us-west1-a", "us-west1-b", "us-west1-c"}},
+	}, nil
+}
+
+func (mock *GCPClientStub) ListImages(ctx context.Context) ([]*clients.Image, error) {
+	return []*clients.Image{
+		{
+			ID:           "projects/test-project/global/images/rhel-9",
+			Name:         "rhel-9",
+			Origin:       clients.ImageOriginAccount,
+			Architecture: clients.ArchitectureTypeX86_64,
+		},
+	}, nil
+}
+
 func (mock *GCPClientStub) Status(ctx context.Context) error {
 	return nil
 }
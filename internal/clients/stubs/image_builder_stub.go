@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
 	"github.com/google/uuid"
 )
 
@@ -45,3 +46,17 @@ func (mock *ImageBuilderClientStub) GetAzureImageInfo(ctx context.Context, compo
 func (mock *ImageBuilderClientStub) GetGCPImageName(ctx context.Context, composeUUID uuid.UUID, instanceType clients.InstanceType) (string, error) {
 	return "projects/red-hat-image-builder/global/images/composer-api-871fa36d-0b5b-4001-8c95-a11f751a4d66-test", nil
 }
+
+func (mock *ImageBuilderClientStub) ListImages(ctx context.Context, provider models.ProviderType) ([]*clients.Image, error) {
+	image := &clients.Image{
+		ID:           "a3d9e4b2-4a6c-4fc3-8e0e-6b1e4cf1b9c1",
+		Name:         "rhel-9-test",
+		Origin:       clients.ImageOriginImageBuilder,
+		Architecture: clients.ArchitectureTypeX86_64,
+	}
+	if provider == models.ProviderTypeAWS {
+		image.Region = "us-east-1"
+		image.ProviderID = "ami-0b8e2a4c6d1f3e5a7-test"
+	}
+	return []*clients.Image{image}, nil
+}
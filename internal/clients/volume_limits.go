@@ -0,0 +1,145 @@
+package clients
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	ErrUnknownVolumeType = errors.New("unknown volume type")
+	ErrInvalidVolumeSize = errors.New("volume size out of range")
+	ErrTooManyVolumes    = errors.New("too many volumes")
+	ErrInvalidRootVolume = errors.New("volume type cannot be used as root volume")
+)
+
+// SizeRange is an inclusive range of volume sizes in GiB.
+type SizeRange struct {
+	Min int32
+	Max int32
+}
+
+// VolumeLimits are provider limits for root and data volumes.
+type VolumeLimits struct {
+	// Types contains sizes of all data volume types.
+	Types map[string]SizeRange
+
+	// RootTypes are types which can be used for the root volume.
+	RootTypes []string
+
+	// RootMax is the maximum root volume size, zero when same as the type maximum.
+	RootMax int32
+
+	// DefaultType is used for data volumes without a type.
+	DefaultType string
+
+	// MaxVolumes is the maximum amount of data volumes.
+	MaxVolumes int
+}
+
+// AWSVolumeLimits are EBS limits, data volumes are attached as /dev/sdf to /dev/sdp.
+var AWSVolumeLimits = VolumeLimits{
+	Types: map[string]SizeRange{
+		"gp2":      {Min: 1, Max: 16384},
+		"gp3":      {Min: 1, Max: 16384},
+		"io1":      {Min: 4, Max: 16384},
+		"io2":      {Min: 4, Max: 16384},
+		"st1":      {Min: 125, Max: 16384},
+		"sc1":      {Min: 125, Max: 16384},
+		"standard": {Min: 1, Max: 1024},
+	},
+	RootTypes:   []string{"gp2", "gp3", "io1", "io2", "standard"},
+	DefaultType: "gp3",
+	MaxVolumes:  11,
+}
+
+// GCPVolumeLimits are persistent disk limits.
+var GCPVolumeLimits = VolumeLimits{
+	Types: map[string]SizeRange{
+		"pd-standard": {Min: 10, Max: 65536},
+		"pd-balanced": {Min: 10, Max: 65536},
+		"pd-ssd":      {Min: 10, Max: 65536},
+		"pd-extreme":  {Min: 500, Max: 65536},
+	},
+	RootTypes:   []string{"pd-standard", "pd-balanced", "pd-ssd", "pd-extreme"},
+	DefaultType: "pd-balanced",
+	MaxVolumes:  16,
+}
+
+// AzureVolumeLimits are managed disk limits. The amount of data disks is also limited by the VM
+// size, smaller sizes support less than the maximum.
+var AzureVolumeLimits = VolumeLimits{
+	Types: map[string]SizeRange{
+		"Standard_LRS":    {Min: 1, Max: 32767},
+		"StandardSSD_LRS": {Min: 1, Max: 32767},
+		"StandardSSD_ZRS": {Min: 1, Max: 32767},
+		"Premium_LRS":     {Min: 1, Max: 32767},
+		"Premium_ZRS":     {Min: 1, Max: 32767},
+	},
+	RootTypes:   []string{"Standard_LRS", "StandardSSD_LRS", "StandardSSD_ZRS", "Premium_LRS", "Premium_ZRS"},
+	RootMax:     4095,
+	DefaultType: "Premium_LRS",
+	MaxVolumes:  16,
+}
+
+// Apply sets default type of data volumes and validates root and data volumes against limits.
+func (l *VolumeLimits) Apply(root *models.RootVolume, volumes []models.Volume) error {
+	if root != nil {
+		if err := l.validateRoot(root); err != nil {
+			return err
+		}
+	}
+
+	if len(volumes) > l.MaxVolumes {
+		return fmt.Errorf("%w: maximum is %d", ErrTooManyVolumes, l.MaxVolumes)
+	}
+	for i := range volumes {
+		if volumes[i].Type == "" {
+			volumes[i].Type = l.DefaultType
+		}
+		if err := l.validateSize(volumes[i].Type, volumes[i].SizeGiB, 0); err != nil {
+			return fmt.Errorf("volume %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (l *VolumeLimits) validateRoot(root *models.RootVolume) error {
+	if root.Type != "" {
+		if !slices.Contains(l.RootTypes, root.Type) {
+			return fmt.Errorf("%w: %s", ErrInvalidRootVolume, root.Type)
+		}
+	}
+
+	// zero keeps the image size
+	if root.SizeGiB == 0 {
+		return nil
+	}
+
+	// type of the image root volume is not known, validate against the default type
+	rootType := root.Type
+	if rootType == "" {
+		rootType = l.DefaultType
+	}
+	if err := l.validateSize(rootType, root.SizeGiB, l.RootMax); err != nil {
+		return fmt.Errorf("root volume: %w", err)
+	}
+	return nil
+}
+
+func (l *VolumeLimits) validateSize(volumeType string, size int32, max int32) error {
+	sizeRange, ok := l.Types[volumeType]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownVolumeType, volumeType)
+	}
+	if max != 0 && max < sizeRange.Max {
+		sizeRange.Max = max
+	}
+	if size < sizeRange.Min || size > sizeRange.Max {
+		return fmt.Errorf("%w: %s must be between %d and %d GiB", ErrInvalidVolumeSize, volumeType, sizeRange.Min, sizeRange.Max)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package clients_test
+
+import (
+	"testing"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolumeLimitsApply(t *testing.T) {
+	t.Run("sets default data volume type", func(t *testing.T) {
+		volumes := []models.Volume{{SizeGiB: 100}}
+		err := clients.AWSVolumeLimits.Apply(nil, volumes)
+		require.NoError(t, err)
+		require.Equal(t, "gp3", volumes[0].Type)
+	})
+
+	t.Run("root volume without type keeps image type", func(t *testing.T) {
+		root := &models.RootVolume{SizeGiB: 50}
+		err := clients.GCPVolumeLimits.Apply(root, nil)
+		require.NoError(t, err)
+		require.Empty(t, root.Type)
+	})
+
+	t.Run("fails on unknown type", func(t *testing.T) {
+		err := clients.AWSVolumeLimits.Apply(nil, []models.Volume{{SizeGiB: 100, Type: "pd-ssd"}})
+		require.ErrorIs(t, err, clients.ErrUnknownVolumeType)
+	})
+
+	t.Run("fails on size out of range", func(t *testing.T) {
+		err := clients.AWSVolumeLimits.Apply(nil, []models.Volume{{SizeGiB: 100, Type: "st1"}})
+		require.ErrorIs(t, err, clients.ErrInvalidVolumeSize)
+
+		err = clients.GCPVolumeLimits.Apply(nil, []models.Volume{{SizeGiB: 5}})
+		require.ErrorIs(t, err, clients.ErrInvalidVolumeSize)
+	})
+
+	t.Run("fails on root volume over root maximum", func(t *testing.T) {
+		err := clients.AzureVolumeLimits.Apply(&models.RootVolume{SizeGiB: 5000, Type: "Premium_LRS"}, nil)
+		require.ErrorIs(t, err, clients.ErrInvalidVolumeSize)
+	})
+
+	t.Run("fails on type not usable as root", func(t *testing.T) {
+		err := clients.AWSVolumeLimits.Apply(&models.RootVolume{SizeGiB: 500, Type: "sc1"}, nil)
+		require.ErrorIs(t, err, clients.ErrInvalidRootVolume)
+	})
+
+	t.Run("fails on too many volumes", func(t *testing.T) {
+		volumes := make([]models.Volume, 12)
+		err := clients.AWSVolumeLimits.Apply(nil, volumes)
+		require.ErrorIs(t, err, clients.ErrTooManyVolumes)
+	})
+
+}
@@ -25,8 +25,8 @@ var config struct {
 			Enabled bool `env:"ENABLED" env-default:"false" env-description:"notifications enabled"`
 		} `env-prefix:"NOTIFICATIONS_"`
 		Cache struct {
-			Type       string        `env:"TYPE" env-default:"none" env-description:"application cache (none, redis)"`
-			Expiration time.Duration `env:"EXPIRATION" env-default:"10m" env-description:"expiration for Redis application cache (time interval syntax)"`
+			Type       string        `env:"TYPE" env-default:"none" env-description:"application cache (none, redis, memory)"`
+			Expiration time.Duration `env:"EXPIRATION" env-default:"10m" env-description:"expiration for application cache (time interval syntax)"`
 			Redis      struct {
 				Host     string `env:"HOST" env-default:"localhost" env-description:"redis hostname"`
 				Port     int    `env:"PORT" env-default:"6379" env-description:"redis port"`
@@ -36,17 +36,28 @@ var config struct {
 			} `env-prefix:"REDIS_"`
 			Memory struct {
 				CleanupInterval time.Duration `env:"CLEANUP_INTERVAL" env-default:"5m" env-description:"in-memory expiration interval (time interval syntax)"`
+				Size            int           `env:"SIZE" env-default:"10000" env-description:"maximum number of items in in-memory cache"`
+				PubSub          bool          `env:"PUBSUB" env-default:"false" env-description:"broadcast invalidations to other replicas via redis pub/sub (REDIS_ settings are used)"`
 			} `env-prefix:"MEM_"`
 		} `env-prefix:"CACHE_"`
+		InstanceTypes struct {
+			Path            string        `env:"PATH" env-default:"" env-description:"directory with instance type data overriding embedded data, same layout as internal/preload (reloaded on SIGHUP)"`
+			RefreshInterval time.Duration `env:"REFRESH_INTERVAL" env-default:"0" env-description:"how often to reload instance type data from path, zero disables periodic reload (time interval syntax)"`
+		} `env-prefix:"INSTANCE_TYPES_"`
 	} `env-prefix:"APP_"`
 	Stats struct {
 		JobQueue             time.Duration `env:"JOBQUEUE_INTERVAL" env-default:"1m" env-description:"how often to pull job queue statistics"`
 		ReservationsInterval time.Duration `env:"RESERVATIONS_INTERVAL" env-default:"10m" env-description:"how often to pull reservation statistics"`
 	} `env-prefix:"STATS_"`
 	Reservation struct {
-		CleanupEnabled  bool          `env:"CLEANUP_ENABLED" env-default:"false" env-description:"reservation cleanup enabled"`
-		Lifetime        time.Duration `env:"LIFETIME" env-default:"8760h" env-description:"how old reservation should be deleted, default equal to 365 days"`
-		CleanupInterval time.Duration `env:"CLEANUP_INTERVAL" env-default:"1h" env-description:"how often to cleanup the reservation"`
+		CleanupEnabled    bool          `env:"CLEANUP_ENABLED" env-default:"false" env-description:"reservation cleanup enabled"`
+		Lifetime          time.Duration `env:"LIFETIME" env-default:"8760h" env-description:"how old reservation should be deleted, default equal to 365 days"`
+		CleanupInterval   time.Duration `env:"CLEANUP_INTERVAL" env-default:"1h" env-description:"how often to cleanup the reservation"`
+		SchedulerEnabled  bool          `env:"SCHEDULER_ENABLED" env-default:"true" env-description:"launch scheduled reservations (stats process)"`
+		SchedulerInterval time.Duration `env:"SCHEDULER_INTERVAL" env-default:"1m" env-description:"how often to check for scheduled reservations"`
+		ReaperEnabled     bool          `env:"REAPER_ENABLED" env-default:"true" env-description:"finish reservations of launch jobs which never finished (stats process)"`
+		ReaperInterval    time.Duration `env:"REAPER_INTERVAL" env-default:"10m" env-description:"how often to check for stale reservations"`
+		ReaperMargin      time.Duration `env:"REAPER_MARGIN" env-default:"15m" env-description:"how long after the worker timeout a pending reservation is considered stale"`
 	} `env-prefix:"RESERVATION_"`
 	Database struct {
 		Host         string        `env:"HOST" env-default:"localhost" env-description:"main database hostname"`
@@ -115,10 +126,26 @@ var config struct {
 		AvailabilityDelay time.Duration `env:"AVAILABILITY_DELAY" env-default:"1s" env-description:"arbitrary delay between sources availability checks (time interval syntax)"`
 		AvailabilityRate  float32       `env:"AVAILABILITY_RATE" env-default:"1.0" env-description:"probability rate for availability checks (0.0 = all skipped, 1.0 = nothing skipped)"`
 	} `env-prefix:"GCP_"`
+	AvailabilityBreaker struct {
+		FailureRate    float64       `env:"FAILURE_RATE" env-default:"0.5" env-description:"rate of failed availability checks caused by provider or sources outage which opens the circuit breaker (0 disables breakers)"`
+		Window         int           `env:"WINDOW" env-default:"20" env-description:"number of last availability checks used to calculate the failure rate"`
+		MinRequests    int           `env:"MIN_REQUESTS" env-default:"10" env-description:"minimum number of availability checks before the circuit breaker can open"`
+		OpenTimeout    time.Duration `env:"OPEN_TIMEOUT" env-default:"30s" env-description:"how long availability checks are skipped after the breaker opens, doubled after every failed probe"`
+		MaxOpenTimeout time.Duration `env:"MAX_OPEN_TIMEOUT" env-default:"10m" env-description:"maximum time availability checks are skipped by an open breaker"`
+		MaxBackoff     time.Duration `env:"MAX_BACKOFF" env-default:"30s" env-description:"maximum delay between availability checks, the delay grows exponentially with the failure rate"`
+	} `env-prefix:"AVAILABILITY_BREAKER_"`
+	AvailabilityDebounce struct {
+		Window    time.Duration `env:"WINDOW" env-default:"10s" env-description:"repeated availability check requests of the same source within the window are dropped by the API (0 disables)"`
+		ResultTTL time.Duration `env:"RESULT_TTL" env-default:"1m" env-description:"how long the statuser reuses the last availability check result of a source, requires application cache (0 disables)"`
+	} `env-prefix:"AVAILABILITY_DEBOUNCE_"`
 	Prometheus struct {
 		Port int    `env:"PORT" env-default:"9000" env-description:"prometheus HTTP port"`
 		Path string `env:"PATH" env-default:"/metrics" env-description:"prometheus metrics path"`
 	} `env-prefix:"PROMETHEUS_"`
+	Admin struct {
+		Port int    `env:"PORT" env-default:"0" env-description:"HTTP port of internal operator endpoints like job queue management (0 disables them)"`
+		PSK  string `env:"PSK" env-default:"" env-description:"pre-shared key required in X-Admin-PSK header of internal operator endpoints (required when enabled)"`
+	} `env-prefix:"ADMIN_"`
 	RestEndpoints struct {
 		RBAC struct {
 			URL      string `env:"URL" env-default:"" env-description:"RBAC URL"`
@@ -141,10 +168,14 @@ var config struct {
 		TraceData bool `env:"TRACE_DATA" env-default:"true" env-description:"open telemetry HTTP context pass and trace"`
 	} `env-prefix:"REST_ENDPOINTS_"`
 	Worker struct {
-		Queue        string        `env:"QUEUE" env-default:"memory" env-description:"job worker implementation (memory, redis, sqs, postgres)"`
-		PollInterval time.Duration `env:"POLL_INTERVAL" env-default:"5s" env-description:"polling interval (network timeout)"`
-		Concurrency  int           `env:"CONCURRENCY" env-default:"33" env-description:"amount of worker polling goroutines (effective concurrency)"`
-		Timeout      time.Duration `env:"TIMEOUT" env-default:"30m" env-description:"total timeout for a single job to complete (duration)"`
+		Queue              string        `env:"QUEUE" env-default:"memory" env-description:"job worker implementation (memory, redis, sqs, postgres)"`
+		PollInterval       time.Duration `env:"POLL_INTERVAL" env-default:"5s" env-description:"polling interval (network timeout)"`
+		Concurrency        int           `env:"CONCURRENCY" env-default:"33" env-description:"amount of worker polling goroutines (effective concurrency)"`
+		AccountConcurrency int           `env:"ACCOUNT_CONCURRENCY" env-default:"0" env-description:"maximum in-flight jobs of a single account in the redis queue (0 disables the limit)"`
+		Timeout            time.Duration `env:"TIMEOUT" env-default:"30m" env-description:"total timeout for a single job to complete (duration)"`
+		ShutdownTimeout    time.Duration `env:"SHUTDOWN_TIMEOUT" env-default:"20s" env-description:"how long to wait for in-flight jobs on shutdown before they are interrupted at the next step and re-queued (redis only, set 0 to wait for all jobs instead)"`
+		InterruptTimeout   time.Duration `env:"INTERRUPT_TIMEOUT" env-default:"20s" env-description:"how long interrupted jobs may finish their current step on shutdown before they are cancelled (redis only)"`
+		HistoryLifetime    time.Duration `env:"HISTORY_LIFETIME" env-default:"720h" env-description:"how long to keep job execution records, deleted by the stats process (0 keeps them forever)"`
 	} `env-prefix:"WORKER_"`
 	Unleash struct {
 		Enabled     bool   `env:"ENABLED" env-default:"false" env-description:"unleash service (feature flags)"`
@@ -184,11 +215,14 @@ var (
 	AWS           = &config.AWS
 	Azure         = &config.Azure
 	GCP           = &config.GCP
+	Breakers      = &config.AvailabilityBreaker
+	Debounce      = &config.AvailabilityDebounce
 	RestEndpoints = &config.RestEndpoints
 	ImageBuilder  = &config.RestEndpoints.ImageBuilder
 	Sources       = &config.RestEndpoints.Sources
 	RBAC          = &config.RestEndpoints.RBAC
 	Worker        = &config.Worker
+	Admin         = &config.Admin
 	Unleash       = &config.Unleash
 	Sentry        = &config.Sentry
 	Kafka         = &config.Kafka
@@ -198,6 +232,7 @@ var (
 var (
 	ErrValidateMissingSecret = errors.New("config error: Cloudwatch enabled but Region or Key or Secret are blank")
 	ErrValidateGroupStream   = errors.New("config error: Cloudwatch enabled but Group or Stream is blank")
+	ErrValidateAdminPSK      = errors.New("config error: Admin port set but PSK is blank")
 )
 
 var hostname string
@@ -125,6 +125,7 @@ func DumpConfig(logger zerolog.Logger) {
 	configCopy.Azure.ClientSecret = replacement
 	configCopy.GCP.JSON = replacement
 	configCopy.Unleash.Token = replacement
+	configCopy.Admin.PSK = replacement
 	configCopy.Kafka.SASL.Username = replacement
 	configCopy.Kafka.SASL.Password = replacement
 	// We want to know if the DSN was empty
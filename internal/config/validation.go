@@ -26,6 +26,10 @@ func validate() error {
 		}
 	}
 
+	if Admin.Port != 0 && Admin.PSK == "" {
+		return ErrValidateAdminPSK
+	}
+
 	slice, err := base64.StdEncoding.DecodeString(config.GCP.JSON)
 	config.GCP.JSON = string(slice)
 	if err != nil {
@@ -0,0 +1,192 @@
+// Package cron parses standard five-field cron expressions (minute, hour, day of month, month
+// and day of week) and calculates the next activation time. All times are evaluated in UTC.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidExpression = errors.New("invalid cron expression")
+	ErrInvalidField      = errors.New("invalid cron field")
+)
+
+// search for the next activation is limited, expressions like "0 0 30 2 *" never match
+const maxYears = 5
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type bounds struct {
+	name     string
+	min, max int
+}
+
+var (
+	minuteBounds = bounds{"minute", 0, 59}
+	hourBounds   = bounds{"hour", 0, 23}
+	domBounds    = bounds{"day of month", 1, 31}
+	monthBounds  = bounds{"month", 1, 12}
+	dowBounds    = bounds{"day of week", 0, 7}
+)
+
+// Schedule is a parsed cron expression, each field is a bit set of allowed values.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// day of month or day of week was restricted, when both are the day matches either of them
+	domStar, dowStar bool
+}
+
+// Parse parses a cron expression like "0 2 * * *" or one of macros like "@daily".
+func Parse(expression string) (*Schedule, error) {
+	expression = strings.TrimSpace(expression)
+	if macro, ok := macros[expression]; ok {
+		expression = macro
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: expected 5 fields, got %d", ErrInvalidExpression, len(fields))
+	}
+
+	var err error
+	s := &Schedule{}
+	if s.minute, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], domBounds); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], dowBounds); err != nil {
+		return nil, err
+	}
+
+	// both 0 and 7 are Sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	s.domStar = fields[2] == "*" || fields[2] == "?"
+	s.dowStar = fields[4] == "*" || fields[4] == "?"
+
+	return s, nil
+}
+
+func parseField(field string, b bounds) (uint64, error) {
+	var result uint64
+	for _, part := range strings.Split(field, ",") {
+		values, err := parsePart(part, b)
+		if err != nil {
+			return 0, err
+		}
+		result |= values
+	}
+	return result, nil
+}
+
+// parsePart parses "*", "N", "N-M" optionally followed by "/STEP"
+func parsePart(part string, b bounds) (uint64, error) {
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+	step := 1
+	if hasStep {
+		var err error
+		step, err = strconv.Atoi(stepPart)
+		if err != nil || step <= 0 {
+			return 0, fmt.Errorf("%w: %s step %q", ErrInvalidField, b.name, part)
+		}
+	}
+
+	var low, high int
+	switch {
+	case rangePart == "*" || rangePart == "?":
+		low, high = b.min, b.max
+	case strings.Contains(rangePart, "-"):
+		lowPart, highPart, _ := strings.Cut(rangePart, "-")
+		var lowErr, highErr error
+		low, lowErr = strconv.Atoi(lowPart)
+		high, highErr = strconv.Atoi(highPart)
+		if lowErr != nil || highErr != nil {
+			return 0, fmt.Errorf("%w: %s range %q", ErrInvalidField, b.name, part)
+		}
+	default:
+		value, err := strconv.Atoi(rangePart)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s value %q", ErrInvalidField, b.name, part)
+		}
+		low, high = value, value
+		// "N/STEP" means from N to the maximum
+		if hasStep {
+			high = b.max
+		}
+	}
+
+	if low < b.min || high > b.max || low > high {
+		return 0, fmt.Errorf("%w: %s %q out of range %d-%d", ErrInvalidField, b.name, part, b.min, b.max)
+	}
+
+	var result uint64
+	for i := low; i <= high; i += step {
+		result |= 1 << uint(i)
+	}
+	return result, nil
+}
+
+func has(set uint64, value int) bool {
+	return set&(1<<uint(value)) != 0
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := has(s.dom, t.Day())
+	dowMatch := has(s.dow, int(t.Weekday()))
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Next returns the first activation time strictly after the given time, or zero time when there
+// is no activation in the next five years.
+func (s *Schedule) Next(after time.Time) time.Time {
+	t := after.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + maxYears
+
+	for t.Year() <= limit {
+		if !has(s.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !has(s.hour, t.Hour()) {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if !has(s.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,56 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mustTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	result, err := time.Parse(time.RFC3339, value)
+	require.NoError(t, err)
+	return result
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		expression string
+		after      string
+		expected   string
+	}{
+		{"0 2 * * *", "2023-05-10T01:59:00Z", "2023-05-10T02:00:00Z"},
+		{"0 2 * * *", "2023-05-10T02:00:00Z", "2023-05-11T02:00:00Z"},
+		{"@daily", "2023-12-31T23:30:00Z", "2024-01-01T00:00:00Z"},
+		{"*/15 * * * *", "2023-05-10T10:07:30Z", "2023-05-10T10:15:00Z"},
+		{"30 8 * * 1-5", "2023-05-12T09:00:00Z", "2023-05-15T08:30:00Z"},
+		{"0 0 * * 7", "2023-05-10T00:00:00Z", "2023-05-14T00:00:00Z"},
+		{"0 0 29 2 *", "2023-03-01T00:00:00Z", "2024-02-29T00:00:00Z"},
+		{"0 12 1 * 1", "2023-05-02T00:00:00Z", "2023-05-08T12:00:00Z"},
+		{"5,10 1-3/2 * * *", "2023-05-10T01:07:00Z", "2023-05-10T01:10:00Z"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			s, err := Parse(test.expression)
+			require.NoError(t, err)
+			require.Equal(t, mustTime(t, test.expected), s.Next(mustTime(t, test.after)))
+		})
+	}
+}
+
+func TestNextNever(t *testing.T) {
+	s, err := Parse("0 0 30 2 *")
+	require.NoError(t, err)
+	require.True(t, s.Next(time.Now()).IsZero())
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, expression := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *", "@often"} {
+		t.Run(expression, func(t *testing.T) {
+			_, err := Parse(expression)
+			require.Error(t, err)
+		})
+	}
+}
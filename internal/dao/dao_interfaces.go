@@ -12,6 +12,7 @@ package dao
 
 import (
 	"context"
+	"time"
 
 	"github.com/RHEnVision/provisioning-backend/internal/clients"
 	"github.com/RHEnVision/provisioning-backend/internal/models"
@@ -45,6 +46,7 @@ type PubkeyDao interface {
 	UnscopedGetResourceBySourceAndRegion(ctx context.Context, pubkeyId int64, sourceId string, region string) (*models.PubkeyResource, error)
 	UnscopedListResourcesByPubkeyId(ctx context.Context, pkId int64) ([]*models.PubkeyResource, error)
 	UnscopedDeleteResource(ctx context.Context, id int64) error
+	UnscopedDeleteResourcesBySourceId(ctx context.Context, sourceId string) (int64, error)
 }
 
 var GetReservationDao func(ctx context.Context) ReservationDao
@@ -105,12 +107,34 @@ type ReservationDao interface {
 	// UpdateReservationInstance updates an instance with its description
 	UpdateReservationInstance(ctx context.Context, reservationID int64, instance *clients.InstanceDescription) error
 
+	// UnscopedCompleteStep records a finished launch job step. Recording the same step again is
+	// not an error. UNSCOPED.
+	UnscopedCompleteStep(ctx context.Context, id int64, step string) error
+
+	// UnscopedListCompletedSteps returns finished launch job steps of a reservation. UNSCOPED.
+	UnscopedListCompletedSteps(ctx context.Context, id int64) ([]string, error)
+
 	// FinishWithSuccess sets Success flag. UNSCOPED.
 	FinishWithSuccess(ctx context.Context, id int64) error
 
 	// FinishWithError sets Success flag and Error flag. UNSCOPED.
 	FinishWithError(ctx context.Context, id int64, errorString string) error
 
+	// UnscopedFailPendingBySourceId finishes all pending reservations of a given source with an error.
+	// Returns number of affected reservations. UNSCOPED.
+	UnscopedFailPendingBySourceId(ctx context.Context, sourceId string, errorString string) (int64, error)
+
+	// UnscopedMarkQueued records the time when the launch job of a reservation was enqueued. UNSCOPED.
+	UnscopedMarkQueued(ctx context.Context, id int64) error
+
+	// UnscopedListStale returns pending reservations queued longer than age ago, oldest first.
+	// Reservations waiting for a schedule or an approval are never queued. UNSCOPED.
+	UnscopedListStale(ctx context.Context, age time.Duration, limit int64) ([]*models.Reservation, error)
+
+	// UnscopedFinishPending finishes a reservation unless it was already finished. Returns false
+	// when the reservation was finished meanwhile. UNSCOPED.
+	UnscopedFinishPending(ctx context.Context, id int64, success bool, errorString string) (bool, error)
+
 	// Delete deletes a reservation. Only used in tests and background cleanup job. UNSCOPED.
 	Delete(ctx context.Context, id int64) error
 
@@ -118,6 +142,96 @@ type ReservationDao interface {
 	Cleanup(ctx context.Context) error
 }
 
+var GetScheduleDao func(ctx context.Context) ScheduleDao
+
+// ScheduleNextFn is called for every due schedule and returns time of the next run, or zero time
+// when the schedule is finished and should be deleted.
+type ScheduleNextFn func(ctx context.Context, schedule *models.ReservationSchedule) time.Time
+
+// ScheduleDao represents reservations launched at a later time or repeatedly.
+type ScheduleDao interface {
+	// Create creates a schedule for a reservation.
+	Create(ctx context.Context, schedule *models.ReservationSchedule) error
+
+	// GetByReservationId returns schedule of a reservation.
+	GetByReservationId(ctx context.Context, reservationId int64) (*models.ReservationSchedule, error)
+
+	// DeleteByReservationId deletes schedule of a reservation, no more launches will be done.
+	DeleteByReservationId(ctx context.Context, reservationId int64) error
+
+	// UnscopedClaimDue moves each schedule due at the given time to the next run time returned
+	// by the function and returns the due schedules as they were before the move. The change is
+	// committed before returning, so the caller launches the schedules only once even when
+	// launching fails half way. Only one process in the whole deployment can claim due schedules
+	// at a time, when another process holds the lock, false is returned and nothing is done. UNSCOPED.
+	UnscopedClaimDue(ctx context.Context, now time.Time, next ScheduleNextFn) ([]*models.ReservationSchedule, bool, error)
+}
+
+var GetApprovalDao func(ctx context.Context) ApprovalDao
+
+// ApprovalDao represents approval rules of an account and reservations waiting for approval.
+type ApprovalDao interface {
+	// CreateRule creates an approval rule.
+	CreateRule(ctx context.Context, rule *models.ApprovalRule) error
+
+	// ListRules returns all approval rules of an account.
+	ListRules(ctx context.Context) ([]*models.ApprovalRule, error)
+
+	// DeleteRule deletes an approval rule, reservations already waiting for approval are not affected.
+	DeleteRule(ctx context.Context, id int64) error
+
+	// Create creates an approval of a reservation.
+	Create(ctx context.Context, approval *models.ReservationApproval) error
+
+	// GetByReservationId returns approval of a reservation.
+	GetByReservationId(ctx context.Context, reservationId int64) (*models.ReservationApproval, error)
+
+	// Decide approves or rejects a pending approval. When the approval was already decided,
+	// ErrAffectedMismatch is returned.
+	Decide(ctx context.Context, approval *models.ReservationApproval) error
+
+	// Reject rejects a pending approval and finishes its reservation with the error message in
+	// one transaction. When the approval was already decided, ErrAffectedMismatch is returned.
+	Reject(ctx context.Context, approval *models.ReservationApproval, message string) error
+
+	// Reopen reverts the decision of an approval so it can be decided again. It is used when
+	// the launch of an approved reservation could not be enqueued or scheduled.
+	Reopen(ctx context.Context, reservationId int64) error
+}
+
+var GetPolicyDao func(ctx context.Context) PolicyDao
+
+// PolicyDao represents the launch policy of an account.
+type PolicyDao interface {
+	// Get returns the launch policy of an account, ErrNoRows is returned when it was not set.
+	Get(ctx context.Context) (*models.LaunchPolicy, error)
+
+	// Upsert creates or replaces the launch policy of an account.
+	Upsert(ctx context.Context, policy *models.LaunchPolicy) error
+
+	// Delete removes the launch policy of an account, ErrAffectedMismatch is returned when it was not set.
+	Delete(ctx context.Context) error
+}
+
+var GetJobExecutionDao func(ctx context.Context) JobExecutionDao
+
+// JobExecutionDao represents execution records of background jobs.
+type JobExecutionDao interface {
+	// UnscopedCreate creates a record of a started job attempt, the attempt number is set to the
+	// count of previous attempts of the same job plus one. UNSCOPED.
+	UnscopedCreate(ctx context.Context, execution *models.JobExecution) error
+
+	// UnscopedFinish stores finish time, result and error of a job attempt. UNSCOPED.
+	UnscopedFinish(ctx context.Context, execution *models.JobExecution) error
+
+	// ListByReservation returns all job attempts of a reservation ordered by start time.
+	ListByReservation(ctx context.Context, reservationId int64) ([]*models.JobExecution, error)
+
+	// UnscopedDeleteBefore deletes records of attempts started longer than lifetime ago and returns
+	// the number of deleted records. UNSCOPED.
+	UnscopedDeleteBefore(ctx context.Context, lifetime time.Duration) (int64, error)
+}
+
 var GetStatDao func(ctx context.Context) StatDao
 
 // StatDao represents stats about the application run
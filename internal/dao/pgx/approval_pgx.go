@@ -0,0 +1,184 @@
+package pgx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/db"
+	"github.com/RHEnVision/provisioning-backend/internal/identity"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+)
+
+func init() {
+	dao.GetApprovalDao = getApprovalDao
+}
+
+type approvalDao struct{}
+
+func getApprovalDao(ctx context.Context) dao.ApprovalDao {
+	return &approvalDao{}
+}
+
+func (x *approvalDao) CreateRule(ctx context.Context, rule *models.ApprovalRule) error {
+	if vError := models.Validate(ctx, rule); vError != nil {
+		return fmt.Errorf("validate: %w", vError)
+	}
+
+	query := `INSERT INTO approval_rules (account_id, name, max_amount, instance_types)
+		VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+	rule.AccountID = identity.AccountId(ctx)
+	if rule.InstanceTypes == nil {
+		rule.InstanceTypes = []string{}
+	}
+
+	err := db.Pool.QueryRow(ctx, query,
+		rule.AccountID,
+		rule.Name,
+		rule.MaxAmount,
+		rule.InstanceTypes).Scan(&rule.ID, &rule.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("pgx error: %w", err)
+	}
+
+	return nil
+}
+
+func (x *approvalDao) ListRules(ctx context.Context) ([]*models.ApprovalRule, error) {
+	query := `SELECT * FROM approval_rules WHERE account_id = $1 ORDER BY id`
+	accountId := identity.AccountId(ctx)
+	var result []*models.ApprovalRule
+
+	err := pgxscan.Select(ctx, db.Pool, &result, query, accountId)
+	if err != nil {
+		return nil, fmt.Errorf("pgx error: %w", err)
+	}
+	return result, nil
+}
+
+func (x *approvalDao) DeleteRule(ctx context.Context, id int64) error {
+	query := `DELETE FROM approval_rules WHERE account_id = $1 AND id = $2`
+	accountId := identity.AccountId(ctx)
+
+	tag, err := db.Pool.Exec(ctx, query, accountId, id)
+	if err != nil {
+		return fmt.Errorf("pgx error: %w", err)
+	}
+	if tag.RowsAffected() != 1 {
+		return fmt.Errorf("expected 1 row, got %d: %w", tag.RowsAffected(), dao.ErrAffectedMismatch)
+	}
+	return nil
+}
+
+func (x *approvalDao) Create(ctx context.Context, approval *models.ReservationApproval) error {
+	query := `INSERT INTO reservation_approvals
+		(reservation_id, account_id, reason, job_type, job_identity, job_edge_id, job_args, schedule_cron, schedule_next_run_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at`
+	approval.AccountID = identity.AccountId(ctx)
+
+	err := db.Pool.QueryRow(ctx, query,
+		approval.ReservationID,
+		approval.AccountID,
+		approval.Reason,
+		approval.JobType,
+		approval.JobIdentity,
+		approval.JobEdgeID,
+		approval.JobArgs,
+		approval.ScheduleCron,
+		approval.ScheduleNextRunAt).Scan(&approval.ID, &approval.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("pgx error: %w", err)
+	}
+
+	return nil
+}
+
+func (x *approvalDao) GetByReservationId(ctx context.Context, reservationId int64) (*models.ReservationApproval, error) {
+	query := `SELECT * FROM reservation_approvals WHERE account_id = $1 AND reservation_id = $2 LIMIT 1`
+	accountId := identity.AccountId(ctx)
+	result := &models.ReservationApproval{}
+
+	err := pgxscan.Get(ctx, db.Pool, result, query, accountId, reservationId)
+	if err != nil {
+		return nil, fmt.Errorf("pgx error: %w", err)
+	}
+	return result, nil
+}
+
+func (x *approvalDao) Decide(ctx context.Context, approval *models.ReservationApproval) error {
+	// only pending approvals are updated, concurrent decisions cannot both succeed
+	query := `UPDATE reservation_approvals SET approved = $3, decided_by = $4, comment = $5, decided_at = current_timestamp
+		WHERE account_id = $1 AND reservation_id = $2 AND approved IS NULL
+		RETURNING decided_at`
+	accountId := identity.AccountId(ctx)
+
+	err := db.Pool.QueryRow(ctx, query,
+		accountId,
+		approval.ReservationID,
+		approval.Approved,
+		approval.DecidedBy,
+		approval.Comment).Scan(&approval.DecidedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("approval was already decided: %w", dao.ErrAffectedMismatch)
+	} else if err != nil {
+		return fmt.Errorf("pgx error: %w", err)
+	}
+
+	return nil
+}
+
+func (x *approvalDao) Reject(ctx context.Context, approval *models.ReservationApproval, message string) error {
+	approvalQuery := `UPDATE reservation_approvals SET approved = false, decided_by = $3, comment = $4, decided_at = current_timestamp
+		WHERE account_id = $1 AND reservation_id = $2 AND approved IS NULL
+		RETURNING decided_at`
+	reservationQuery := `UPDATE reservations SET status = 'Rejected', success = false, error = $3, finished_at = now()
+		WHERE account_id = $1 AND id = $2`
+	accountId := identity.AccountId(ctx)
+
+	txErr := dao.WithTransaction(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, approvalQuery,
+			accountId,
+			approval.ReservationID,
+			approval.DecidedBy,
+			approval.Comment).Scan(&approval.DecidedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("approval was already decided: %w", dao.ErrAffectedMismatch)
+		} else if err != nil {
+			return fmt.Errorf("pgx error: %w", err)
+		}
+
+		tag, err := tx.Exec(ctx, reservationQuery, accountId, approval.ReservationID, message)
+		if err != nil {
+			return fmt.Errorf("pgx error: %w", err)
+		}
+		if tag.RowsAffected() != 1 {
+			return fmt.Errorf("expected 1 row, got %d: %w", tag.RowsAffected(), dao.ErrAffectedMismatch)
+		}
+		return nil
+	})
+	if txErr != nil {
+		return fmt.Errorf("transaction error: %w", txErr)
+	}
+
+	approval.Approved = sql.NullBool{Bool: false, Valid: true}
+	return nil
+}
+
+func (x *approvalDao) Reopen(ctx context.Context, reservationId int64) error {
+	query := `UPDATE reservation_approvals SET approved = NULL, decided_by = '', comment = '', decided_at = NULL
+		WHERE account_id = $1 AND reservation_id = $2`
+	accountId := identity.AccountId(ctx)
+
+	tag, err := db.Pool.Exec(ctx, query, accountId, reservationId)
+	if err != nil {
+		return fmt.Errorf("pgx error: %w", err)
+	}
+	if tag.RowsAffected() != 1 {
+		return fmt.Errorf("expected 1 row, got %d: %w", tag.RowsAffected(), dao.ErrAffectedMismatch)
+	}
+	return nil
+}
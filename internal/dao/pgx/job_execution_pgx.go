@@ -0,0 +1,75 @@
+package pgx
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/db"
+	"github.com/RHEnVision/provisioning-backend/internal/identity"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+func init() {
+	dao.GetJobExecutionDao = getJobExecutionDao
+}
+
+type jobExecutionDao struct{}
+
+func getJobExecutionDao(ctx context.Context) dao.JobExecutionDao {
+	return &jobExecutionDao{}
+}
+
+func (x *jobExecutionDao) UnscopedCreate(ctx context.Context, execution *models.JobExecution) error {
+	query := `INSERT INTO job_executions (job_id, job_type, reservation_id, account_id, attempt, hostname)
+		VALUES ($1, $2, $3, $4, (SELECT count(*) + 1 FROM job_executions WHERE job_id = $1), $5)
+		RETURNING id, attempt, started_at`
+
+	err := db.Pool.QueryRow(ctx, query,
+		execution.JobID,
+		execution.JobType,
+		execution.ReservationID,
+		execution.AccountID,
+		execution.Hostname).Scan(&execution.ID, &execution.Attempt, &execution.StartedAt)
+	if err != nil {
+		return fmt.Errorf("pgx error: %w", err)
+	}
+
+	return nil
+}
+
+func (x *jobExecutionDao) UnscopedFinish(ctx context.Context, execution *models.JobExecution) error {
+	query := `UPDATE job_executions SET finished_at = current_timestamp, result = $2, error = $3
+		WHERE id = $1 RETURNING finished_at`
+
+	err := db.Pool.QueryRow(ctx, query, execution.ID, execution.Result, execution.Error).Scan(&execution.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("pgx error: %w", err)
+	}
+
+	return nil
+}
+
+func (x *jobExecutionDao) ListByReservation(ctx context.Context, reservationId int64) ([]*models.JobExecution, error) {
+	query := `SELECT * FROM job_executions WHERE account_id = $1 AND reservation_id = $2 ORDER BY started_at, id`
+	accountId := identity.AccountId(ctx)
+	var result []*models.JobExecution
+
+	err := pgxscan.Select(ctx, db.Pool, &result, query, accountId, reservationId)
+	if err != nil {
+		return nil, fmt.Errorf("pgx error: %w", err)
+	}
+	return result, nil
+}
+
+func (x *jobExecutionDao) UnscopedDeleteBefore(ctx context.Context, lifetime time.Duration) (int64, error) {
+	query := `DELETE FROM job_executions WHERE started_at < now() - cast($1 as interval)`
+
+	tag, err := db.Pool.Exec(ctx, query, lifetime.String())
+	if err != nil {
+		return 0, fmt.Errorf("pgx error: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
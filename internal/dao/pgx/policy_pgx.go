@@ -0,0 +1,66 @@
+package pgx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/db"
+	"github.com/RHEnVision/provisioning-backend/internal/identity"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+func init() {
+	dao.GetPolicyDao = getPolicyDao
+}
+
+type policyDao struct{}
+
+func getPolicyDao(ctx context.Context) dao.PolicyDao {
+	return &policyDao{}
+}
+
+func (x *policyDao) Get(ctx context.Context) (*models.LaunchPolicy, error) {
+	query := `SELECT * FROM launch_policies WHERE account_id = $1 LIMIT 1`
+	accountId := identity.AccountId(ctx)
+	result := &models.LaunchPolicy{}
+
+	err := pgxscan.Get(ctx, db.Pool, result, query, accountId)
+	if err != nil {
+		return nil, fmt.Errorf("pgx error: %w", err)
+	}
+	return result, nil
+}
+
+func (x *policyDao) Upsert(ctx context.Context, policy *models.LaunchPolicy) error {
+	if vError := models.Validate(ctx, policy); vError != nil {
+		return fmt.Errorf("validate: %w", vError)
+	}
+
+	query := `INSERT INTO launch_policies (account_id, document) VALUES ($1, $2)
+		ON CONFLICT (account_id) DO UPDATE SET document = EXCLUDED.document, updated_at = current_timestamp
+		RETURNING updated_at`
+	policy.AccountID = identity.AccountId(ctx)
+
+	err := db.Pool.QueryRow(ctx, query, policy.AccountID, policy.Document).Scan(&policy.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("pgx error: %w", err)
+	}
+
+	return nil
+}
+
+func (x *policyDao) Delete(ctx context.Context) error {
+	query := `DELETE FROM launch_policies WHERE account_id = $1`
+	accountId := identity.AccountId(ctx)
+
+	tag, err := db.Pool.Exec(ctx, query, accountId)
+	if err != nil {
+		return fmt.Errorf("pgx error: %w", err)
+	}
+	if tag.RowsAffected() != 1 {
+		return fmt.Errorf("expected 1 row, got %d: %w", tag.RowsAffected(), dao.ErrAffectedMismatch)
+	}
+	return nil
+}
@@ -195,3 +195,13 @@ func (x *pubkeyDao) UnscopedDeleteResource(ctx context.Context, id int64) error
 	}
 	return nil
 }
+
+func (x *pubkeyDao) UnscopedDeleteResourcesBySourceId(ctx context.Context, sourceId string) (int64, error) {
+	query := `DELETE FROM pubkey_resources WHERE source_id = $1`
+
+	tag, err := db.Pool.Exec(ctx, query, sourceId)
+	if err != nil {
+		return 0, fmt.Errorf("pgx error: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
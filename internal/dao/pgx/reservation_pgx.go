@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/RHEnVision/provisioning-backend/internal/clients"
 	"github.com/RHEnVision/provisioning-backend/internal/config"
@@ -151,14 +152,15 @@ func (x *reservationDao) createGenericReservation(ctx context.Context, tx pgx.Tx
 	reservation.AccountID = identity.AccountId(ctx)
 	reservation.Status = "Created"
 
-	reservationQuery := `INSERT INTO reservations (provider, account_id, steps, step_titles, status)
-		VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+	reservationQuery := `INSERT INTO reservations (provider, account_id, steps, step_titles, status, parent_id)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
 	err := tx.QueryRow(ctx, reservationQuery,
 		reservation.Provider,
 		reservation.AccountID,
 		reservation.Steps,
 		reservation.StepTitles,
-		reservation.Status).Scan(&reservation.ID, &reservation.CreatedAt)
+		reservation.Status,
+		reservation.ParentID).Scan(&reservation.ID, &reservation.CreatedAt)
 	if err != nil {
 		if strings.Contains(err.Error(), "too many pending reservations") {
 			return fmt.Errorf("%w: %s", dao.ErrReservationRateExceeded, err.Error())
@@ -218,7 +220,7 @@ func (x *reservationDao) GetById(ctx context.Context, id int64) (*models.Reserva
 }
 
 func (x *reservationDao) GetAWSById(ctx context.Context, id int64) (*models.AWSReservation, error) {
-	query := `SELECT id, provider, account_id, created_at, steps, step, status, error, finished_at, success,
+	query := `SELECT id, provider, account_id, created_at, steps, step, status, error, finished_at, success, parent_id,
     	pubkey_id, source_id, image_id, aws_reservation_id, detail
 		FROM reservations, aws_reservation_details
 		WHERE account_id = $1 AND id = $2 AND id = reservation_id AND provider = provider_type_aws() LIMIT 1`
@@ -233,7 +235,7 @@ func (x *reservationDao) GetAWSById(ctx context.Context, id int64) (*models.AWSR
 }
 
 func (x *reservationDao) GetAzureById(ctx context.Context, id int64) (*models.AzureReservation, error) {
-	query := `SELECT id, reservations.provider, account_id, created_at, steps, step, status, error, finished_at, success,
+	query := `SELECT id, reservations.provider, account_id, created_at, steps, step, status, error, finished_at, success, parent_id,
     	pubkey_id, source_id, image_id, detail
 		FROM reservations, azure_reservation_details
 		WHERE account_id = $1 AND id = $2 AND id = reservation_id AND reservations.provider = provider_type_azure() LIMIT 1`
@@ -248,7 +250,7 @@ func (x *reservationDao) GetAzureById(ctx context.Context, id int64) (*models.Az
 }
 
 func (x *reservationDao) GetGCPById(ctx context.Context, id int64) (*models.GCPReservation, error) {
-	query := `SELECT id, provider, account_id, created_at, steps, step, status, error, finished_at, success,
+	query := `SELECT id, provider, account_id, created_at, steps, step, status, error, finished_at, success, parent_id,
     	pubkey_id, source_id, image_id, detail
 		FROM reservations, gcp_reservation_details
 		WHERE account_id = $1 AND id = $2 AND id = reservation_id AND provider = provider_type_gcp() LIMIT 1`
@@ -364,6 +366,28 @@ func (x *reservationDao) UpdateOperationNameForGCP(ctx context.Context, id int64
 	return nil
 }
 
+func (x *reservationDao) UnscopedCompleteStep(ctx context.Context, id int64, step string) error {
+	query := `INSERT INTO reservation_checkpoints (reservation_id, step) VALUES ($1, $2)
+		ON CONFLICT (reservation_id, step) DO NOTHING`
+
+	_, err := db.Pool.Exec(ctx, query, id, step)
+	if err != nil {
+		return fmt.Errorf("pgx error: %w", err)
+	}
+	return nil
+}
+
+func (x *reservationDao) UnscopedListCompletedSteps(ctx context.Context, id int64) ([]string, error) {
+	query := `SELECT step FROM reservation_checkpoints WHERE reservation_id = $1 ORDER BY completed_at`
+	var result []string
+
+	err := pgxscan.Select(ctx, db.Pool, &result, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("pgx error: %w", err)
+	}
+	return result, nil
+}
+
 func (x *reservationDao) FinishWithSuccess(ctx context.Context, id int64) error {
 	query := `UPDATE reservations SET success = true, finished_at = now() WHERE id = $1`
 
@@ -390,6 +414,56 @@ func (x *reservationDao) FinishWithError(ctx context.Context, id int64, errorStr
 	return nil
 }
 
+func (x *reservationDao) UnscopedFailPendingBySourceId(ctx context.Context, sourceId string, errorString string) (int64, error) {
+	query := `UPDATE reservations SET success = false, error = $2, finished_at = now()
+		WHERE success IS NULL AND id IN (
+			SELECT reservation_id FROM aws_reservation_details WHERE source_id = $1
+			UNION SELECT reservation_id FROM azure_reservation_details WHERE source_id = $1
+			UNION SELECT reservation_id FROM gcp_reservation_details WHERE source_id = $1)`
+
+	tag, err := db.Pool.Exec(ctx, query, sourceId, errorString)
+	if err != nil {
+		return 0, fmt.Errorf("pgx error: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (x *reservationDao) UnscopedMarkQueued(ctx context.Context, id int64) error {
+	query := `UPDATE reservations SET queued_at = now() WHERE id = $1`
+
+	tag, err := db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("pgx error: %w", err)
+	}
+	if tag.RowsAffected() != 1 {
+		return fmt.Errorf("expected 1 row, got %d: %w", tag.RowsAffected(), dao.ErrAffectedMismatch)
+	}
+	return nil
+}
+
+func (x *reservationDao) UnscopedListStale(ctx context.Context, age time.Duration, limit int64) ([]*models.Reservation, error) {
+	query := `SELECT * FROM reservations
+		WHERE success IS NULL AND finished_at IS NULL AND queued_at < now() - cast($1 as interval)
+		ORDER BY queued_at LIMIT $2`
+	var result []*models.Reservation
+
+	err := pgxscan.Select(ctx, db.Pool, &result, query, age.String(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("pgx error: %w", err)
+	}
+	return result, nil
+}
+
+func (x *reservationDao) UnscopedFinishPending(ctx context.Context, id int64, success bool, errorString string) (bool, error) {
+	query := `UPDATE reservations SET success = $2, error = $3, finished_at = now() WHERE id = $1 AND success IS NULL`
+
+	tag, err := db.Pool.Exec(ctx, query, id, success, errorString)
+	if err != nil {
+		return false, fmt.Errorf("pgx error: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
 func (x *reservationDao) Delete(ctx context.Context, id int64) error {
 	query := `DELETE FROM reservations WHERE id = $1`
 
@@ -405,7 +479,8 @@ func (x *reservationDao) Delete(ctx context.Context, id int64) error {
 
 func (x *reservationDao) Cleanup(ctx context.Context) error {
 	logger := zerolog.Ctx(ctx)
-	query := `DELETE FROM reservations WHERE created_at < now() - cast($1 as interval)`
+	query := `DELETE FROM reservations WHERE created_at < now() - cast($1 as interval)
+		AND id NOT IN (SELECT reservation_id FROM reservation_schedules)`
 	reservationLifetime := config.Reservation.Lifetime.String()
 
 	tag, err := db.Pool.Exec(ctx, query, reservationLifetime)
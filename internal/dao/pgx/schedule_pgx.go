@@ -0,0 +1,120 @@
+package pgx
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/db"
+	"github.com/RHEnVision/provisioning-backend/internal/identity"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+)
+
+// Advisory lock key for the reservation scheduler, any number unique within the database.
+const scheduleLockKey int64 = 0x7363686564756c65
+
+// Maximum amount of schedules processed in one run, the rest is processed in the next one.
+const scheduleBatchSize = 100
+
+func init() {
+	dao.GetScheduleDao = getScheduleDao
+}
+
+type scheduleDao struct{}
+
+func getScheduleDao(ctx context.Context) dao.ScheduleDao {
+	return &scheduleDao{}
+}
+
+func (x *scheduleDao) Create(ctx context.Context, schedule *models.ReservationSchedule) error {
+	query := `INSERT INTO reservation_schedules
+		(reservation_id, account_id, cron, next_run_at, job_type, job_identity, job_edge_id, job_args)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at`
+	schedule.AccountID = identity.AccountId(ctx)
+
+	err := db.Pool.QueryRow(ctx, query,
+		schedule.ReservationID,
+		schedule.AccountID,
+		schedule.Cron,
+		schedule.NextRunAt,
+		schedule.JobType,
+		schedule.JobIdentity,
+		schedule.JobEdgeID,
+		schedule.JobArgs).Scan(&schedule.ID, &schedule.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("pgx error: %w", err)
+	}
+
+	return nil
+}
+
+func (x *scheduleDao) GetByReservationId(ctx context.Context, reservationId int64) (*models.ReservationSchedule, error) {
+	query := `SELECT * FROM reservation_schedules WHERE account_id = $1 AND reservation_id = $2 LIMIT 1`
+	accountId := identity.AccountId(ctx)
+	result := &models.ReservationSchedule{}
+
+	err := pgxscan.Get(ctx, db.Pool, result, query, accountId, reservationId)
+	if err != nil {
+		return nil, fmt.Errorf("pgx error: %w", err)
+	}
+	return result, nil
+}
+
+func (x *scheduleDao) DeleteByReservationId(ctx context.Context, reservationId int64) error {
+	query := `DELETE FROM reservation_schedules WHERE account_id = $1 AND reservation_id = $2`
+	accountId := identity.AccountId(ctx)
+
+	tag, err := db.Pool.Exec(ctx, query, accountId, reservationId)
+	if err != nil {
+		return fmt.Errorf("pgx error: %w", err)
+	}
+	if tag.RowsAffected() != 1 {
+		return fmt.Errorf("expected 1 row, got %d: %w", tag.RowsAffected(), dao.ErrAffectedMismatch)
+	}
+	return nil
+}
+
+func (x *scheduleDao) UnscopedClaimDue(ctx context.Context, now time.Time, next dao.ScheduleNextFn) ([]*models.ReservationSchedule, bool, error) {
+	var locked bool
+	var schedules []*models.ReservationSchedule
+	txErr := dao.WithTransaction(ctx, func(tx pgx.Tx) error {
+		// transaction level lock is released on commit or rollback
+		err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, scheduleLockKey).Scan(&locked)
+		if err != nil {
+			return fmt.Errorf("pgx error: %w", err)
+		}
+		if !locked {
+			return nil
+		}
+
+		query := `SELECT * FROM reservation_schedules WHERE next_run_at <= $1 ORDER BY next_run_at LIMIT $2`
+		err = pgxscan.Select(ctx, tx, &schedules, query, now, scheduleBatchSize)
+		if err != nil {
+			return fmt.Errorf("pgx error: %w", err)
+		}
+
+		for _, schedule := range schedules {
+			nextRunAt := next(ctx, schedule)
+
+			if nextRunAt.IsZero() {
+				_, err = tx.Exec(ctx, `DELETE FROM reservation_schedules WHERE id = $1`, schedule.ID)
+			} else {
+				_, err = tx.Exec(ctx, `UPDATE reservation_schedules SET next_run_at = $2, last_run_at = $3 WHERE id = $1`,
+					schedule.ID, nextRunAt, now)
+			}
+			if err != nil {
+				return fmt.Errorf("pgx error: %w", err)
+			}
+		}
+
+		return nil
+	})
+
+	if txErr != nil {
+		return nil, locked, fmt.Errorf("pgx tx error: %w", txErr)
+	}
+	return schedules, locked, nil
+}
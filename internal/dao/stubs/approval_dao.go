@@ -0,0 +1,108 @@
+package stubs
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+)
+
+type approvalDaoStub struct {
+	rules     []*models.ApprovalRule
+	approvals []*models.ReservationApproval
+}
+
+func init() {
+	dao.GetApprovalDao = getApprovalDao
+}
+
+func ApprovalStubCount(ctx context.Context) int {
+	apDao := getApprovalDaoStub(ctx)
+	return len(apDao.approvals)
+}
+
+func getApprovalDao(ctx context.Context) dao.ApprovalDao {
+	return getApprovalDaoStub(ctx)
+}
+
+func (stub *approvalDaoStub) CreateRule(ctx context.Context, rule *models.ApprovalRule) error {
+	if vError := models.Validate(ctx, rule); vError != nil {
+		return fmt.Errorf("approval rule validation: %w", vError)
+	}
+
+	rule.ID = int64(len(stub.rules)) + 1
+	rule.AccountID = ctxAccountId(ctx)
+	stub.rules = append(stub.rules, rule)
+	return nil
+}
+
+func (stub *approvalDaoStub) ListRules(ctx context.Context) ([]*models.ApprovalRule, error) {
+	var result []*models.ApprovalRule
+	for _, rule := range stub.rules {
+		if rule.AccountID == ctxAccountId(ctx) {
+			result = append(result, rule)
+		}
+	}
+	return result, nil
+}
+
+func (stub *approvalDaoStub) DeleteRule(ctx context.Context, id int64) error {
+	for i, rule := range stub.rules {
+		if rule.AccountID == ctxAccountId(ctx) && rule.ID == id {
+			stub.rules = append(stub.rules[:i], stub.rules[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("expected 1 row, got 0: %w", dao.ErrAffectedMismatch)
+}
+
+func (stub *approvalDaoStub) Create(ctx context.Context, approval *models.ReservationApproval) error {
+	approval.ID = int64(len(stub.approvals)) + 1
+	approval.AccountID = ctxAccountId(ctx)
+	stub.approvals = append(stub.approvals, approval)
+	return nil
+}
+
+func (stub *approvalDaoStub) GetByReservationId(ctx context.Context, reservationId int64) (*models.ReservationApproval, error) {
+	for _, approval := range stub.approvals {
+		if approval.AccountID == ctxAccountId(ctx) && approval.ReservationID == reservationId {
+			return approval, nil
+		}
+	}
+	return nil, dao.ErrNoRows
+}
+
+func (stub *approvalDaoStub) Decide(ctx context.Context, approval *models.ReservationApproval) error {
+	stored, err := stub.GetByReservationId(ctx, approval.ReservationID)
+	if err != nil || !stored.Pending() {
+		return fmt.Errorf("approval was already decided: %w", dao.ErrAffectedMismatch)
+	}
+
+	approval.DecidedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	stored.Approved = approval.Approved
+	stored.DecidedBy = approval.DecidedBy
+	stored.DecidedAt = approval.DecidedAt
+	stored.Comment = approval.Comment
+	return nil
+}
+
+func (stub *approvalDaoStub) Reject(ctx context.Context, approval *models.ReservationApproval, message string) error {
+	approval.Approved = sql.NullBool{Bool: false, Valid: true}
+	return stub.Decide(ctx, approval)
+}
+
+func (stub *approvalDaoStub) Reopen(ctx context.Context, reservationId int64) error {
+	stored, err := stub.GetByReservationId(ctx, reservationId)
+	if err != nil {
+		return fmt.Errorf("expected 1 row, got 0: %w", dao.ErrAffectedMismatch)
+	}
+
+	stored.Approved = sql.NullBool{}
+	stored.DecidedBy = ""
+	stored.DecidedAt = sql.NullTime{}
+	stored.Comment = ""
+	return nil
+}
@@ -14,6 +14,10 @@ const (
 	accountCtxKey     daoStubCtxKeyType = iota
 	pubkeyCtxKey      daoStubCtxKeyType = iota
 	reservationCtxKey daoStubCtxKeyType = iota
+	scheduleCtxKey    daoStubCtxKeyType = iota
+	approvalCtxKey    daoStubCtxKeyType = iota
+	policyCtxKey      daoStubCtxKeyType = iota
+	executionCtxKey   daoStubCtxKeyType = iota
 )
 
 func ctxAccountId(ctx context.Context) int64 {
@@ -45,6 +49,7 @@ func WithReservationDao(parent context.Context) context.Context {
 
 	ctx := context.WithValue(parent, reservationCtxKey, &reservationDaoStub{
 		instances: make(map[int64][]*models.ReservationInstance),
+		steps:     make(map[int64][]string),
 	})
 	return ctx
 }
@@ -58,6 +63,78 @@ func getReservationDaoStub(ctx context.Context) *reservationDaoStub {
 	return resDao
 }
 
+func WithScheduleDao(parent context.Context) context.Context {
+	if parent.Value(scheduleCtxKey) != nil {
+		panic(dao.ErrStubContextAlreadySet)
+	}
+
+	ctx := context.WithValue(parent, scheduleCtxKey, &scheduleDaoStub{})
+	return ctx
+}
+
+func getScheduleDaoStub(ctx context.Context) *scheduleDaoStub {
+	var ok bool
+	var schDao *scheduleDaoStub
+	if schDao, ok = ctx.Value(scheduleCtxKey).(*scheduleDaoStub); !ok {
+		panic(dao.ErrStubMissingContext)
+	}
+	return schDao
+}
+
+func WithApprovalDao(parent context.Context) context.Context {
+	if parent.Value(approvalCtxKey) != nil {
+		panic(dao.ErrStubContextAlreadySet)
+	}
+
+	ctx := context.WithValue(parent, approvalCtxKey, &approvalDaoStub{})
+	return ctx
+}
+
+func getApprovalDaoStub(ctx context.Context) *approvalDaoStub {
+	var ok bool
+	var apDao *approvalDaoStub
+	if apDao, ok = ctx.Value(approvalCtxKey).(*approvalDaoStub); !ok {
+		panic(dao.ErrStubMissingContext)
+	}
+	return apDao
+}
+
+func WithPolicyDao(parent context.Context) context.Context {
+	if parent.Value(policyCtxKey) != nil {
+		panic(dao.ErrStubContextAlreadySet)
+	}
+
+	ctx := context.WithValue(parent, policyCtxKey, &policyDaoStub{})
+	return ctx
+}
+
+func getPolicyDaoStub(ctx context.Context) *policyDaoStub {
+	var ok bool
+	var plDao *policyDaoStub
+	if plDao, ok = ctx.Value(policyCtxKey).(*policyDaoStub); !ok {
+		panic(dao.ErrStubMissingContext)
+	}
+	return plDao
+}
+
+func WithJobExecutionDao(parent context.Context) context.Context {
+	if parent.Value(executionCtxKey) != nil {
+		panic(dao.ErrStubContextAlreadySet)
+	}
+
+	ctx := context.WithValue(parent, executionCtxKey, &jobExecutionDaoStub{})
+	return ctx
+}
+
+func getJobExecutionDaoStub(ctx context.Context) *jobExecutionDaoStub {
+	var ok bool
+	var exDao *jobExecutionDaoStub
+	if exDao, ok = ctx.Value(executionCtxKey).(*jobExecutionDaoStub); !ok {
+		panic(dao.ErrStubMissingContext)
+	}
+	return exDao
+}
+
 func WithAccountDaoOne(parent context.Context) context.Context {
 	if parent.Value(accountCtxKey) != nil {
 		panic(dao.ErrStubContextAlreadySet)
@@ -0,0 +1,74 @@
+package stubs
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+)
+
+type jobExecutionDaoStub struct {
+	store []*models.JobExecution
+}
+
+func init() {
+	dao.GetJobExecutionDao = getJobExecutionDao
+}
+
+func getJobExecutionDao(ctx context.Context) dao.JobExecutionDao {
+	return getJobExecutionDaoStub(ctx)
+}
+
+func (stub *jobExecutionDaoStub) UnscopedCreate(ctx context.Context, execution *models.JobExecution) error {
+	execution.Attempt = 1
+	for _, existing := range stub.store {
+		if existing.JobID == execution.JobID {
+			execution.Attempt++
+		}
+	}
+	execution.ID = int64(len(stub.store)) + 1
+	execution.StartedAt = time.Now()
+	stub.store = append(stub.store, execution)
+	return nil
+}
+
+func (stub *jobExecutionDaoStub) UnscopedFinish(ctx context.Context, execution *models.JobExecution) error {
+	for _, existing := range stub.store {
+		if existing.ID == execution.ID {
+			execution.FinishedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			*existing = *execution
+			return nil
+		}
+	}
+	return fmt.Errorf("expected 1 row, got 0: %w", dao.ErrAffectedMismatch)
+}
+
+func (stub *jobExecutionDaoStub) ListByReservation(ctx context.Context, reservationId int64) ([]*models.JobExecution, error) {
+	var result []*models.JobExecution
+	for _, execution := range stub.store {
+		if execution.AccountID == ctxAccountId(ctx) && execution.ReservationID.Valid && execution.ReservationID.Int64 == reservationId {
+			result = append(result, execution)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].StartedAt.Before(result[j].StartedAt)
+	})
+	return result, nil
+}
+
+func (stub *jobExecutionDaoStub) UnscopedDeleteBefore(ctx context.Context, lifetime time.Duration) (int64, error) {
+	startedBefore := time.Now().Add(-lifetime)
+	kept := make([]*models.JobExecution, 0, len(stub.store))
+	for _, execution := range stub.store {
+		if !execution.StartedAt.Before(startedBefore) {
+			kept = append(kept, execution)
+		}
+	}
+	deleted := int64(len(stub.store) - len(kept))
+	stub.store = kept
+	return deleted, nil
+}
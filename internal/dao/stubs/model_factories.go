@@ -15,3 +15,24 @@ func AddAWSReservation(ctx context.Context, reservation *models.AWSReservation)
 	reservationDao := getReservationDaoStub(ctx)
 	return reservationDao.CreateAWS(ctx, reservation)
 }
+
+func AddSchedule(ctx context.Context, schedule *models.ReservationSchedule) error {
+	scheduleDao := getScheduleDaoStub(ctx)
+	return scheduleDao.Create(ctx, schedule)
+}
+
+func AddApprovalRule(ctx context.Context, rule *models.ApprovalRule) error {
+	approvalDao := getApprovalDaoStub(ctx)
+	return approvalDao.CreateRule(ctx, rule)
+}
+
+func AddPolicy(ctx context.Context, document *models.PolicyDocument) error {
+	policyDao := getPolicyDaoStub(ctx)
+	return policyDao.Upsert(ctx, &models.LaunchPolicy{Document: document})
+}
+
+func AddJobExecution(ctx context.Context, execution *models.JobExecution) error {
+	executionDao := getJobExecutionDaoStub(ctx)
+	execution.AccountID = ctxAccountId(ctx)
+	return executionDao.UnscopedCreate(ctx, execution)
+}
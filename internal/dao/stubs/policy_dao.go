@@ -0,0 +1,58 @@
+package stubs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+)
+
+type policyDaoStub struct {
+	store []*models.LaunchPolicy
+}
+
+func init() {
+	dao.GetPolicyDao = getPolicyDao
+}
+
+func getPolicyDao(ctx context.Context) dao.PolicyDao {
+	return getPolicyDaoStub(ctx)
+}
+
+func (stub *policyDaoStub) Get(ctx context.Context) (*models.LaunchPolicy, error) {
+	for _, policy := range stub.store {
+		if policy.AccountID == ctxAccountId(ctx) {
+			return policy, nil
+		}
+	}
+	return nil, dao.ErrNoRows
+}
+
+func (stub *policyDaoStub) Upsert(ctx context.Context, policy *models.LaunchPolicy) error {
+	if vError := models.Validate(ctx, policy); vError != nil {
+		return fmt.Errorf("launch policy validation: %w", vError)
+	}
+
+	policy.AccountID = ctxAccountId(ctx)
+	policy.UpdatedAt = time.Now()
+	for i, existing := range stub.store {
+		if existing.AccountID == policy.AccountID {
+			stub.store[i] = policy
+			return nil
+		}
+	}
+	stub.store = append(stub.store, policy)
+	return nil
+}
+
+func (stub *policyDaoStub) Delete(ctx context.Context) error {
+	for i, policy := range stub.store {
+		if policy.AccountID == ctxAccountId(ctx) {
+			stub.store = append(stub.store[:i], stub.store[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("expected 1 row, got 0: %w", dao.ErrAffectedMismatch)
+}
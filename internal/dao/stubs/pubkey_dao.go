@@ -132,6 +132,20 @@ func (stub *pubkeyDaoStub) UnscopedDeleteResource(ctx context.Context, id int64)
 	return nil
 }
 
+func (stub *pubkeyDaoStub) UnscopedDeleteResourcesBySourceId(ctx context.Context, sourceId string) (int64, error) {
+	var deleted int64
+	kept := make([]*models.PubkeyResource, 0, len(stub.resourceStore))
+	for _, pkr := range stub.resourceStore {
+		if pkr.SourceID == sourceId {
+			deleted++
+		} else {
+			kept = append(kept, pkr)
+		}
+	}
+	stub.resourceStore = kept
+	return deleted, nil
+}
+
 func (stub *pubkeyDaoStub) UnscopedListResourcesByPubkeyId(ctx context.Context, pkId int64) ([]*models.PubkeyResource, error) {
 	var result []*models.PubkeyResource
 	for _, pkr := range stub.resourceStore {
@@ -2,11 +2,14 @@ package stubs
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/RHEnVision/provisioning-backend/internal/clients"
 	"github.com/RHEnVision/provisioning-backend/internal/dao"
 	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"golang.org/x/exp/slices"
 )
 
 type reservationDaoStub struct {
@@ -14,6 +17,7 @@ type reservationDaoStub struct {
 	storeAzure []*models.AzureReservation
 	storeGCP   []*models.GCPReservation
 	instances  map[int64][]*models.ReservationInstance
+	steps      map[int64][]string
 }
 
 func init() {
@@ -73,6 +77,16 @@ func (stub *reservationDaoStub) GetById(ctx context.Context, id int64) (*models.
 			return &awsReservation.Reservation, nil
 		}
 	}
+	for _, azureReservation := range stub.storeAzure {
+		if azureReservation.AccountID == ctxAccountId(ctx) && azureReservation.ID == id {
+			return &azureReservation.Reservation, nil
+		}
+	}
+	for _, gcpReservation := range stub.storeGCP {
+		if gcpReservation.AccountID == ctxAccountId(ctx) && gcpReservation.ID == id {
+			return &gcpReservation.Reservation, nil
+		}
+	}
 	return nil, dao.ErrNoRows
 }
 
@@ -136,6 +150,17 @@ func (stub *reservationDaoStub) UpdateOperationNameForGCP(ctx context.Context, i
 	return nil
 }
 
+func (stub *reservationDaoStub) UnscopedCompleteStep(ctx context.Context, id int64, step string) error {
+	if !slices.Contains(stub.steps[id], step) {
+		stub.steps[id] = append(stub.steps[id], step)
+	}
+	return nil
+}
+
+func (stub *reservationDaoStub) UnscopedListCompletedSteps(ctx context.Context, id int64) ([]string, error) {
+	return stub.steps[id], nil
+}
+
 func (stub *reservationDaoStub) FinishWithSuccess(ctx context.Context, id int64) error {
 	return nil
 }
@@ -144,6 +169,75 @@ func (stub *reservationDaoStub) FinishWithError(ctx context.Context, id int64, e
 	return nil
 }
 
+func (stub *reservationDaoStub) UnscopedFailPendingBySourceId(ctx context.Context, sourceId string, errorString string) (int64, error) {
+	var affected int64
+	fail := func(sid string, res *models.Reservation) {
+		if sid == sourceId && !res.Success.Valid {
+			res.Success = sql.NullBool{Bool: false, Valid: true}
+			res.Error = errorString
+			affected++
+		}
+	}
+	for _, r := range stub.storeAWS {
+		fail(r.SourceID, &r.Reservation)
+	}
+	for _, r := range stub.storeAzure {
+		fail(r.SourceID, &r.Reservation)
+	}
+	for _, r := range stub.storeGCP {
+		fail(r.SourceID, &r.Reservation)
+	}
+	return affected, nil
+}
+
+// all returns reservations of all accounts
+func (stub *reservationDaoStub) all() []*models.Reservation {
+	result := make([]*models.Reservation, 0, len(stub.storeAWS)+len(stub.storeAzure)+len(stub.storeGCP))
+	for _, r := range stub.storeAWS {
+		result = append(result, &r.Reservation)
+	}
+	for _, r := range stub.storeAzure {
+		result = append(result, &r.Reservation)
+	}
+	for _, r := range stub.storeGCP {
+		result = append(result, &r.Reservation)
+	}
+	return result
+}
+
+func (stub *reservationDaoStub) UnscopedMarkQueued(ctx context.Context, id int64) error {
+	for _, r := range stub.all() {
+		if r.ID == id {
+			r.QueuedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			return nil
+		}
+	}
+	return dao.ErrAffectedMismatch
+}
+
+func (stub *reservationDaoStub) UnscopedListStale(ctx context.Context, age time.Duration, limit int64) ([]*models.Reservation, error) {
+	queuedBefore := time.Now().Add(-age)
+	var result []*models.Reservation
+	for _, r := range stub.all() {
+		if !r.Success.Valid && !r.FinishedAt.Valid && r.QueuedAt.Valid && r.QueuedAt.Time.Before(queuedBefore) && int64(len(result)) < limit {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (stub *reservationDaoStub) UnscopedFinishPending(ctx context.Context, id int64, success bool, errorString string) (bool, error) {
+	for _, r := range stub.all() {
+		if r.ID == id && !r.Success.Valid {
+			r.Success = sql.NullBool{Bool: success, Valid: true}
+			r.FinishedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			r.Error = errorString
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (stub *reservationDaoStub) Delete(ctx context.Context, id int64) error {
 	return nil
 }
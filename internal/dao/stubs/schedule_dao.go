@@ -0,0 +1,76 @@
+package stubs
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+)
+
+type scheduleDaoStub struct {
+	store []*models.ReservationSchedule
+}
+
+func init() {
+	dao.GetScheduleDao = getScheduleDao
+}
+
+func ScheduleStubCount(ctx context.Context) int {
+	schDao := getScheduleDaoStub(ctx)
+	return len(schDao.store)
+}
+
+func getScheduleDao(ctx context.Context) dao.ScheduleDao {
+	return getScheduleDaoStub(ctx)
+}
+
+func (stub *scheduleDaoStub) Create(ctx context.Context, schedule *models.ReservationSchedule) error {
+	schedule.ID = int64(len(stub.store)) + 1
+	schedule.AccountID = ctxAccountId(ctx)
+	stub.store = append(stub.store, schedule)
+	return nil
+}
+
+func (stub *scheduleDaoStub) GetByReservationId(ctx context.Context, reservationId int64) (*models.ReservationSchedule, error) {
+	for _, schedule := range stub.store {
+		if schedule.AccountID == ctxAccountId(ctx) && schedule.ReservationID == reservationId {
+			return schedule, nil
+		}
+	}
+	return nil, dao.ErrNoRows
+}
+
+func (stub *scheduleDaoStub) DeleteByReservationId(ctx context.Context, reservationId int64) error {
+	for i, schedule := range stub.store {
+		if schedule.AccountID == ctxAccountId(ctx) && schedule.ReservationID == reservationId {
+			stub.store = append(stub.store[:i], stub.store[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("expected 1 row, got 0: %w", dao.ErrAffectedMismatch)
+}
+
+func (stub *scheduleDaoStub) UnscopedClaimDue(ctx context.Context, now time.Time, next dao.ScheduleNextFn) ([]*models.ReservationSchedule, bool, error) {
+	var due []*models.ReservationSchedule
+	kept := make([]*models.ReservationSchedule, 0, len(stub.store))
+	for _, schedule := range stub.store {
+		if schedule.NextRunAt.After(now) {
+			kept = append(kept, schedule)
+			continue
+		}
+
+		claimed := *schedule
+		due = append(due, &claimed)
+		nextRunAt := next(ctx, schedule)
+		if !nextRunAt.IsZero() {
+			schedule.NextRunAt = nextRunAt
+			schedule.LastRunAt = sql.NullTime{Time: now, Valid: true}
+			kept = append(kept, schedule)
+		}
+	}
+	stub.store = kept
+	return due, true, nil
+}
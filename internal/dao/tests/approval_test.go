@@ -0,0 +1,172 @@
+//go:build integration
+// +build integration
+
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"testing"
+
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/testing/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupApproval(t *testing.T) (dao.ApprovalDao, context.Context) {
+	ctx := identity.WithTenant(t, context.Background())
+	approvalDao := dao.GetApprovalDao(ctx)
+	return approvalDao, ctx
+}
+
+func createReservationApproval(t *testing.T, ctx context.Context) *models.ReservationApproval {
+	t.Helper()
+	res := newAWSReservation()
+	err := dao.GetReservationDao(ctx).CreateAWS(ctx, res)
+	require.NoError(t, err)
+
+	approval := &models.ReservationApproval{
+		ReservationID: res.ID,
+		Reason:        "large",
+		JobType:       "launch_instances_aws",
+		JobArgs:       []byte(`{"ReservationID": 1}`),
+	}
+	err = dao.GetApprovalDao(ctx).Create(ctx, approval)
+	require.NoError(t, err)
+	return approval
+}
+
+func TestApprovalRules(t *testing.T) {
+	approvalDao, ctx := setupApproval(t)
+	defer reset()
+
+	rule := &models.ApprovalRule{Name: "GPU", InstanceTypes: []string{"p3.*"}}
+	err := approvalDao.CreateRule(ctx, rule)
+	require.NoError(t, err)
+
+	t.Run("list", func(t *testing.T) {
+		rules, err := approvalDao.ListRules(ctx)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		assert.Equal(t, "GPU", rules[0].Name)
+		assert.Equal(t, []string{"p3.*"}, rules[0].InstanceTypes)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		err := approvalDao.CreateRule(ctx, &models.ApprovalRule{Name: "invalid", InstanceTypes: []string{"p3.["}})
+		require.Error(t, err)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		err := approvalDao.DeleteRule(ctx, rule.ID)
+		require.NoError(t, err)
+
+		err = approvalDao.DeleteRule(ctx, rule.ID)
+		require.ErrorIs(t, err, dao.ErrAffectedMismatch)
+	})
+}
+
+func TestApprovalCreate(t *testing.T) {
+	approvalDao, ctx := setupApproval(t)
+	defer reset()
+
+	t.Run("success", func(t *testing.T) {
+		approval := createReservationApproval(t, ctx)
+
+		newApproval, err := approvalDao.GetByReservationId(ctx, approval.ReservationID)
+		require.NoError(t, err)
+		assert.Equal(t, "large", newApproval.Reason)
+		assert.True(t, newApproval.Pending())
+		assert.False(t, newApproval.ScheduleNextRunAt.Valid)
+		assert.JSONEq(t, `{"ReservationID": 1}`, string(newApproval.JobArgs))
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		_, err := approvalDao.GetByReservationId(ctx, math.MaxInt64)
+		require.ErrorIs(t, err, dao.ErrNoRows)
+	})
+}
+
+func TestApprovalDecide(t *testing.T) {
+	approvalDao, ctx := setupApproval(t)
+	defer reset()
+
+	approval := createReservationApproval(t, ctx)
+	decision := &models.ReservationApproval{
+		ReservationID: approval.ReservationID,
+		Approved:      sql.NullBool{Bool: true, Valid: true},
+		DecidedBy:     "admin",
+		Comment:       "ok",
+	}
+	err := approvalDao.Decide(ctx, decision)
+	require.NoError(t, err)
+	assert.True(t, decision.DecidedAt.Valid)
+
+	decided, err := approvalDao.GetByReservationId(ctx, approval.ReservationID)
+	require.NoError(t, err)
+	assert.False(t, decided.Pending())
+	assert.True(t, decided.Approved.Bool)
+	assert.Equal(t, "admin", decided.DecidedBy)
+
+	err = approvalDao.Decide(ctx, decision)
+	require.ErrorIs(t, err, dao.ErrAffectedMismatch)
+}
+
+func TestApprovalReject(t *testing.T) {
+	approvalDao, ctx := setupApproval(t)
+	defer reset()
+
+	approval := createReservationApproval(t, ctx)
+	decision := &models.ReservationApproval{
+		ReservationID: approval.ReservationID,
+		DecidedBy:     "admin",
+		Comment:       "too expensive",
+	}
+	err := approvalDao.Reject(ctx, decision, "Launch was rejected: too expensive")
+	require.NoError(t, err)
+	assert.True(t, decision.DecidedAt.Valid)
+
+	decided, err := approvalDao.GetByReservationId(ctx, approval.ReservationID)
+	require.NoError(t, err)
+	assert.False(t, decided.Pending())
+	assert.False(t, decided.Approved.Bool)
+
+	reservation, err := dao.GetReservationDao(ctx).GetById(ctx, approval.ReservationID)
+	require.NoError(t, err)
+	assert.Equal(t, "Rejected", reservation.Status)
+	assert.True(t, reservation.Success.Valid)
+	assert.False(t, reservation.Success.Bool)
+	assert.Equal(t, "Launch was rejected: too expensive", reservation.Error)
+
+	err = approvalDao.Reject(ctx, decision, "Launch was rejected")
+	require.ErrorIs(t, err, dao.ErrAffectedMismatch)
+}
+
+func TestApprovalReopen(t *testing.T) {
+	approvalDao, ctx := setupApproval(t)
+	defer reset()
+
+	approval := createReservationApproval(t, ctx)
+	decision := &models.ReservationApproval{
+		ReservationID: approval.ReservationID,
+		Approved:      sql.NullBool{Bool: true, Valid: true},
+		DecidedBy:     "admin",
+	}
+	err := approvalDao.Decide(ctx, decision)
+	require.NoError(t, err)
+
+	err = approvalDao.Reopen(ctx, approval.ReservationID)
+	require.NoError(t, err)
+
+	reopened, err := approvalDao.GetByReservationId(ctx, approval.ReservationID)
+	require.NoError(t, err)
+	assert.True(t, reopened.Pending())
+	assert.Empty(t, reopened.DecidedBy)
+	assert.False(t, reopened.DecidedAt.Valid)
+
+	err = approvalDao.Decide(ctx, decision)
+	require.NoError(t, err)
+}
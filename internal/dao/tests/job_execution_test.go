@@ -0,0 +1,73 @@
+//go:build integration
+// +build integration
+
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/testing/identity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobExecution(t *testing.T) {
+	ctx := identity.WithTenant(t, context.Background())
+	exDao := dao.GetJobExecutionDao(ctx)
+	defer reset()
+
+	res := newAWSReservation()
+	err := dao.GetReservationDao(ctx).CreateAWS(ctx, res)
+	require.NoError(t, err)
+
+	jobID := uuid.New()
+	newExecution := func() *models.JobExecution {
+		return &models.JobExecution{
+			JobID:         jobID,
+			JobType:       "launch_instances_aws",
+			ReservationID: sql.NullInt64{Int64: res.ID, Valid: true},
+			AccountID:     res.AccountID,
+			Hostname:      "worker-1",
+		}
+	}
+
+	first := newExecution()
+	err = exDao.UnscopedCreate(ctx, first)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, first.Attempt)
+
+	first.Result = models.JobResultInterrupted
+	err = exDao.UnscopedFinish(ctx, first)
+	require.NoError(t, err)
+	assert.True(t, first.FinishedAt.Valid)
+
+	second := newExecution()
+	err = exDao.UnscopedCreate(ctx, second)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, second.Attempt)
+
+	t.Run("list by reservation", func(t *testing.T) {
+		executions, err := exDao.ListByReservation(ctx, res.ID)
+		require.NoError(t, err)
+		require.Len(t, executions, 2)
+		assert.Equal(t, models.JobResultInterrupted, executions[0].Result)
+		assert.Equal(t, jobID, executions[1].JobID)
+		assert.False(t, executions[1].FinishedAt.Valid)
+	})
+
+	t.Run("delete before", func(t *testing.T) {
+		deleted, err := exDao.UnscopedDeleteBefore(ctx, time.Hour)
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+
+		deleted, err = exDao.UnscopedDeleteBefore(ctx, 0)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, deleted)
+	})
+}
@@ -0,0 +1,63 @@
+//go:build integration
+// +build integration
+
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/testing/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPolicy(t *testing.T) (dao.PolicyDao, context.Context) {
+	ctx := identity.WithTenant(t, context.Background())
+	policyDao := dao.GetPolicyDao(ctx)
+	return policyDao, ctx
+}
+
+func TestPolicyUpsert(t *testing.T) {
+	policyDao, ctx := setupPolicy(t)
+	defer reset()
+
+	_, err := policyDao.Get(ctx)
+	require.ErrorIs(t, err, dao.ErrNoRows)
+
+	err = policyDao.Upsert(ctx, &models.LaunchPolicy{Document: &models.PolicyDocument{
+		AllowedRegions: models.PolicyRegions{AWS: []string{"us-east-*"}},
+		MaxAmount:      5,
+	}})
+	require.NoError(t, err)
+
+	err = policyDao.Upsert(ctx, &models.LaunchPolicy{Document: &models.PolicyDocument{
+		RequiredTags: []string{"cost-center"},
+	}})
+	require.NoError(t, err)
+
+	policy, err := policyDao.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cost-center"}, policy.Document.RequiredTags)
+	assert.Empty(t, policy.Document.AllowedRegions.AWS)
+	assert.Zero(t, policy.Document.MaxAmount)
+}
+
+func TestPolicyDelete(t *testing.T) {
+	policyDao, ctx := setupPolicy(t)
+	defer reset()
+
+	err := policyDao.Upsert(ctx, &models.LaunchPolicy{Document: &models.PolicyDocument{MaxAmount: 5}})
+	require.NoError(t, err)
+
+	err = policyDao.Delete(ctx)
+	require.NoError(t, err)
+
+	_, err = policyDao.Get(ctx)
+	require.ErrorIs(t, err, dao.ErrNoRows)
+
+	err = policyDao.Delete(ctx)
+	require.ErrorIs(t, err, dao.ErrAffectedMismatch)
+}
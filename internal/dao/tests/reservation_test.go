@@ -160,6 +160,31 @@ func TestReservationCreateGCP(t *testing.T) {
 	})
 }
 
+func TestReservationCreateWithParent(t *testing.T) {
+	reservationDao, ctx := setupReservation(t)
+	defer reset()
+
+	t.Run("success", func(t *testing.T) {
+		parent := newAWSReservation()
+		err := reservationDao.CreateAWS(ctx, parent)
+		require.NoError(t, err)
+
+		res := newAWSReservation()
+		res.ParentID = &parent.ID
+		err = reservationDao.CreateAWS(ctx, res)
+		require.NoError(t, err)
+
+		newRes, err := reservationDao.GetAWSById(ctx, res.ID)
+		require.NoError(t, err)
+		require.NotNil(t, newRes.ParentID)
+		assert.Equal(t, parent.ID, *newRes.ParentID)
+
+		parentRes, err := reservationDao.GetById(ctx, parent.ID)
+		require.NoError(t, err)
+		assert.Nil(t, parentRes.ParentID)
+	})
+}
+
 func TestReservationCreateAWSInstance(t *testing.T) {
 	reservationDao, ctx := setupReservation(t)
 	defer reset()
@@ -312,6 +337,74 @@ func TestReservationDelete(t *testing.T) {
 	})
 }
 
+func TestReservationCompletedSteps(t *testing.T) {
+	reservationDao, ctx := setupReservation(t)
+	defer reset()
+
+	t.Run("success", func(t *testing.T) {
+		res := newNoopReservation()
+		err := reservationDao.CreateNoop(ctx, res)
+		require.NoError(t, err)
+
+		steps, err := reservationDao.UnscopedListCompletedSteps(ctx, res.ID)
+		require.NoError(t, err)
+		assert.Empty(t, steps)
+
+		err = reservationDao.UnscopedCompleteStep(ctx, res.ID, "ensure_pubkey")
+		require.NoError(t, err)
+		err = reservationDao.UnscopedCompleteStep(ctx, res.ID, "ensure_pubkey")
+		require.NoError(t, err, "recording a step again must not fail")
+
+		steps, err = reservationDao.UnscopedListCompletedSteps(ctx, res.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ensure_pubkey"}, steps)
+	})
+}
+
+func TestReservationStale(t *testing.T) {
+	reservationDao, ctx := setupReservation(t)
+	defer reset()
+
+	t.Run("list and finish pending", func(t *testing.T) {
+		res := newNoopReservation()
+		err := reservationDao.CreateNoop(ctx, res)
+		require.NoError(t, err)
+
+		stale, err := reservationDao.UnscopedListStale(ctx, 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, stale, "reservations which were not queued are not stale")
+
+		err = reservationDao.UnscopedMarkQueued(ctx, res.ID)
+		require.NoError(t, err)
+
+		stale, err = reservationDao.UnscopedListStale(ctx, time.Hour, 10)
+		require.NoError(t, err)
+		assert.Empty(t, stale, "recently queued reservations are not stale")
+
+		stale, err = reservationDao.UnscopedListStale(ctx, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, stale, 1)
+		assert.Equal(t, res.ID, stale[0].ID)
+
+		finished, err := reservationDao.UnscopedFinishPending(ctx, res.ID, false, "lost")
+		require.NoError(t, err)
+		assert.True(t, finished)
+
+		finished, err = reservationDao.UnscopedFinishPending(ctx, res.ID, true, "")
+		require.NoError(t, err)
+		assert.False(t, finished, "finished reservation must not be finished again")
+
+		updated, err := reservationDao.GetById(ctx, res.ID)
+		require.NoError(t, err)
+		assert.False(t, updated.Success.Bool)
+		assert.Equal(t, "lost", updated.Error)
+
+		stale, err = reservationDao.UnscopedListStale(ctx, 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, stale)
+	})
+}
+
 func TestReservationFinish(t *testing.T) {
 	reservationDao, ctx := setupReservation(t)
 	defer reset()
@@ -0,0 +1,116 @@
+//go:build integration
+// +build integration
+
+package tests
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/testing/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSchedule(t *testing.T) (dao.ScheduleDao, context.Context) {
+	ctx := identity.WithTenant(t, context.Background())
+	scheduleDao := dao.GetScheduleDao(ctx)
+	return scheduleDao, ctx
+}
+
+func createScheduledReservation(t *testing.T, ctx context.Context, cron string, nextRunAt time.Time) *models.ReservationSchedule {
+	t.Helper()
+	res := newAWSReservation()
+	err := dao.GetReservationDao(ctx).CreateAWS(ctx, res)
+	require.NoError(t, err)
+
+	schedule := &models.ReservationSchedule{
+		ReservationID: res.ID,
+		Cron:          cron,
+		NextRunAt:     nextRunAt,
+		JobType:       "launch_instances_aws",
+		JobArgs:       []byte(`{"ReservationID": 1}`),
+	}
+	err = dao.GetScheduleDao(ctx).Create(ctx, schedule)
+	require.NoError(t, err)
+	return schedule
+}
+
+func TestScheduleCreate(t *testing.T) {
+	scheduleDao, ctx := setupSchedule(t)
+	defer reset()
+
+	t.Run("success", func(t *testing.T) {
+		nextRunAt := time.Now().Add(time.Hour).UTC().Truncate(time.Minute)
+		schedule := createScheduledReservation(t, ctx, "0 2 * * *", nextRunAt)
+
+		newSchedule, err := scheduleDao.GetByReservationId(ctx, schedule.ReservationID)
+		require.NoError(t, err)
+		assert.Equal(t, schedule.ID, newSchedule.ID)
+		assert.Equal(t, "0 2 * * *", newSchedule.Cron)
+		assert.True(t, nextRunAt.Equal(newSchedule.NextRunAt))
+		assert.False(t, newSchedule.LastRunAt.Valid)
+		assert.JSONEq(t, `{"ReservationID": 1}`, string(newSchedule.JobArgs))
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		_, err := scheduleDao.GetByReservationId(ctx, math.MaxInt64)
+		require.ErrorIs(t, err, dao.ErrNoRows)
+	})
+}
+
+func TestScheduleDelete(t *testing.T) {
+	scheduleDao, ctx := setupSchedule(t)
+	defer reset()
+
+	schedule := createScheduledReservation(t, ctx, "", time.Now().Add(time.Hour))
+	err := scheduleDao.DeleteByReservationId(ctx, schedule.ReservationID)
+	require.NoError(t, err)
+
+	_, err = scheduleDao.GetByReservationId(ctx, schedule.ReservationID)
+	require.ErrorIs(t, err, dao.ErrNoRows)
+
+	err = scheduleDao.DeleteByReservationId(ctx, schedule.ReservationID)
+	require.ErrorIs(t, err, dao.ErrAffectedMismatch)
+}
+
+func TestScheduleClaimDue(t *testing.T) {
+	scheduleDao, ctx := setupSchedule(t)
+	defer reset()
+
+	now := time.Now().UTC().Truncate(time.Minute)
+	oneTime := createScheduledReservation(t, ctx, "", now.Add(-time.Minute))
+	recurring := createScheduledReservation(t, ctx, "0 2 * * *", now.Add(-time.Minute))
+	future := createScheduledReservation(t, ctx, "", now.Add(time.Hour))
+	next := now.Add(24 * time.Hour)
+
+	due, locked, err := scheduleDao.UnscopedClaimDue(ctx, now, func(ctx context.Context, schedule *models.ReservationSchedule) time.Time {
+		if schedule.Recurring() {
+			return next
+		}
+		return time.Time{}
+	})
+	require.NoError(t, err)
+	assert.True(t, locked)
+	claimed := make([]int64, 0, len(due))
+	for _, schedule := range due {
+		claimed = append(claimed, schedule.ReservationID)
+	}
+	assert.ElementsMatch(t, []int64{oneTime.ReservationID, recurring.ReservationID}, claimed)
+
+	_, err = scheduleDao.GetByReservationId(ctx, oneTime.ReservationID)
+	require.ErrorIs(t, err, dao.ErrNoRows)
+
+	updated, err := scheduleDao.GetByReservationId(ctx, recurring.ReservationID)
+	require.NoError(t, err)
+	assert.True(t, next.Equal(updated.NextRunAt))
+	require.True(t, updated.LastRunAt.Valid)
+	assert.True(t, now.Equal(updated.LastRunAt.Time))
+
+	_, err = scheduleDao.GetByReservationId(ctx, future.ReservationID)
+	require.NoError(t, err)
+}
@@ -0,0 +1,97 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/pkg/worker"
+	"github.com/rs/zerolog"
+	"golang.org/x/exp/slices"
+)
+
+var ErrReservationFinished = errors.New("reservation already finished")
+
+// Names of launch job steps recorded in reservation checkpoints.
+const (
+	StepEnsurePubkey      = "ensure_pubkey"
+	StepLaunchInstances   = "launch_instances"
+	StepFetchDescriptions = "fetch_descriptions"
+)
+
+// checkpoint tracks finished steps of a launch job. When a worker is killed in the middle of a job
+// and the job is delivered again, steps which were already finished are skipped. A step is only
+// recorded after it returns without an error, step functions must tolerate being interrupted and
+// called again.
+type checkpoint struct {
+	reservationID int64
+	completed     []string
+}
+
+// loadCheckpoint returns finished steps of a reservation. It returns ErrReservationFinished when
+// the reservation is finished, the job must not be processed again in that case.
+func loadCheckpoint(ctx context.Context, reservationID int64) (*checkpoint, error) {
+	rDao := dao.GetReservationDao(ctx)
+	reservation, err := rDao.GetById(ctx, reservationID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get reservation by id: %w", err)
+	}
+	if reservation.FinishedAt.Valid || reservation.Success.Valid {
+		return nil, fmt.Errorf("%w: %d", ErrReservationFinished, reservationID)
+	}
+
+	completed, err := rDao.UnscopedListCompletedSteps(ctx, reservationID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list completed steps: %w", err)
+	}
+	if len(completed) > 0 {
+		zerolog.Ctx(ctx).Warn().Strs("completed_steps", completed).Msg("Resuming launch job delivered again")
+	}
+
+	return &checkpoint{reservationID: reservationID, completed: completed}, nil
+}
+
+// run calls the step function unless the step was finished by a previous delivery of the job and
+// records the step when it succeeds. Jobs interrupted by a worker shutdown stop at the step
+// boundary, the step is not recorded and is run again by the re-queued job.
+func (c *checkpoint) run(ctx context.Context, step string, f func() error) error {
+	if slices.Contains(c.completed, step) {
+		zerolog.Ctx(ctx).Info().Str("job_step", step).Msg("Skipping step finished by a previous delivery")
+		return nil
+	}
+	if worker.Interrupted(ctx) {
+		return fmt.Errorf("%w: before step %s", worker.ErrJobInterrupted, step)
+	}
+
+	err := f()
+	if err != nil {
+		if worker.Interrupted(ctx) {
+			return fmt.Errorf("%w: during step %s: %s", worker.ErrJobInterrupted, step, err.Error())
+		}
+		return err
+	}
+
+	err = dao.GetReservationDao(ctx).UnscopedCompleteStep(ctx, c.reservationID, step)
+	if err != nil {
+		return fmt.Errorf("cannot record completed step %s: %w", step, err)
+	}
+	c.completed = append(c.completed, step)
+	worker.SetProgress(ctx, step)
+	return nil
+}
+
+// existingInstanceIDs returns IDs of instances already stored for the reservation, so launch steps
+// which are run again do not store the same instance twice.
+func existingInstanceIDs(ctx context.Context, reservationID int64) (map[string]bool, error) {
+	instances, err := dao.GetReservationDao(ctx).ListInstances(ctx, reservationID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get instances list: %w", err)
+	}
+
+	result := make(map[string]bool, len(instances))
+	for _, instance := range instances {
+		result[instance.InstanceID] = true
+	}
+	return result, nil
+}
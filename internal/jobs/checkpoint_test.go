@@ -0,0 +1,97 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	daoStubs "github.com/RHEnVision/provisioning-backend/internal/dao/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/testing/identity"
+	"github.com/RHEnVision/provisioning-backend/pkg/worker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errStepFailed = errors.New("step failed")
+
+func TestCheckpoint(t *testing.T) {
+	ctx := daoStubs.WithAccountDaoOne(context.Background())
+	ctx = identity.WithTenant(t, ctx)
+	ctx = daoStubs.WithReservationDao(ctx)
+
+	reservation := &models.AWSReservation{Detail: &models.AWSDetail{}}
+	reservation.AccountID = 1
+	rDao := dao.GetReservationDao(ctx)
+	require.NoError(t, rDao.CreateAWS(ctx, reservation))
+
+	calls := make(map[string]int)
+	step := func(name string, err error) func() error {
+		return func() error {
+			calls[name]++
+			return err
+		}
+	}
+
+	cp, err := loadCheckpoint(ctx, reservation.ID)
+	require.NoError(t, err)
+	require.NoError(t, cp.run(ctx, StepEnsurePubkey, step(StepEnsurePubkey, nil)))
+	require.ErrorIs(t, cp.run(ctx, StepLaunchInstances, step(StepLaunchInstances, errStepFailed)), errStepFailed)
+
+	t.Run("resumes after completed steps", func(t *testing.T) {
+		cp, err := loadCheckpoint(ctx, reservation.ID)
+		require.NoError(t, err)
+		require.NoError(t, cp.run(ctx, StepEnsurePubkey, step(StepEnsurePubkey, nil)))
+		require.NoError(t, cp.run(ctx, StepLaunchInstances, step(StepLaunchInstances, nil)))
+
+		assert.Equal(t, 1, calls[StepEnsurePubkey])
+		assert.Equal(t, 2, calls[StepLaunchInstances])
+	})
+
+	t.Run("interrupted by shutdown", func(t *testing.T) {
+		cp, err := loadCheckpoint(ctx, reservation.ID)
+		require.NoError(t, err)
+		jobCtx, cancel := context.WithCancelCause(ctx)
+		cancel(worker.ErrJobInterrupted)
+
+		err = cp.run(jobCtx, StepFetchDescriptions, step(StepFetchDescriptions, nil))
+		require.ErrorIs(t, err, worker.ErrJobInterrupted)
+		assert.Zero(t, calls[StepFetchDescriptions], "step started after interruption")
+
+		steps, err := rDao.UnscopedListCompletedSteps(ctx, reservation.ID)
+		require.NoError(t, err)
+		assert.NotContains(t, steps, StepFetchDescriptions)
+	})
+
+	t.Run("interrupted during a step", func(t *testing.T) {
+		interrupted := &models.AWSReservation{Detail: &models.AWSDetail{}}
+		interrupted.AccountID = 1
+		require.NoError(t, rDao.CreateAWS(ctx, interrupted))
+		cp, err := loadCheckpoint(ctx, interrupted.ID)
+		require.NoError(t, err)
+		jobCtx, interrupt := worker.WithInterrupt(ctx)
+
+		// the running step is finished and recorded, the next one is not started
+		err = cp.run(jobCtx, StepLaunchInstances, func() error {
+			interrupt()
+			return jobCtx.Err()
+		})
+		require.NoError(t, err)
+		err = cp.run(jobCtx, StepFetchDescriptions, step(StepFetchDescriptions, nil))
+		require.ErrorIs(t, err, worker.ErrJobInterrupted)
+		assert.Zero(t, calls[StepFetchDescriptions], "step started after interruption")
+
+		steps, err := rDao.UnscopedListCompletedSteps(ctx, interrupted.ID)
+		require.NoError(t, err)
+		assert.Contains(t, steps, StepLaunchInstances)
+		assert.NotContains(t, steps, StepFetchDescriptions)
+	})
+
+	t.Run("finished reservation", func(t *testing.T) {
+		reservation.Success = sql.NullBool{Bool: true, Valid: true}
+		_, err := loadCheckpoint(ctx, reservation.ID)
+		require.ErrorIs(t, err, ErrReservationFinished)
+	})
+}
@@ -10,15 +10,16 @@ import (
 
 	"github.com/RHEnVision/provisioning-backend/internal/dao"
 	"github.com/RHEnVision/provisioning-backend/internal/metrics"
+	"github.com/RHEnVision/provisioning-backend/pkg/worker"
 	"github.com/rs/zerolog"
 )
 
-var (
-	ErrTypeAssertion = errors.New("type assert error")
-	ErrPanicInJob    = errors.New("panic during job")
-)
-
 func finishJob(ctx context.Context, reservationId int64, jobErr error) {
+	// a job stopped by an interruption is re-queued and finishes the reservation later
+	if errors.Is(jobErr, worker.ErrJobInterrupted) {
+		finishWithError(ctx, reservationId, jobErr)
+		return
+	}
 	nc := notifications.GetNotificationClient(ctx)
 
 	if jobErr != nil {
@@ -32,6 +33,7 @@ func finishJob(ctx context.Context, reservationId int64, jobErr error) {
 
 func finishWithSuccess(ctx context.Context, reservationId int64) {
 	logger := zerolog.Ctx(ctx)
+	recordResult(ctx, nil)
 	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 		// the original context is expired and unusable at this point
 		ctx = copyContext(ctx)
@@ -64,6 +66,12 @@ func finishWithSuccess(ctx context.Context, reservationId int64) {
 // stored into the reservation.
 func finishWithError(ctx context.Context, reservationId int64, jobError error) {
 	logger := zerolog.Ctx(ctx)
+	recordResult(ctx, jobError)
+	if errors.Is(jobError, worker.ErrJobInterrupted) {
+		logger.Warn().Err(jobError).Msg("Job interrupted by worker shutdown, reservation is finished by the re-queued job")
+		worker.ReportInterrupted(ctx)
+		return
+	}
 	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 		// the original context is expired and unusable at this point
 		ctx = copyContext(ctx)
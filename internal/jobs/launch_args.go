@@ -0,0 +1,296 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/preload"
+	"github.com/RHEnVision/provisioning-backend/internal/queue"
+	"github.com/RHEnVision/provisioning-backend/pkg/worker"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownLaunchJobType      = errors.New("unknown launch job type")
+	ErrUnknownLaunchInstanceType = errors.New("unknown instance type of the reservation")
+)
+
+// DecodeLaunchArgs decodes JSON encoded arguments of a launch job type, for example arguments
+// stored with a scheduled reservation.
+func DecodeLaunchArgs(jobType worker.JobType, data []byte) (any, error) {
+	var args any
+	var err error
+	switch jobType {
+	case TypeLaunchInstanceAws:
+		awsArgs := LaunchInstanceAWSTaskArgs{}
+		err = json.Unmarshal(data, &awsArgs)
+		args = awsArgs
+	case TypeLaunchInstanceAzure:
+		azureArgs := LaunchInstanceAzureTaskArgs{}
+		err = json.Unmarshal(data, &azureArgs)
+		args = azureArgs
+	case TypeLaunchInstanceGcp:
+		gcpArgs := LaunchInstanceGCPTaskArgs{}
+		err = json.Unmarshal(data, &gcpArgs)
+		args = gcpArgs
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownLaunchJobType, jobType)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode %s arguments: %w", jobType, err)
+	}
+	return args, nil
+}
+
+// LaunchReservationID returns reservation ID of launch job arguments including no-operation
+// launches, false for other jobs.
+func LaunchReservationID(args any) (int64, bool) {
+	switch a := args.(type) {
+	case NoopJobArgs:
+		return a.ReservationID, true
+	case LaunchInstanceAWSTaskArgs:
+		return a.ReservationID, true
+	case LaunchInstanceAzureTaskArgs:
+		return a.ReservationID, true
+	case LaunchInstanceGCPTaskArgs:
+		return a.ReservationID, true
+	default:
+		return 0, false
+	}
+}
+
+// EnqueueLaunch records the time when the reservation of a launch job was queued and enqueues the
+// job. Stale reservations are looked up by this time, reservations waiting for a schedule or an
+// approval are never considered stale.
+func EnqueueLaunch(ctx context.Context, job *worker.Job) error {
+	if id, ok := LaunchReservationID(job.Args); ok {
+		err := dao.GetReservationDao(ctx).UnscopedMarkQueued(ctx, id)
+		if err != nil {
+			return fmt.Errorf("unable to mark reservation queued: %w", err)
+		}
+	}
+
+	err := queue.GetEnqueuer(ctx).Enqueue(ctx, job)
+	if err != nil {
+		return fmt.Errorf("unable to enqueue launch job: %w", err)
+	}
+	return nil
+}
+
+// StorableArgs returns a copy of launch job arguments without authentication (ARN, Azure
+// subscription or GCP project ID) and the image resolved from image builder, so the arguments can
+// be stored in the database until the job is enqueued. Both must be resolved again before enqueueing.
+func StorableArgs(args any) any {
+	switch a := args.(type) {
+	case LaunchInstanceAWSTaskArgs:
+		a.ARN = nil
+		a.AMI = ""
+		return a
+	case LaunchInstanceAzureTaskArgs:
+		a.Subscription = nil
+		a.AzureImageID = ""
+		return a
+	case LaunchInstanceGCPTaskArgs:
+		a.ProjectID = nil
+		a.ImageName = ""
+		return a
+	default:
+		return args
+	}
+}
+
+// RedactArgs returns a copy of job arguments with authentication payloads (ARN, Azure subscription
+// or GCP project ID) replaced, so the arguments can be presented to operators.
+func RedactArgs(args any) any {
+	switch a := args.(type) {
+	case LaunchInstanceAWSTaskArgs:
+		a.ARN = redactAuthentication(a.ARN)
+		return a
+	case LaunchInstanceAzureTaskArgs:
+		a.Subscription = redactAuthentication(a.Subscription)
+		return a
+	case LaunchInstanceGCPTaskArgs:
+		a.ProjectID = redactAuthentication(a.ProjectID)
+		return a
+	default:
+		return args
+	}
+}
+
+// Redacted returns arguments with redacted authentication, see RedactArgs.
+func (args LaunchInstanceAWSTaskArgs) Redacted() any {
+	return RedactArgs(args)
+}
+
+// Redacted returns arguments with redacted authentication, see RedactArgs.
+func (args LaunchInstanceAzureTaskArgs) Redacted() any {
+	return RedactArgs(args)
+}
+
+// Redacted returns arguments with redacted authentication, see RedactArgs.
+func (args LaunchInstanceGCPTaskArgs) Redacted() any {
+	return RedactArgs(args)
+}
+
+func redactAuthentication(auth *clients.Authentication) *clients.Authentication {
+	if auth == nil {
+		return nil
+	}
+	redacted := *auth
+	redacted.Payload = "****"
+	return &redacted
+}
+
+// ResolveLaunchArgs returns launch job arguments stored via StorableArgs with authentication
+// fetched from Sources and the image fetched from image builder. Reservations launched at a later
+// time use the current state of the source and the image, nothing is kept in the database.
+func ResolveLaunchArgs(ctx context.Context, args any) (any, error) {
+	switch a := args.(type) {
+	case LaunchInstanceAWSTaskArgs:
+		return resolveAWSLaunchArgs(ctx, a)
+	case LaunchInstanceAzureTaskArgs:
+		return resolveAzureLaunchArgs(ctx, a)
+	case LaunchInstanceGCPTaskArgs:
+		return resolveGCPLaunchArgs(ctx, a)
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnknownLaunchJobType, args)
+	}
+}
+
+// SourceAuthentication returns authentication of a source which must be of the given provider.
+func SourceAuthentication(ctx context.Context, sourceID string, provider models.ProviderType) (*clients.Authentication, error) {
+	sourcesClient, err := clients.GetSourcesClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get sources client: %w", err)
+	}
+
+	authentication, err := sourcesClient.GetAuthentication(ctx, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get source authentication: %w", err)
+	}
+
+	if err := authentication.MustBe(provider); err != nil {
+		return nil, fmt.Errorf("unable to use authentication: %w", err)
+	}
+	return authentication, nil
+}
+
+func resolveAWSLaunchArgs(ctx context.Context, args LaunchInstanceAWSTaskArgs) (any, error) {
+	reservation, err := dao.GetReservationDao(ctx).GetAWSById(ctx, args.ReservationID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get AWS reservation: %w", err)
+	}
+
+	args.ARN, err = SourceAuthentication(ctx, reservation.SourceID, models.ProviderTypeAWS)
+	if err != nil {
+		return nil, err
+	}
+
+	// direct AMI or no image (launch template)
+	if reservation.ImageID == "" || strings.HasPrefix(reservation.ImageID, "ami-") {
+		args.AMI = reservation.ImageID
+		return args, nil
+	}
+
+	composeUUID, err := uuid.Parse(reservation.ImageID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse image ID: %w", err)
+	}
+	instanceType := preload.EC2InstanceType.FindInstanceType(clients.InstanceTypeName(reservation.Detail.InstanceType))
+	if instanceType == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownLaunchInstanceType, reservation.Detail.InstanceType)
+	}
+
+	ibc, err := clients.GetImageBuilderClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get image builder client: %w", err)
+	}
+	args.AMI, err = ibc.GetAWSAmi(ctx, composeUUID, *instanceType)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get AMI: %w", err)
+	}
+	return args, nil
+}
+
+func resolveAzureLaunchArgs(ctx context.Context, args LaunchInstanceAzureTaskArgs) (any, error) {
+	reservation, err := dao.GetReservationDao(ctx).GetAzureById(ctx, args.ReservationID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get Azure reservation: %w", err)
+	}
+
+	args.Subscription, err = SourceAuthentication(ctx, reservation.SourceID, models.ProviderTypeAzure)
+	if err != nil {
+		return nil, err
+	}
+
+	composeUUID, err := uuid.Parse(reservation.ImageID)
+	if err != nil {
+		if strings.HasPrefix(reservation.ImageID, "composer-api") {
+			// image name in the resource group of the reservation
+			args.AzureImageID = fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/images/%s",
+				args.Subscription.Payload, args.ResourceGroupName, reservation.ImageID)
+		} else {
+			// direct Azure image ID
+			args.AzureImageID = reservation.ImageID
+		}
+		return args, nil
+	}
+
+	instanceType := preload.AzureInstanceType.FindInstanceType(clients.InstanceTypeName(reservation.Detail.InstanceSize))
+	if instanceType == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownLaunchInstanceType, reservation.Detail.InstanceSize)
+	}
+
+	ibc, err := clients.GetImageBuilderClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get image builder client: %w", err)
+	}
+	resourceGroupName, imageName, err := ibc.GetAzureImageInfo(ctx, composeUUID, *instanceType)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get Azure image: %w", err)
+	}
+	args.AzureImageID = fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/images/%s",
+		args.Subscription.Payload, resourceGroupName, imageName)
+	return args, nil
+}
+
+func resolveGCPLaunchArgs(ctx context.Context, args LaunchInstanceGCPTaskArgs) (any, error) {
+	reservation, err := dao.GetReservationDao(ctx).GetGCPById(ctx, args.ReservationID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get GCP reservation: %w", err)
+	}
+
+	args.ProjectID, err = SourceAuthentication(ctx, reservation.SourceID, models.ProviderTypeGCP)
+	if err != nil {
+		return nil, err
+	}
+
+	composeUUID, err := uuid.Parse(reservation.ImageID)
+	if err != nil {
+		// direct image name or URL
+		args.ImageName = reservation.ImageID
+		return args, nil
+	}
+
+	instanceType := preload.GCPInstanceType.FindInstanceType(clients.InstanceTypeName(reservation.Detail.MachineType))
+	if instanceType == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownLaunchInstanceType, reservation.Detail.MachineType)
+	}
+
+	ibc, err := clients.GetImageBuilderClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get image builder client: %w", err)
+	}
+	args.ImageName, err = ibc.GetGCPImageName(ctx, composeUUID, *instanceType)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get GCP image name: %w", err)
+	}
+	return args, nil
+}
@@ -0,0 +1,133 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	clientStubs "github.com/RHEnVision/provisioning-backend/internal/clients/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	daoStubs "github.com/RHEnVision/provisioning-backend/internal/dao/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/identity"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	tidentity "github.com/RHEnVision/provisioning-backend/internal/testing/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeLaunchArgs(t *testing.T) {
+	args := LaunchInstanceAWSTaskArgs{
+		ReservationID: 42,
+		Region:        "us-east-1",
+		PubkeyID:      1,
+		SourceID:      "1",
+		Detail:        &models.AWSDetail{InstanceType: "t3.small", Amount: 1},
+		AMI:           "ami-12345",
+		ARN:           clients.NewAuthentication("arn:aws:iam::1:role/test", models.ProviderTypeAWS),
+	}
+	data, err := json.Marshal(args)
+	require.NoError(t, err)
+
+	decoded, err := DecodeLaunchArgs(TypeLaunchInstanceAws, data)
+	require.NoError(t, err)
+	require.Equal(t, args, decoded)
+}
+
+func TestDecodeLaunchArgsUnknownType(t *testing.T) {
+	_, err := DecodeLaunchArgs(TypeNoop, []byte("{}"))
+	require.ErrorIs(t, err, ErrUnknownLaunchJobType)
+}
+
+func TestRedactArgs(t *testing.T) {
+	args := LaunchInstanceAzureTaskArgs{
+		ReservationID: 42,
+		Subscription:  clients.NewAuthentication("4b9d213f-712f-4d17-a483-8a10bbe9df3a", models.ProviderTypeAzure),
+	}
+
+	redacted, ok := RedactArgs(args).(LaunchInstanceAzureTaskArgs)
+	require.True(t, ok)
+	require.Equal(t, "****", redacted.Subscription.Payload)
+	require.Equal(t, models.ProviderTypeAzure, redacted.Subscription.ProviderType)
+	require.Equal(t, int64(42), redacted.ReservationID)
+	require.Equal(t, "4b9d213f-712f-4d17-a483-8a10bbe9df3a", args.Subscription.Payload, "original arguments must not change")
+}
+
+func TestStorableArgs(t *testing.T) {
+	args := LaunchInstanceGCPTaskArgs{
+		ReservationID: 42,
+		Zone:          "us-east1-b",
+		ImageName:     "projects/red-hat-image-builder/global/images/composer-api-test",
+		ProjectID:     clients.NewAuthentication("test@org.com", models.ProviderTypeGCP),
+	}
+
+	storable, ok := StorableArgs(args).(LaunchInstanceGCPTaskArgs)
+	require.True(t, ok)
+	require.Nil(t, storable.ProjectID)
+	require.Empty(t, storable.ImageName)
+	require.Equal(t, int64(42), storable.ReservationID)
+	require.Equal(t, "us-east1-b", storable.Zone)
+	require.NotNil(t, args.ProjectID, "original arguments must not change")
+}
+
+func TestResolveLaunchArgs(t *testing.T) {
+	ctx := daoStubs.WithAccountDaoOne(context.Background())
+	ctx = tidentity.WithTenant(t, ctx)
+	ctx = daoStubs.WithReservationDao(ctx)
+	ctx = clientStubs.WithSourcesClient(ctx)
+	ctx = clientStubs.WithImageBuilderClient(ctx)
+
+	t.Run("AWS compose", func(t *testing.T) {
+		reservation := &models.AWSReservation{
+			SourceID: "1",
+			ImageID:  "2bc640f6-927a-404a-9594-5b2da7e06608",
+			Detail:   &models.AWSDetail{Region: "us-east-1", InstanceType: "t1.micro", Amount: 1},
+		}
+		reservation.AccountID = identity.AccountId(ctx)
+		reservation.Provider = models.ProviderTypeAWS
+		require.NoError(t, daoStubs.AddAWSReservation(ctx, reservation))
+
+		resolved, err := ResolveLaunchArgs(ctx, LaunchInstanceAWSTaskArgs{ReservationID: reservation.ID, Region: "us-east-1"})
+		require.NoError(t, err)
+		args, ok := resolved.(LaunchInstanceAWSTaskArgs)
+		require.True(t, ok)
+		assert.Equal(t, "ami-0c830793775595d4b-test", args.AMI)
+		require.NotNil(t, args.ARN)
+		assert.Equal(t, models.ProviderTypeAWS, args.ARN.ProviderType)
+		assert.Equal(t, "us-east-1", args.Region)
+	})
+
+	t.Run("Azure compose", func(t *testing.T) {
+		source, err := clientStubs.AddSource(ctx, models.ProviderTypeAzure)
+		require.NoError(t, err)
+		reservation := &models.AzureReservation{
+			SourceID: source.ID,
+			ImageID:  "92ea98f8-7697-472e-80b1-7454fa0e7fa7",
+			Detail:   &models.AzureDetail{Location: "eastus", InstanceSize: "Standard_A1_v2", Amount: 1},
+		}
+		reservation.AccountID = identity.AccountId(ctx)
+		reservation.Provider = models.ProviderTypeAzure
+		require.NoError(t, dao.GetReservationDao(ctx).CreateAzure(ctx, reservation))
+
+		resolved, err := ResolveLaunchArgs(ctx, LaunchInstanceAzureTaskArgs{ReservationID: reservation.ID})
+		require.NoError(t, err)
+		args, ok := resolved.(LaunchInstanceAzureTaskArgs)
+		require.True(t, ok)
+		require.NotNil(t, args.Subscription)
+		assert.Equal(t, "/subscriptions/4b9d213f-712f-4d17-a483-8a10bbe9df3a/resourceGroups/myTestGroup/providers/Microsoft.Compute/images/composer-api-92ea98f8-7697-472e-80b1-7454fa0e7fa7", args.AzureImageID)
+	})
+
+	t.Run("source of another provider", func(t *testing.T) {
+		reservation := &models.GCPReservation{
+			SourceID: "1",
+			ImageID:  "projects/rhel-cloud/global/images/rhel-9",
+			Detail:   &models.GCPDetail{Zone: "us-east1-b", MachineType: "e2-micro", Amount: 1},
+		}
+		reservation.AccountID = identity.AccountId(ctx)
+		reservation.Provider = models.ProviderTypeGCP
+		require.NoError(t, dao.GetReservationDao(ctx).CreateGCP(ctx, reservation))
+
+		_, err := ResolveLaunchArgs(ctx, LaunchInstanceGCPTaskArgs{ReservationID: reservation.ID})
+		require.ErrorIs(t, err, clients.ErrUnknownAuthenticationType)
+	})
+}
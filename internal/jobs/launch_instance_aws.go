@@ -4,14 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/RHEnVision/provisioning-backend/internal/clients"
 	"github.com/RHEnVision/provisioning-backend/internal/clients/http"
+	"github.com/RHEnVision/provisioning-backend/internal/config"
 	"github.com/RHEnVision/provisioning-backend/internal/dao"
 	"github.com/RHEnVision/provisioning-backend/internal/models"
 	"github.com/RHEnVision/provisioning-backend/internal/telemetry"
 	"github.com/RHEnVision/provisioning-backend/internal/userdata"
-	"github.com/RHEnVision/provisioning-backend/pkg/worker"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/rs/zerolog"
 	"go.opentelemetry.io/otel/codes"
@@ -43,46 +44,33 @@ type LaunchInstanceAWSTaskArgs struct {
 	ARN *clients.Authentication
 }
 
-// HandleLaunchInstanceAWS unmarshalls arguments and handles error
-func HandleLaunchInstanceAWS(ctx context.Context, job *worker.Job) {
+// HandleLaunchInstanceAWS runs launch job steps and finishes the reservation
+func HandleLaunchInstanceAWS(ctx context.Context, args LaunchInstanceAWSTaskArgs) {
 	logger := zerolog.Ctx(ctx)
-	if job == nil {
-		logger.Error().Msg("No job for HandleLaunchInstanceAWS")
-		return
-	}
+	logger.Info().Msg("Started launch instance AWS job")
 
-	args, ok := job.Args.(LaunchInstanceAWSTaskArgs)
-	if !ok {
-		err := fmt.Errorf("%w: job %s, reservation: %#v", ErrTypeAssertion, job.ID, job.Args)
-		logger.Error().Err(err).Msg("Type assertion error for job")
+	cp, jobErr := loadCheckpoint(ctx, args.ReservationID)
+	if errors.Is(jobErr, ErrReservationFinished) {
+		logger.Warn().Msg("Reservation already finished, skipping the job")
+		return
+	} else if jobErr != nil {
+		finishWithError(ctx, args.ReservationID, jobErr)
 		return
 	}
 
-	// context and logger
-	ctx, logger = reservationContextLogger(ctx, args.ReservationID)
-	logger.Info().Msg("Started launch instance AWS job")
-
-	// ensure panic finishes the job
-	defer func() {
-		if r := recover(); r != nil {
-			panicErr := fmt.Errorf("%w: %s", ErrPanicInJob, r)
-			finishWithError(ctx, args.ReservationID, panicErr)
-		}
-	}()
-
-	jobErr := DoEnsurePubkeyOnAWS(ctx, &args)
+	jobErr = cp.run(ctx, StepEnsurePubkey, func() error { return DoEnsurePubkeyOnAWS(ctx, &args) })
 	if jobErr != nil {
 		finishWithError(ctx, args.ReservationID, jobErr)
 		return
 	}
 
-	jobErr = DoLaunchInstanceAWS(ctx, &args)
+	jobErr = cp.run(ctx, StepLaunchInstances, func() error { return DoLaunchInstanceAWS(ctx, &args) })
 	if jobErr != nil {
 		finishWithError(ctx, args.ReservationID, jobErr)
 		return
 	}
 
-	jobErr = FetchInstancesDescriptionAWS(ctx, &args)
+	jobErr = cp.run(ctx, StepFetchDescriptions, func() error { return FetchInstancesDescriptionAWS(ctx, &args) })
 	if jobErr != nil {
 		finishWithError(ctx, args.ReservationID, jobErr)
 	}
@@ -219,6 +207,11 @@ func DoLaunchInstanceAWS(ctx context.Context, args *LaunchInstanceAWSTaskArgs) e
 		span.SetStatus(codes.Error, "cannot get aws reservation by id")
 		return fmt.Errorf("cannot get aws reservation by id: %w", err)
 	}
+	if reservation.AWSReservationID != nil {
+		// the reservation id is stored last, all instances are stored too
+		logger.Warn().Str("aws_reservation_id", *reservation.AWSReservationID).Msg("Instances already launched, skipping")
+		return nil
+	}
 
 	// Generate user data
 	userDataInput := userdata.UserData{
@@ -244,6 +237,11 @@ func DoLaunchInstanceAWS(ctx context.Context, args *LaunchInstanceAWSTaskArgs) e
 		AMI:              args.AMI,
 		KeyName:          reservation.Detail.PubkeyName,
 		UserData:         userData,
+		RootVolume:       args.Detail.RootVolume,
+		Volumes:          args.Detail.Volumes,
+		Tags:             args.Detail.Tags,
+		// launching the same reservation again returns the already launched instances
+		ClientToken: config.EnvironmentPrefix("r", strconv.FormatInt(args.ReservationID, 10)),
 	}
 
 	logger.Trace().Msg("Executing RunInstances")
@@ -253,8 +251,17 @@ func DoLaunchInstanceAWS(ctx context.Context, args *LaunchInstanceAWSTaskArgs) e
 		return fmt.Errorf("cannot run instances: %w", err)
 	}
 
+	existing, err := existingInstanceIDs(ctx, args.ReservationID)
+	if err != nil {
+		span.SetStatus(codes.Error, "cannot get instances list")
+		return err
+	}
+
 	// For each instance that was created in AWS, add it as a DB record
 	for _, instanceId := range instances {
+		if existing[*instanceId] {
+			continue
+		}
 		err = resD.CreateInstance(ctx, &models.ReservationInstance{
 			ReservationID: args.ReservationID,
 			InstanceID:    *instanceId,
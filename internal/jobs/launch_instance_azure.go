@@ -2,6 +2,7 @@ package jobs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -15,7 +16,6 @@ import (
 	"github.com/RHEnVision/provisioning-backend/internal/models"
 	"github.com/RHEnVision/provisioning-backend/internal/ptr"
 	"github.com/RHEnVision/provisioning-backend/internal/userdata"
-	"github.com/RHEnVision/provisioning-backend/pkg/worker"
 	"github.com/rs/zerolog"
 	"go.opentelemetry.io/otel/codes"
 )
@@ -54,22 +54,9 @@ type LaunchInstanceAzureTaskArgs struct {
 	Name string
 }
 
-func HandleLaunchInstanceAzure(ctx context.Context, job *worker.Job) {
+// HandleLaunchInstanceAzure runs launch job steps and finishes the reservation
+func HandleLaunchInstanceAzure(ctx context.Context, args LaunchInstanceAzureTaskArgs) {
 	logger := zerolog.Ctx(ctx)
-	if job == nil {
-		logger.Error().Msg("No job for HandleLaunchInstanceAzure")
-		return
-	}
-
-	args, ok := job.Args.(LaunchInstanceAzureTaskArgs)
-	if !ok {
-		err := fmt.Errorf("%w: job %s, reservation: %#v", ErrTypeAssertion, job.ID, job.Args)
-		logger.Error().Err(err).Msg("Type assertion error for job")
-		return
-	}
-
-	// context and logger
-	ctx, logger = reservationContextLogger(ctx, args.ReservationID)
 	logger.Info().Msg("Started launch instance Azure job")
 
 	if args.ResourceGroupName == "" {
@@ -89,24 +76,23 @@ func HandleLaunchInstanceAzure(ctx context.Context, job *worker.Job) {
 		args.Name = DefaultVMName
 	}
 
-	// ensure panic finishes the job
-	defer func() {
-		if r := recover(); r != nil {
-			panicErr := fmt.Errorf("%w: %s", ErrPanicInJob, r)
-			finishWithError(ctx, args.ReservationID, panicErr)
-		}
-	}()
-
-	ctx, span := telemetry.StartSpan(ctx, "LaunchInstanceAzureJob")
-	defer span.End()
+	cp, jobErr := loadCheckpoint(ctx, args.ReservationID)
+	if errors.Is(jobErr, ErrReservationFinished) {
+		logger.Warn().Msg("Reservation already finished, skipping the job")
+		return
+	} else if jobErr != nil {
+		finishWithError(ctx, args.ReservationID, jobErr)
+		return
+	}
 
-	jobErr := DoEnsureAzureResourceGroup(ctx, &args)
+	// not checkpointed, ensuring the group is idempotent and it sets the location
+	jobErr = DoEnsureAzureResourceGroup(ctx, &args)
 	if jobErr != nil {
 		finishWithError(ctx, args.ReservationID, jobErr)
 		return
 	}
 
-	jobErr = DoLaunchInstanceAzure(ctx, &args)
+	jobErr = cp.run(ctx, StepLaunchInstances, func() error { return DoLaunchInstanceAzure(ctx, &args) })
 	if jobErr != nil {
 		finishWithError(ctx, args.ReservationID, jobErr)
 		return
@@ -171,11 +157,39 @@ func DoLaunchInstanceAzure(ctx context.Context, args *LaunchInstanceAzureTaskArg
 		return fmt.Errorf("cannot get azure reservation by id: %w", err)
 	}
 
+	existing, err := existingInstanceIDs(ctx, args.ReservationID)
+	if err != nil {
+		span.SetStatus(codes.Error, "cannot get instances list")
+		return err
+	}
+
 	azureClient, err := clients.GetAzureClient(ctx, args.Subscription)
 	if err != nil {
 		span.SetStatus(codes.Error, "cannot instantiate Azure client")
 		return fmt.Errorf("failed to instantiate Azure client: %w", err)
 	}
+
+	// VM names are random, VMs tagged with the reservation are the only trace of a previous delivery
+	rid := config.EnvironmentPrefix("r", strconv.FormatInt(reservation.ID, 10))
+	instanceDescriptions, err := azureClient.ListVMsByTag(ctx, "rh-rid", rid)
+	if err != nil {
+		span.SetStatus(codes.Error, "cannot list tagged instances")
+		return fmt.Errorf("cannot list Azure instances: %w", err)
+	}
+	missing := reservation.Detail.Amount - int64(len(instanceDescriptions))
+	if len(instanceDescriptions) > 0 {
+		zerolog.Ctx(ctx).Warn().Int("instances", len(instanceDescriptions)).Int64("missing", missing).
+			Msg("Instances already launched by a previous delivery")
+	}
+	if missing <= 0 {
+		err = storeAzureInstances(ctx, args.ReservationID, instanceDescriptions, existing)
+		if err != nil {
+			span.SetStatus(codes.Error, "failed to save instance to DB")
+			return err
+		}
+		return nil
+	}
+
 	// Generate user data
 	userDataInput := userdata.UserData{
 		Type:         models.ProviderTypeAzure,
@@ -188,6 +202,17 @@ func DoLaunchInstanceAzure(ctx context.Context, args *LaunchInstanceAzureTaskArg
 		return fmt.Errorf("cannot generate user data: %w", err)
 	}
 
+	tags := map[string]*string{
+		"rh-rid": ptr.To(rid),
+		"rh-org": ptr.To(identity.Identity(ctx).Identity.OrgID),
+	}
+	// user tags must not override the tags above
+	for key, value := range reservation.Detail.Tags {
+		if _, ok := tags[key]; !ok {
+			tags[key] = ptr.To(value)
+		}
+	}
+
 	vmParams := clients.AzureInstanceParams{
 		Location:          args.Location,
 		ResourceGroupName: args.ResourceGroupName,
@@ -195,21 +220,36 @@ func DoLaunchInstanceAzure(ctx context.Context, args *LaunchInstanceAzureTaskArg
 		Pubkey:            pubkey,
 		InstanceType:      clients.InstanceTypeName(reservation.Detail.InstanceSize),
 		UserData:          userData,
-		Tags: map[string]*string{
-			"rh-rid": ptr.To(config.EnvironmentPrefix("r", strconv.FormatInt(reservation.ID, 10))),
-			"rh-org": ptr.To(identity.Identity(ctx).Identity.OrgID),
-		},
+		Tags:              tags,
+		RootVolume:        reservation.Detail.RootVolume,
+		Volumes:           reservation.Detail.Volumes,
 	}
 
-	instanceDescriptions, err := azureClient.CreateVMs(ctx, vmParams, reservation.Detail.Amount, args.Name)
+	created, err := azureClient.CreateVMs(ctx, vmParams, missing, args.Name)
 	if err != nil {
 		span.SetStatus(codes.Error, "failed to create instances")
 		return fmt.Errorf("cannot create Azure instance: %w", err)
 	}
 
+	err = storeAzureInstances(ctx, args.ReservationID, append(instanceDescriptions, created...), existing)
+	if err != nil {
+		span.SetStatus(codes.Error, "failed to save instance to DB")
+		return err
+	}
+
+	return nil
+}
+
+// storeAzureInstances stores launched instances which are not stored yet.
+func storeAzureInstances(ctx context.Context, reservationID int64, instanceDescriptions []clients.InstanceDescription, existing map[string]bool) error {
+	resDao := dao.GetReservationDao(ctx)
+
 	for _, instanceDescription := range instanceDescriptions {
-		err = resDao.CreateInstance(ctx, &models.ReservationInstance{
-			ReservationID: args.ReservationID,
+		if existing[instanceDescription.ID] {
+			continue
+		}
+		err := resDao.CreateInstance(ctx, &models.ReservationInstance{
+			ReservationID: reservationID,
 			InstanceID:    instanceDescription.ID,
 			Detail: models.ReservationInstanceDetail{
 				PublicIPv4:  instanceDescription.IPv4,
@@ -217,7 +257,6 @@ func DoLaunchInstanceAzure(ctx context.Context, args *LaunchInstanceAzureTaskArg
 			},
 		})
 		if err != nil {
-			span.SetStatus(codes.Error, "failed to save instance to DB")
 			return fmt.Errorf("cannot create instance reservation for id %s: %w", instanceDescription.ID, err)
 		}
 	}
@@ -2,14 +2,17 @@ package jobs_test
 
 import (
 	"context"
+	"strconv"
 	"testing"
 
 	"github.com/RHEnVision/provisioning-backend/internal/clients"
 	clientStubs "github.com/RHEnVision/provisioning-backend/internal/clients/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/config"
 	"github.com/RHEnVision/provisioning-backend/internal/dao"
 	daoStubs "github.com/RHEnVision/provisioning-backend/internal/dao/stubs"
 	"github.com/RHEnVision/provisioning-backend/internal/jobs"
 	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/ptr"
 	"github.com/RHEnVision/provisioning-backend/internal/testing/factories"
 	"github.com/RHEnVision/provisioning-backend/internal/testing/identity"
 	"github.com/stretchr/testify/assert"
@@ -141,4 +144,59 @@ func TestDoLaunchInstanceAzure(t *testing.T) {
 	require.NoError(t, err, "failed to fetch created instances")
 	assert.Len(t, resultInstances, 2)
 	assert.NotEmpty(t, resultInstances[0].Detail.PublicIPv4)
+
+	t.Run("delivered again", func(t *testing.T) {
+		err = jobs.DoLaunchInstanceAzure(ctx, args)
+		require.NoError(t, err, "launch instances failed to run")
+
+		assert.Equal(t, 2, clientStubs.CountStubAzureVMs(ctx))
+		resultInstances, err := rDao.ListInstances(ctx, res.ID)
+		require.NoError(t, err, "failed to fetch created instances")
+		assert.Len(t, resultInstances, 2)
+	})
+}
+
+func TestDoLaunchInstanceAzureRedelivered(t *testing.T) {
+	ctx := prepareAzureContext(t)
+
+	pk := factories.NewPubkeyRSA()
+	err := daoStubs.AddPubkey(ctx, pk)
+	require.NoError(t, err, "failed to add stubbed key")
+
+	res := prepareAzureReservation(t, ctx, pk)
+	res.Detail.Amount = 2
+
+	rDao := dao.GetReservationDao(ctx)
+	err = rDao.CreateAzure(ctx, res)
+	require.NoError(t, err, "failed to add stubbed reservation")
+
+	auth := clients.NewAuthentication("subUUID", models.ProviderTypeAzure)
+	args := &jobs.LaunchInstanceAzureTaskArgs{
+		AzureImageID:  "/subscriptions/subUUID/rgName/images/uuid2",
+		Location:      "useast",
+		PubkeyID:      pk.ID,
+		ReservationID: res.ID,
+		SourceID:      "2",
+		Subscription:  auth,
+	}
+
+	// previous delivery created a VM but the worker was lost before the instance was stored
+	azureClient, err := clients.GetAzureClient(ctx, auth)
+	require.NoError(t, err, "failed to get stubbed client")
+	params := clients.AzureInstanceParams{
+		Location: "useast",
+		Tags:     map[string]*string{"rh-rid": ptr.To(config.EnvironmentPrefix("r", strconv.FormatInt(res.ID, 10)))},
+	}
+	launched, err := azureClient.CreateVMs(ctx, params, 1, "previous")
+	require.NoError(t, err, "failed to create stubbed VM")
+
+	err = jobs.DoLaunchInstanceAzure(ctx, args)
+	require.NoError(t, err, "launch instances failed to run")
+
+	assert.Equal(t, 2, clientStubs.CountStubAzureVMs(ctx))
+	resultInstances, err := rDao.ListInstances(ctx, res.ID)
+	require.NoError(t, err, "failed to fetch created instances")
+	require.Len(t, resultInstances, 2)
+	assert.Equal(t, launched[0].ID, resultInstances[0].InstanceID)
+	assert.NotEqual(t, launched[0].ID, resultInstances[1].InstanceID)
 }
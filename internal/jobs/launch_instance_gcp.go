@@ -2,6 +2,7 @@ package jobs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/RHEnVision/provisioning-backend/internal/telemetry"
@@ -11,8 +12,8 @@ import (
 	_ "github.com/RHEnVision/provisioning-backend/internal/clients/http/gcp"
 	"github.com/RHEnVision/provisioning-backend/internal/dao"
 	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/ptr"
 	"github.com/RHEnVision/provisioning-backend/internal/userdata"
-	"github.com/RHEnVision/provisioning-backend/pkg/worker"
 	"github.com/rs/zerolog"
 )
 
@@ -41,39 +42,27 @@ type LaunchInstanceGCPTaskArgs struct {
 	LaunchTemplateID string
 }
 
-// HandleLaunchInstanceGCP unmarshalls arguments and handles error
-func HandleLaunchInstanceGCP(ctx context.Context, job *worker.Job) {
+// HandleLaunchInstanceGCP runs launch job steps and finishes the reservation
+func HandleLaunchInstanceGCP(ctx context.Context, args LaunchInstanceGCPTaskArgs) {
 	logger := zerolog.Ctx(ctx)
-	if job == nil {
-		logger.Error().Msg("No job for HandleLaunchInstanceGCP")
+	logger.Info().Msg("Started launch instance GCP job")
+
+	cp, jobErr := loadCheckpoint(ctx, args.ReservationID)
+	if errors.Is(jobErr, ErrReservationFinished) {
+		logger.Warn().Msg("Reservation already finished, skipping the job")
 		return
-	}
-	args, ok := job.Args.(LaunchInstanceGCPTaskArgs)
-	if !ok {
-		err := fmt.Errorf("%w: job %s, reservation: %#v", ErrTypeAssertion, job.ID, job.Args)
-		logger.Error().Err(err).Msg("Type assertion error for job")
+	} else if jobErr != nil {
+		finishWithError(ctx, args.ReservationID, jobErr)
 		return
 	}
 
-	// context and logger
-	ctx, logger = reservationContextLogger(ctx, args.ReservationID)
-	logger.Info().Msg("Started launch instance GCP job")
-
-	// ensure panic finishes the job
-	defer func() {
-		if r := recover(); r != nil {
-			panicErr := fmt.Errorf("%w: %s", ErrPanicInJob, r)
-			finishWithError(ctx, args.ReservationID, panicErr)
-		}
-	}()
-
-	jobErr := DoLaunchInstanceGCP(ctx, &args)
+	jobErr = cp.run(ctx, StepLaunchInstances, func() error { return DoLaunchInstanceGCP(ctx, &args) })
 	if jobErr != nil {
 		finishWithError(ctx, args.ReservationID, jobErr)
 		return
 	}
 
-	jobErr = FetchInstancesDescriptionGCP(ctx, &args)
+	jobErr = cp.run(ctx, StepFetchDescriptions, func() error { return FetchInstancesDescriptionGCP(ctx, &args) })
 	if jobErr != nil {
 		finishWithError(ctx, args.ReservationID, jobErr)
 		return
@@ -130,24 +119,48 @@ func DoLaunchInstanceGCP(ctx context.Context, args *LaunchInstanceGCPTaskArgs) e
 		ReservationID:    args.ReservationID,
 		UUID:             args.Detail.UUID,
 		LaunchTemplateID: args.LaunchTemplateID,
+		RootVolume:       args.Detail.RootVolume,
+		Volumes:          args.Detail.Volumes,
+		Labels:           args.Detail.Tags,
 	}
 
-	instances, opName, err := gcpClient.InsertInstances(ctx, params, args.Detail.Amount)
+	rDao := dao.GetReservationDao(ctx)
+
+	// instances are labeled with the reservation UUID, a job delivered again finds them
+	instances, err := gcpClient.ListInstancesIDsByLabel(ctx, args.Detail.UUID)
 	if err != nil {
-		span.SetStatus(codes.Error, "cannot run instances for gcp client")
-		return fmt.Errorf("cannot run instances for gcp client: %w", err)
+		span.SetStatus(codes.Error, "cannot list instances ids by tag")
+		return fmt.Errorf("cannot list instances ids by tag: %w", err)
 	}
 
-	rDao := dao.GetReservationDao(ctx)
+	opName := ptr.To("")
+	if len(instances) > 0 {
+		logger.Warn().Int("instances", len(instances)).Msg("Instances already launched, skipping")
+	} else {
+		instances, opName, err = gcpClient.InsertInstances(ctx, params, args.Detail.Amount)
+		if err != nil {
+			span.SetStatus(codes.Error, "cannot run instances for gcp client")
+			return fmt.Errorf("cannot run instances for gcp client: %w", err)
+		}
+
+		err = rDao.UpdateOperationNameForGCP(ctx, args.ReservationID, *opName)
+		if err != nil {
+			span.SetStatus(codes.Error, "cannot update operation name for GCP")
+			return fmt.Errorf("cannot update operation name for GCP: %w", err)
+		}
+	}
 
-	err = rDao.UpdateOperationNameForGCP(ctx, args.ReservationID, *opName)
+	existing, err := existingInstanceIDs(ctx, args.ReservationID)
 	if err != nil {
-		span.SetStatus(codes.Error, "cannot update operation name for GCP")
-		return fmt.Errorf("cannot update operation name for GCP: %w", err)
+		span.SetStatus(codes.Error, "cannot get instances list")
+		return err
 	}
 
 	// For each instance that was created in GCP, add it as a DB record
 	for _, instanceId := range instances {
+		if existing[*instanceId] {
+			continue
+		}
 		err = rDao.CreateInstance(ctx, &models.ReservationInstance{
 			ReservationID: args.ReservationID,
 			InstanceID:    *instanceId,
@@ -0,0 +1,97 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/RHEnVision/provisioning-backend/internal/config"
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/ptr"
+	"github.com/RHEnVision/provisioning-backend/pkg/worker"
+	"github.com/rs/zerolog"
+)
+
+type executionCtxKeyType int
+
+const executionCtxKey executionCtxKeyType = iota
+
+// ReservationContext adds the reservation ID of launch jobs to the context and logger.
+func ReservationContext(next worker.JobHandler) worker.JobHandler {
+	return func(ctx context.Context, job *worker.Job) {
+		if id, ok := LaunchReservationID(job.Args); ok {
+			ctx, _ = reservationContextLogger(ctx, id)
+		}
+		next(ctx, job)
+	}
+}
+
+// RecordExecution stores a record of every job attempt with the worker hostname and the result.
+// The result is success unless the reservation was finished with an error or the job was
+// interrupted by worker shutdown.
+func RecordExecution(next worker.JobHandler) worker.JobHandler {
+	return func(ctx context.Context, job *worker.Job) {
+		logger := zerolog.Ctx(ctx)
+		execution := &models.JobExecution{
+			JobID:     job.ID,
+			JobType:   job.Type.String(),
+			AccountID: job.AccountID,
+			Hostname:  config.Hostname(),
+		}
+		if id, ok := LaunchReservationID(job.Args); ok {
+			execution.ReservationID = sql.NullInt64{Int64: id, Valid: true}
+		}
+
+		exDao := dao.GetJobExecutionDao(ctx)
+		err := exDao.UnscopedCreate(ctx, execution)
+		if err != nil {
+			logger.Warn().Err(err).Msg("Unable to record job execution")
+			next(ctx, job)
+			return
+		}
+		logger = ptr.To(logger.With().Int32("attempt", execution.Attempt).Logger())
+
+		next(context.WithValue(logger.WithContext(ctx), executionCtxKey, execution), job)
+
+		if execution.Result == "" {
+			execution.Result = models.JobResultSuccess
+		}
+		if ctx.Err() != nil {
+			// the job context is cancelled, the record must be still finished
+			ctx = copyContext(ctx)
+		}
+		err = exDao.UnscopedFinish(ctx, execution)
+		if err != nil {
+			logger.Warn().Err(err).Msg("Unable to finish job execution record")
+		}
+	}
+}
+
+// recordResult sets the result of the current job attempt, it does nothing when the job is not
+// recorded.
+func recordResult(ctx context.Context, jobErr error) {
+	execution, ok := ctx.Value(executionCtxKey).(*models.JobExecution)
+	if !ok {
+		return
+	}
+	if errors.Is(jobErr, worker.ErrJobInterrupted) {
+		execution.Result = models.JobResultInterrupted
+		execution.Error = jobErr.Error()
+	} else if jobErr != nil {
+		execution.Result = models.JobResultFailure
+		execution.Error = jobErr.Error()
+	} else {
+		execution.Result = models.JobResultSuccess
+	}
+}
+
+// FinishReservationOnPanic records the error of a job which panicked and finishes the reservation
+// of launch jobs.
+func FinishReservationOnPanic(ctx context.Context, job *worker.Job, err error) {
+	recordResult(ctx, err)
+	if id, ok := LaunchReservationID(job.Args); ok {
+		ctx, _ = reservationContextLogger(ctx, id)
+		finishWithError(ctx, id, err)
+	}
+}
@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	daoStubs "github.com/RHEnVision/provisioning-backend/internal/dao/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/testing/identity"
+	"github.com/RHEnVision/provisioning-backend/pkg/worker"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordExecution(t *testing.T) {
+	ctx := daoStubs.WithAccountDaoOne(context.Background())
+	ctx = identity.WithTenant(t, ctx)
+	ctx = daoStubs.WithJobExecutionDao(ctx)
+
+	job := &worker.Job{
+		ID:        uuid.New(),
+		Type:      TypeNoop,
+		AccountID: 1,
+		Args:      NoopJobArgs{ReservationID: 42},
+	}
+	run := func(ctx context.Context, handler worker.JobHandler) {
+		RecordExecution(handler)(ctx, job)
+	}
+
+	run(ctx, func(ctx context.Context, _ *worker.Job) {
+		recordResult(ctx, ErrNoOperationFailure)
+	})
+	run(ctx, func(ctx context.Context, _ *worker.Job) {
+		recordResult(ctx, fmt.Errorf("%w: before step %s", worker.ErrJobInterrupted, StepLaunchInstances))
+	})
+	run(ctx, func(_ context.Context, _ *worker.Job) {})
+
+	executions, err := dao.GetJobExecutionDao(ctx).ListByReservation(ctx, 42)
+	require.NoError(t, err)
+	require.Len(t, executions, 3)
+	for i, execution := range executions {
+		assert.EqualValues(t, i+1, execution.Attempt)
+		assert.Equal(t, job.ID, execution.JobID)
+		assert.True(t, execution.FinishedAt.Valid)
+	}
+	assert.Equal(t, models.JobResultFailure, executions[0].Result)
+	assert.Equal(t, ErrNoOperationFailure.Error(), executions[0].Error)
+	assert.Equal(t, models.JobResultInterrupted, executions[1].Result)
+	assert.Equal(t, models.JobResultSuccess, executions[2].Result)
+}
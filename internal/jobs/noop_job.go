@@ -3,11 +3,9 @@ package jobs
 import (
 	"context"
 	"errors"
-	"fmt"
 	"time"
 
 	"github.com/RHEnVision/provisioning-backend/internal/notifications"
-	"github.com/RHEnVision/provisioning-backend/pkg/worker"
 	"github.com/rs/zerolog"
 )
 
@@ -19,23 +17,8 @@ type NoopJobArgs struct {
 
 var ErrNoOperationFailure = errors.New("job failed on request")
 
-// HandleNoop unmarshalls arguments and handle error
-func HandleNoop(ctx context.Context, job *worker.Job) {
-	if job == nil {
-		zerolog.Ctx(ctx).Error().Msg("No job to handle")
-		return
-	}
-
-	args, ok := job.Args.(NoopJobArgs)
-	if !ok {
-		err := fmt.Errorf("%w: job %s, reservation: %#v", ErrTypeAssertion, job.ID, job.Args)
-		zerolog.Ctx(ctx).Error().Err(err).Msg("Type assertion error for job")
-		return
-	}
-
-	// context and logger
-	ctx, _ = reservationContextLogger(ctx, args.ReservationID)
-
+// HandleNoop runs the no-operation job and finishes the reservation
+func HandleNoop(ctx context.Context, args NoopJobArgs) {
 	nc := notifications.GetNotificationClient(ctx)
 
 	jobErr := DoNoop(ctx, &args)
@@ -11,6 +11,11 @@ type Broker interface {
 
 	// Consume messages of a single topic in a loop. Blocking call, use context cancellation to stop.
 	Consume(ctx context.Context, topic string, since time.Time, handler func(ctx context.Context, message *GenericMessage))
+
+	// ConsumeGroup consumes messages of a single topic as a member of a consumer group in a loop.
+	// Offsets are committed after the handler returns, so a restarted consumer continues after
+	// the last processed message. Blocking call, use context cancellation to stop.
+	ConsumeGroup(ctx context.Context, topic, group string, handler func(ctx context.Context, message *GenericMessage))
 }
 
 var broker Broker = &noopBroker{}
@@ -23,3 +28,7 @@ func Send(ctx context.Context, messages ...*GenericMessage) error {
 func Consume(ctx context.Context, topic string, since time.Time, handler func(ctx context.Context, message *GenericMessage)) {
 	broker.Consume(ctx, topic, since, handler)
 }
+
+func ConsumeGroup(ctx context.Context, topic, group string, handler func(ctx context.Context, message *GenericMessage)) {
+	broker.ConsumeGroup(ctx, topic, group, handler)
+}
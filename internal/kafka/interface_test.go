@@ -64,3 +64,22 @@ func TestMultipleTopics(t *testing.T) {
 	_ = bus.Send(ctx, createMessage("topic2", "key2", "value"))
 	wg.Wait()
 }
+
+func TestSendAndConsumeGroup(t *testing.T) {
+	ctx := context.Background()
+	bus := NewStubBroker(16)
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	cct, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go bus.ConsumeGroup(cct, "topic", "group", func(ctx context.Context, msg *GenericMessage) {
+		require.EqualValues(t, "key", msg.Key)
+		require.EqualValues(t, "value", msg.Value)
+		wg.Done()
+	})
+
+	_ = bus.Send(ctx, createMessage("topic", "key", "value"), createMessage("topic", "key", "value"))
+	wg.Wait()
+}
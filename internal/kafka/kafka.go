@@ -180,6 +180,20 @@ func (b *kafkaBroker) NewReader(ctx context.Context, topic string) *kafka.Reader
 	})
 }
 
+// NewGroupReader creates a reader of a consumer group. A new group starts from the last offset,
+// otherwise it continues from the committed offset. Use Close() function to close the reader.
+func (b *kafkaBroker) NewGroupReader(ctx context.Context, topic, group string) *kafka.Reader {
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     config.Kafka.Brokers,
+		Dialer:      b.dialer,
+		Topic:       topic,
+		GroupID:     group,
+		StartOffset: kafka.LastOffset,
+		Logger:      kafka.LoggerFunc(newContextLogger(ctx)),
+		ErrorLogger: kafka.LoggerFunc(newContextErrLogger(ctx)),
+	})
+}
+
 // NewWriter creates synchronous writer created from the pool. It does not have associated any topic with it,
 // therefore topic must be set on the message-level. Make sure to close it with Close() function.
 func (b *kafkaBroker) NewWriter(ctx context.Context) *kafka.Writer {
@@ -208,7 +222,6 @@ func (b *kafkaBroker) Consume(ctx context.Context, topic string, since time.Time
 	}
 
 	for {
-		var span trace.Span
 		msg, err := r.ReadMessage(ctx)
 		if err != nil && errors.Is(err, io.EOF) {
 			logger.Warn().Err(err).Msg("Kafka receiver has been closed")
@@ -219,41 +232,80 @@ func (b *kafkaBroker) Consume(ctx context.Context, topic string, since time.Time
 		} else if err != nil {
 			logger.Warn().Err(err).Msg("Error when reading message")
 		} else {
-			logger.Trace().Bytes("payload", msg.Value).Msgf("Received message with key: %s, topic: %s, offset: %d, partition: %d",
-				msg.Key, msg.Topic, msg.Offset, msg.Partition)
-
-			// build new context - identity and trace id
-			logCtx := logger.With().Str("msg_id", random.TraceID().String())
-			newCtx, msgErr := identity.WithIdentityFrom64(ctx, header("X-RH-Identity", msg.Headers))
-			if msgErr != nil {
-				errLogger := logCtx.Logger()
-				errLogger.Warn().Err(msgErr).Msgf("Could not extract identity from context to Kafka message")
-			} else {
-				id := identity.Identity(newCtx)
-				logCtx = logCtx.
-					Str("account_number", id.Identity.AccountNumber).
-					Str("org_id", id.Identity.OrgID)
-			}
+			handleMessage(ctx, topic, &msg, handler)
+		}
+	}
+}
 
-			gMsg := NewMessageFromKafka(&msg)
+// ConsumeGroup reads messages as a member of a consumer group and commits the offset of each
+// message after the handler returns. It blocks, therefore it should be called from a separate
+// goroutine. Use context cancellation to stop the loop.
+func (b *kafkaBroker) ConsumeGroup(ctx context.Context, topic, group string, handler func(ctx context.Context, message *GenericMessage)) {
+	logger := zerolog.Ctx(ctx)
+	r := b.NewGroupReader(ctx, topic, group)
+	defer func() {
+		if tempErr := r.Close(); tempErr != nil {
+			logger.Warn().Err(tempErr).Msg("Unable to close the kafka reader")
+		}
+	}()
 
-			if config.Telemetry.Enabled {
-				newCtx = otel.GetTextMapPropagator().Extract(newCtx, propagation.MapCarrier(headersMap(gMsg.Headers)))
-				newCtx, span = telemetry.StartSpan(newCtx, fmt.Sprintf("Processing message on topic %s", topic))
+	for {
+		msg, err := r.FetchMessage(ctx)
+		if err != nil && errors.Is(err, io.EOF) {
+			logger.Warn().Err(err).Msg("Kafka receiver has been closed")
+			break
+		} else if err != nil && errors.Is(err, context.Canceled) {
+			logger.Debug().Msg("Kafka receiver has been cancelled")
+			break
+		} else if err != nil {
+			logger.Warn().Err(err).Msg("Error when fetching message")
+		} else {
+			handleMessage(ctx, topic, &msg, handler)
 
-				logCtx.Str("trace_id", span.SpanContext().TraceID().String())
-			} else {
-				// noopSpan from empty context
-				span = trace.SpanFromContext(context.Background())
+			if commitErr := r.CommitMessages(ctx, msg); commitErr != nil {
+				logger.Warn().Err(commitErr).Msgf("Unable to commit offset %d of partition %d", msg.Offset, msg.Partition)
 			}
+		}
+	}
+}
 
-			newCtx = logCtx.Logger().WithContext(newCtx)
+// handleMessage builds the message context (identity, logger and trace) and calls the handler.
+func handleMessage(ctx context.Context, topic string, msg *kafka.Message, handler func(ctx context.Context, message *GenericMessage)) {
+	var span trace.Span
+	logger := zerolog.Ctx(ctx)
+	logger.Trace().Bytes("payload", msg.Value).Msgf("Received message with key: %s, topic: %s, offset: %d, partition: %d",
+		msg.Key, msg.Topic, msg.Offset, msg.Partition)
+
+	// build new context - identity and trace id
+	logCtx := logger.With().Str("msg_id", random.TraceID().String())
+	newCtx, msgErr := identity.WithIdentityFrom64(ctx, header("X-RH-Identity", msg.Headers))
+	if msgErr != nil {
+		errLogger := logCtx.Logger()
+		errLogger.Warn().Err(msgErr).Msgf("Could not extract identity from context to Kafka message")
+	} else {
+		id := identity.Identity(newCtx)
+		logCtx = logCtx.
+			Str("account_number", id.Identity.AccountNumber).
+			Str("org_id", id.Identity.OrgID)
+	}
 
-			handler(newCtx, gMsg)
+	gMsg := NewMessageFromKafka(msg)
 
-			span.End()
-		}
+	if config.Telemetry.Enabled {
+		newCtx = otel.GetTextMapPropagator().Extract(newCtx, propagation.MapCarrier(headersMap(gMsg.Headers)))
+		newCtx, span = telemetry.StartSpan(newCtx, fmt.Sprintf("Processing message on topic %s", topic))
+
+		logCtx.Str("trace_id", span.SpanContext().TraceID().String())
+	} else {
+		// noopSpan from empty context
+		span = trace.SpanFromContext(context.Background())
 	}
+
+	newCtx = logCtx.Logger().WithContext(newCtx)
+
+	handler(newCtx, gMsg)
+
+	span.End()
 }
 
 func header(name string, headers []protocol.Header) string {
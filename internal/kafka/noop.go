@@ -17,6 +17,11 @@ func (s *noopBroker) Consume(ctx context.Context, topic string, since time.Time,
 	logger.Warn().Msg("Consume loop not started (Kafka not configured)")
 }
 
+func (s *noopBroker) ConsumeGroup(ctx context.Context, topic, group string, handler func(ctx context.Context, message *GenericMessage)) {
+	logger := zerolog.Ctx(ctx)
+	logger.Warn().Msg("Consume loop not started (Kafka not configured)")
+}
+
 func (s *noopBroker) Send(ctx context.Context, messages ...*GenericMessage) error {
 	logger := zerolog.Ctx(ctx)
 	logger.Warn().Msgf("Throwing away %d messages (Kafka not configured)", len(messages))
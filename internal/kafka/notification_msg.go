@@ -11,11 +11,12 @@ import (
 )
 
 const (
-	application                  = "image-builder"
-	bundle                       = "rhel"
-	notificationMessageVersion   = "v2.0.0"
-	NotificationSuccessEventType = "launch-success"
-	NotificationFailureEventType = "launch-failed"
+	application                   = "image-builder"
+	bundle                        = "rhel"
+	notificationMessageVersion    = "v2.0.0"
+	NotificationSuccessEventType  = "launch-success"
+	NotificationFailureEventType  = "launch-failed"
+	NotificationApprovalEventType = "launch-approval-requested"
 )
 
 type NotificationEvent struct {
@@ -31,6 +32,10 @@ type NotificationError struct {
 	Error string `json:"error"`
 }
 
+type NotificationApproval struct {
+	Reason string `json:"reason"`
+}
+
 type notificationRecipients struct {
 	OnlyAdmins            bool     `json:"only_admins"`
 	IgnoreUserPreferences bool     `json:"ignore_user_preferences"`
@@ -21,6 +21,39 @@ type SourceResult struct {
 	MissingPermissions []string        `json:"-"` // Sources do not support reason field
 }
 
+// CachedSourceResult is the last reported availability status of a source, the statuser reuses it
+// for repeated requests of the same source.
+type CachedSourceResult struct {
+	ResourceID   string
+	ResourceType string
+	Status       StatusType
+	UserError    string
+}
+
+func (CachedSourceResult) CacheKeyName() string {
+	return "source-status-"
+}
+
+func NewCachedSourceResult(sr SourceResult) *CachedSourceResult {
+	return &CachedSourceResult{
+		ResourceID:   sr.ResourceID,
+		ResourceType: sr.ResourceType,
+		Status:       sr.Status,
+		UserError:    sr.UserError,
+	}
+}
+
+// SourceResult returns the cached status as a result to be sent to Sources.
+func (c CachedSourceResult) SourceResult(ctx context.Context) SourceResult {
+	return SourceResult{
+		MessageContext: ctx,
+		ResourceID:     c.ResourceID,
+		ResourceType:   c.ResourceType,
+		Status:         c.Status,
+		UserError:      c.UserError,
+	}
+}
+
 func (sr SourceResult) GenericMessage(ctx context.Context) (GenericMessage, error) {
 	return genericMessage(ctx, sr, sr.ResourceID, SourcesStatusTopic)
 }
@@ -0,0 +1,74 @@
+package kafka
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// SourcesEventType is the value of the "event_type" header of the sources event-stream messages.
+type SourcesEventType string
+
+const (
+	SourceDestroyEvent         SourcesEventType = "Source.destroy"
+	ApplicationUpdateEvent     SourcesEventType = "Application.update"
+	ApplicationDestroyEvent    SourcesEventType = "Application.destroy"
+	AuthenticationCreateEvent  SourcesEventType = "Authentication.create"
+	AuthenticationUpdateEvent  SourcesEventType = "Authentication.update"
+	AuthenticationDestroyEvent SourcesEventType = "Authentication.destroy"
+)
+
+var ErrMissingSourceID = errors.New("sources event does not contain source id")
+
+// SourcesEventMessage is a subset of fields sent by Sources on the event-stream topic. Different
+// event types carry different payloads, only fields needed to find the associated source are mapped.
+type SourcesEventMessage struct {
+	// Event type from the message header
+	EventType SourcesEventType `json:"-"`
+
+	// Resource ID, for Source events this is the source ID itself
+	ID json.Number `json:"id"`
+
+	// Source ID for Application and Authentication events (not present in Source events)
+	SourceID json.Number `json:"source_id"`
+
+	// Resource type and ID of an Authentication event (e.g. "Application" and application ID)
+	ResourceType string      `json:"resource_type"`
+	ResourceID   json.Number `json:"resource_id"`
+}
+
+// NewSourcesEventMessage parses a message from the sources event-stream topic. Numeric identifiers
+// can be sent both as JSON strings and numbers.
+func NewSourcesEventMessage(msg *GenericMessage) (*SourcesEventMessage, error) {
+	sem := SourcesEventMessage{}
+	err := json.Unmarshal(msg.Value, &sem)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal sources event message: %w", err)
+	}
+	sem.EventType = SourcesEventType(msg.Header("event_type"))
+
+	return &sem, nil
+}
+
+// AffectedSourceID returns source ID the event is related to, or ErrMissingSourceID when
+// the payload does not contain it.
+func (m SourcesEventMessage) AffectedSourceID() (string, error) {
+	id := m.SourceID
+	if m.EventType == SourceDestroyEvent {
+		id = m.ID
+	}
+
+	if id.String() == "" {
+		return "", fmt.Errorf("%w: event %s", ErrMissingSourceID, m.EventType)
+	}
+	if _, err := strconv.ParseInt(id.String(), 10, 64); err != nil {
+		return "", fmt.Errorf("%w: event %s has invalid id %s", ErrMissingSourceID, m.EventType, id)
+	}
+
+	return id.String(), nil
+}
+
+func (et SourcesEventType) String() string {
+	return string(et)
+}
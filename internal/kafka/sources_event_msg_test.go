@@ -0,0 +1,60 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createSourcesEvent(eventType, value string) *GenericMessage {
+	return &GenericMessage{
+		Topic:   "platform.sources.event-stream",
+		Value:   []byte(value),
+		Headers: GenericHeaders("event_type", eventType),
+	}
+}
+
+func TestSourcesEventSourceDestroy(t *testing.T) {
+	sem, err := NewSourcesEventMessage(createSourcesEvent("Source.destroy", `{"id":"42","name":"test"}`))
+	require.NoError(t, err)
+	require.Equal(t, SourceDestroyEvent, sem.EventType)
+
+	id, err := sem.AffectedSourceID()
+	require.NoError(t, err)
+	require.Equal(t, "42", id)
+}
+
+func TestSourcesEventApplicationDestroy(t *testing.T) {
+	sem, err := NewSourcesEventMessage(createSourcesEvent("Application.destroy", `{"id":1,"source_id":13}`))
+	require.NoError(t, err)
+	require.Equal(t, ApplicationDestroyEvent, sem.EventType)
+
+	id, err := sem.AffectedSourceID()
+	require.NoError(t, err)
+	require.Equal(t, "13", id)
+}
+
+func TestSourcesEventAuthenticationUpdate(t *testing.T) {
+	sem, err := NewSourcesEventMessage(createSourcesEvent("Authentication.update",
+		`{"id":"5","source_id":"7","resource_type":"Application","resource_id":"3"}`))
+	require.NoError(t, err)
+	require.Equal(t, AuthenticationUpdateEvent, sem.EventType)
+	require.Equal(t, "Application", sem.ResourceType)
+
+	id, err := sem.AffectedSourceID()
+	require.NoError(t, err)
+	require.Equal(t, "7", id)
+}
+
+func TestSourcesEventMissingSourceID(t *testing.T) {
+	sem, err := NewSourcesEventMessage(createSourcesEvent("Application.destroy", `{"id":"1"}`))
+	require.NoError(t, err)
+
+	_, err = sem.AffectedSourceID()
+	require.ErrorIs(t, err, ErrMissingSourceID)
+}
+
+func TestSourcesEventInvalidPayload(t *testing.T) {
+	_, err := NewSourcesEventMessage(createSourcesEvent("Source.destroy", `{`))
+	require.Error(t, err)
+}
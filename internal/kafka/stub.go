@@ -54,6 +54,11 @@ func (s *stubBroker) Consume(ctx context.Context, topic string, since time.Time,
 	}
 }
 
+// ConsumeGroup behaves like Consume, there is only one consumer of a topic in tests.
+func (s *stubBroker) ConsumeGroup(ctx context.Context, topic, _ string, handler func(ctx context.Context, message *GenericMessage)) {
+	s.Consume(ctx, topic, time.Time{}, handler)
+}
+
 func (s *stubBroker) Send(_ context.Context, messages ...*GenericMessage) error {
 	for _, m := range messages {
 		ch := s.find(m.Topic)
@@ -11,6 +11,14 @@ var (
 	availabilityStatusRequestTopicReq = "platform.provisioning.internal.availability-check"
 	sendStatusToSourcesTopicReq       = "platform.sources.status"
 	sendNotificationMessage           = "platform.notifications.ingress"
+	sourcesEventStreamTopicReq        = "platform.sources.event-stream"
+)
+
+// consumer groups
+const (
+	// SourcesEventStreamGroup keeps the offset of the sources event-stream consumer, so events
+	// published while the consumer is not running are processed after restart.
+	SourcesEventStreamGroup = "provisioning-sources-event-stream"
 )
 
 // topics after clowder mapping
@@ -18,6 +26,7 @@ var (
 	AvailabilityStatusRequestTopic string
 	SourcesStatusTopic             string
 	NotificationTopic              string
+	SourcesEventStreamTopic        string
 )
 
 // InitializeTopicRequests performs clowder mapping of topics.
@@ -25,4 +34,5 @@ func InitializeTopicRequests(ctx context.Context) {
 	AvailabilityStatusRequestTopic = config.TopicName(ctx, availabilityStatusRequestTopicReq)
 	SourcesStatusTopic = config.TopicName(ctx, sendStatusToSourcesTopicReq)
 	NotificationTopic = config.TopicName(ctx, sendNotificationMessage)
+	SourcesEventStreamTopic = config.TopicName(ctx, sourcesEventStreamTopicReq)
 }
@@ -25,6 +25,42 @@ var TotalInvalidAvailabilityCheckReqs = prometheus.NewCounter(
 	},
 )
 
+var TotalSourcesEvents = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name:        "provisioning_sources_events_total",
+		Help:        "sources event-stream messages count partitioned by event type and result (processed, error)",
+		ConstLabels: prometheus.Labels{"service": version.PrometheusLabelName, "component": "statuser"},
+	},
+	[]string{"type", "result"},
+)
+
+var AvailabilityCheckSkipped = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name:        "provisioning_availability_check_skipped_total",
+		Help:        "availability check requests skipped by debouncing partitioned by reason (duplicate, cached)",
+		ConstLabels: prometheus.Labels{"service": version.PrometheusLabelName},
+	},
+	[]string{"reason"},
+)
+
+var CircuitBreakerState = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name:        "provisioning_circuit_breaker_state",
+		Help:        "circuit breaker state partitioned by name (0 = closed, 1 = half-open, 2 = open)",
+		ConstLabels: prometheus.Labels{"service": version.PrometheusLabelName, "component": "statuser"},
+	},
+	[]string{"name"},
+)
+
+var CircuitBreakerTransitions = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name:        "provisioning_circuit_breaker_transitions_total",
+		Help:        "circuit breaker state changes partitioned by name and new state (closed, half-open, open)",
+		ConstLabels: prometheus.Labels{"service": version.PrometheusLabelName, "component": "statuser"},
+	},
+	[]string{"name", "state"},
+)
+
 var CacheHits = prometheus.NewCounterVec(prometheus.CounterOpts{
 	Name:        "provisioning_cache_hits",
 	Help:        "The total number of cache hits per type with result (hit, miss, err)",
@@ -72,6 +108,34 @@ var BackgroundJobDuration = prometheus.NewHistogramVec(
 	[]string{"type"},
 )
 
+var JobQueueWaitDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:        "provisioning_job_queue_wait_duration",
+		Help:        "time between enqueue and dequeue of a job (in seconds) by lane",
+		ConstLabels: prometheus.Labels{"service": version.PrometheusLabelName, "component": "worker"},
+		Buckets:     []float64{0.1, 0.5, 1, 2, 5, 10, 30, 60, 60 * 5, 60 * 15, 60 * 60},
+	},
+	[]string{"lane"},
+)
+
+var JobShutdownCount = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name:        "provisioning_job_shutdown_total",
+		Help:        "in-flight jobs during worker shutdown by result (finished/requeued/error)",
+		ConstLabels: prometheus.Labels{"service": version.PrometheusLabelName, "component": "worker"},
+	},
+	[]string{"result"},
+)
+
+var WorkerShutdownDuration = prometheus.NewHistogram(
+	prometheus.HistogramOpts{
+		Name:        "provisioning_worker_shutdown_duration",
+		Help:        "time between worker stop and end of the last in-flight job (in seconds)",
+		ConstLabels: prometheus.Labels{"service": version.PrometheusLabelName, "component": "worker"},
+		Buckets:     []float64{0.1, 0.5, 1, 2, 5, 10, 20, 30, 60},
+	},
+)
+
 var ReservationCount = prometheus.NewCounterVec(
 	prometheus.CounterOpts{
 		Name:        "provisioning_reservation_count",
@@ -81,6 +145,15 @@ var ReservationCount = prometheus.NewCounterVec(
 	[]string{"type", "result"},
 )
 
+var StaleReservations = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name:        "provisioning_stale_reservations_total",
+		Help:        "stale reservations finished by the reaper by provider and result (success/failure/error)",
+		ConstLabels: prometheus.Labels{"service": version.PrometheusLabelName, "component": "stats"},
+	},
+	[]string{"provider", "result"},
+)
+
 var DbStatsDuration = prometheus.NewHistogram(
 	prometheus.HistogramOpts{
 		Name:        "provisioning_db_stats_duration",
@@ -139,6 +212,10 @@ func ObserveBackgroundJobDuration(jobType string, observedFunc func()) {
 	observedFunc()
 }
 
+func ObserveJobQueueWait(lane string, wait time.Duration) {
+	JobQueueWaitDuration.WithLabelValues(lane).Observe(wait.Seconds())
+}
+
 func IncTotalSentAvailabilityCheckReqs(provider string, statusType string, err error) {
 	errString := "false"
 	if err != nil {
@@ -151,6 +228,22 @@ func IncTotalInvalidAvailabilityCheckReqs() {
 	TotalInvalidAvailabilityCheckReqs.Inc()
 }
 
+func IncAvailabilityCheckSkipped(reason string) {
+	AvailabilityCheckSkipped.WithLabelValues(reason).Inc()
+}
+
+func SetCircuitBreakerState(name string, state int) {
+	CircuitBreakerState.WithLabelValues(name).Set(float64(state))
+}
+
+func IncCircuitBreakerTransition(name, state string) {
+	CircuitBreakerTransitions.WithLabelValues(name, state).Inc()
+}
+
+func IncSourcesEvent(eventType, result string) {
+	TotalSourcesEvents.WithLabelValues(eventType, result).Inc()
+}
+
 func IncCacheHit(model, result string) {
 	CacheHits.WithLabelValues(model, result).Inc()
 }
@@ -163,10 +256,22 @@ func SetJobQueueInFlight(workerName string, inflight int64) {
 	JobQueueInFlight.WithLabelValues(workerName).Set(float64(inflight))
 }
 
+func IncJobShutdown(result string) {
+	JobShutdownCount.WithLabelValues(result).Inc()
+}
+
+func ObserveWorkerShutdown(duration time.Duration) {
+	WorkerShutdownDuration.Observe(duration.Seconds())
+}
+
 func IncReservationCount(rtype, result string) {
 	ReservationCount.WithLabelValues(rtype, result).Inc()
 }
 
+func IncStaleReservation(provider, result string) {
+	StaleReservations.WithLabelValues(provider, result).Inc()
+}
+
 func ObserveDbStatsDuration(observedFunc func()) {
 	start := time.Now()
 	defer func() {
@@ -7,6 +7,10 @@ func RegisterStatuserMetrics() {
 		TotalSentAvailabilityCheckReqs,
 		AvailabilityCheckReqsDuration,
 		TotalInvalidAvailabilityCheckReqs,
+		TotalSourcesEvents,
+		AvailabilityCheckSkipped,
+		CircuitBreakerState,
+		CircuitBreakerTransitions,
 		RbacAclFetchDuration,
 		CacheHits,
 	)
@@ -19,6 +23,7 @@ func RegisterStatsMetrics() {
 		DbStatsDuration,
 		Reservations24hCount,
 		Reservations28dCount,
+		StaleReservations,
 	)
 }
 
@@ -27,12 +32,16 @@ func RegisterApiMetrics() {
 		RbacAclFetchDuration,
 		CacheHits,
 		AvailabilityBatchSendDuration,
+		AvailabilityCheckSkipped,
 	)
 }
 
 func RegisterWorkerMetrics() {
 	prometheus.MustRegister(
 		BackgroundJobDuration,
+		JobQueueWaitDuration,
+		JobShutdownCount,
+		WorkerShutdownDuration,
 		ReservationCount,
 		RbacAclFetchDuration,
 		CacheHits,
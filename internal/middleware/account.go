@@ -1,7 +1,8 @@
 package middleware
 
 import (
-	"errors"
+	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/RHEnVision/provisioning-backend/internal/cache"
@@ -21,31 +22,18 @@ func AccountMiddleware(next http.Handler) http.Handler {
 		accountNumber := rhId.Identity.AccountNumber
 		logger := log.Ctx(r.Context()).With().Str("account_number", accountNumber).Str("org_id", orgID).Logger()
 
-		cachedAccount := &models.Account{}
-		err := cache.Find(r.Context(), orgID+accountNumber, cachedAccount)
-		if errors.Is(err, cache.ErrNotFound) {
-			// account not found in cache
-			accDao := dao.GetAccountDao(r.Context())
-
-			cachedAccount, err = accDao.GetOrCreateByIdentity(r.Context(), orgID, accountNumber)
-			if err != nil {
-				logger.Error().Err(err).Msg("Failed to fetch account")
-				http.Error(w, err.Error(), 500)
-				return
-			}
-
-			err = cache.Set(r.Context(), orgID+accountNumber, cachedAccount)
-			if err != nil {
-				logger.Error().Err(err).Msg("Unable to store account to cache")
-				http.Error(w, err.Error(), 500)
-				return
-			}
-		} else if err != nil {
-			logger.Error().Err(err).Msg("Cache returned error")
+		cachedAccount, err := cache.GetOrLoad(r.Context(), orgID+accountNumber, cache.LoadOptions{},
+			func(ctx context.Context) (models.Account, error) {
+				account, err := dao.GetAccountDao(ctx).GetOrCreateByIdentity(ctx, orgID, accountNumber)
+				if err != nil {
+					return models.Account{}, fmt.Errorf("unable to get or create account: %w", err)
+				}
+				return *account, nil
+			})
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to fetch account")
 			http.Error(w, err.Error(), 500)
 			return
-		} else {
-			logger.Trace().Int64("account", cachedAccount.ID).Msg("Account cache hit")
 		}
 
 		// set contexts - account id
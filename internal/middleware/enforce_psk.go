@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog"
+)
+
+var ErrPSK = errors.New("pre-shared key error")
+
+// EnforcePSK aborts requests without the X-Admin-PSK header matching the pre-shared key. An empty
+// key rejects all requests.
+func EnforcePSK(psk string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("X-Admin-PSK")
+
+			if psk == "" || subtle.ConstantTimeCompare([]byte(header), []byte(psk)) != 1 {
+				errRender := render.Render(w, r, payloads.NewMissingIdentityError(r.Context(), "missing or invalid X-Admin-PSK header", ErrPSK))
+				if errRender != nil {
+					zerolog.Ctx(r.Context()).Warn().Err(errRender).Msg("Cannot render pre-shared key middleware error")
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
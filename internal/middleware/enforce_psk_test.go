@@ -0,0 +1,45 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RHEnVision/provisioning-backend/internal/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnforcePSK(t *testing.T) {
+	request := func(t *testing.T, psk, header string) int {
+		t.Helper()
+		req, err := http.NewRequestWithContext(context.Background(), "DELETE", "/internal/jobs", nil)
+		require.NoError(t, err, "failed to create request")
+		if header != "" {
+			req.Header.Set("X-Admin-PSK", header)
+		}
+
+		rr := httptest.NewRecorder()
+		next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+		middleware.EnforcePSK(psk)(next).ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	t.Run("valid key", func(t *testing.T) {
+		require.Equal(t, http.StatusNoContent, request(t, "secret", "secret"))
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		require.Equal(t, http.StatusForbidden, request(t, "secret", ""))
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		require.Equal(t, http.StatusForbidden, request(t, "secret", "guess"))
+	})
+
+	t.Run("key not configured", func(t *testing.T) {
+		require.Equal(t, http.StatusForbidden, request(t, "", ""))
+	})
+}
@@ -6,9 +6,11 @@ import (
 	"hash/crc64"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
+	"golang.org/x/exp/slices"
 )
 
 // An InstanceTypeExpiration represents the default expiration time for instance types
@@ -30,7 +32,10 @@ type ETag struct {
 
 type ETagValueFunc func() *ETag
 
-var etags = make([]*ETag, 0)
+var (
+	etags   = make([]*ETag, 0)
+	etagsMu sync.Mutex
+)
 
 func (etag *ETag) Header() string {
 	return fmt.Sprintf("\"pb-%s-%s\"", etag.Name, etag.Value)
@@ -82,11 +87,28 @@ func GenerateETagFromBuffer(name string, expiration time.Duration, buffers ...[]
 		Value:      fmt.Sprintf("%x", hash.Sum64()),
 		HashTime:   time.Since(start),
 	}
-	etags = append(etags, etag)
+	registerETag(etag)
 	return etag, nil
 }
 
+// registerETag adds etag to the diagnostic list, an etag with the same name is replaced
+func registerETag(etag *ETag) {
+	etagsMu.Lock()
+	defer etagsMu.Unlock()
+
+	for i, e := range etags {
+		if e.Name == etag.Name {
+			etags[i] = etag
+			return
+		}
+	}
+	etags = append(etags, etag)
+}
+
 // AllETags returns all ETags for diagnostic purposes
 func AllETags() []*ETag {
-	return etags
+	etagsMu.Lock()
+	defer etagsMu.Unlock()
+
+	return slices.Clone(etags)
 }
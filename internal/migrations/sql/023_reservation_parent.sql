@@ -0,0 +1,4 @@
+ALTER TABLE reservations ADD COLUMN
+  parent_id BIGINT NULL REFERENCES reservations(id) ON DELETE SET NULL;
+
+CREATE INDEX reservations_parent_id_idx ON reservations(parent_id);
@@ -0,0 +1,22 @@
+--
+-- Reservations launched at a later time (not_before) or repeatedly (cron). The table keeps the launch
+-- job prepared by the API, so the scheduler does not need to call any external services. Recurring
+-- schedules keep the reservation as a template, each run creates a new reservation linked to it via
+-- parent_id.
+--
+CREATE TABLE reservation_schedules
+(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  reservation_id BIGINT NOT NULL UNIQUE REFERENCES reservations(id) ON DELETE CASCADE,
+  account_id BIGINT NOT NULL REFERENCES accounts(id),
+  cron TEXT NOT NULL DEFAULT '',
+  next_run_at TIMESTAMP NOT NULL,
+  last_run_at TIMESTAMP,
+  job_type TEXT NOT NULL CHECK (NOT empty(job_type)),
+  job_identity JSONB NOT NULL,
+  job_edge_id TEXT NOT NULL DEFAULT '',
+  job_args JSONB NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+CREATE INDEX reservation_schedules_next_run_at_idx ON reservation_schedules(next_run_at);
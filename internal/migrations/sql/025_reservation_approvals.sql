@@ -0,0 +1,34 @@
+--
+-- Launches matching any approval rule of the account wait for approval. The launch job prepared by
+-- the API is kept with the approval and enqueued (or scheduled) once the reservation is approved.
+--
+CREATE TABLE approval_rules
+(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  account_id BIGINT NOT NULL REFERENCES accounts(id),
+  name TEXT NOT NULL CHECK (NOT empty(name)),
+  max_amount INTEGER NOT NULL DEFAULT 0 CHECK (max_amount >= 0),
+  instance_types TEXT[] NOT NULL DEFAULT '{}',
+  created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+
+  UNIQUE(name, account_id)
+);
+
+CREATE TABLE reservation_approvals
+(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  reservation_id BIGINT NOT NULL UNIQUE REFERENCES reservations(id) ON DELETE CASCADE,
+  account_id BIGINT NOT NULL REFERENCES accounts(id),
+  reason TEXT NOT NULL DEFAULT '',
+  job_type TEXT NOT NULL CHECK (NOT empty(job_type)),
+  job_identity JSONB NOT NULL,
+  job_edge_id TEXT NOT NULL DEFAULT '',
+  job_args JSONB NOT NULL,
+  schedule_cron TEXT NOT NULL DEFAULT '',
+  schedule_next_run_at TIMESTAMP NULL,
+  approved BOOLEAN NULL,
+  decided_by TEXT NOT NULL DEFAULT '',
+  decided_at TIMESTAMP NULL,
+  comment TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
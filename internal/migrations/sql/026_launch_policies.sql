@@ -0,0 +1,10 @@
+--
+-- Launch policy of an account is a JSON document with guardrails (allowed regions, instance
+-- types and images, required tags and maximum amount) checked for every new reservation.
+--
+CREATE TABLE launch_policies
+(
+  account_id BIGINT NOT NULL PRIMARY KEY REFERENCES accounts(id),
+  document JSONB NOT NULL,
+  updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
@@ -0,0 +1,12 @@
+--
+-- Finished steps of launch jobs. A job delivered again after a worker crash skips steps which
+-- were already recorded for the reservation.
+--
+CREATE TABLE reservation_checkpoints
+(
+  reservation_id BIGINT NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+  step TEXT NOT NULL CHECK (NOT empty(step)),
+  completed_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+
+  PRIMARY KEY (reservation_id, step)
+);
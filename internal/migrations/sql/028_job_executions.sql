@@ -0,0 +1,22 @@
+--
+-- Execution records of background jobs. Every attempt of a job is stored with the worker host
+-- and the outcome, records are deleted by the stats process after the configured lifetime.
+--
+CREATE TABLE job_executions
+(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  job_id UUID NOT NULL,
+  job_type TEXT NOT NULL CHECK (NOT empty(job_type)),
+  reservation_id BIGINT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+  account_id BIGINT NOT NULL REFERENCES accounts(id),
+  attempt INTEGER NOT NULL CHECK (attempt > 0),
+  hostname TEXT NOT NULL DEFAULT '',
+  started_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+  finished_at TIMESTAMP NULL,
+  result TEXT NOT NULL DEFAULT '',
+  error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX job_executions_job_id_idx ON job_executions(job_id);
+CREATE INDEX job_executions_reservation_id_idx ON job_executions(reservation_id);
+CREATE INDEX job_executions_started_at_idx ON job_executions(started_at);
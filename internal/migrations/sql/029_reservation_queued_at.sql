@@ -0,0 +1,13 @@
+--
+-- Time when the launch job of a reservation was enqueued. Reservations waiting for a schedule or
+-- an approval are not queued yet, stale reservations are only looked up among queued ones.
+--
+ALTER TABLE reservations ADD COLUMN
+  queued_at TIMESTAMP NULL;
+
+UPDATE reservations r SET queued_at = created_at
+  WHERE r.success IS NULL AND r.finished_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM reservation_schedules s WHERE s.reservation_id = r.id)
+  AND NOT EXISTS (SELECT 1 FROM reservation_approvals a WHERE a.reservation_id = r.id AND a.approved IS NULL);
+
+CREATE INDEX reservations_queued_at_idx ON reservations(queued_at) WHERE success IS NULL AND finished_at IS NULL;
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"path"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/identity"
+)
+
+// ApprovalRule is an account rule which puts matching reservations into the pending approval state
+// instead of launching them.
+type ApprovalRule struct {
+	// Required auto-generated PK.
+	ID int64 `db:"id"`
+
+	// Account ID. Required.
+	AccountID int64 `db:"account_id"`
+
+	// Rule name, it is reported as the reason of the approval. Required.
+	Name string `db:"name" validate:"required"`
+
+	// Reservations with more instances require approval, zero disables the check.
+	MaxAmount int32 `db:"max_amount" validate:"gte=0"`
+
+	// Reservations of instance types matching any of the patterns require approval. Patterns use
+	// shell syntax, for example "p3.*" or "Standard_NC*".
+	InstanceTypes []string `db:"instance_types" validate:"dive,required,pattern"`
+
+	// Time when rule was created.
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Matches returns true when a reservation of the given amount and instance type requires approval.
+func (r *ApprovalRule) Matches(amount int64, instanceType string) bool {
+	if r.MaxAmount > 0 && amount > int64(r.MaxAmount) {
+		return true
+	}
+
+	if instanceType == "" {
+		return false
+	}
+	for _, pattern := range r.InstanceTypes {
+		if ok, _ := path.Match(pattern, instanceType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ReservationApproval is a pending or decided approval of a reservation. The launch job is
+// prepared by the API when the reservation is created and it is stored with the approval.
+type ReservationApproval struct {
+	// Required auto-generated PK.
+	ID int64 `db:"id"`
+
+	// Reservation waiting for the approval.
+	ReservationID int64 `db:"reservation_id"`
+
+	// Account ID. Required.
+	AccountID int64 `db:"account_id"`
+
+	// Names of the matching approval rules.
+	Reason string `db:"reason"`
+
+	// Launch job type.
+	JobType string `db:"job_type"`
+
+	// Identity of the user who created the reservation.
+	JobIdentity identity.Principal `db:"job_identity"`
+
+	// Edge request ID for logging.
+	JobEdgeID string `db:"job_edge_id"`
+
+	// Launch job arguments encoded as JSON, the type depends on JobType.
+	JobArgs json.RawMessage `db:"job_args"`
+
+	// Cron expression when the reservation is launched repeatedly after approval.
+	ScheduleCron string `db:"schedule_cron"`
+
+	// Requested time of the first launch or NULL when it is launched immediately after approval.
+	ScheduleNextRunAt sql.NullTime `db:"schedule_next_run_at"`
+
+	// Decision, NULL while the approval is pending.
+	Approved sql.NullBool `db:"approved"`
+
+	// User name of the approver.
+	DecidedBy string `db:"decided_by"`
+
+	// Time of the decision or NULL while the approval is pending.
+	DecidedAt sql.NullTime `db:"decided_at"`
+
+	// Optional comment of the approver.
+	Comment string `db:"comment"`
+
+	// Time when approval was requested.
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Pending returns true when the approval was neither approved nor rejected.
+func (a *ReservationApproval) Pending() bool {
+	return !a.Approved.Valid
+}
@@ -0,0 +1,42 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/RHEnVision/provisioning-backend/internal/testing/initialization"
+
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApprovalRuleMatches(t *testing.T) {
+	rule := models.ApprovalRule{
+		Name:          "large or GPU",
+		MaxAmount:     10,
+		InstanceTypes: []string{"p3.*", "Standard_NC*"},
+	}
+
+	assert.False(t, rule.Matches(10, "t3.micro"), "amount equal to maximum should not match")
+	assert.True(t, rule.Matches(11, "t3.micro"), "amount over maximum should match")
+	assert.True(t, rule.Matches(1, "p3.2xlarge"), "AWS GPU type should match")
+	assert.True(t, rule.Matches(1, "Standard_NC6s_v3"), "Azure GPU type should match")
+	assert.False(t, rule.Matches(1, "p4d.24xlarge"), "different family should not match")
+	assert.False(t, rule.Matches(1, ""), "launch template without type should not match")
+}
+
+func TestApprovalRuleMatchesWithoutMaxAmount(t *testing.T) {
+	rule := models.ApprovalRule{Name: "GPU", InstanceTypes: []string{"a2-*"}}
+
+	assert.False(t, rule.Matches(1000, "n1-standard-1"), "zero maximum should not limit amount")
+	assert.True(t, rule.Matches(1, "a2-highgpu-1g"), "GCP GPU type should match")
+}
+
+func TestApprovalRuleValidation(t *testing.T) {
+	rule := models.ApprovalRule{Name: "invalid", InstanceTypes: []string{"p3.["}}
+	assert.NotNil(t, models.Validate(context.Background(), &rule), "invalid pattern should not pass validation")
+
+	rule = models.ApprovalRule{Name: "valid", InstanceTypes: []string{"p3.*"}}
+	assert.Nil(t, models.Validate(context.Background(), &rule), "valid pattern should pass validation")
+}
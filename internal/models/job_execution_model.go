@@ -0,0 +1,56 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Results of job executions.
+const (
+	// JobResultSuccess is stored when the job finished successfully.
+	JobResultSuccess = "success"
+
+	// JobResultFailure is stored when the job finished with an error.
+	JobResultFailure = "failure"
+
+	// JobResultInterrupted is stored when the job was interrupted by worker shutdown and re-queued.
+	JobResultInterrupted = "interrupted"
+)
+
+// JobExecution is a record of a single attempt of a background job.
+type JobExecution struct {
+	// Required auto-generated PK.
+	ID int64 `db:"id"`
+
+	// Job ID, all attempts of the same job share it.
+	JobID uuid.UUID `db:"job_id"`
+
+	// Job type.
+	JobType string `db:"job_type"`
+
+	// Reservation of a launch job or NULL for other jobs.
+	ReservationID sql.NullInt64 `db:"reservation_id"`
+
+	// Account ID. Required.
+	AccountID int64 `db:"account_id"`
+
+	// Attempt number starting from 1, it is increased every time the job is delivered again.
+	Attempt int32 `db:"attempt"`
+
+	// Hostname of the worker which executed the attempt.
+	Hostname string `db:"hostname"`
+
+	// Time when the attempt was started.
+	StartedAt time.Time `db:"started_at"`
+
+	// Time when the attempt was finished or NULL when it is still running or the worker crashed.
+	FinishedAt sql.NullTime `db:"finished_at"`
+
+	// Result of the attempt, empty string when it was not finished.
+	Result string `db:"result"`
+
+	// Error message of a failed attempt.
+	Error string `db:"error"`
+}
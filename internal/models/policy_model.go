@@ -0,0 +1,132 @@
+package models
+
+import (
+	"fmt"
+	"path"
+	"time"
+)
+
+// LaunchPolicy holds guardrails of an account which every new reservation must satisfy.
+type LaunchPolicy struct {
+	// Account ID, an account has at most one policy. Required.
+	AccountID int64 `db:"account_id"`
+
+	// Policy document stored as JSON.
+	Document *PolicyDocument `db:"document" validate:"required"`
+
+	// Time when policy was created or last replaced.
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// PolicyDocument defines the rules of a launch policy, empty rules do not restrict launches.
+// Lists of regions, instance types and images are shell patterns, for example "us-east-*",
+// "t3.*" or "ami-*".
+type PolicyDocument struct {
+	// Allowed regions (AWS), locations (Azure) and zones (GCP) per provider.
+	AllowedRegions PolicyRegions `json:"allowed_regions"`
+
+	// Allowed instance types, sizes or machine types.
+	AllowedInstanceTypes []string `json:"allowed_instance_types,omitempty" validate:"dive,required,pattern"`
+
+	// Allowed image IDs as sent in reservation requests.
+	AllowedImages []string `json:"allowed_images,omitempty" validate:"dive,required,pattern"`
+
+	// Tags which must be set to a non-empty value.
+	RequiredTags []string `json:"required_tags,omitempty" validate:"dive,required"`
+
+	// Maximum amount of instances of a reservation, zero disables the check.
+	MaxAmount int64 `json:"max_amount,omitempty" validate:"gte=0"`
+}
+
+// PolicyRegions holds region patterns per provider, providers without patterns are not restricted.
+type PolicyRegions struct {
+	AWS   []string `json:"aws,omitempty" validate:"dive,required,pattern"`
+	Azure []string `json:"azure,omitempty" validate:"dive,required,pattern"`
+	GCP   []string `json:"gcp,omitempty" validate:"dive,required,pattern"`
+}
+
+// PolicyLaunch describes a reservation checked against a launch policy.
+type PolicyLaunch struct {
+	Provider     ProviderType
+	Region       string
+	InstanceType string
+	ImageID      string
+	Amount       int64
+	Tags         map[string]string
+}
+
+// PolicyViolation is returned by the policy check, Rule is the name of the violated rule in the
+// policy document.
+type PolicyViolation struct {
+	Rule    string
+	Message string
+}
+
+func (v *PolicyViolation) Error() string {
+	return fmt.Sprintf("%s: %s", v.Rule, v.Message)
+}
+
+// Check returns PolicyViolation for the first rule the launch does not satisfy, or nil.
+func (d *PolicyDocument) Check(launch *PolicyLaunch) error {
+	if d.MaxAmount > 0 && launch.Amount > d.MaxAmount {
+		return &PolicyViolation{"max_amount", fmt.Sprintf("amount %d exceeds the maximum of %d", launch.Amount, d.MaxAmount)}
+	}
+
+	if regions := d.AllowedRegions.For(launch.Provider); len(regions) > 0 && !matchesAny(regions, launch.Region) {
+		if launch.Region == "" {
+			return &PolicyViolation{"allowed_regions", fmt.Sprintf("region must be set for %s", launch.Provider)}
+		}
+		return &PolicyViolation{"allowed_regions", fmt.Sprintf("region %q is not allowed for %s", launch.Region, launch.Provider)}
+	}
+
+	if len(d.AllowedInstanceTypes) > 0 && !matchesAny(d.AllowedInstanceTypes, launch.InstanceType) {
+		if launch.InstanceType == "" {
+			return &PolicyViolation{"allowed_instance_types", "instance type must be set"}
+		}
+		return &PolicyViolation{"allowed_instance_types", fmt.Sprintf("instance type %q is not allowed", launch.InstanceType)}
+	}
+
+	if len(d.AllowedImages) > 0 && !matchesAny(d.AllowedImages, launch.ImageID) {
+		if launch.ImageID == "" {
+			return &PolicyViolation{"allowed_images", "image must be set"}
+		}
+		return &PolicyViolation{"allowed_images", fmt.Sprintf("image %q is not allowed", launch.ImageID)}
+	}
+
+	for _, tag := range d.RequiredTags {
+		if launch.Tags[tag] == "" {
+			return &PolicyViolation{"required_tags", fmt.Sprintf("tag %q is missing", tag)}
+		}
+	}
+
+	return nil
+}
+
+// For returns allowed regions of the provider.
+func (r *PolicyRegions) For(provider ProviderType) []string {
+	switch provider {
+	case ProviderTypeAWS:
+		return r.AWS
+	case ProviderTypeAzure:
+		return r.Azure
+	case ProviderTypeGCP:
+		return r.GCP
+	case ProviderTypeNoop:
+	case ProviderTypeUnknown:
+	default:
+		return nil
+	}
+	return nil
+}
+
+func matchesAny(patterns []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package models_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	_ "github.com/RHEnVision/provisioning-backend/internal/testing/initialization"
+
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyDocumentCheck(t *testing.T) {
+	document := models.PolicyDocument{
+		AllowedRegions: models.PolicyRegions{
+			AWS: []string{"us-east-*"},
+			GCP: []string{"us-east1-*"},
+		},
+		AllowedInstanceTypes: []string{"t3.*", "n1-standard-*"},
+		AllowedImages:        []string{"ami-*", "rhel-*"},
+		RequiredTags:         []string{"cost-center"},
+		MaxAmount:            10,
+	}
+	valid := func() *models.PolicyLaunch {
+		return &models.PolicyLaunch{
+			Provider:     models.ProviderTypeAWS,
+			Region:       "us-east-1",
+			InstanceType: "t3.micro",
+			ImageID:      "ami-0123",
+			Amount:       1,
+			Tags:         map[string]string{"cost-center": "42"},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(launch *models.PolicyLaunch)
+		rule   string
+	}{
+		{"valid launch", func(_ *models.PolicyLaunch) {}, ""},
+		{"amount over maximum", func(l *models.PolicyLaunch) { l.Amount = 11 }, "max_amount"},
+		{"region not allowed", func(l *models.PolicyLaunch) { l.Region = "eu-west-1" }, "allowed_regions"},
+		{"zone of another provider", func(l *models.PolicyLaunch) { l.Region = "us-east1-b" }, "allowed_regions"},
+		{"provider without regions", func(l *models.PolicyLaunch) {
+			l.Provider = models.ProviderTypeAzure
+			l.Region = "westeurope"
+		}, ""},
+		{"instance type not allowed", func(l *models.PolicyLaunch) { l.InstanceType = "p3.2xlarge" }, "allowed_instance_types"},
+		{"instance type from launch template", func(l *models.PolicyLaunch) { l.InstanceType = "" }, "allowed_instance_types"},
+		{"image not allowed", func(l *models.PolicyLaunch) { l.ImageID = "2bc640f6-927a-404a-9594-5b2da7e06608" }, "allowed_images"},
+		{"missing tag", func(l *models.PolicyLaunch) { l.Tags = nil }, "required_tags"},
+		{"empty tag", func(l *models.PolicyLaunch) { l.Tags["cost-center"] = "" }, "required_tags"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			launch := valid()
+			tt.modify(launch)
+
+			err := document.Check(launch)
+			if tt.rule == "" {
+				require.NoError(t, err)
+				return
+			}
+			var violation *models.PolicyViolation
+			require.True(t, errors.As(err, &violation), "policy violation expected")
+			assert.Equal(t, tt.rule, violation.Rule)
+		})
+	}
+}
+
+func TestPolicyDocumentCheckEmpty(t *testing.T) {
+	document := models.PolicyDocument{}
+	err := document.Check(&models.PolicyLaunch{Provider: models.ProviderTypeGCP, Amount: 1000})
+	assert.NoError(t, err, "empty policy should not restrict launches")
+}
+
+func TestPolicyValidation(t *testing.T) {
+	policy := models.LaunchPolicy{Document: &models.PolicyDocument{
+		AllowedRegions: models.PolicyRegions{Azure: []string{"east["}},
+	}}
+	assert.NotNil(t, models.Validate(context.Background(), &policy), "invalid pattern should not pass validation")
+
+	policy = models.LaunchPolicy{Document: &models.PolicyDocument{MaxAmount: -1}}
+	assert.NotNil(t, models.Validate(context.Background(), &policy), "negative amount should not pass validation")
+
+	policy = models.LaunchPolicy{Document: &models.PolicyDocument{RequiredTags: []string{"owner"}}}
+	assert.Nil(t, models.Validate(context.Background(), &policy))
+}
@@ -21,6 +21,10 @@ type Reservation struct {
 	// Time when reservation was made.
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 
+	// Time when the launch job was enqueued, nil while the reservation waits for a schedule or
+	// an approval.
+	QueuedAt sql.NullTime `db:"queued_at" json:"-"`
+
 	// Total number of job steps for this reservation.
 	Steps int32 `db:"steps" json:"steps"`
 
@@ -44,6 +48,34 @@ type Reservation struct {
 
 	// Flag indicating success, error or unknown state (NULL). See Status for the actual error.
 	Success sql.NullBool `db:"success" json:"success"`
+
+	// Reservation this one was created from (e.g. a retry of a failed reservation), nil otherwise.
+	ParentID *int64 `db:"parent_id" json:"parent_id,omitempty"`
+}
+
+// RootVolume overrides the root disk of the image.
+type RootVolume struct {
+	// Size in GiB, zero keeps the image size.
+	SizeGiB int32 `json:"size_gib"`
+
+	// Provider volume type, empty keeps the image or provider default.
+	Type string `json:"type,omitempty"`
+}
+
+// Volume is an additional data volume created and attached to each instance.
+type Volume struct {
+	// Size in GiB.
+	SizeGiB int32 `json:"size_gib"`
+
+	// Provider volume type: for example "gp3" for AWS, "pd-balanced" for GCP or "Premium_LRS" for Azure.
+	Type string `json:"type"`
+
+	// Encrypt the volume with the account default key. Only applied on AWS, GCP and Azure disks are
+	// always encrypted at rest.
+	Encrypted bool `json:"encrypted"`
+
+	// Delete the volume when the instance is terminated.
+	DeleteOnTermination bool `json:"delete_on_termination"`
 }
 
 type NoopReservation struct {
@@ -70,6 +102,15 @@ type AWSDetail struct {
 
 	// PubkeyName on AWS in given region. Found by the EnsurePubkey job.
 	PubkeyName string `json:"pubkey_name"`
+
+	// Optional root volume override.
+	RootVolume *RootVolume `json:"root_volume,omitempty"`
+
+	// Optional additional data volumes.
+	Volumes []Volume `json:"volumes,omitempty"`
+
+	// Optional user tags of the instances.
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 type AWSReservation struct {
@@ -112,6 +153,15 @@ type GCPDetail struct {
 
 	// Immediately power off the system after initialization
 	PowerOff bool `json:"poweroff"`
+
+	// Optional root volume override.
+	RootVolume *RootVolume `json:"root_volume,omitempty"`
+
+	// Optional additional data volumes.
+	Volumes []Volume `json:"volumes,omitempty"`
+
+	// Optional user tags of the instances.
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 type GCPReservation struct {
@@ -152,6 +202,15 @@ type AzureDetail struct {
 
 	// ResourceGroup is name of Resource Group to put the created resources into
 	ResourceGroup string `json:"resource_group"`
+
+	// Optional root volume override.
+	RootVolume *RootVolume `json:"root_volume,omitempty"`
+
+	// Optional additional data volumes.
+	Volumes []Volume `json:"volumes,omitempty"`
+
+	// Optional user tags of the instances.
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 type AzureReservation struct {
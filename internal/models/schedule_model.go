@@ -0,0 +1,51 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/identity"
+)
+
+// ReservationSchedule launches a reservation at a later time or repeatedly. The launch job is
+// prepared by the API when the reservation is created and it is stored with the schedule.
+type ReservationSchedule struct {
+	// Required auto-generated PK.
+	ID int64 `db:"id"`
+
+	// Scheduled reservation, for recurring schedules it is a template for reservations of each run.
+	ReservationID int64 `db:"reservation_id"`
+
+	// Account ID. Required.
+	AccountID int64 `db:"account_id"`
+
+	// Cron expression of a recurring schedule or empty string for a single launch.
+	Cron string `db:"cron"`
+
+	// Time of the next launch.
+	NextRunAt time.Time `db:"next_run_at"`
+
+	// Time of the last launch or NULL when it was not launched yet.
+	LastRunAt sql.NullTime `db:"last_run_at"`
+
+	// Launch job type.
+	JobType string `db:"job_type"`
+
+	// Identity of the user who created the schedule.
+	JobIdentity identity.Principal `db:"job_identity"`
+
+	// Edge request ID for logging.
+	JobEdgeID string `db:"job_edge_id"`
+
+	// Launch job arguments encoded as JSON, the type depends on JobType.
+	JobArgs json.RawMessage `db:"job_args"`
+
+	// Time when schedule was created.
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Recurring returns true for schedules with a cron expression.
+func (s *ReservationSchedule) Recurring() bool {
+	return s.Cron != ""
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"context"
 	"errors"
+	"path"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/rs/zerolog"
@@ -18,6 +19,11 @@ func init() {
 		_, _, _, _, err := ssh.ParseAuthorizedKey([]byte(fl.Field().String()))
 		return err == nil
 	})
+
+	_ = validate.RegisterValidation("pattern", func(fl validator.FieldLevel) bool {
+		_, err := path.Match(fl.Field().String(), "")
+		return err == nil
+	})
 }
 
 func Validate(ctx context.Context, model interface{}) validator.ValidationErrors {
@@ -9,4 +9,5 @@ var GetNotificationClient func(ctx context.Context) NotificationClient = getNoop
 type NotificationClient interface {
 	SuccessfulLaunch(ctx context.Context, reservationId int64)
 	FailedLaunch(ctx context.Context, reservationId int64, jobError error)
+	ApprovalRequested(ctx context.Context, reservationId int64, reason string)
 }
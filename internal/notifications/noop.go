@@ -24,3 +24,8 @@ func (s *noopNotificationClient) FailedLaunch(ctx context.Context, reservationId
 	logger := zerolog.Ctx(ctx)
 	logger.Warn().Msg("FailedLaunch not started (Notifications not configured)")
 }
+
+func (s *noopNotificationClient) ApprovalRequested(ctx context.Context, reservationId int64, reason string) {
+	logger := zerolog.Ctx(ctx)
+	logger.Warn().Msg("ApprovalRequested not started (Notifications not configured)")
+}
@@ -94,3 +94,34 @@ func (x *client) FailedLaunch(ctx context.Context, reservationId int64, jobError
 		logger.Error().Err(err).Msg("Unable to send notification message via kafka")
 	}
 }
+
+func (x *client) ApprovalRequested(ctx context.Context, reservationId int64, reason string) {
+	logger := zerolog.Ctx(ctx)
+	logger.Debug().Msg("Triggering an approval request notification")
+	rDao := dao.GetReservationDao(ctx)
+	reservation, err := rDao.GetById(ctx, reservationId)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to find reservation by id")
+		return
+	}
+	marshalApproval, err := json.Marshal(kafka.NotificationApproval{Reason: reason})
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to marshal approval")
+		return
+	}
+
+	notificationEvent := []kafka.NotificationEvent{{Payload: marshalApproval}}
+	notificationMsg, err := kafka.NotificationMessage{
+		Context:   kafka.NotificationContext{Provider: reservation.Provider.String(), LaunchID: reservationId},
+		EventType: kafka.NotificationApprovalEventType, Events: notificationEvent,
+	}.GenericMessage(ctx)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to create notification approval message")
+		return
+	}
+	logger.Info().Msg("Sending notification message")
+	err = kafka.Send(ctx, &notificationMsg)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to send notification message via kafka")
+	}
+}
@@ -0,0 +1,130 @@
+package payloads
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/go-chi/render"
+)
+
+// See models.ApprovalRule
+type ApprovalRuleRequest struct {
+	// Rule name, it is reported as the reason of the approval.
+	Name string `json:"name" yaml:"name"`
+
+	// Reservations with more instances require approval, zero or not set disables the check.
+	MaxAmount int32 `json:"max_amount,omitempty" yaml:"max_amount,omitempty"`
+
+	// Reservations of instance types matching any of the shell patterns require approval,
+	// for example "p3.*", "Standard_NC*" or "a2-*".
+	InstanceTypes []string `json:"instance_types,omitempty" yaml:"instance_types,omitempty"`
+}
+
+// See models.ApprovalRule
+type ApprovalRuleResponse struct {
+	ID            int64    `json:"id" yaml:"id"`
+	Name          string   `json:"name" yaml:"name"`
+	MaxAmount     int32    `json:"max_amount" yaml:"max_amount"`
+	InstanceTypes []string `json:"instance_types" yaml:"instance_types"`
+}
+
+type ApprovalRuleListResponse struct {
+	Data []*ApprovalRuleResponse `json:"data" yaml:"data"`
+}
+
+// ApprovalDecisionRequest is an optional body of approve and reject requests.
+type ApprovalDecisionRequest struct {
+	// Optional comment of the approver, for rejected reservations it is a part of the error.
+	Comment string `json:"comment,omitempty" yaml:"comment,omitempty"`
+}
+
+// ApprovalResponse is an approval of a reservation.
+type ApprovalResponse struct {
+	// Reservation waiting for the approval.
+	ReservationID int64 `json:"reservation_id" yaml:"reservation_id"`
+
+	// Names of the matching approval rules.
+	Reason string `json:"reason" yaml:"reason"`
+
+	// Approval status: pending, approved or rejected.
+	Status string `json:"status" yaml:"status"`
+
+	// User name of the approver, only present when decided.
+	DecidedBy string `json:"decided_by,omitempty" yaml:"decided_by,omitempty"`
+
+	// Time of the decision, only present when decided.
+	DecidedAt *time.Time `json:"decided_at,omitempty" yaml:"decided_at,omitempty"`
+
+	// Comment of the approver.
+	Comment string `json:"comment,omitempty" yaml:"comment,omitempty"`
+}
+
+func (p *ApprovalRuleRequest) Bind(_ *http.Request) error {
+	return nil
+}
+
+func (p *ApprovalRuleResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func (p *ApprovalRuleListResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func (p *ApprovalDecisionRequest) Bind(_ *http.Request) error {
+	return nil
+}
+
+func (p *ApprovalResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func (p *ApprovalRuleRequest) NewModel() *models.ApprovalRule {
+	return &models.ApprovalRule{
+		Name:          p.Name,
+		MaxAmount:     p.MaxAmount,
+		InstanceTypes: p.InstanceTypes,
+	}
+}
+
+func NewApprovalRuleResponse(rule *models.ApprovalRule) *ApprovalRuleResponse {
+	instanceTypes := rule.InstanceTypes
+	if instanceTypes == nil {
+		instanceTypes = []string{}
+	}
+	return &ApprovalRuleResponse{
+		ID:            rule.ID,
+		Name:          rule.Name,
+		MaxAmount:     rule.MaxAmount,
+		InstanceTypes: instanceTypes,
+	}
+}
+
+func NewApprovalRuleListResponse(rules []*models.ApprovalRule) render.Renderer {
+	list := make([]*ApprovalRuleResponse, len(rules))
+	for i, rule := range rules {
+		list[i] = NewApprovalRuleResponse(rule)
+	}
+	return &ApprovalRuleListResponse{Data: list}
+}
+
+func NewApprovalResponse(approval *models.ReservationApproval) render.Renderer {
+	response := &ApprovalResponse{
+		ReservationID: approval.ReservationID,
+		Reason:        approval.Reason,
+		Status:        "pending",
+		DecidedBy:     approval.DecidedBy,
+		Comment:       approval.Comment,
+	}
+	if approval.Approved.Valid {
+		response.Status = "rejected"
+		if approval.Approved.Bool {
+			response.Status = "approved"
+		}
+	}
+	if approval.DecidedAt.Valid {
+		response.DecidedAt = &approval.DecidedAt.Time
+	}
+	return response
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/RHEnVision/provisioning-backend/internal/clients"
 	"github.com/RHEnVision/provisioning-backend/internal/usrerr"
+	"github.com/RHEnVision/provisioning-backend/pkg/worker"
 	"github.com/aws/smithy-go"
 
 	"github.com/RHEnVision/provisioning-backend/internal/logging"
@@ -170,6 +171,17 @@ func NewEnqueueTaskError(ctx context.Context, message string, err error) *Respon
 	return NewResponseError(ctx, http.StatusInternalServerError, message, err)
 }
 
+func NewJobQueueError(ctx context.Context, message string, err error) *ResponseError {
+	if errors.Is(err, worker.ErrJobNotFound) {
+		return NewNotFoundError(ctx, message, err)
+	}
+	if errors.Is(err, worker.ErrJobNotInFlight) {
+		return NewResponseError(ctx, http.StatusBadRequest, fmt.Sprintf("Job is not in flight: %s", message), err)
+	}
+	message = fmt.Sprintf("Job queue error: %s", message)
+	return NewResponseError(ctx, http.StatusInternalServerError, message, err)
+}
+
 func NewDAOError(ctx context.Context, message string, err error) *ResponseError {
 	if response := findUserResponse(ctx, "DAO error", err); response != nil {
 		return response
@@ -178,6 +190,14 @@ func NewDAOError(ctx context.Context, message string, err error) *ResponseError
 	return NewResponseError(ctx, http.StatusInternalServerError, message, err)
 }
 
+func NewPolicyError(ctx context.Context, message string, err error) *ResponseError {
+	if response := findUserResponse(ctx, "Policy error", err); response != nil {
+		return response
+	}
+	message = fmt.Sprintf("Policy error: %s", message)
+	return NewResponseError(ctx, http.StatusInternalServerError, message, err)
+}
+
 func NewRenderError(ctx context.Context, message string, err error) *ResponseError {
 	message = fmt.Sprintf("Rendering error: %s", message)
 	return NewResponseError(ctx, http.StatusInternalServerError, message, err)
@@ -0,0 +1,57 @@
+package payloads
+
+import (
+	"net/http"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/go-chi/render"
+)
+
+// See clients.Image
+type SourceImageResponse struct {
+	// Image identifier to be used as image_id of a reservation.
+	ID string `json:"id" yaml:"id"`
+
+	Name string `json:"name" yaml:"name"`
+
+	// Origin is one of "image-builder", "account" or "shared".
+	Origin string `json:"origin" yaml:"origin"`
+
+	// Architecture is empty when not known.
+	Architecture string `json:"architecture,omitempty" yaml:"architecture,omitempty"`
+
+	// Region is not present for global images.
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
+
+	// Compatible is only present when instance type was requested and image architecture is known.
+	Compatible *bool `json:"compatible,omitempty" yaml:"compatible,omitempty"`
+}
+
+type SourceImageListResponse struct {
+	Provider string                 `json:"provider" yaml:"provider"`
+	Data     []*SourceImageResponse `json:"data" yaml:"data"`
+}
+
+func (s *SourceImageListResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// NewSourceImageListResponse creates image list response, compatibility is calculated when
+// instance type is not nil.
+func NewSourceImageListResponse(provider string, images []*clients.Image, instanceType *clients.InstanceType) render.Renderer {
+	list := make([]*SourceImageResponse, len(images))
+	for i, image := range images {
+		list[i] = &SourceImageResponse{
+			ID:           image.ID,
+			Name:         image.Name,
+			Origin:       string(image.Origin),
+			Architecture: image.Architecture.String(),
+			Region:       image.Region,
+		}
+		if instanceType != nil && image.Architecture != "" {
+			compatible := image.Architecture == instanceType.Architecture
+			list[i].Compatible = &compatible
+		}
+	}
+	return &SourceImageListResponse{Provider: provider, Data: list}
+}
@@ -37,7 +37,47 @@ func NewListInstanceTypeResponse(sl []*clients.InstanceType) render.Renderer {
 			Supported:          it.Supported,
 			Architecture:       it.Architecture,
 			AzureDetail:        it.AzureDetail,
+			PricePerHour:       it.PricePerHour,
 		}
 	}
 	return &InstanceTypeListResponse{Data: list}
 }
+
+type RecommendedInstanceTypeResponse struct {
+	InstanceType *InstanceTypeResponse `json:"instance_type" yaml:"instance_type"`
+
+	// Distance from the requested shape, zero is an exact match
+	Distance float64 `json:"distance" yaml:"distance"`
+}
+
+type InstanceTypeRecommendationResponse struct {
+	Provider string `json:"provider" yaml:"provider"`
+
+	// Region the instance types are available in, empty when no region was requested
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
+
+	InstanceTypes []*RecommendedInstanceTypeResponse `json:"instance_types" yaml:"instance_types"`
+}
+
+type InstanceTypeRecommendationListResponse struct {
+	Data []*InstanceTypeRecommendationResponse `json:"data" yaml:"data"`
+}
+
+func (s *InstanceTypeRecommendationListResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func NewInstanceTypeRecommendationResponse(provider, region string, ranked []clients.RankedInstanceType) *InstanceTypeRecommendationResponse {
+	list := make([]*RecommendedInstanceTypeResponse, len(ranked))
+	for i, rt := range ranked {
+		list[i] = &RecommendedInstanceTypeResponse{
+			InstanceType: (*InstanceTypeResponse)(rt.InstanceType),
+			Distance:     rt.Distance,
+		}
+	}
+	return &InstanceTypeRecommendationResponse{
+		Provider:      provider,
+		Region:        region,
+		InstanceTypes: list,
+	}
+}
@@ -0,0 +1,175 @@
+package payloads
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/pkg/worker"
+	"github.com/go-chi/render"
+)
+
+// JobResponse is a queued or in-flight background job, it is only available through internal
+// operator endpoints.
+type JobResponse struct {
+	// Job UUID, also logged as job_id.
+	ID string `json:"id" yaml:"id"`
+
+	// Job type, for example launch_instances_aws.
+	Type string `json:"type" yaml:"type"`
+
+	// Associated account.
+	AccountID int64 `json:"account_id" yaml:"account_id"`
+
+	// Associated organization.
+	OrgID string `json:"org_id" yaml:"org_id"`
+
+	// Queue lane: high, normal or low.
+	Priority string `json:"priority" yaml:"priority"`
+
+	// Job state: delayed, queued or in_flight.
+	State string `json:"state" yaml:"state"`
+
+	// Time when the job was enqueued, blank for jobs enqueued by older versions.
+	EnqueuedAt *time.Time `json:"enqueued_at,omitempty" yaml:"enqueued_at,omitempty"`
+
+	// Time when a delayed job is delivered, blank for other jobs.
+	RunAt *time.Time `json:"run_at,omitempty" yaml:"run_at,omitempty"`
+
+	// Time when a worker started processing the job, blank for queued jobs.
+	StartedAt *time.Time `json:"started_at,omitempty" yaml:"started_at,omitempty"`
+
+	// Job arguments with redacted authentication.
+	Args any `json:"args" yaml:"args"`
+}
+
+type JobListResponse struct {
+	Data []*JobResponse `json:"data" yaml:"data"`
+}
+
+// JobDrainResponse is returned when a queue is drained.
+type JobDrainResponse struct {
+	// Number of removed queued jobs.
+	Drained int64 `json:"drained" yaml:"drained"`
+}
+
+// JobExecutionResponse is a single attempt of a background job of a reservation.
+type JobExecutionResponse struct {
+	// Job UUID, all attempts of the same job share it.
+	JobID string `json:"job_id" yaml:"job_id"`
+
+	// Job type, for example launch_instances_aws.
+	JobType string `json:"job_type" yaml:"job_type"`
+
+	// Attempt number starting from 1.
+	Attempt int32 `json:"attempt" yaml:"attempt"`
+
+	// Hostname of the worker which executed the attempt.
+	Hostname string `json:"hostname" yaml:"hostname"`
+
+	// Time when the attempt was started.
+	StartedAt time.Time `json:"started_at" yaml:"started_at"`
+
+	// Time when the attempt was finished, blank when it is running or the worker was killed.
+	FinishedAt *time.Time `json:"finished_at,omitempty" yaml:"finished_at,omitempty"`
+
+	// Attempt result: success, failure or interrupted, blank when it was not finished.
+	Result string `json:"result,omitempty" yaml:"result,omitempty"`
+
+	// Error message of a failed attempt.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type JobExecutionListResponse struct {
+	Data []*JobExecutionResponse `json:"data" yaml:"data"`
+}
+
+func (p *JobResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func (p *JobListResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func (p *JobDrainResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func (p *JobExecutionResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func (p *JobExecutionListResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// redactable job arguments contain authentication which must not be presented to operators.
+type redactable interface {
+	Redacted() any
+}
+
+func redactedArgs(args any) any {
+	if r, ok := args.(redactable); ok {
+		return r.Redacted()
+	}
+	return args
+}
+
+func NewJobResponse(info *worker.JobInfo) *JobResponse {
+	response := &JobResponse{
+		ID:        info.Job.ID.String(),
+		Type:      info.Job.Type.String(),
+		AccountID: info.Job.AccountID,
+		OrgID:     info.Job.Identity.Identity.OrgID,
+		Priority:  string(info.Job.Priority.Lane()),
+		State:     string(info.State),
+		Args:      redactedArgs(info.Job.Args),
+	}
+	if !info.Job.EnqueuedAt.IsZero() {
+		response.EnqueuedAt = &info.Job.EnqueuedAt
+	}
+	if !info.RunAt.IsZero() {
+		response.RunAt = &info.RunAt
+	}
+	if !info.StartedAt.IsZero() {
+		response.StartedAt = &info.StartedAt
+	}
+	return response
+}
+
+func NewJobListResponse(infos []*worker.JobInfo) render.Renderer {
+	list := make([]*JobResponse, len(infos))
+	for i, info := range infos {
+		list[i] = NewJobResponse(info)
+	}
+	return &JobListResponse{Data: list}
+}
+
+func NewJobDrainResponse(drained int64) render.Renderer {
+	return &JobDrainResponse{Drained: drained}
+}
+
+func NewJobExecutionResponse(execution *models.JobExecution) *JobExecutionResponse {
+	response := &JobExecutionResponse{
+		JobID:     execution.JobID.String(),
+		JobType:   execution.JobType,
+		Attempt:   execution.Attempt,
+		Hostname:  execution.Hostname,
+		StartedAt: execution.StartedAt,
+		Result:    execution.Result,
+		Error:     execution.Error,
+	}
+	if execution.FinishedAt.Valid {
+		response.FinishedAt = &execution.FinishedAt.Time
+	}
+	return response
+}
+
+func NewJobExecutionListResponse(executions []*models.JobExecution) render.Renderer {
+	list := make([]*JobExecutionResponse, len(executions))
+	for i, execution := range executions {
+		list[i] = NewJobExecutionResponse(execution)
+	}
+	return &JobExecutionListResponse{Data: list}
+}
@@ -0,0 +1,89 @@
+package payloads
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/go-chi/render"
+)
+
+// PolicyRequest is a launch policy document, it can be sent as JSON or YAML. See models.PolicyDocument
+type PolicyRequest struct {
+	// Allowed regions (AWS), locations (Azure) and zones (GCP) per provider as shell patterns,
+	// for example "us-east-*". Launches into other regions of the provider are rejected.
+	AllowedRegions PolicyRegionsPayload `json:"allowed_regions" yaml:"allowed_regions"`
+
+	// Allowed instance types, sizes or machine types as shell patterns, for example "t3.*".
+	AllowedInstanceTypes []string `json:"allowed_instance_types,omitempty" yaml:"allowed_instance_types,omitempty"`
+
+	// Allowed image IDs as sent in reservation requests, shell patterns, for example "ami-*".
+	AllowedImages []string `json:"allowed_images,omitempty" yaml:"allowed_images,omitempty"`
+
+	// Tags which must be set to a non-empty value in reservation requests.
+	RequiredTags []string `json:"required_tags,omitempty" yaml:"required_tags,omitempty"`
+
+	// Maximum amount of instances of a reservation, zero or not set disables the check.
+	MaxAmount int64 `json:"max_amount,omitempty" yaml:"max_amount,omitempty"`
+}
+
+type PolicyRegionsPayload struct {
+	AWS   []string `json:"aws,omitempty" yaml:"aws,omitempty"`
+	Azure []string `json:"azure,omitempty" yaml:"azure,omitempty"`
+	GCP   []string `json:"gcp,omitempty" yaml:"gcp,omitempty"`
+}
+
+// PolicyResponse is the launch policy of the account. See models.LaunchPolicy
+type PolicyResponse struct {
+	AllowedRegions       PolicyRegionsPayload `json:"allowed_regions" yaml:"allowed_regions"`
+	AllowedInstanceTypes []string             `json:"allowed_instance_types" yaml:"allowed_instance_types"`
+	AllowedImages        []string             `json:"allowed_images" yaml:"allowed_images"`
+	RequiredTags         []string             `json:"required_tags" yaml:"required_tags"`
+	MaxAmount            int64                `json:"max_amount" yaml:"max_amount"`
+
+	// Time when the policy was created or last replaced.
+	UpdatedAt time.Time `json:"updated_at" yaml:"updated_at"`
+}
+
+func (p *PolicyResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func (p *PolicyRequest) NewModel() *models.LaunchPolicy {
+	return &models.LaunchPolicy{
+		Document: &models.PolicyDocument{
+			AllowedRegions: models.PolicyRegions{
+				AWS:   p.AllowedRegions.AWS,
+				Azure: p.AllowedRegions.Azure,
+				GCP:   p.AllowedRegions.GCP,
+			},
+			AllowedInstanceTypes: p.AllowedInstanceTypes,
+			AllowedImages:        p.AllowedImages,
+			RequiredTags:         p.RequiredTags,
+			MaxAmount:            p.MaxAmount,
+		},
+	}
+}
+
+func NewPolicyResponse(policy *models.LaunchPolicy) render.Renderer {
+	document := policy.Document
+	return &PolicyResponse{
+		AllowedRegions: PolicyRegionsPayload{
+			AWS:   document.AllowedRegions.AWS,
+			Azure: document.AllowedRegions.Azure,
+			GCP:   document.AllowedRegions.GCP,
+		},
+		AllowedInstanceTypes: emptyIfNil(document.AllowedInstanceTypes),
+		AllowedImages:        emptyIfNil(document.AllowedImages),
+		RequiredTags:         emptyIfNil(document.RequiredTags),
+		MaxAmount:            document.MaxAmount,
+		UpdatedAt:            policy.UpdatedAt,
+	}
+}
+
+func emptyIfNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
@@ -1,11 +1,17 @@
 package payloads
 
 import (
+	"fmt"
+	"math"
 	"net/http"
+	"sort"
 	"time"
 
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
 	"github.com/RHEnVision/provisioning-backend/internal/models"
 	"github.com/RHEnVision/provisioning-backend/internal/page"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads/validation"
+	"github.com/RHEnVision/provisioning-backend/internal/ptr"
 	"github.com/go-chi/render"
 )
 
@@ -41,6 +47,9 @@ type GenericReservationResponse struct {
 
 	// Flag indicating success, error or unknown state (NULL). See Status for the actual error.
 	Success *bool `json:"success" nullable:"true" yaml:"success"`
+
+	// ID of the reservation this one retries, only present for retried reservations.
+	ParentID *int64 `json:"parent_id,omitempty" yaml:"parent_id,omitempty"`
 }
 
 type InstanceResponse struct {
@@ -51,9 +60,68 @@ type InstanceResponse struct {
 	Detail models.ReservationInstanceDetail `json:"detail" yaml:"detail"`
 }
 
+// CostEstimateResponse is an estimated on-demand cost of all instances of a reservation in USD.
+type CostEstimateResponse struct {
+	// Estimated cost per hour.
+	Hourly float64 `json:"hourly" yaml:"hourly"`
+
+	// Estimated cost per month of 730 hours.
+	Monthly float64 `json:"monthly" yaml:"monthly"`
+}
+
+// RootVolumeRequest overrides the root volume of the image.
+type RootVolumeRequest struct {
+	// Size in GiB, zero keeps the image size.
+	SizeGiB int32 `json:"size_gib" yaml:"size_gib"`
+
+	// Volume type: for example "gp3" for AWS, "pd-ssd" for GCP or "Premium_LRS" for Azure. Empty keeps the image type.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+}
+
+// VolumeRequest is an additional data volume attached to each instance.
+type VolumeRequest struct {
+	// Size in GiB.
+	SizeGiB int32 `json:"size_gib" yaml:"size_gib"`
+
+	// Volume type, defaults to "gp3" for AWS, "pd-balanced" for GCP and "Premium_LRS" for Azure.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// Encrypt the volume with the account default key. Only applied on AWS, GCP and Azure disks are always encrypted.
+	Encrypted bool `json:"encrypted,omitempty" yaml:"encrypted,omitempty"`
+
+	// Delete the volume when the instance is terminated, defaults to true.
+	DeleteOnTermination *bool `json:"delete_on_termination,omitempty" yaml:"delete_on_termination,omitempty"`
+}
+
+type RootVolumeResponse struct {
+	SizeGiB int32 `json:"size_gib" yaml:"size_gib"`
+
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+}
+
+type VolumeResponse struct {
+	SizeGiB int32 `json:"size_gib" yaml:"size_gib"`
+
+	Type string `json:"type" yaml:"type"`
+
+	Encrypted bool `json:"encrypted" yaml:"encrypted"`
+
+	DeleteOnTermination bool `json:"delete_on_termination" yaml:"delete_on_termination"`
+}
+
+// Tag is a key-value tag of instances, labels are used on GCP.
+type Tag struct {
+	Key string `json:"key" yaml:"key"`
+
+	Value string `json:"value" yaml:"value"`
+}
+
 type AWSReservationResponse struct {
 	ID int64 `json:"reservation_id" yaml:"reservation_id"`
 
+	// ID of the reservation this one retries, only present for retried reservations.
+	ParentID *int64 `json:"parent_id,omitempty" yaml:"parent_id,omitempty"`
+
 	// Pubkey ID.
 	PubkeyID *int64 `json:"pubkey_id,omitempty" yaml:"pubkey_id,omitempty"`
 
@@ -86,11 +154,26 @@ type AWSReservationResponse struct {
 
 	// Instances array, only present for finished reservations
 	Instances []InstanceResponse `json:"instances,omitempty" yaml:"instances"`
+
+	// Root volume override, only present when requested.
+	RootVolume *RootVolumeResponse `json:"root_volume,omitempty" yaml:"root_volume,omitempty"`
+
+	// Additional data volumes.
+	Volumes []VolumeResponse `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+
+	// User tags of the instances.
+	Tags []Tag `json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	// Estimated on-demand cost, only present when the instance type price is known.
+	EstimatedCost *CostEstimateResponse `json:"estimated_cost,omitempty" yaml:"estimated_cost,omitempty"`
 }
 
 type AzureReservationResponse struct {
 	ID int64 `json:"reservation_id" yaml:"reservation_id"`
 
+	// ID of the reservation this one retries, only present for retried reservations.
+	ParentID *int64 `json:"parent_id,omitempty" yaml:"parent_id,omitempty"`
+
 	PubkeyID *int64 `json:"pubkey_id,omitempty" yaml:"pubkey_id,omitempty"`
 
 	SourceID string `json:"source_id" yaml:"source_id"`
@@ -118,11 +201,26 @@ type AzureReservationResponse struct {
 
 	// Instances IDs, only present for finished reservations.
 	Instances []InstanceResponse `json:"instances,omitempty" yaml:"instances"`
+
+	// Root volume override, only present when requested.
+	RootVolume *RootVolumeResponse `json:"root_volume,omitempty" yaml:"root_volume,omitempty"`
+
+	// Additional data volumes.
+	Volumes []VolumeResponse `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+
+	// User tags of the instances.
+	Tags []Tag `json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	// Estimated on-demand cost, only present when the instance type price is known.
+	EstimatedCost *CostEstimateResponse `json:"estimated_cost,omitempty" yaml:"estimated_cost,omitempty"`
 }
 
 type GCPReservationResponse struct {
 	ID int64 `json:"reservation_id" yaml:"reservation_id"`
 
+	// ID of the reservation this one retries, only present for retried reservations.
+	ParentID *int64 `json:"parent_id,omitempty" yaml:"parent_id,omitempty"`
+
 	// Pubkey ID.
 	PubkeyID *int64 `json:"pubkey_id,omitempty" yaml:"pubkey_id,omitempty"`
 
@@ -155,6 +253,18 @@ type GCPReservationResponse struct {
 
 	// Instances IDs, only present for finished reservations.
 	Instances []InstanceResponse `json:"instances,omitempty" yaml:"instances"`
+
+	// Root volume override, only present when requested.
+	RootVolume *RootVolumeResponse `json:"root_volume,omitempty" yaml:"root_volume,omitempty"`
+
+	// Additional data volumes.
+	Volumes []VolumeResponse `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+
+	// User tags of the instances.
+	Tags []Tag `json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	// Estimated on-demand cost, only present when the instance type price is known.
+	EstimatedCost *CostEstimateResponse `json:"estimated_cost,omitempty" yaml:"estimated_cost,omitempty"`
 }
 
 type NoopReservationResponse struct {
@@ -188,6 +298,22 @@ type AWSReservationRequest struct {
 
 	// Immediately power off the system after initialization
 	PowerOff bool `json:"poweroff" yaml:"poweroff"`
+
+	// Optional root volume override.
+	RootVolume *RootVolumeRequest `json:"root_volume,omitempty" yaml:"root_volume,omitempty"`
+
+	// Optional additional data volumes.
+	Volumes []VolumeRequest `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+
+	// Optional tags of the instances (labels on GCP), the launch policy can require some tags.
+	Tags []Tag `json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	// Optional time of the launch, reservation is launched immediately when not set or in the past.
+	NotBefore *time.Time `json:"not_before,omitempty" yaml:"not_before,omitempty"`
+
+	// Optional cron expression in UTC (e.g. "0 2 * * *") to launch the same configuration repeatedly,
+	// each launch creates a new reservation. First launch is not done before NotBefore when set.
+	Schedule string `json:"schedule,omitempty" yaml:"schedule,omitempty"`
 }
 
 type AzureReservationRequest struct {
@@ -217,6 +343,22 @@ type AzureReservationRequest struct {
 
 	// Immediately power off the system after initialization.
 	PowerOff bool `json:"poweroff" yaml:"poweroff"`
+
+	// Optional root volume override.
+	RootVolume *RootVolumeRequest `json:"root_volume,omitempty" yaml:"root_volume,omitempty"`
+
+	// Optional additional data volumes.
+	Volumes []VolumeRequest `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+
+	// Optional tags of the instances (labels on GCP), the launch policy can require some tags.
+	Tags []Tag `json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	// Optional time of the launch, reservation is launched immediately when not set or in the past.
+	NotBefore *time.Time `json:"not_before,omitempty" yaml:"not_before,omitempty"`
+
+	// Optional cron expression in UTC (e.g. "0 2 * * *") to launch the same configuration repeatedly,
+	// each launch creates a new reservation. First launch is not done before NotBefore when set.
+	Schedule string `json:"schedule,omitempty" yaml:"schedule,omitempty"`
 }
 
 type GCPReservationRequest struct {
@@ -246,6 +388,59 @@ type GCPReservationRequest struct {
 
 	// Immediately power off the system after initialization.
 	PowerOff bool `json:"poweroff" yaml:"poweroff"`
+
+	// Optional root volume override.
+	RootVolume *RootVolumeRequest `json:"root_volume,omitempty" yaml:"root_volume,omitempty"`
+
+	// Optional additional data volumes.
+	Volumes []VolumeRequest `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+
+	// Optional tags of the instances (labels on GCP), the launch policy can require some tags.
+	Tags []Tag `json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	// Optional time of the launch, reservation is launched immediately when not set or in the past.
+	NotBefore *time.Time `json:"not_before,omitempty" yaml:"not_before,omitempty"`
+
+	// Optional cron expression in UTC (e.g. "0 2 * * *") to launch the same configuration repeatedly,
+	// each launch creates a new reservation. First launch is not done before NotBefore when set.
+	Schedule string `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+}
+
+// ReservationRetryRequest contains optional overrides for a retry of a failed reservation, all other
+// parameters are copied from the original reservation. Only fields of the reservation provider apply.
+type ReservationRetryRequest struct {
+	// AWS region override.
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
+
+	// AWS instance type override.
+	InstanceType string `json:"instance_type,omitempty" yaml:"instance_type,omitempty"`
+
+	// Azure location override.
+	Location string `json:"location,omitempty" yaml:"location,omitempty"`
+
+	// Azure instance size override.
+	InstanceSize string `json:"instance_size,omitempty" yaml:"instance_size,omitempty"`
+
+	// GCP zone override.
+	Zone string `json:"zone,omitempty" yaml:"zone,omitempty"`
+
+	// GCP machine type override.
+	MachineType string `json:"machine_type,omitempty" yaml:"machine_type,omitempty"`
+}
+
+// ScheduleResponse is a launch schedule of a reservation.
+type ScheduleResponse struct {
+	// Scheduled reservation, for recurring schedules it is a template of launched reservations.
+	ReservationID int64 `json:"reservation_id" yaml:"reservation_id"`
+
+	// Cron expression of a recurring schedule, empty for a single launch.
+	Cron string `json:"cron,omitempty" yaml:"cron,omitempty"`
+
+	// Time of the next launch.
+	NextRunAt time.Time `json:"next_run_at" yaml:"next_run_at"`
+
+	// Time of the last launch, only present when launched at least once.
+	LastRunAt *time.Time `json:"last_run_at,omitempty" yaml:"last_run_at,omitempty"`
 }
 
 type GenericReservationListResponse struct {
@@ -258,7 +453,7 @@ func (p *GenericReservationResponse) Render(_ http.ResponseWriter, _ *http.Reque
 }
 
 func (p *AWSReservationRequest) Bind(_ *http.Request) error {
-	return nil
+	return validateTags(p.Tags, validation.AWSTag)
 }
 
 func (p *AWSReservationResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
@@ -266,7 +461,7 @@ func (p *AWSReservationResponse) Render(_ http.ResponseWriter, _ *http.Request)
 }
 
 func (p *AzureReservationRequest) Bind(_ *http.Request) error {
-	return nil
+	return validateTags(p.Tags, validation.AzureTag)
 }
 
 func (p *AzureReservationResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
@@ -277,6 +472,10 @@ func (p *GCPReservationResponse) Render(_ http.ResponseWriter, _ *http.Request)
 	return nil
 }
 
+func (p *ScheduleResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
 func (p *NoopReservationResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
 	return nil
 }
@@ -290,10 +489,131 @@ func NewReservationResponse(reservation *models.Reservation) render.Renderer {
 }
 
 func (p *GCPReservationRequest) Bind(_ *http.Request) error {
+	return validateTags(p.Tags, validation.GCPLabel)
+}
+
+func (p *ReservationRetryRequest) Bind(_ *http.Request) error {
 	return nil
 }
 
-func NewAWSReservationResponse(reservation *models.AWSReservation, instances []*models.ReservationInstance) render.Renderer {
+// NewCostEstimateResponse returns cost estimate for amount of instances, or nil when price per
+// hour is unknown.
+func NewCostEstimateResponse(pricePerHour *float64, amount int64) *CostEstimateResponse {
+	if pricePerHour == nil {
+		return nil
+	}
+
+	hourly := *pricePerHour * float64(amount)
+	return &CostEstimateResponse{
+		Hourly:  math.Round(hourly*10000) / 10000,
+		Monthly: math.Round(hourly*clients.HoursPerMonth*100) / 100,
+	}
+}
+
+// NewVolumeDetails converts requested volumes to reservation details, delete on termination
+// defaults to true.
+func NewVolumeDetails(root *RootVolumeRequest, volumes []VolumeRequest) (*models.RootVolume, []models.Volume) {
+	var rootDetail *models.RootVolume
+	if root != nil {
+		rootDetail = &models.RootVolume{SizeGiB: root.SizeGiB, Type: root.Type}
+	}
+
+	if len(volumes) == 0 {
+		return rootDetail, nil
+	}
+	details := make([]models.Volume, len(volumes))
+	for i, volume := range volumes {
+		details[i] = models.Volume{
+			SizeGiB:             volume.SizeGiB,
+			Type:                volume.Type,
+			Encrypted:           volume.Encrypted,
+			DeleteOnTermination: volume.DeleteOnTermination == nil || *volume.DeleteOnTermination,
+		}
+	}
+	return rootDetail, details
+}
+
+// NewVolumeRequests converts volumes of a reservation detail back to requested volumes.
+func NewVolumeRequests(root *models.RootVolume, volumes []models.Volume) (*RootVolumeRequest, []VolumeRequest) {
+	var rootRequest *RootVolumeRequest
+	if root != nil {
+		rootRequest = &RootVolumeRequest{SizeGiB: root.SizeGiB, Type: root.Type}
+	}
+
+	if len(volumes) == 0 {
+		return rootRequest, nil
+	}
+	requests := make([]VolumeRequest, len(volumes))
+	for i, volume := range volumes {
+		requests[i] = VolumeRequest{
+			SizeGiB:             volume.SizeGiB,
+			Type:                volume.Type,
+			Encrypted:           volume.Encrypted,
+			DeleteOnTermination: ptr.To(volume.DeleteOnTermination),
+		}
+	}
+	return rootRequest, requests
+}
+
+// validateTags checks all requested tags, the first invalid tag is returned as an error.
+func validateTags(tags []Tag, validate func(key, value string) error) error {
+	for _, tag := range tags {
+		if err := validate(tag.Key, tag.Value); err != nil {
+			return fmt.Errorf("tags: %w", err)
+		}
+	}
+	return nil
+}
+
+// NewTagDetails converts requested tags to reservation details, the last value of a repeated key is used.
+func NewTagDetails(tags []Tag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	details := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		details[tag.Key] = tag.Value
+	}
+	return details
+}
+
+// NewTags converts tags of a reservation detail back to tags sorted by key.
+func NewTags(details map[string]string) []Tag {
+	if len(details) == 0 {
+		return nil
+	}
+	tags := make([]Tag, 0, len(details))
+	for key, value := range details {
+		tags = append(tags, Tag{Key: key, Value: value})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Key < tags[j].Key })
+	return tags
+}
+
+func newRootVolumeResponse(root *models.RootVolume) *RootVolumeResponse {
+	if root == nil {
+		return nil
+	}
+	return &RootVolumeResponse{SizeGiB: root.SizeGiB, Type: root.Type}
+}
+
+func newVolumeResponses(volumes []models.Volume) []VolumeResponse {
+	if len(volumes) == 0 {
+		return nil
+	}
+	result := make([]VolumeResponse, len(volumes))
+	for i, volume := range volumes {
+		result[i] = VolumeResponse{
+			SizeGiB:             volume.SizeGiB,
+			Type:                volume.Type,
+			Encrypted:           volume.Encrypted,
+			DeleteOnTermination: volume.DeleteOnTermination,
+		}
+	}
+	return result
+}
+
+func NewAWSReservationResponse(reservation *models.AWSReservation, instances []*models.ReservationInstance, pricePerHour *float64) render.Renderer {
 	instancesResponse := make([]InstanceResponse, len(instances))
 	for iter, inst := range instances {
 		instancesResponse[iter] = InstanceResponse{InstanceID: inst.InstanceID, Detail: inst.Detail}
@@ -307,10 +627,15 @@ func NewAWSReservationResponse(reservation *models.AWSReservation, instances []*
 		Amount:           reservation.Detail.Amount,
 		InstanceType:     reservation.Detail.InstanceType,
 		ID:               reservation.ID,
+		ParentID:         reservation.ParentID,
 		Name:             reservation.Detail.Name,
 		PowerOff:         reservation.Detail.PowerOff,
 		Instances:        instancesResponse,
 		LaunchTemplateID: reservation.Detail.LaunchTemplateID,
+		EstimatedCost:    NewCostEstimateResponse(pricePerHour, int64(reservation.Detail.Amount)),
+		RootVolume:       newRootVolumeResponse(reservation.Detail.RootVolume),
+		Volumes:          newVolumeResponses(reservation.Detail.Volumes),
+		Tags:             NewTags(reservation.Detail.Tags),
 	}
 	if reservation.AWSReservationID != nil {
 		response.AWSReservationID = *reservation.AWSReservationID
@@ -318,7 +643,7 @@ func NewAWSReservationResponse(reservation *models.AWSReservation, instances []*
 	return &response
 }
 
-func NewAzureReservationResponse(reservation *models.AzureReservation, instances []*models.ReservationInstance) render.Renderer {
+func NewAzureReservationResponse(reservation *models.AzureReservation, instances []*models.ReservationInstance, pricePerHour *float64) render.Renderer {
 	instanceIds := make([]InstanceResponse, len(instances))
 	for iter, inst := range instances {
 		instanceIds[iter] = InstanceResponse{InstanceID: inst.InstanceID, Detail: inst.Detail}
@@ -333,14 +658,19 @@ func NewAzureReservationResponse(reservation *models.AzureReservation, instances
 		Amount:        reservation.Detail.Amount,
 		InstanceSize:  reservation.Detail.InstanceSize,
 		ID:            reservation.ID,
+		ParentID:      reservation.ParentID,
 		Name:          reservation.Detail.Name,
 		PowerOff:      reservation.Detail.PowerOff,
 		Instances:     instanceIds,
+		EstimatedCost: NewCostEstimateResponse(pricePerHour, reservation.Detail.Amount),
+		RootVolume:    newRootVolumeResponse(reservation.Detail.RootVolume),
+		Volumes:       newVolumeResponses(reservation.Detail.Volumes),
+		Tags:          NewTags(reservation.Detail.Tags),
 	}
 	return &response
 }
 
-func NewGCPReservationResponse(reservation *models.GCPReservation, instances []*models.ReservationInstance) render.Renderer {
+func NewGCPReservationResponse(reservation *models.GCPReservation, instances []*models.ReservationInstance, pricePerHour *float64) render.Renderer {
 	instanceIds := make([]InstanceResponse, len(instances))
 	for iter, inst := range instances {
 		instanceIds[iter] = InstanceResponse{
@@ -359,9 +689,26 @@ func NewGCPReservationResponse(reservation *models.GCPReservation, instances []*
 		MachineType:      reservation.Detail.MachineType,
 		GCPOperationName: reservation.GCPOperationName,
 		ID:               reservation.ID,
+		ParentID:         reservation.ParentID,
 		PowerOff:         reservation.Detail.PowerOff,
 		Instances:        instanceIds,
 		LaunchTemplateID: reservation.Detail.LaunchTemplateID,
+		EstimatedCost:    NewCostEstimateResponse(pricePerHour, reservation.Detail.Amount),
+		RootVolume:       newRootVolumeResponse(reservation.Detail.RootVolume),
+		Volumes:          newVolumeResponses(reservation.Detail.Volumes),
+		Tags:             NewTags(reservation.Detail.Tags),
+	}
+	return &response
+}
+
+func NewScheduleResponse(schedule *models.ReservationSchedule) render.Renderer {
+	response := ScheduleResponse{
+		ReservationID: schedule.ReservationID,
+		Cron:          schedule.Cron,
+		NextRunAt:     schedule.NextRunAt,
+	}
+	if schedule.LastRunAt.Valid {
+		response.LastRunAt = &schedule.LastRunAt.Time
 	}
 	return &response
 }
@@ -400,5 +747,6 @@ func reservationResponseMapper(reservation *models.Reservation) *GenericReservat
 		Step:       reservation.Step,
 		StepTitles: reservation.StepTitles,
 		Error:      reservation.Error,
+		ParentID:   reservation.ParentID,
 	}
 }
@@ -0,0 +1,74 @@
+package payloads
+
+import (
+	"testing"
+
+	"github.com/RHEnVision/provisioning-backend/internal/payloads/validation"
+	"github.com/RHEnVision/provisioning-backend/internal/ptr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCostEstimateResponse(t *testing.T) {
+	estimate := NewCostEstimateResponse(ptr.To(0.096), 3)
+	require.NotNil(t, estimate)
+	require.InDelta(t, 0.288, estimate.Hourly, 0.00001)
+	require.InDelta(t, 210.24, estimate.Monthly, 0.00001)
+}
+
+func TestNewCostEstimateResponseUnknownPrice(t *testing.T) {
+	require.Nil(t, NewCostEstimateResponse(nil, 3))
+}
+
+func TestNewVolumeDetails(t *testing.T) {
+	root, volumes := NewVolumeDetails(&RootVolumeRequest{SizeGiB: 30}, []VolumeRequest{
+		{SizeGiB: 100, Type: "gp3", Encrypted: true},
+		{SizeGiB: 200, DeleteOnTermination: ptr.To(false)},
+	})
+	require.Equal(t, int32(30), root.SizeGiB)
+	require.Len(t, volumes, 2)
+	require.True(t, volumes[0].DeleteOnTermination, "delete on termination defaults to true")
+	require.True(t, volumes[0].Encrypted)
+	require.False(t, volumes[1].DeleteOnTermination)
+}
+
+func TestNewVolumeDetailsEmpty(t *testing.T) {
+	root, volumes := NewVolumeDetails(nil, nil)
+	require.Nil(t, root)
+	require.Nil(t, volumes)
+}
+
+func TestNewVolumeRequests(t *testing.T) {
+	rootDetail, volumeDetails := NewVolumeDetails(&RootVolumeRequest{SizeGiB: 30, Type: "gp3"}, []VolumeRequest{
+		{SizeGiB: 100, Type: "gp3", Encrypted: true},
+		{SizeGiB: 200, DeleteOnTermination: ptr.To(false)},
+	})
+	root, volumes := NewVolumeRequests(rootDetail, volumeDetails)
+	require.Equal(t, &RootVolumeRequest{SizeGiB: 30, Type: "gp3"}, root)
+	require.Len(t, volumes, 2)
+	require.True(t, *volumes[0].DeleteOnTermination)
+	require.True(t, volumes[0].Encrypted)
+	require.False(t, *volumes[1].DeleteOnTermination)
+}
+
+func TestNewTags(t *testing.T) {
+	details := NewTagDetails([]Tag{{Key: "owner", Value: "jdoe"}, {Key: "cost-center", Value: "1"}, {Key: "cost-center", Value: "42"}})
+	require.Equal(t, map[string]string{"owner": "jdoe", "cost-center": "42"}, details)
+	require.Equal(t, []Tag{{Key: "cost-center", Value: "42"}, {Key: "owner", Value: "jdoe"}}, NewTags(details))
+	require.Nil(t, NewTagDetails(nil))
+	require.Nil(t, NewTags(nil))
+}
+
+func TestReservationRequestBindTags(t *testing.T) {
+	aws := &AWSReservationRequest{Tags: []Tag{{Key: "team", Value: "provisioning"}}}
+	require.NoError(t, aws.Bind(nil))
+	aws.Tags = append(aws.Tags, Tag{Key: "aws:owner", Value: "me"})
+	require.ErrorIs(t, aws.Bind(nil), validation.ErrInvalidTag)
+
+	azure := &AzureReservationRequest{Tags: []Tag{{Key: "", Value: "empty"}}}
+	require.ErrorIs(t, azure.Bind(nil), validation.ErrInvalidTag)
+
+	gcp := &GCPReservationRequest{Tags: []Tag{{Key: "team", Value: "provisioning"}}}
+	require.NoError(t, gcp.Bind(nil))
+	gcp.Tags[0].Value = "Provisioning"
+	require.ErrorIs(t, gcp.Bind(nil), validation.ErrInvalidTag)
+}
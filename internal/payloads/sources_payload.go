@@ -56,3 +56,39 @@ type SourceUploadInfoResponse struct {
 func (s SourceUploadInfoResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
 	return nil
 }
+
+// See clients.RegionDetail
+type SourceRegionResponse struct {
+	Name string `json:"name" yaml:"name"`
+
+	// Region can be used for provisioning, it is false for regions not enabled by the account owner.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Zone names, not present when the provider does not list them.
+	Zones []string `json:"zones,omitempty" yaml:"zones,omitempty"`
+}
+
+type SourceRegionListResponse struct {
+	Provider string                  `json:"provider" yaml:"provider"`
+	Data     []*SourceRegionResponse `json:"data" yaml:"data"`
+}
+
+func (s *SourceRegionListResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func NewSourceRegionListResponse(provider string, regions clients.SourceRegions) render.Renderer {
+	list := make([]*SourceRegionResponse, len(regions))
+	for i, region := range regions {
+		zones := make([]string, len(region.Zones))
+		for j, zone := range region.Zones {
+			zones[j] = zone.String()
+		}
+		list[i] = &SourceRegionResponse{
+			Name:    region.Name.String(),
+			Enabled: region.Enabled,
+			Zones:   zones,
+		}
+	}
+	return &SourceRegionListResponse{Provider: provider, Data: list}
+}
@@ -0,0 +1,71 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidTag = errors.New("invalid tag")
+
+var (
+	gcpLabelKeyRegexp   = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,62}$`)
+	gcpLabelValueRegexp = regexp.MustCompile(`^[a-z0-9_-]{0,63}$`)
+)
+
+// AWSTag checks the key and value of a user tag against EC2 tag restrictions.
+func AWSTag(key, value string) error {
+	if key == "" {
+		return fmt.Errorf("%w: key must not be empty", ErrInvalidTag)
+	}
+	if utf8.RuneCountInString(key) > 128 {
+		return fmt.Errorf("%w: key %q is longer than 128 characters", ErrInvalidTag, key)
+	}
+	if strings.HasPrefix(strings.ToLower(key), "aws:") {
+		return fmt.Errorf("%w: key %q uses the reserved aws: prefix", ErrInvalidTag, key)
+	}
+	if utf8.RuneCountInString(value) > 256 {
+		return fmt.Errorf("%w: value of %q is longer than 256 characters", ErrInvalidTag, key)
+	}
+	return nil
+}
+
+// AzureTag checks the key and value of a user tag against Azure resource tag restrictions.
+func AzureTag(key, value string) error {
+	if key == "" {
+		return fmt.Errorf("%w: key must not be empty", ErrInvalidTag)
+	}
+	if utf8.RuneCountInString(key) > 512 {
+		return fmt.Errorf("%w: key %q is longer than 512 characters", ErrInvalidTag, key)
+	}
+	if strings.ContainsAny(key, `<>%&\?/`) {
+		return fmt.Errorf("%w: key %q contains one of <>%%&\\?/ characters", ErrInvalidTag, key)
+	}
+	for _, prefix := range []string{"azure", "microsoft", "windows"} {
+		if strings.HasPrefix(strings.ToLower(key), prefix) {
+			return fmt.Errorf("%w: key %q uses the reserved %s prefix", ErrInvalidTag, key, prefix)
+		}
+	}
+	if utf8.RuneCountInString(value) > 256 {
+		return fmt.Errorf("%w: value of %q is longer than 256 characters", ErrInvalidTag, key)
+	}
+	return nil
+}
+
+// GCPLabel checks the key and value of a user tag against GCP label syntax, keys start with
+// a lowercase letter and both keys and values contain only lowercase letters, digits,
+// underscores and dashes up to 63 characters.
+func GCPLabel(key, value string) error {
+	if key == "" {
+		return fmt.Errorf("%w: key must not be empty", ErrInvalidTag)
+	}
+	if !gcpLabelKeyRegexp.MatchString(key) {
+		return fmt.Errorf("%w: key %q is not a valid GCP label key", ErrInvalidTag, key)
+	}
+	if !gcpLabelValueRegexp.MatchString(value) {
+		return fmt.Errorf("%w: value of %q is not a valid GCP label value", ErrInvalidTag, key)
+	}
+	return nil
+}
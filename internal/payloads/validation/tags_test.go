@@ -0,0 +1,41 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWSTag(t *testing.T) {
+	require.NoError(t, AWSTag("Team", "Provisioning & Co."))
+	require.NoError(t, AWSTag("empty", ""))
+
+	require.ErrorIs(t, AWSTag("", "value"), ErrInvalidTag)
+	require.ErrorIs(t, AWSTag("aws:cloudformation", "value"), ErrInvalidTag)
+	require.ErrorIs(t, AWSTag("AWS:name", "value"), ErrInvalidTag)
+	require.ErrorIs(t, AWSTag(strings.Repeat("k", 129), "value"), ErrInvalidTag)
+	require.ErrorIs(t, AWSTag("key", strings.Repeat("v", 257)), ErrInvalidTag)
+}
+
+func TestAzureTag(t *testing.T) {
+	require.NoError(t, AzureTag("team", "provisioning"))
+
+	require.ErrorIs(t, AzureTag("", "value"), ErrInvalidTag)
+	require.ErrorIs(t, AzureTag("cost/center", "value"), ErrInvalidTag)
+	require.ErrorIs(t, AzureTag("Microsoft.Owner", "value"), ErrInvalidTag)
+	require.ErrorIs(t, AzureTag("key", strings.Repeat("v", 257)), ErrInvalidTag)
+}
+
+func TestGCPLabel(t *testing.T) {
+	require.NoError(t, GCPLabel("team", "provisioning-1"))
+	require.NoError(t, GCPLabel("empty", ""))
+
+	require.ErrorIs(t, GCPLabel("", "value"), ErrInvalidTag)
+	require.ErrorIs(t, GCPLabel("Team", "value"), ErrInvalidTag)
+	require.ErrorIs(t, GCPLabel("1team", "value"), ErrInvalidTag)
+	require.ErrorIs(t, GCPLabel("team", "Provisioning"), ErrInvalidTag)
+	require.ErrorIs(t, GCPLabel("team", "a b"), ErrInvalidTag)
+	require.ErrorIs(t, GCPLabel(strings.Repeat("k", 64), "value"), ErrInvalidTag)
+	require.ErrorIs(t, GCPLabel("key", strings.Repeat("v", 64)), ErrInvalidTag)
+}
@@ -7,6 +7,7 @@ var AzureInstanceType instanceType
 func init() {
 	AzureInstanceType = instanceType{
 		filename: "azure_types.yaml",
+		prices:   "azure_prices.yaml",
 		path:     "azure_availability",
 		etagName: "azure-types",
 	}
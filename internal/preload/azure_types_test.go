@@ -23,3 +23,7 @@ func TestAzureValidateRegion(t *testing.T) {
 	require.True(t, AzureInstanceType.ValidateRegion("westeurope_1"))
 	require.False(t, AzureInstanceType.ValidateRegion("centralprague_6"))
 }
+
+func TestAzureValidate(t *testing.T) {
+	require.Empty(t, AzureInstanceType.Validate())
+}
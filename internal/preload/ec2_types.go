@@ -7,6 +7,7 @@ var EC2InstanceType instanceType
 func init() {
 	EC2InstanceType = instanceType{
 		filename: "ec2_types.yaml",
+		prices:   "ec2_prices.yaml",
 		path:     "ec2_availability",
 		etagName: "ec2-types",
 	}
@@ -23,3 +23,7 @@ func TestEC2ValidateRegion(t *testing.T) {
 	require.True(t, EC2InstanceType.ValidateRegion("us-east-1"))
 	require.False(t, EC2InstanceType.ValidateRegion("cz-olomouc-2"))
 }
+
+func TestEC2Validate(t *testing.T) {
+	require.Empty(t, EC2InstanceType.Validate())
+}
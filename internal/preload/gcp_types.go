@@ -7,6 +7,7 @@ var GCPInstanceType instanceType
 func init() {
 	GCPInstanceType = instanceType{
 		filename: "gcp_types.yaml",
+		prices:   "gcp_prices.yaml",
 		path:     "gcp_availability",
 		etagName: "gcp-types",
 	}
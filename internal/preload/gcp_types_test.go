@@ -23,3 +23,7 @@ func TestGCPValidateRegion(t *testing.T) {
 	require.True(t, GCPInstanceType.ValidateRegion("europe-west1-b"))
 	require.False(t, GCPInstanceType.ValidateRegion("velky-tynec7-b"))
 }
+
+func TestGCPValidate(t *testing.T) {
+	require.Empty(t, GCPInstanceType.Validate())
+}
@@ -1,89 +1,156 @@
 package preload
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"sync/atomic"
 
 	"github.com/RHEnVision/provisioning-backend/internal/clients"
 	"github.com/RHEnVision/provisioning-backend/internal/middleware"
 )
 
+var ErrInvalidInstanceTypeData = errors.New("invalid instance type data")
+
 type instanceType struct {
 	filename string
+	prices   string
 	path     string
 	etagName string
+	data     atomic.Pointer[instanceTypeData]
+}
+
+// instanceTypeData is an immutable snapshot of loaded data, it is swapped as a whole on reload.
+type instanceTypeData struct {
 	tag      *middleware.ETag
 	typeInfo clients.InstanceTypeInfo
 }
 
+// Load loads embedded data.
 func (p *instanceType) Load() error {
+	return p.LoadFrom(fsTypes)
+}
+
+// LoadFrom loads data from a filesystem with the same layout as the embedded data. New data is
+// validated and replaces the current data atomically, on error the current data is kept.
+func (p *instanceType) LoadFrom(fsys fs.FS) error {
+	data := instanceTypeData{}
+
 	// load instance types
-	typesBuf, err := fsTypes.ReadFile(p.filename)
+	typesBuf, err := fs.ReadFile(fsys, p.filename)
 	if err != nil {
 		return fmt.Errorf("unable to read instance types %s: %w", p.filename, err)
 	}
 
-	err = p.typeInfo.RegisteredTypes.Load(typesBuf)
+	err = data.typeInfo.RegisteredTypes.Load(typesBuf)
 	if err != nil {
 		return fmt.Errorf("unable to load instance types %s: %w", p.filename, err)
 	}
 
 	// load availability information
-	err = p.typeInfo.RegionalAvailability.Load(fsTypes, p.path)
+	err = data.typeInfo.RegionalAvailability.Load(fsys, p.path)
 	if err != nil {
 		return fmt.Errorf("unable to load regional info %s: %w", p.path, err)
 	}
 
-	availBuf := clients.ConcatBuffers(fsTypes, p.path)
-	p.tag, err = middleware.GenerateETagFromBuffer(p.etagName, middleware.InstanceTypeExpiration, typesBuf, availBuf)
+	// load prices, they are optional
+	pricesBuf, err := fs.ReadFile(fsys, p.prices)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("unable to read instance type prices %s: %w", p.prices, err)
+	}
+
+	err = data.typeInfo.Prices.Load(pricesBuf)
+	if err != nil {
+		return fmt.Errorf("unable to load instance type prices %s: %w", p.prices, err)
+	}
+
+	if errs := data.typeInfo.Validate(); len(errs) > 0 {
+		return fmt.Errorf("%w %s: %w", ErrInvalidInstanceTypeData, p.filename, errors.Join(errs...))
+	}
+
+	availBuf, err := clients.ConcatBuffers(fsys, p.path)
+	if err != nil {
+		return fmt.Errorf("unable to read regional info %s: %w", p.path, err)
+	}
+
+	data.tag, err = middleware.GenerateETagFromBuffer(p.etagName, middleware.InstanceTypeExpiration, typesBuf, availBuf, pricesBuf)
 	if err != nil {
 		return fmt.Errorf("unable to generate etag %s: %w", p.etagName, err)
 	}
+
+	p.data.Store(&data)
 	return nil
 }
 
+// current returns the current data snapshot.
+func (p *instanceType) current() *instanceTypeData {
+	return p.data.Load()
+}
+
 // ETagValue returns HTTP ETag information. It is calculated as a hash from source YAML files.
 func (p *instanceType) ETagValue() *middleware.ETag {
-	return p.tag
+	return p.current().tag
 }
 
 // PrintRegisteredTypes prints relevant data to standard output.
 func (p *instanceType) PrintRegisteredTypes(typeName string) {
-	p.typeInfo.RegisteredTypes.Print(typeName)
+	p.current().typeInfo.RegisteredTypes.Print(typeName)
 }
 
 // PrintRegionalAvailability prints relevant data to standard output.
 func (p *instanceType) PrintRegionalAvailability(region, zone string) {
-	str := p.typeInfo.RegionalAvailability.Sprint(region, zone)
+	str := p.current().typeInfo.RegionalAvailability.Sprint(region, zone)
 	fmt.Println(str)
 }
 
 // InstanceTypesForZone returns instance type info for particular zone. Can list supported, unsupported
 // or all types when nil is passed.
 func (p *instanceType) InstanceTypesForZone(region, zone string, supported *bool) ([]*clients.InstanceType, error) {
-	result, err := p.typeInfo.InstanceTypesForZone(region, zone, supported)
+	info := p.current().typeInfo
+	result, err := info.InstanceTypesForZone(region, zone, supported)
 	if err != nil {
 		return nil, fmt.Errorf("unable to list instance types for region and zone: %w", err)
 	}
 	return result, nil
 }
 
+// InstanceTypesForRegion returns instance types available in any zone of a region, or all types
+// when region is empty.
+func (p *instanceType) InstanceTypesForRegion(region string) ([]*clients.InstanceType, error) {
+	info := p.current().typeInfo
+	result, err := info.InstanceTypesForRegion(region)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list instance types for region: %w", err)
+	}
+	return result, nil
+}
+
 // FindInstanceType looks up instance type by name.
 func (p *instanceType) FindInstanceType(name clients.InstanceTypeName) *clients.InstanceType {
-	return p.typeInfo.RegisteredTypes.Get(name)
+	return p.current().typeInfo.RegisteredTypes.Get(name)
 }
 
-// ValidateRegion checks if a region is preloaded.
-func (p *instanceType) ValidateRegion(region string) bool {
-	dirEntries, err := fsTypes.ReadDir(p.path)
-	if err != nil {
-		panic(fmt.Errorf("unable to read availability dir: %w", err))
+// PricePerHour returns on-demand price of an instance type in a region or zone, nil when unknown.
+func (p *instanceType) PricePerHour(region string, name clients.InstanceTypeName) *float64 {
+	info := p.current().typeInfo
+	price, ok := info.Prices.Get(region, name)
+	if !ok {
+		return nil
 	}
+	return &price
+}
 
-	fullName := region + ".yaml"
-	for _, e := range dirEntries {
-		if e.Name() == fullName {
-			return true
-		}
-	}
-	return false
+// Validate checks referential integrity of preloaded data, see InstanceTypeInfo.Validate.
+func (p *instanceType) Validate() []error {
+	return p.current().typeInfo.Validate()
+}
+
+// Diff compares preloaded data with new (live) data.
+func (p *instanceType) Diff(updated *clients.InstanceTypeInfo) *clients.InstanceTypeDiff {
+	return clients.DiffInstanceTypeInfo(&p.current().typeInfo, updated)
+}
+
+// ValidateRegion checks if a region is preloaded.
+func (p *instanceType) ValidateRegion(region string) bool {
+	return p.current().typeInfo.RegionalAvailability.Contains(region)
 }
@@ -0,0 +1,34 @@
+package preload
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+var all = []*instanceType{&EC2InstanceType, &AzureInstanceType, &GCPInstanceType}
+
+// Reload replaces data of all providers from a directory with the same layout as the embedded data
+// (e.g. "ec2_types.yaml", "ec2_availability/*.yaml" and optional "ec2_prices.yaml"). Providers
+// without the types file in the directory are reloaded from the embedded data. Providers which
+// fail to load keep their current data, all errors are returned joined.
+func Reload(dir string) error {
+	fsys := os.DirFS(dir)
+
+	var errs []error
+	for _, it := range all {
+		var err error
+		if _, statErr := fs.Stat(fsys, it.filename); statErr == nil {
+			err = it.LoadFrom(fsys)
+		} else {
+			err = it.Load()
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to reload %s: %w", it.etagName, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
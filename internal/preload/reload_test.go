@@ -0,0 +1,91 @@
+package preload
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestTypes(t *testing.T, dir, types, availability string) {
+	t.Helper()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "ec2_availability"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ec2_types.yaml"), []byte(types), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ec2_availability", "xx-test-1.yaml"), []byte(availability), 0o600))
+}
+
+func TestReload(t *testing.T) {
+	t.Cleanup(func() {
+		require.NoError(t, EC2InstanceType.Load())
+	})
+	dir := t.TempDir()
+	writeTestTypes(t, dir, `
+x9.test:
+    name: x9.test
+    vcpus: 2
+    cores: 1
+    memory_mib: 4096
+    storage_gb: 0
+    supported: true
+    arch: x86_64
+`, "- x9.test\n")
+	embeddedTag := EC2InstanceType.ETagValue().Value
+
+	err := Reload(dir)
+	require.NoError(t, err)
+
+	require.NotNil(t, EC2InstanceType.FindInstanceType("x9.test"))
+	require.Nil(t, EC2InstanceType.FindInstanceType("m1.small"))
+	require.True(t, EC2InstanceType.ValidateRegion("xx-test-1"))
+	require.NotEqual(t, embeddedTag, EC2InstanceType.ETagValue().Value)
+
+	// other providers are loaded from embedded data
+	require.NotNil(t, AzureInstanceType.FindInstanceType("Standard_A1_v2"))
+}
+
+func TestReloadInvalidKeepsData(t *testing.T) {
+	t.Cleanup(func() {
+		require.NoError(t, EC2InstanceType.Load())
+	})
+	dir := t.TempDir()
+	writeTestTypes(t, dir, "{}\n", "- x9.unknown\n")
+
+	err := Reload(dir)
+	require.ErrorIs(t, err, ErrInvalidInstanceTypeData)
+	require.NotNil(t, EC2InstanceType.FindInstanceType("m1.small"))
+}
+
+func TestReloadPrices(t *testing.T) {
+	t.Cleanup(func() {
+		require.NoError(t, EC2InstanceType.Load())
+	})
+	dir := t.TempDir()
+	writeTestTypes(t, dir, `
+x9.test:
+    name: x9.test
+    vcpus: 2
+    cores: 1
+    memory_mib: 4096
+    storage_gb: 0
+    supported: true
+    arch: x86_64
+`, "- x9.test\n")
+	require.NoError(t, Reload(dir))
+
+	// types without price data have no prices
+	require.Nil(t, EC2InstanceType.PricePerHour("xx-test-1", "x9.test"))
+	types, err := EC2InstanceType.InstanceTypesForRegion("xx-test-1")
+	require.NoError(t, err)
+	require.Nil(t, types[0].PricePerHour)
+	etag := EC2InstanceType.ETagValue().Value
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ec2_prices.yaml"), []byte("xx-test-1:\n    x9.test: 0.25\n"), 0o600))
+	require.NoError(t, Reload(dir))
+	require.NotEqual(t, etag, EC2InstanceType.ETagValue().Value, "etag must change with prices")
+	require.NotNil(t, EC2InstanceType.PricePerHour("xx-test-1", "x9.test"))
+	require.InDelta(t, 0.25, *EC2InstanceType.PricePerHour("xx-test-1", "x9.test"), 0.0001)
+	types, err = EC2InstanceType.InstanceTypesForRegion("xx-test-1")
+	require.NoError(t, err)
+	require.NotNil(t, types[0].PricePerHour)
+}
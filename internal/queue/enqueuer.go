@@ -7,3 +7,6 @@ import (
 )
 
 var GetEnqueuer func(ctx context.Context) worker.JobEnqueuer
+
+// GetWorker returns the job worker, it is used for inspection and management of jobs by operators.
+var GetWorker func(ctx context.Context) worker.JobWorker
@@ -20,16 +20,30 @@ func getEnqueuer(_ context.Context) worker.JobEnqueuer {
 	return enqueuer
 }
 
+func getWorker(_ context.Context) worker.JobWorker {
+	return workers
+}
+
 func init() {
 	queue.GetEnqueuer = getEnqueuer
+	queue.GetWorker = getWorker
 }
 
 func RegisterJobs(logger *zerolog.Logger) {
 	logger.Debug().Msg("Registering job queue handlers and interfaces")
-	workers.RegisterHandler(jobs.TypeNoop, jobs.HandleNoop, jobs.NoopJobArgs{})
-	workers.RegisterHandler(jobs.TypeLaunchInstanceAws, jobs.HandleLaunchInstanceAWS, jobs.LaunchInstanceAWSTaskArgs{})
-	workers.RegisterHandler(jobs.TypeLaunchInstanceAzure, jobs.HandleLaunchInstanceAzure, jobs.LaunchInstanceAzureTaskArgs{})
-	workers.RegisterHandler(jobs.TypeLaunchInstanceGcp, jobs.HandleLaunchInstanceGCP, jobs.LaunchInstanceGCPTaskArgs{})
+	workers.Use(
+		worker.Logging(),
+		worker.Tracing(),
+		jobs.RecordExecution,
+		worker.Recover(jobs.FinishReservationOnPanic),
+		worker.Metrics(),
+		worker.Timeout(config.Worker.Timeout),
+		jobs.ReservationContext,
+	)
+	worker.RegisterTypedHandler(workers, jobs.TypeNoop, jobs.HandleNoop)
+	worker.RegisterTypedHandler(workers, jobs.TypeLaunchInstanceAws, jobs.HandleLaunchInstanceAWS)
+	worker.RegisterTypedHandler(workers, jobs.TypeLaunchInstanceAzure, jobs.HandleLaunchInstanceAzure)
+	worker.RegisterTypedHandler(workers, jobs.TypeLaunchInstanceGcp, jobs.HandleLaunchInstanceGCP)
 }
 
 func Initialize(_ context.Context, logger *zerolog.Logger) error {
@@ -44,7 +58,8 @@ func Initialize(_ context.Context, logger *zerolog.Logger) error {
 		wk, err := worker.NewRedisWorker(config.RedisHostAndPort(),
 			config.Application.Cache.Redis.User, config.Application.Cache.Redis.Password,
 			config.Application.Cache.Redis.DB, "provisioning-job-queue",
-			config.Worker.PollInterval, config.Worker.Concurrency)
+			config.Worker.PollInterval, config.Worker.Concurrency, config.Worker.AccountConcurrency,
+			config.Worker.InterruptTimeout)
 		if err != nil {
 			return fmt.Errorf("cannot initialize redis worker queue: %w", err)
 		}
@@ -63,9 +78,17 @@ func StartDequeueLoop(ctx context.Context) {
 	workers.DequeueLoop(ctx)
 }
 
+// StopDequeueLoop waits for in-flight jobs up to the shutdown timeout, unfinished jobs are then
+// interrupted and re-queued by the Redis worker. The default timeout does not wait for long jobs,
+// only zero waits for all jobs to finish.
 func StopDequeueLoop(ctx context.Context) {
 	logger := zerolog.Ctx(ctx)
-	logger.Debug().Msg("Stopping dequeue loop")
+	logger.Debug().Dur("shutdown_timeout", config.Worker.ShutdownTimeout).Msg("Stopping dequeue loop")
+	if config.Worker.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Worker.ShutdownTimeout)
+		defer cancel()
+	}
 	workers.Stop(ctx)
 }
 
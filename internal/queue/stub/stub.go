@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/RHEnVision/provisioning-backend/internal/queue"
 	"github.com/RHEnVision/provisioning-backend/pkg/worker"
@@ -25,6 +26,13 @@ type hollowEnqueuer struct{}
 
 type stubEnqueuer struct {
 	enqueued []*worker.Job
+	delayed  []DelayedJob
+}
+
+// DelayedJob is a job enqueued via EnqueueAt or EnqueueAfter.
+type DelayedJob struct {
+	Job   *worker.Job
+	RunAt time.Time
 }
 
 func init() {
@@ -42,6 +50,18 @@ func EnqueuedJobs(ctx context.Context) []*worker.Job {
 	return enquer.enqueued
 }
 
+// DelayedJobs returns jobs enqueued via EnqueueAt or EnqueueAfter, they are not part of EnqueuedJobs.
+func DelayedJobs(ctx context.Context) []DelayedJob {
+	enquer := getEnqueuerStub(ctx)
+	return enquer.delayed
+}
+
+// Enqueuer returns the enqueuer stored in the context by WithEnqueuer, or an enqueuer ignoring all
+// jobs. It can be used to restore the stub when queue.GetEnqueuer was replaced by another package.
+func Enqueuer(ctx context.Context) worker.JobEnqueuer {
+	return getEnqueuer(ctx)
+}
+
 func getEnqueuer(ctx context.Context) worker.JobEnqueuer {
 	if enqueue := getEnqueuerStub(ctx); enqueue != nil {
 		return enqueue
@@ -61,6 +81,14 @@ func (h hollowEnqueuer) Enqueue(_ context.Context, _ *worker.Job) error {
 	return nil
 }
 
+func (h hollowEnqueuer) EnqueueAt(_ context.Context, _ *worker.Job, _ time.Time) error {
+	return nil
+}
+
+func (h hollowEnqueuer) EnqueueAfter(_ context.Context, _ *worker.Job, _ time.Duration) error {
+	return nil
+}
+
 func (s *stubEnqueuer) Enqueue(ctx context.Context, job *worker.Job) error {
 	if job == nil {
 		return fmt.Errorf("failed to enqueue: %w", ErrJobNotFound)
@@ -68,3 +96,15 @@ func (s *stubEnqueuer) Enqueue(ctx context.Context, job *worker.Job) error {
 	s.enqueued = append(s.enqueued, job)
 	return nil
 }
+
+func (s *stubEnqueuer) EnqueueAt(_ context.Context, job *worker.Job, at time.Time) error {
+	if job == nil {
+		return fmt.Errorf("failed to enqueue: %w", ErrJobNotFound)
+	}
+	s.delayed = append(s.delayed, DelayedJob{Job: job, RunAt: at})
+	return nil
+}
+
+func (s *stubEnqueuer) EnqueueAfter(ctx context.Context, job *worker.Job, delay time.Duration) error {
+	return s.EnqueueAt(ctx, job, time.Now().Add(delay))
+}
@@ -0,0 +1,184 @@
+//go:build integration
+// +build integration
+
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/config"
+	"github.com/RHEnVision/provisioning-backend/internal/jobs"
+	"github.com/RHEnVision/provisioning-backend/internal/queue"
+	"github.com/RHEnVision/provisioning-backend/pkg/worker"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisInspectInFlight(t *testing.T) {
+	ctx := context.Background()
+	wk := queue.GetWorker(ctx)
+
+	job := worker.Job{
+		AccountID: 1,
+		Type:      jobs.TypeNoop,
+		Args: jobs.NoopJobArgs{
+			Sleep: 500 * time.Millisecond,
+		},
+	}
+	err := queue.GetEnqueuer(ctx).Enqueue(ctx, &job)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		info, getErr := wk.GetJob(ctx, job.ID)
+		return getErr == nil && info.State == worker.JobStateInFlight
+	}, time.Second, 20*time.Millisecond, "job is not in flight")
+
+	infos, err := wk.ListJobs(ctx, &worker.JobFilter{Type: jobs.TypeNoop, AccountID: 1})
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	require.Equal(t, job.ID, infos[0].Job.ID)
+	require.False(t, infos[0].StartedAt.IsZero())
+
+	err = wk.RequeueJob(ctx, job.ID)
+	require.NoError(t, err)
+
+	// the requeued job is processed again and then removed from in-flight jobs
+	require.Eventually(t, func() bool {
+		_, getErr := wk.GetJob(ctx, job.ID)
+		return getErr != nil
+	}, 3*time.Second, 50*time.Millisecond, "job is still queued or in flight")
+}
+
+func TestRedisDeleteAndDrain(t *testing.T) {
+	ctx := context.Background()
+	wk := queue.GetWorker(ctx)
+
+	_, err := wk.DrainQueue(ctx)
+	require.NoError(t, err)
+
+	err = wk.DeleteJob(ctx, uuid.New())
+	require.ErrorIs(t, err, worker.ErrJobNotFound)
+}
+
+func TestRedisShutdownRequeue(t *testing.T) {
+	ctx := context.Background()
+	newWorker := func() *worker.RedisWorker {
+		wk, err := worker.NewRedisWorker(config.RedisHostAndPort(),
+			config.Application.Cache.Redis.User, config.Application.Cache.Redis.Password,
+			config.Application.Cache.Redis.DB, "provisioning-shutdown-test", 50*time.Millisecond, 1, 0, 100*time.Millisecond)
+		require.NoError(t, err)
+		return wk
+	}
+
+	interrupted := make(chan bool, 1)
+	wk := newWorker()
+	wk.RegisterHandler("shutdown_test", func(ctx context.Context, _ *worker.Job) {
+		worker.SetProgress(ctx, "first")
+		<-ctx.Done()
+		interrupted <- worker.Interrupted(ctx)
+		worker.ReportInterrupted(ctx)
+	}, "")
+	wk.DequeueLoop(ctx)
+
+	job := worker.Job{AccountID: 1, Type: "shutdown_test", Args: "test"}
+	require.NoError(t, wk.Enqueue(ctx, &job))
+	require.Eventually(t, func() bool {
+		info, getErr := wk.GetJob(ctx, job.ID)
+		return getErr == nil && info.State == worker.JobStateInFlight
+	}, time.Second, 20*time.Millisecond, "job is not in flight")
+
+	stopCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	wk.Stop(stopCtx)
+	require.True(t, <-interrupted, "handler was not interrupted")
+
+	// the job is queued again for another worker
+	info, err := newWorker().GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, worker.JobStateQueued, info.State)
+	require.Equal(t, "first", info.Job.Progress)
+	require.Equal(t, 1, info.Job.Interruptions)
+
+	_, err = newWorker().DrainQueue(ctx)
+	require.NoError(t, err)
+}
+
+func TestRedisShutdownInterruptStep(t *testing.T) {
+	ctx := context.Background()
+	newWorker := func() *worker.RedisWorker {
+		wk, err := worker.NewRedisWorker(config.RedisHostAndPort(),
+			config.Application.Cache.Redis.User, config.Application.Cache.Redis.Password,
+			config.Application.Cache.Redis.DB, "provisioning-shutdown-test", 50*time.Millisecond, 1, 0, time.Minute)
+		require.NoError(t, err)
+		return wk
+	}
+
+	cancelled := make(chan bool, 1)
+	wk := newWorker()
+	wk.RegisterHandler("shutdown_test", func(ctx context.Context, _ *worker.Job) {
+		worker.SetProgress(ctx, "first")
+		// the current step continues, the handler stops at the step boundary
+		for !worker.Interrupted(ctx) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		cancelled <- ctx.Err() != nil
+		worker.ReportInterrupted(ctx)
+	}, "")
+	wk.DequeueLoop(ctx)
+
+	job := worker.Job{AccountID: 1, Type: "shutdown_test", Args: "test"}
+	require.NoError(t, wk.Enqueue(ctx, &job))
+	require.Eventually(t, func() bool {
+		info, getErr := wk.GetJob(ctx, job.ID)
+		return getErr == nil && info.State == worker.JobStateInFlight
+	}, time.Second, 20*time.Millisecond, "job is not in flight")
+
+	stopCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	wk.Stop(stopCtx)
+	require.False(t, <-cancelled, "job context was cancelled before the interrupt timeout")
+
+	info, err := newWorker().GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, worker.JobStateQueued, info.State)
+	require.Equal(t, 1, info.Job.Interruptions)
+
+	_, err = newWorker().DrainQueue(ctx)
+	require.NoError(t, err)
+}
+
+func TestRedisShutdownFinishedNotRequeued(t *testing.T) {
+	ctx := context.Background()
+	newWorker := func() *worker.RedisWorker {
+		wk, err := worker.NewRedisWorker(config.RedisHostAndPort(),
+			config.Application.Cache.Redis.User, config.Application.Cache.Redis.Password,
+			config.Application.Cache.Redis.DB, "provisioning-shutdown-test", 50*time.Millisecond, 1, 0, time.Minute)
+		require.NoError(t, err)
+		return wk
+	}
+
+	wk := newWorker()
+	wk.RegisterHandler("shutdown_test", func(ctx context.Context, _ *worker.Job) {
+		// the handler finishes the job without reporting the interruption
+		for !worker.Interrupted(ctx) {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}, "")
+	wk.DequeueLoop(ctx)
+
+	job := worker.Job{AccountID: 1, Type: "shutdown_test", Args: "test"}
+	require.NoError(t, wk.Enqueue(ctx, &job))
+	require.Eventually(t, func() bool {
+		info, getErr := wk.GetJob(ctx, job.ID)
+		return getErr == nil && info.State == worker.JobStateInFlight
+	}, time.Second, 20*time.Millisecond, "job is not in flight")
+
+	stopCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	wk.Stop(stopCtx)
+
+	_, err := newWorker().GetJob(ctx, job.ID)
+	require.ErrorIs(t, err, worker.ErrJobNotFound, "finished job was re-queued")
+}
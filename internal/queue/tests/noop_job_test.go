@@ -154,3 +154,40 @@ func TestRedisNoopTimeout(t *testing.T) {
 	require.False(t, updatedRes.Success.Bool)
 	require.Equal(t, "Timeout", updatedRes.Status)
 }
+
+func TestRedisNoopEnqueueAfter(t *testing.T) {
+	reservationDao, ctx := getReservationDao(t)
+	defer reset()
+
+	res := &models.NoopReservation{
+		Reservation: models.Reservation{
+			AccountID:  1,
+			Steps:      1,
+			StepTitles: []string{"Test step"},
+			Provider:   models.ProviderTypeNoop,
+			Status:     "Created",
+		},
+	}
+	err := reservationDao.CreateNoop(ctx, res)
+	require.NoError(t, err)
+	require.NotZero(t, res.ID)
+
+	job := worker.Job{
+		AccountID: 1,
+		Type:      jobs.TypeNoop,
+		Args: jobs.NoopJobArgs{
+			ReservationID: res.ID,
+		},
+	}
+
+	err = queue.GetEnqueuer(ctx).EnqueueAfter(context.Background(), &job, 300*time.Millisecond)
+	require.NoError(t, err)
+
+	info, err := queue.GetWorker(ctx).GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, worker.JobStateDelayed, info.State)
+
+	updatedRes := waitForReservation(t, res.ID)
+	require.True(t, updatedRes.Success.Valid)
+	require.True(t, updatedRes.Success.Bool)
+}
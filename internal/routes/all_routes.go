@@ -42,6 +42,29 @@ func MountRoot(r *chi.Mux) {
 	})
 }
 
+// MountInternal mounts operator endpoints. They are not part of the API and not protected by
+// identity or permissions, they are served on a separate admin port and every request must
+// carry the pre-shared key.
+func MountInternal(r *chi.Mux, psk string) {
+	r.Route("/internal", func(r chi.Router) {
+		r.Use(middleware.CorrelationID)
+		r.Use(middleware.LoggerMiddleware(&log.Logger))
+		r.Use(render.SetContentType(render.ContentTypeJSON))
+		r.Use(middleware.EnforcePSK(psk))
+
+		// Inspection and management of the job queue
+		r.Route("/jobs", func(r chi.Router) {
+			r.Get("/", s.ListJobs)
+			r.Delete("/", s.DrainJobs)
+			r.Route("/{ID}", func(r chi.Router) {
+				r.Get("/", s.GetJob)
+				r.Delete("/", s.DeleteJob)
+				r.Post("/requeue", s.RequeueJob)
+			})
+		})
+	})
+}
+
 func MountAPI(r *chi.Mux) {
 	r.Route("/openapi.json", func(r chi.Router) {
 		r.Use(middleware.ETagMiddleware(api.ETagValue))
@@ -71,6 +94,8 @@ func MountAPI(r *chi.Mux) {
 
 				r.With(middleware.Pagination).Get("/launch_templates", s.ListLaunchTemplates)
 				r.Get("/upload_info", s.GetSourceUploadInfo)
+				r.Get("/regions", s.ListSourceRegions)
+				r.Get("/images", s.ListSourceImages)
 				r.Route("/validate_permissions", func(r chi.Router) {
 					r.Get("/", s.ValidatePermissions)
 				})
@@ -98,6 +123,29 @@ func MountAPI(r *chi.Mux) {
 			})
 			// Generic reservation detail request (no details provided)
 			r.With(middleware.EnforcePermissions("reservation", "read")).Get("/{ID}", s.GetReservationDetail)
+			// Retry of a failed reservation, provider specific permission check is in the service function
+			r.With(middleware.EnforcePermissions("reservation", "write")).Post("/{ID}/retry", s.RetryReservation)
+			// Launch schedule of a reservation created with not_before or schedule field
+			r.With(middleware.EnforcePermissions("reservation", "read")).Get("/{ID}/schedule", s.GetReservationSchedule)
+			r.With(middleware.EnforcePermissions("reservation", "write")).Delete("/{ID}/schedule", s.DeleteReservationSchedule)
+			// Attempts of background jobs of a reservation
+			r.With(middleware.EnforcePermissions("reservation", "read")).Get("/{ID}/jobs", s.ListReservationJobs)
+			// Reservations matching approval rules are launched only after approval
+			r.With(middleware.EnforcePermissions("reservation", "read")).Get("/{ID}/approval", s.GetReservationApproval)
+			r.With(middleware.EnforcePermissions("reservation", "approve")).Post("/{ID}/approve", s.ApproveReservation)
+			r.With(middleware.EnforcePermissions("reservation", "approve")).Post("/{ID}/reject", s.RejectReservation)
+		})
+
+		r.Route("/approval_rules", func(r chi.Router) {
+			r.With(middleware.EnforcePermissions("reservation", "read")).Get("/", s.ListApprovalRules)
+			r.With(middleware.EnforcePermissions("reservation", "approve")).Post("/", s.CreateApprovalRule)
+			r.With(middleware.EnforcePermissions("reservation", "approve")).Delete("/{ID}", s.DeleteApprovalRule)
+		})
+
+		r.Route("/policy", func(r chi.Router) {
+			r.With(middleware.EnforcePermissions("policy", "read")).Get("/", s.GetPolicy)
+			r.With(middleware.EnforcePermissions("policy", "write")).Put("/", s.PutPolicy)
+			r.With(middleware.EnforcePermissions("policy", "write")).Delete("/", s.DeletePolicy)
 		})
 
 		// Endpoint used by sources background checker (no permissions needed)
@@ -138,6 +186,9 @@ func MountAPI(r *chi.Mux) {
 				r.Use(middleware.ETagMiddleware(preload.GCPInstanceType.ETagValue))
 				r.Get("/", s.ListBuiltinInstanceTypes(preload.GCPInstanceType.InstanceTypesForZone))
 			})
+
+			// Closest supported types of all providers for requested vCPUs and memory.
+			r.Get("/recommend", s.RecommendInstanceTypes)
 		})
 
 		// We expose feature flags for image builder, this is undocumented since we
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/db"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+func ListApprovalRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := dao.GetApprovalDao(r.Context()).ListRules(r.Context())
+	if err != nil {
+		renderError(w, r, payloads.NewDAOError(r.Context(), "list approval rules", err))
+		return
+	}
+
+	if err := render.Render(w, r, payloads.NewApprovalRuleListResponse(rules)); err != nil {
+		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render approval rules list", err))
+	}
+}
+
+func CreateApprovalRule(w http.ResponseWriter, r *http.Request) {
+	payload := &payloads.ApprovalRuleRequest{}
+	if err := render.Bind(r, payload); err != nil {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "create approval rule", err))
+		return
+	}
+
+	if payload.MaxAmount == 0 && len(payload.InstanceTypes) == 0 {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), ErrApprovalRuleEmpty.Error(), ErrApprovalRuleEmpty))
+		return
+	}
+
+	rule := payload.NewModel()
+	err := dao.GetApprovalDao(r.Context()).CreateRule(r.Context(), rule)
+	var validationError validator.ValidationErrors
+	if err != nil {
+		if db.IsPostgresError(err, db.UniqueConstraintErrorCode) != nil {
+			renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "approval rule with such name already exists for this account", err))
+		} else if errors.As(err, &validationError) {
+			renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "validation error", err))
+		} else {
+			renderError(w, r, payloads.NewDAOError(r.Context(), "create approval rule", err))
+		}
+		return
+	}
+
+	if err := render.Render(w, r, payloads.NewApprovalRuleResponse(rule)); err != nil {
+		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render approval rule", err))
+	}
+}
+
+func DeleteApprovalRule(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseInt64(r, "ID")
+	if err != nil {
+		renderError(w, r, payloads.NewURLParsingError(r.Context(), "unable to parse ID parameter", err))
+		return
+	}
+
+	err = dao.GetApprovalDao(r.Context()).DeleteRule(r.Context(), id)
+	if errors.Is(err, dao.ErrAffectedMismatch) {
+		renderError(w, r, payloads.NewNotFoundError(r.Context(), fmt.Sprintf("approval rule with id %d", id), err))
+		return
+	} else if err != nil {
+		renderError(w, r, payloads.NewDAOError(r.Context(), fmt.Sprintf("delete approval rule with id %d", id), err))
+		return
+	}
+
+	writeNoContent(w, r)
+}
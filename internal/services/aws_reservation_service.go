@@ -18,34 +18,41 @@ import (
 	"github.com/RHEnVision/provisioning-backend/internal/models"
 	"github.com/RHEnVision/provisioning-backend/internal/payloads"
 	"github.com/RHEnVision/provisioning-backend/internal/preload"
-	"github.com/RHEnVision/provisioning-backend/internal/queue"
 	"github.com/RHEnVision/provisioning-backend/pkg/worker"
 	"github.com/go-chi/render"
 	"github.com/rs/zerolog"
 )
 
 func CreateAWSReservation(w http.ResponseWriter, r *http.Request) {
-	logger := zerolog.Ctx(r.Context())
-
-	var accountId int64 = identity.AccountId(r.Context())
-	var id identity.Principal = identity.Identity(r.Context())
-
 	payload := &payloads.AWSReservationRequest{}
 	if err := render.Bind(r, payload); err != nil {
 		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "AWS reservation", err))
 		return
 	}
 
+	createAWSReservation(w, r, payload, nil)
+}
+
+// createAWSReservation validates the request, stores the reservation and enqueues the launch job.
+// Parent ID is only set for retries of a failed reservation.
+func createAWSReservation(w http.ResponseWriter, r *http.Request, payload *payloads.AWSReservationRequest, parentID *int64) {
+	logger := zerolog.Ctx(r.Context())
+
+	var accountId int64 = identity.AccountId(r.Context())
+	var id identity.Principal = identity.Identity(r.Context())
+
 	rDao := dao.GetReservationDao(r.Context())
 	pkDao := dao.GetPubkeyDao(r.Context())
 
-	// Check for preloaded region
+	// Check for preloaded region or a region enabled for the account (e.g. opt-in regions)
 	if payload.Region == "" {
 		payload.Region = "us-east-1"
 	}
 	if !preload.EC2InstanceType.ValidateRegion(payload.Region) {
-		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "Unsupported region", ErrUnsupportedRegion))
-		return
+		if !sourceRegionEnabled(r.Context(), payload.SourceID, payload.Region) {
+			renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "Unsupported region", ErrUnsupportedRegion))
+			return
+		}
 	}
 
 	// Either Launch Template or Instance Type must be set. Both can be set too, in that case, instance type overrides the launch template.
@@ -66,12 +73,55 @@ func CreateAWSReservation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Root volume override needs the image root device name
+	rootVolume, volumes := payloads.NewVolumeDetails(payload.RootVolume, payload.Volumes)
+	if rootVolume != nil && payload.ImageID == "" {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "Root volume requires an image", ErrVolumesWithoutImage))
+		return
+	}
+	if err := clients.AWSVolumeLimits.Apply(rootVolume, volumes); err != nil {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "Invalid volumes", err))
+		return
+	}
+
+	// Launch at a later time or repeatedly, the launch job is prepared now and enqueued by the scheduler
+	schedule, schErr := newReservationSchedule(payload.NotBefore, payload.Schedule)
+	if schErr != nil {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "Invalid schedule", schErr))
+		return
+	}
+
+	// Launches violating the launch policy of the account are rejected
+	tags := payloads.NewTagDetails(payload.Tags)
+	policyErr := checkLaunchPolicy(r.Context(), &models.PolicyLaunch{
+		Provider:     models.ProviderTypeAWS,
+		Region:       payload.Region,
+		InstanceType: payload.InstanceType,
+		ImageID:      payload.ImageID,
+		Amount:       int64(payload.Amount),
+		Tags:         tags,
+	})
+	if policyErr != nil {
+		renderError(w, r, payloads.NewPolicyError(r.Context(), "check launch policy", policyErr))
+		return
+	}
+
+	// Launches matching approval rules of the account wait for approval
+	reason, err := approvalReason(r.Context(), int64(payload.Amount), payload.InstanceType)
+	if err != nil {
+		renderError(w, r, payloads.NewDAOError(r.Context(), "check approval rules", err))
+		return
+	}
+
 	detail := &models.AWSDetail{
 		Region:           payload.Region,
 		LaunchTemplateID: payload.LaunchTemplateID,
 		InstanceType:     payload.InstanceType,
 		Amount:           payload.Amount,
 		PowerOff:         payload.PowerOff,
+		RootVolume:       rootVolume,
+		Volumes:          volumes,
+		Tags:             tags,
 	}
 	reservation := &models.AWSReservation{
 		PubkeyID: &payload.PubkeyID,
@@ -82,6 +132,7 @@ func CreateAWSReservation(w http.ResponseWriter, r *http.Request) {
 	reservation.AccountID = accountId
 	reservation.Status = "Created"
 	reservation.Provider = models.ProviderTypeAWS
+	reservation.ParentID = parentID
 	reservation.Steps = 3
 	reservation.StepTitles = []string{"Ensure public key", "Launch instance(s)", "Fetch instance(s) description"}
 	newName := config.Application.InstancePrefix + payload.Name
@@ -179,16 +230,34 @@ func CreateAWSReservation(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
-	err = queue.GetEnqueuer(r.Context()).Enqueue(r.Context(), &launchJob)
-	if err != nil {
-		renderError(w, r, payloads.NewEnqueueTaskError(r.Context(), "job enqueue error", err))
-		return
+	if reason != "" {
+		err = requestApproval(r.Context(), reservation.ID, &launchJob, schedule, reason)
+		if err != nil {
+			renderError(w, r, payloads.NewDAOError(r.Context(), "request reservation approval", err))
+			return
+		}
+		reservation.Status = pendingApprovalStatus
+		logger.Debug().Msgf("Reservation waits for approval: %s", reason)
+	} else if schedule != nil {
+		err = scheduleLaunch(r.Context(), reservation.ID, &launchJob, schedule)
+		if err != nil {
+			renderError(w, r, payloads.NewDAOError(r.Context(), "schedule reservation", err))
+			return
+		}
+		reservation.Status = scheduledStatus
+		logger.Debug().Msgf("Scheduled reservation launch at %s", schedule.NextRunAt)
+	} else {
+		err = jobs.EnqueueLaunch(r.Context(), &launchJob)
+		if err != nil {
+			renderError(w, r, payloads.NewEnqueueTaskError(r.Context(), "job enqueue error", err))
+			return
+		}
+		logger.Debug().Msgf("Enqueued reservation job %s", launchJob.ID)
 	}
-	logger.Debug().Msgf("Enqueued reservation job %s", launchJob.ID)
 
 	// Return response payload
 	unused := make([]*models.ReservationInstance, 0, 0)
-	if err := render.Render(w, r, payloads.NewAWSReservationResponse(reservation, unused)); err != nil {
+	if err := render.Render(w, r, payloads.NewAWSReservationResponse(reservation, unused, awsPricePerHour(reservation))); err != nil {
 		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render AWS reservation", err))
 	}
 }
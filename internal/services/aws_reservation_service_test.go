@@ -10,6 +10,8 @@ import (
 
 	Clientstubs "github.com/RHEnVision/provisioning-backend/internal/clients/stubs"
 	"github.com/RHEnVision/provisioning-backend/internal/dao/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/queue"
+	"github.com/RHEnVision/provisioning-backend/internal/queue/stub"
 	"github.com/RHEnVision/provisioning-backend/internal/services"
 	"github.com/RHEnVision/provisioning-backend/internal/testing/factories"
 	"github.com/RHEnVision/provisioning-backend/internal/testing/identity"
@@ -19,12 +21,20 @@ import (
 )
 
 func TestCreateAWSReservationHandler(t *testing.T) {
+	getEnqueuer := queue.GetEnqueuer
+	queue.GetEnqueuer = stub.Enqueuer
+	t.Cleanup(func() {
+		queue.GetEnqueuer = getEnqueuer
+	})
 	var json_data []byte
 	ctx := stubs.WithAccountDaoOne(context.Background())
 	ctx = identity.WithTenant(t, ctx)
 	ctx = Clientstubs.WithSourcesClient(ctx)
+	ctx = Clientstubs.WithEC2Client(ctx)
 	ctx = Clientstubs.WithImageBuilderClient(ctx)
 	ctx = stubs.WithReservationDao(ctx)
+	ctx = stubs.WithApprovalDao(ctx)
+	ctx = stubs.WithPolicyDao(ctx)
 	ctx = stubs.WithPubkeyDao(ctx)
 	pk := factories.NewPubkeyRSA()
 	err := stubs.AddPubkey(ctx, pk)
@@ -51,7 +61,7 @@ func TestCreateAWSReservationHandler(t *testing.T) {
 		handler := http.HandlerFunc(services.CreateAWSReservation)
 		handler.ServeHTTP(rr, req)
 
-		require.Equal(t, http.StatusOK, rr.Code, "Handler returned wrong status code")
+		require.Equal(t, http.StatusOK, rr.Code, "Handler returned wrong status code: %s", rr.Body.String())
 
 		stubCount := stubs.AWSReservationStubCount(ctx)
 		assert.Equal(t, 1, stubCount, "Reservation has not been created through DAO")
@@ -83,3 +93,62 @@ func TestCreateAWSReservationHandler(t *testing.T) {
 		require.Equal(t, http.StatusBadRequest, rr.Code, "Handler returned wrong status code")
 	})
 }
+
+func TestCreateAWSReservationHandlerVolumes(t *testing.T) {
+	ctx := stubs.WithAccountDaoOne(context.Background())
+	ctx = identity.WithTenant(t, ctx)
+	ctx = Clientstubs.WithSourcesClient(ctx)
+	ctx = Clientstubs.WithImageBuilderClient(ctx)
+	ctx = stubs.WithReservationDao(ctx)
+	ctx = stubs.WithApprovalDao(ctx)
+	ctx = stubs.WithPolicyDao(ctx)
+	ctx = stubs.WithPubkeyDao(ctx)
+	pk := factories.NewPubkeyRSA()
+	err := stubs.AddPubkey(ctx, pk)
+	require.NoError(t, err, "failed to generate pubkey")
+
+	createReservation := func(t *testing.T, values map[string]interface{}) *httptest.ResponseRecorder {
+		t.Helper()
+		jsonData, err := json.Marshal(values)
+		require.NoError(t, err, "unable to marshal values to json")
+
+		req, err := http.NewRequestWithContext(ctx, "POST", "/api/provisioning/reservations/aws", bytes.NewBuffer(jsonData))
+		require.NoError(t, err, "failed to create request")
+		req.Header.Add("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(services.CreateAWSReservation)
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("failed reservation with volume size out of range", func(t *testing.T) {
+		rr := createReservation(t, map[string]interface{}{
+			"source_id":     "1",
+			"region":        "us-east-1",
+			"image_id":      "2bc640f6-927a-404a-9594-5b2da7e06608",
+			"amount":        1,
+			"instance_type": "t1.micro",
+			"pubkey_id":     pk.ID,
+			"volumes":       []map[string]interface{}{{"size_gib": 20000, "type": "gp3"}},
+		})
+
+		assert.Contains(t, rr.Body.String(), "Invalid volumes")
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Handler returned wrong status code")
+	})
+
+	t.Run("failed reservation with root volume and no image", func(t *testing.T) {
+		rr := createReservation(t, map[string]interface{}{
+			"source_id":          "1",
+			"region":             "us-east-1",
+			"amount":             1,
+			"instance_type":      "t1.micro",
+			"launch_template_id": "lt-8732678436272377",
+			"pubkey_id":          pk.ID,
+			"root_volume":        map[string]interface{}{"size_gib": 50},
+		})
+
+		assert.Contains(t, rr.Body.String(), "Root volume requires an image")
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Handler returned wrong status code")
+	})
+}
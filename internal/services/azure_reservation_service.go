@@ -15,7 +15,6 @@ import (
 	"github.com/RHEnVision/provisioning-backend/internal/models"
 	"github.com/RHEnVision/provisioning-backend/internal/payloads"
 	"github.com/RHEnVision/provisioning-backend/internal/preload"
-	"github.com/RHEnVision/provisioning-backend/internal/queue"
 	"github.com/RHEnVision/provisioning-backend/pkg/worker"
 	"github.com/go-chi/render"
 	"github.com/google/uuid"
@@ -23,14 +22,19 @@ import (
 )
 
 func CreateAzureReservation(w http.ResponseWriter, r *http.Request) {
-	logger := zerolog.Ctx(r.Context())
-
 	payload := &payloads.AzureReservationRequest{}
 	if err := render.Bind(r, payload); err != nil {
 		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "Azure reservation", err))
 		return
 	}
 
+	createAzureReservation(w, r, payload, nil)
+}
+
+// createAzureReservation creates the reservation and its job, parent ID is set when retrying.
+func createAzureReservation(w http.ResponseWriter, r *http.Request, payload *payloads.AzureReservationRequest, parentID *int64) {
+	logger := zerolog.Ctx(r.Context())
+
 	pkDao := dao.GetPubkeyDao(r.Context())
 	rDao := dao.GetReservationDao(r.Context())
 
@@ -41,11 +45,49 @@ func CreateAzureReservation(w http.ResponseWriter, r *http.Request) {
 		if preload.AzureInstanceType.ValidateRegion(payload.Location) {
 			logger.Warn().Msgf("Azure region passed with location suffix (%s), this is deprecated behaviour format", payload.Location)
 		} else {
-			renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "Unsupported location", ErrUnsupportedRegion))
-			return
+			// location can be available for the subscription but not preloaded
+			if !sourceRegionEnabled(r.Context(), payload.SourceID, payload.Location) {
+				renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "Unsupported location", ErrUnsupportedRegion))
+				return
+			}
 		}
 	}
 
+	rootVolume, volumes := payloads.NewVolumeDetails(payload.RootVolume, payload.Volumes)
+	if err := clients.AzureVolumeLimits.Apply(rootVolume, volumes); err != nil {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "Invalid volumes", err))
+		return
+	}
+
+	// Launch at a later time or repeatedly, the launch job is prepared now and enqueued by the scheduler
+	schedule, schErr := newReservationSchedule(payload.NotBefore, payload.Schedule)
+	if schErr != nil {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "Invalid schedule", schErr))
+		return
+	}
+
+	// Launches violating the launch policy of the account are rejected
+	tags := payloads.NewTagDetails(payload.Tags)
+	policyErr := checkLaunchPolicy(r.Context(), &models.PolicyLaunch{
+		Provider:     models.ProviderTypeAzure,
+		Region:       payload.Location,
+		InstanceType: payload.InstanceSize,
+		ImageID:      payload.ImageID,
+		Amount:       payload.Amount,
+		Tags:         tags,
+	})
+	if policyErr != nil {
+		renderError(w, r, payloads.NewPolicyError(r.Context(), "check launch policy", policyErr))
+		return
+	}
+
+	// Launches matching approval rules of the account wait for approval
+	reason, err := approvalReason(r.Context(), payload.Amount, payload.InstanceSize)
+	if err != nil {
+		renderError(w, r, payloads.NewDAOError(r.Context(), "check approval rules", err))
+		return
+	}
+
 	// Validate pubkey
 	logger.Debug().Msgf("Validating existence of pubkey %d for this account", payload.PubkeyID)
 	pk, err := pkDao.GetById(r.Context(), payload.PubkeyID)
@@ -136,6 +178,9 @@ func CreateAzureReservation(w http.ResponseWriter, r *http.Request) {
 		Amount:        payload.Amount,
 		PowerOff:      payload.PowerOff,
 		Name:          name,
+		RootVolume:    rootVolume,
+		Volumes:       volumes,
+		Tags:          tags,
 	}
 	reservation := &models.AzureReservation{
 		PubkeyID: &payload.PubkeyID,
@@ -143,6 +188,7 @@ func CreateAzureReservation(w http.ResponseWriter, r *http.Request) {
 		ImageID:  payload.ImageID,
 		Detail:   detail,
 	}
+	reservation.ParentID = parentID
 	reservation.Steps = int32(len(jobs.LaunchInstanceAzureSteps))
 	reservation.StepTitles = jobs.LaunchInstanceAzureSteps
 
@@ -171,16 +217,34 @@ func CreateAzureReservation(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
-	err = queue.GetEnqueuer(r.Context()).Enqueue(r.Context(), &launchJob)
-	if err != nil {
-		renderError(w, r, payloads.NewEnqueueTaskError(r.Context(), "job enqueue error", err))
-		return
+	if reason != "" {
+		err = requestApproval(r.Context(), reservation.ID, &launchJob, schedule, reason)
+		if err != nil {
+			renderError(w, r, payloads.NewDAOError(r.Context(), "request reservation approval", err))
+			return
+		}
+		reservation.Status = pendingApprovalStatus
+		logger.Debug().Msgf("Reservation waits for approval: %s", reason)
+	} else if schedule != nil {
+		err = scheduleLaunch(r.Context(), reservation.ID, &launchJob, schedule)
+		if err != nil {
+			renderError(w, r, payloads.NewDAOError(r.Context(), "schedule reservation", err))
+			return
+		}
+		reservation.Status = scheduledStatus
+		logger.Debug().Msgf("Scheduled reservation launch at %s", schedule.NextRunAt)
+	} else {
+		err = jobs.EnqueueLaunch(r.Context(), &launchJob)
+		if err != nil {
+			renderError(w, r, payloads.NewEnqueueTaskError(r.Context(), "job enqueue error", err))
+			return
+		}
+		logger.Debug().Msgf("Enqueued reservation job %s", launchJob.ID)
 	}
-	logger.Debug().Msgf("Enqueued reservation job %s", launchJob.ID)
 
 	// Return response payload
 	unused := make([]*models.ReservationInstance, 0, 0)
-	if err = render.Render(w, r, payloads.NewAzureReservationResponse(reservation, unused)); err != nil {
+	if err = render.Render(w, r, payloads.NewAzureReservationResponse(reservation, unused, azurePricePerHour(reservation))); err != nil {
 		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render Azure reservation", err))
 	}
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/RHEnVision/provisioning-backend/internal/jobs"
 	"github.com/RHEnVision/provisioning-backend/internal/models"
 	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/RHEnVision/provisioning-backend/internal/queue"
 	"github.com/RHEnVision/provisioning-backend/internal/queue/stub"
 	"github.com/RHEnVision/provisioning-backend/internal/services"
 	"github.com/RHEnVision/provisioning-backend/internal/testing/factories"
@@ -23,12 +24,19 @@ import (
 )
 
 func TestCreateAzureReservationHandler(t *testing.T) {
+	getEnqueuer := queue.GetEnqueuer
+	queue.GetEnqueuer = stub.Enqueuer
+	t.Cleanup(func() {
+		queue.GetEnqueuer = getEnqueuer
+	})
 	var json_data []byte
 	sharedCtx := stubs.WithAccountDaoOne(context.Background())
 	sharedCtx = identity.WithTenant(t, sharedCtx)
 	sharedCtx = Clientstubs.WithSourcesClient(sharedCtx)
 	sharedCtx = Clientstubs.WithImageBuilderClient(sharedCtx)
 	sharedCtx = stubs.WithPubkeyDao(sharedCtx)
+	sharedCtx = stubs.WithApprovalDao(sharedCtx)
+	sharedCtx = stubs.WithPolicyDao(sharedCtx)
 	pk := factories.NewPubkeyRSA()
 	err := stubs.AddPubkey(sharedCtx, pk)
 	require.NoError(t, err, "failed to generate pubkey")
@@ -1,16 +1,23 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/RHEnVision/provisioning-backend/internal/clients"
 	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/RHEnVision/provisioning-backend/internal/preload"
 	"github.com/go-chi/render"
 	"github.com/rs/zerolog"
 )
 
+var ErrInvalidAzureGeneration = errors.New("azure generation must be v1 or v2")
+
 type InstanceTypesForZoneFunc func(region, zone string, supported *bool) ([]*clients.InstanceType, error)
 
 func ListBuiltinInstanceTypes(typeFunc InstanceTypesForZoneFunc) func(w http.ResponseWriter, r *http.Request) {
@@ -28,6 +35,12 @@ func ListBuiltinInstanceTypes(typeFunc InstanceTypesForZoneFunc) func(w http.Res
 			return
 		}
 
+		filter, param, err := parseInstanceTypeFilter(r)
+		if err != nil {
+			renderError(w, r, payloads.NewInvalidRequestError(r.Context(), fmt.Sprintf("parameter '%s' could not be parsed", param), err))
+			return
+		}
+
 		start := time.Now()
 		instances, err := typeFunc(region, zone, supported)
 		logger := zerolog.Ctx(r.Context())
@@ -37,9 +50,159 @@ func ListBuiltinInstanceTypes(typeFunc InstanceTypesForZoneFunc) func(w http.Res
 			return
 		}
 
+		instances = filter.Filter(instances)
+		if sortBy := r.URL.Query().Get("sort"); sortBy != "" {
+			if err := clients.SortInstanceTypes(instances, sortBy); err != nil {
+				renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "parameter 'sort' could not be parsed", err))
+				return
+			}
+		}
+
 		if err := render.Render(w, r, payloads.NewListInstanceTypeResponse(instances)); err != nil {
 			renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render instance types list", err))
 			return
 		}
 	}
 }
+
+// parseInstanceTypeFilter reads filter query parameters, it returns name of the parameter which
+// could not be parsed along with the error.
+func parseInstanceTypeFilter(r *http.Request) (*clients.InstanceTypeFilter, string, error) {
+	query := r.URL.Query()
+	filter := clients.InstanceTypeFilter{
+		NamePrefix: query.Get("name_prefix"),
+		Family:     query.Get("family"),
+	}
+
+	intParams := []struct {
+		name  string
+		field **int64
+	}{
+		{"min_vcpus", &filter.MinVCPUs},
+		{"max_vcpus", &filter.MaxVCPUs},
+		{"min_cores", &filter.MinCores},
+		{"max_cores", &filter.MaxCores},
+		{"min_memory_mib", &filter.MinMemoryMiB},
+		{"max_memory_mib", &filter.MaxMemoryMiB},
+		{"min_storage_gb", &filter.MinStorageGB},
+	}
+	for _, param := range intParams {
+		value, err := ParseOptionalInt64(query.Get(param.name))
+		if err != nil {
+			return nil, param.name, err
+		}
+		*param.field = value
+	}
+
+	if arch := query.Get("architecture"); arch != "" {
+		architecture, err := clients.MapArchitectures(context.Background(), arch)
+		if err != nil {
+			return nil, "architecture", fmt.Errorf("unknown architecture: %w", err)
+		}
+		filter.Architecture = &architecture
+	}
+
+	if gen := strings.ToLower(query.Get("azure_generation")); gen != "" {
+		if gen != "v1" && gen != "v2" {
+			return nil, "azure_generation", fmt.Errorf("%w: %s", ErrInvalidAzureGeneration, gen)
+		}
+		filter.AzureGeneration = gen
+	}
+
+	return &filter, "", nil
+}
+
+const (
+	defaultRecommendLimit = 3
+	maxRecommendLimit     = 20
+)
+
+var (
+	ErrInvalidShape = errors.New("vcpus and memory_mib must be positive numbers")
+	ErrInvalidLimit = errors.New("limit must be between 1 and 20")
+)
+
+type instanceTypesForRegionFunc func(region string) ([]*clients.InstanceType, error)
+
+// RecommendInstanceTypes returns supported instance types of all providers closest to the
+// requested shape. Regions can be set per provider, all known types are ranked otherwise.
+func RecommendInstanceTypes(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	vcpus, err := ParseOptionalInt64(query.Get("vcpus"))
+	if err != nil {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "parameter 'vcpus' could not be parsed", err))
+		return
+	}
+	memory, err := ParseOptionalInt64(query.Get("memory_mib"))
+	if err != nil {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "parameter 'memory_mib' could not be parsed", err))
+		return
+	}
+	if vcpus == nil || memory == nil {
+		renderError(w, r, payloads.NewMissingRequestParameterError(r.Context(), "vcpus and memory_mib parameters are required"))
+		return
+	}
+	if *vcpus <= 0 || *memory <= 0 || *vcpus > math.MaxInt32 {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "invalid instance type shape", ErrInvalidShape))
+		return
+	}
+
+	shape := clients.InstanceTypeShape{
+		VCPUs:        int32(*vcpus),
+		MemoryMiB:    *memory,
+		Architecture: clients.ArchitectureTypeX86_64,
+	}
+	if arch := query.Get("arch"); arch != "" {
+		shape.Architecture, err = clients.MapArchitectures(r.Context(), arch)
+		if err != nil {
+			renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "parameter 'arch' could not be parsed", err))
+			return
+		}
+	}
+
+	limit := defaultRecommendLimit
+	l, err := ParseOptionalInt64(query.Get("limit"))
+	if err != nil {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "parameter 'limit' could not be parsed", err))
+		return
+	}
+	if l != nil {
+		if *l < 1 || *l > maxRecommendLimit {
+			renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "parameter 'limit' is out of range", ErrInvalidLimit))
+			return
+		}
+		limit = int(*l)
+	}
+
+	providers := []struct {
+		name     string
+		region   string
+		typeFunc instanceTypesForRegionFunc
+	}{
+		{"aws", query.Get("aws_region"), preload.EC2InstanceType.InstanceTypesForRegion},
+		{"azure", query.Get("azure_region"), preload.AzureInstanceType.InstanceTypesForRegion},
+		{"gcp", query.Get("gcp_region"), preload.GCPInstanceType.InstanceTypesForRegion},
+	}
+
+	result := &payloads.InstanceTypeRecommendationListResponse{
+		Data: make([]*payloads.InstanceTypeRecommendationResponse, 0, len(providers)),
+	}
+	for _, p := range providers {
+		region := strings.ToLower(p.region)
+		types, typeErr := p.typeFunc(region)
+		if typeErr != nil {
+			renderError(w, r, payloads.NewInvalidRequestError(r.Context(),
+				fmt.Sprintf("instance types not found for %s region '%s'", p.name, region), typeErr))
+			return
+		}
+
+		ranked := clients.RankInstanceTypes(types, shape, limit)
+		result.Data = append(result.Data, payloads.NewInstanceTypeRecommendationResponse(p.name, region, ranked))
+	}
+
+	if err := render.Render(w, r, result); err != nil {
+		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render instance type recommendations", err))
+		return
+	}
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stubInstanceTypesForZone(_, _ string, _ *bool) ([]*clients.InstanceType, error) {
+	return []*clients.InstanceType{
+		{Name: "t3.small", VCPUs: 2, MemoryMiB: 2048, Architecture: clients.ArchitectureTypeX86_64},
+		{Name: "t4g.large", VCPUs: 2, MemoryMiB: 8192, Architecture: clients.ArchitectureTypeArm64},
+		{Name: "c5.xlarge", VCPUs: 4, MemoryMiB: 8192, Architecture: clients.ArchitectureTypeX86_64},
+	}, nil
+}
+
+func listBuiltinTypes(t *testing.T, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), "GET", "/api/provisioning/instance_types/aws?"+query, nil)
+	require.NoError(t, err, "failed to create request")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ListBuiltinInstanceTypes(stubInstanceTypesForZone))
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestListBuiltinInstanceTypesFilter(t *testing.T) {
+	t.Run("filter and sort", func(t *testing.T) {
+		rr := listBuiltinTypes(t, "region=us-east-1&architecture=x86_64&min_memory_mib=2048&sort=-vcpus")
+		require.Equal(t, http.StatusOK, rr.Code, "Handler returned wrong status code")
+
+		var result payloads.InstanceTypeListResponse
+		err := json.NewDecoder(rr.Body).Decode(&result)
+		require.NoError(t, err, "failed to decode response body")
+
+		require.Len(t, result.Data, 2)
+		assert.Equal(t, clients.InstanceTypeName("c5.xlarge"), result.Data[0].Name)
+		assert.Equal(t, clients.InstanceTypeName("t3.small"), result.Data[1].Name)
+	})
+
+	t.Run("invalid number", func(t *testing.T) {
+		rr := listBuiltinTypes(t, "region=us-east-1&min_vcpus=many")
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Handler returned wrong status code")
+	})
+
+	t.Run("invalid sort", func(t *testing.T) {
+		rr := listBuiltinTypes(t, "region=us-east-1&sort=price")
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Handler returned wrong status code")
+	})
+}
+
+func recommendTypes(t *testing.T, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), "GET", "/api/provisioning/instance_types/recommend?"+query, nil)
+	require.NoError(t, err, "failed to create request")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(RecommendInstanceTypes)
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestRecommendInstanceTypes(t *testing.T) {
+	t.Run("all providers", func(t *testing.T) {
+		rr := recommendTypes(t, "vcpus=4&memory_mib=16384&arch=x86_64&aws_region=us-east-1&limit=5")
+		require.Equal(t, http.StatusOK, rr.Code, "Handler returned wrong status code")
+
+		var result payloads.InstanceTypeRecommendationListResponse
+		err := json.NewDecoder(rr.Body).Decode(&result)
+		require.NoError(t, err, "failed to decode response body")
+
+		require.Len(t, result.Data, 3)
+		for _, provider := range result.Data {
+			require.NotEmpty(t, provider.InstanceTypes, provider.Provider)
+			require.LessOrEqual(t, len(provider.InstanceTypes), 5)
+			// memory sizes slightly differ between providers
+			assert.Less(t, provider.InstanceTypes[0].Distance, 0.1, provider.Provider)
+			assert.Equal(t, int32(4), provider.InstanceTypes[0].InstanceType.VCPUs)
+			assert.Equal(t, clients.ArchitectureTypeX86_64, provider.InstanceTypes[0].InstanceType.Architecture)
+		}
+		assert.Equal(t, "us-east-1", result.Data[0].Region)
+	})
+
+	t.Run("missing shape", func(t *testing.T) {
+		rr := recommendTypes(t, "vcpus=4")
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Handler returned wrong status code")
+	})
+
+	t.Run("unknown region", func(t *testing.T) {
+		rr := recommendTypes(t, "vcpus=4&memory_mib=16384&gcp_region=mars")
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Handler returned wrong status code")
+	})
+}
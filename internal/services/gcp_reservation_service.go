@@ -18,33 +18,42 @@ import (
 	"github.com/RHEnVision/provisioning-backend/internal/jobs"
 	"github.com/RHEnVision/provisioning-backend/internal/models"
 	"github.com/RHEnVision/provisioning-backend/internal/payloads"
-	"github.com/RHEnVision/provisioning-backend/internal/queue"
 	"github.com/RHEnVision/provisioning-backend/pkg/worker"
 	"github.com/go-chi/render"
 )
 
-func CreateGCPReservation(w http.ResponseWriter, r *http.Request) {
-	logger := zerolog.Ctx(r.Context())
-
-	var accountId int64 = identity.AccountId(r.Context())
-	var id identity.Principal = identity.Identity(r.Context())
+// defaultGCPNamePattern is used when no name pattern was requested.
+const defaultGCPNamePattern = "inst-####"
 
+func CreateGCPReservation(w http.ResponseWriter, r *http.Request) {
 	payload := &payloads.GCPReservationRequest{}
 	if err := render.Bind(r, payload); err != nil {
 		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "GCP reservation", err))
 		return
 	}
 
+	createGCPReservation(w, r, payload, nil)
+}
+
+// createGCPReservation is shared by new and retried reservations, parent ID is nil for new ones.
+func createGCPReservation(w http.ResponseWriter, r *http.Request, payload *payloads.GCPReservationRequest, parentID *int64) {
+	logger := zerolog.Ctx(r.Context())
+
+	var accountId int64 = identity.AccountId(r.Context())
+	var id identity.Principal = identity.Identity(r.Context())
+
 	rDao := dao.GetReservationDao(r.Context())
 	pkDao := dao.GetPubkeyDao(r.Context())
 
-	// Check for preloaded region
+	// Check for preloaded zone or a zone enabled for the account
 	if !preload.GCPInstanceType.ValidateRegion(payload.Zone) {
-		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "Unsupported zone", ErrUnsupportedRegion))
-		return
+		if !sourceRegionEnabled(r.Context(), payload.SourceID, payload.Zone) {
+			renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "Unsupported zone", ErrUnsupportedRegion))
+			return
+		}
 	}
 
-	namePattern := "inst-####"
+	namePattern := defaultGCPNamePattern
 	// Verify name pattern is lower cased and add #####
 	if payload.NamePattern != "" {
 		ok := isValidNamePattern(payload.NamePattern)
@@ -56,6 +65,46 @@ func CreateGCPReservation(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Disks are only defined when launching from an image, launch templates define their own
+	rootVolume, volumes := payloads.NewVolumeDetails(payload.RootVolume, payload.Volumes)
+	if (rootVolume != nil || len(volumes) > 0) && payload.ImageID == "" {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "Volumes require an image", ErrVolumesWithoutImage))
+		return
+	}
+	if err := clients.GCPVolumeLimits.Apply(rootVolume, volumes); err != nil {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "Invalid volumes", err))
+		return
+	}
+
+	// Launch at a later time or repeatedly, the launch job is prepared now and enqueued by the scheduler
+	schedule, schErr := newReservationSchedule(payload.NotBefore, payload.Schedule)
+	if schErr != nil {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "Invalid schedule", schErr))
+		return
+	}
+
+	// Launches violating the launch policy of the account are rejected
+	tags := payloads.NewTagDetails(payload.Tags)
+	policyErr := checkLaunchPolicy(r.Context(), &models.PolicyLaunch{
+		Provider:     models.ProviderTypeGCP,
+		Region:       payload.Zone,
+		InstanceType: payload.MachineType,
+		ImageID:      payload.ImageID,
+		Amount:       payload.Amount,
+		Tags:         tags,
+	})
+	if policyErr != nil {
+		renderError(w, r, payloads.NewPolicyError(r.Context(), "check launch policy", policyErr))
+		return
+	}
+
+	// Launches matching approval rules of the account wait for approval
+	reason, err := approvalReason(r.Context(), payload.Amount, payload.MachineType)
+	if err != nil {
+		renderError(w, r, payloads.NewDAOError(r.Context(), "check approval rules", err))
+		return
+	}
+
 	resUUID := uuid.New().String()
 	detail := &models.GCPDetail{
 		NamePattern:      &namePattern,
@@ -65,6 +114,9 @@ func CreateGCPReservation(w http.ResponseWriter, r *http.Request) {
 		PowerOff:         payload.PowerOff,
 		UUID:             resUUID,
 		LaunchTemplateID: payload.LaunchTemplateID,
+		RootVolume:       rootVolume,
+		Volumes:          volumes,
+		Tags:             tags,
 	}
 	reservation := &models.GCPReservation{
 		PubkeyID: &payload.PubkeyID,
@@ -76,6 +128,7 @@ func CreateGCPReservation(w http.ResponseWriter, r *http.Request) {
 	reservation.AccountID = accountId
 	reservation.Status = "Created"
 	reservation.Provider = models.ProviderTypeGCP
+	reservation.ParentID = parentID
 	reservation.Steps = 2
 	reservation.StepTitles = jobs.LaunchInstanceGCPSteps
 
@@ -166,16 +219,34 @@ func CreateGCPReservation(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
-	err = queue.GetEnqueuer(r.Context()).Enqueue(r.Context(), &launchJob)
-	if err != nil {
-		renderError(w, r, payloads.NewEnqueueTaskError(r.Context(), "job enqueue error", err))
-		return
+	if reason != "" {
+		err = requestApproval(r.Context(), reservation.ID, &launchJob, schedule, reason)
+		if err != nil {
+			renderError(w, r, payloads.NewDAOError(r.Context(), "request reservation approval", err))
+			return
+		}
+		reservation.Status = pendingApprovalStatus
+		logger.Debug().Msgf("Reservation waits for approval: %s", reason)
+	} else if schedule != nil {
+		err = scheduleLaunch(r.Context(), reservation.ID, &launchJob, schedule)
+		if err != nil {
+			renderError(w, r, payloads.NewDAOError(r.Context(), "schedule reservation", err))
+			return
+		}
+		reservation.Status = scheduledStatus
+		logger.Debug().Msgf("Scheduled reservation launch at %s", schedule.NextRunAt)
+	} else {
+		err = jobs.EnqueueLaunch(r.Context(), &launchJob)
+		if err != nil {
+			renderError(w, r, payloads.NewEnqueueTaskError(r.Context(), "job enqueue error", err))
+			return
+		}
+		logger.Debug().Msgf("Enqueued reservation job %s", launchJob.ID)
 	}
-	logger.Debug().Msgf("Enqueued reservation job %s", launchJob.ID)
 
 	unused := make([]*models.ReservationInstance, 0, 0)
 	// Return response payload
-	if err := render.Render(w, r, payloads.NewGCPReservationResponse(reservation, unused)); err != nil {
+	if err := render.Render(w, r, payloads.NewGCPReservationResponse(reservation, unused, gcpPricePerHour(reservation))); err != nil {
 		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render reservation", err))
 		return
 	}
@@ -11,6 +11,8 @@ import (
 	Clientstubs "github.com/RHEnVision/provisioning-backend/internal/clients/stubs"
 	"github.com/RHEnVision/provisioning-backend/internal/dao/stubs"
 	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/queue"
+	"github.com/RHEnVision/provisioning-backend/internal/queue/stub"
 	"github.com/RHEnVision/provisioning-backend/internal/services"
 	"github.com/RHEnVision/provisioning-backend/internal/testing/factories"
 	"github.com/RHEnVision/provisioning-backend/internal/testing/identity"
@@ -20,12 +22,19 @@ import (
 )
 
 func TestCreateGCPReservationHandler(t *testing.T) {
+	getEnqueuer := queue.GetEnqueuer
+	queue.GetEnqueuer = stub.Enqueuer
+	t.Cleanup(func() {
+		queue.GetEnqueuer = getEnqueuer
+	})
 	var json_data []byte
 	ctx := stubs.WithAccountDaoOne(context.Background())
 	ctx = identity.WithTenant(t, ctx)
 	ctx = Clientstubs.WithSourcesClient(ctx)
 	ctx = Clientstubs.WithImageBuilderClient(ctx)
 	ctx = stubs.WithReservationDao(ctx)
+	ctx = stubs.WithApprovalDao(ctx)
+	ctx = stubs.WithPolicyDao(ctx)
 	ctx = stubs.WithPubkeyDao(ctx)
 	pk := factories.NewPubkeyRSA()
 	err := stubs.AddPubkey(ctx, pk)
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/RHEnVision/provisioning-backend/internal/queue"
+	"github.com/RHEnVision/provisioning-backend/pkg/worker"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// ListJobs returns queued and in-flight jobs of all accounts. Optional filters are type,
+// account_id and older_than (duration, for example 15m).
+func ListJobs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &worker.JobFilter{Type: worker.JobType(query.Get("type"))}
+
+	accountID, err := ParseOptionalInt64(query.Get("account_id"))
+	if err != nil {
+		renderError(w, r, payloads.NewURLParsingError(r.Context(), "unable to parse account_id", err))
+		return
+	}
+	if accountID != nil {
+		filter.AccountID = *accountID
+	}
+
+	if olderThan := query.Get("older_than"); olderThan != "" {
+		filter.OlderThan, err = time.ParseDuration(olderThan)
+		if err != nil {
+			renderError(w, r, payloads.NewURLParsingError(r.Context(), "unable to parse older_than", err))
+			return
+		}
+	}
+
+	infos, err := queue.GetWorker(r.Context()).ListJobs(r.Context(), filter)
+	if err != nil {
+		renderError(w, r, payloads.NewJobQueueError(r.Context(), "list jobs", err))
+		return
+	}
+
+	if err := render.Render(w, r, payloads.NewJobListResponse(infos)); err != nil {
+		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render jobs list", err))
+	}
+}
+
+func GetJob(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		renderError(w, r, payloads.NewURLParsingError(r.Context(), "unable to parse job id", err))
+		return
+	}
+
+	info, err := queue.GetWorker(r.Context()).GetJob(r.Context(), id)
+	if err != nil {
+		renderError(w, r, payloads.NewJobQueueError(r.Context(), "get job", err))
+		return
+	}
+
+	if err := render.Render(w, r, payloads.NewJobResponse(info)); err != nil {
+		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render job", err))
+	}
+}
+
+// DeleteJob removes a queued job or a record of an in-flight job, running jobs are not cancelled.
+func DeleteJob(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		renderError(w, r, payloads.NewURLParsingError(r.Context(), "unable to parse job id", err))
+		return
+	}
+
+	err = queue.GetWorker(r.Context()).DeleteJob(r.Context(), id)
+	if err != nil {
+		renderError(w, r, payloads.NewJobQueueError(r.Context(), "delete job", err))
+		return
+	}
+
+	zerolog.Ctx(r.Context()).Warn().Str("job_id", id.String()).Msg("Job deleted by operator")
+	writeNoContent(w, r)
+}
+
+// RequeueJob enqueues an in-flight job again, it is intended for jobs of killed workers.
+func RequeueJob(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		renderError(w, r, payloads.NewURLParsingError(r.Context(), "unable to parse job id", err))
+		return
+	}
+
+	err = queue.GetWorker(r.Context()).RequeueJob(r.Context(), id)
+	if err != nil {
+		renderError(w, r, payloads.NewJobQueueError(r.Context(), "requeue job", err))
+		return
+	}
+
+	zerolog.Ctx(r.Context()).Warn().Str("job_id", id.String()).Msg("Job requeued by operator")
+	writeNoContent(w, r)
+}
+
+// DrainJobs removes all queued jobs.
+func DrainJobs(w http.ResponseWriter, r *http.Request) {
+	drained, err := queue.GetWorker(r.Context()).DrainQueue(r.Context())
+	if err != nil {
+		renderError(w, r, payloads.NewJobQueueError(r.Context(), "drain queue", err))
+		return
+	}
+
+	if err := render.Render(w, r, payloads.NewJobDrainResponse(drained)); err != nil {
+		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render drained jobs", err))
+	}
+}
@@ -0,0 +1,135 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/jobs"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/RHEnVision/provisioning-backend/internal/queue"
+	"github.com/RHEnVision/provisioning-backend/internal/services"
+	"github.com/RHEnVision/provisioning-backend/pkg/worker"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func requestJob(t *testing.T, ctx context.Context, method, id string, handlerFunc http.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("ID", id)
+	req, err := http.NewRequestWithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx), method, "/internal/jobs/"+id, nil)
+	require.NoError(t, err, "failed to create request")
+
+	rr := httptest.NewRecorder()
+	handlerFunc.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestJobs(t *testing.T) {
+	ctx := context.Background()
+	wk := worker.NewMemoryClient()
+	origGetWorker := queue.GetWorker
+	queue.GetWorker = func(_ context.Context) worker.JobWorker {
+		return wk
+	}
+	defer func() {
+		queue.GetWorker = origGetWorker
+	}()
+
+	// the first job blocks the only memory dequeue goroutine, the second one stays queued
+	started := make(chan struct{})
+	release := make(chan struct{})
+	wk.RegisterHandler(jobs.TypeNoop, func(_ context.Context, _ *worker.Job) {
+		close(started)
+		<-release
+	}, jobs.NoopJobArgs{})
+	wk.DequeueLoop(ctx)
+	defer close(release)
+
+	inFlightJob := &worker.Job{ID: uuid.New(), Type: jobs.TypeNoop, AccountID: 1, Args: jobs.NoopJobArgs{}}
+	require.NoError(t, wk.Enqueue(ctx, inFlightJob))
+	<-started
+
+	queuedJob := &worker.Job{
+		ID:        uuid.New(),
+		Type:      jobs.TypeLaunchInstanceAws,
+		AccountID: 2,
+		Args: jobs.LaunchInstanceAWSTaskArgs{
+			ReservationID: 1,
+			ARN:           clients.NewAuthentication("arn:aws:iam::230214684733:role/Test", models.ProviderTypeAWS),
+		},
+	}
+	go func() {
+		_ = wk.Enqueue(ctx, queuedJob)
+	}()
+	require.Eventually(t, func() bool {
+		_, err := wk.GetJob(ctx, queuedJob.ID)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	listJobs := func(t *testing.T, query string) []*payloads.JobResponse {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, "GET", "/internal/jobs"+query, nil)
+		require.NoError(t, err, "failed to create request")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(services.ListJobs)
+		handler.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
+
+		var result payloads.JobListResponse
+		err = json.NewDecoder(rr.Body).Decode(&result)
+		require.NoError(t, err, "failed to decode response body")
+		return result.Data
+	}
+
+	t.Run("list", func(t *testing.T) {
+		result := listJobs(t, "")
+		require.Len(t, result, 2)
+		assert.Equal(t, inFlightJob.ID.String(), result[0].ID)
+		assert.Equal(t, "in_flight", result[0].State)
+		assert.NotNil(t, result[0].StartedAt)
+		assert.Equal(t, queuedJob.ID.String(), result[1].ID)
+		assert.Equal(t, "queued", result[1].State)
+	})
+
+	t.Run("list filtered", func(t *testing.T) {
+		assert.Len(t, listJobs(t, "?type=launch_instances_aws"), 1)
+		assert.Len(t, listJobs(t, "?account_id=1"), 1)
+		assert.Empty(t, listJobs(t, "?older_than=1h"))
+	})
+
+	t.Run("get redacted", func(t *testing.T) {
+		rr := requestJob(t, ctx, "GET", queuedJob.ID.String(), services.GetJob)
+		require.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
+		assert.NotContains(t, rr.Body.String(), "230214684733")
+		assert.Contains(t, rr.Body.String(), `"payload":"****"`)
+	})
+
+	t.Run("requeue queued", func(t *testing.T) {
+		rr := requestJob(t, ctx, "POST", queuedJob.ID.String(), services.RequeueJob)
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Wrong status code")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		rr := requestJob(t, ctx, "DELETE", queuedJob.ID.String(), services.DeleteJob)
+		require.Equal(t, http.StatusNoContent, rr.Code, "Wrong status code")
+
+		rr = requestJob(t, ctx, "GET", queuedJob.ID.String(), services.GetJob)
+		require.Equal(t, http.StatusNotFound, rr.Code, "Wrong status code")
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		rr := requestJob(t, ctx, "GET", "42", services.GetJob)
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Wrong status code")
+	})
+}
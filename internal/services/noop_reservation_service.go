@@ -10,7 +10,6 @@ import (
 	"github.com/RHEnVision/provisioning-backend/internal/jobs"
 	"github.com/RHEnVision/provisioning-backend/internal/models"
 	"github.com/RHEnVision/provisioning-backend/internal/payloads"
-	"github.com/RHEnVision/provisioning-backend/internal/queue"
 	"github.com/RHEnVision/provisioning-backend/pkg/worker"
 	"github.com/go-chi/render"
 	"github.com/rs/zerolog"
@@ -51,7 +50,7 @@ func CreateNoopReservation(w http.ResponseWriter, r *http.Request) {
 			ReservationID: reservation.ID,
 		},
 	}
-	err = queue.GetEnqueuer(r.Context()).Enqueue(r.Context(), &pj)
+	err = jobs.EnqueueLaunch(r.Context(), &pj)
 	if err != nil {
 		renderError(w, r, payloads.NewEnqueueTaskError(r.Context(), "job enqueue error", err))
 		return
@@ -39,3 +39,15 @@ func ParseBool(str string) (*bool, error) {
 	}
 	return &b, nil
 }
+
+// ParseOptionalInt64 converts string into int64. Returns nil when string is empty.
+func ParseOptionalInt64(str string) (*int64, error) {
+	if str == "" {
+		return nil, nil
+	}
+	i, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing '%s' to int64: %w", str, err)
+	}
+	return &i, nil
+}
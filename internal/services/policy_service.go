@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/RHEnVision/provisioning-backend/internal/usrerr"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"gopkg.in/yaml.v3"
+)
+
+// checkLaunchPolicy returns an error with HTTP code 403 naming the violated rule when the launch
+// does not satisfy the launch policy of the account. Accounts without policy are not restricted.
+func checkLaunchPolicy(ctx context.Context, launch *models.PolicyLaunch) error {
+	policy, err := dao.GetPolicyDao(ctx).Get(ctx)
+	if errors.Is(err, dao.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to get launch policy: %w", err)
+	}
+
+	var violation *models.PolicyViolation
+	if err := policy.Document.Check(launch); errors.As(err, &violation) {
+		return fmt.Errorf("launch policy: %w",
+			usrerr.New(http.StatusForbidden, violation.Error(), "launch policy violation, "+violation.Error()))
+	}
+	return nil
+}
+
+// decodePolicy decodes JSON or YAML policy document. Unknown fields are rejected so a typo in a
+// rule name does not silently disable the rule.
+func decodePolicy(r *http.Request, payload *payloads.PolicyRequest) error {
+	contentType := r.Header.Get("Content-Type")
+	if strings.Contains(contentType, "yaml") {
+		decoder := yaml.NewDecoder(r.Body)
+		decoder.KnownFields(true)
+		if err := decoder.Decode(payload); err != nil {
+			return fmt.Errorf("unable to decode YAML policy: %w", err)
+		}
+		return nil
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(payload); err != nil {
+		return fmt.Errorf("unable to decode JSON policy: %w", err)
+	}
+	return nil
+}
+
+func GetPolicy(w http.ResponseWriter, r *http.Request) {
+	policy, err := dao.GetPolicyDao(r.Context()).Get(r.Context())
+	if err != nil {
+		renderNotFoundOrDAOError(w, r, err, "get launch policy")
+		return
+	}
+
+	if err := render.Render(w, r, payloads.NewPolicyResponse(policy)); err != nil {
+		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render launch policy", err))
+	}
+}
+
+// PutPolicy creates or replaces the launch policy of the account.
+func PutPolicy(w http.ResponseWriter, r *http.Request) {
+	payload := &payloads.PolicyRequest{}
+	if err := decodePolicy(r, payload); err != nil {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "launch policy", err))
+		return
+	}
+
+	policy := payload.NewModel()
+	err := dao.GetPolicyDao(r.Context()).Upsert(r.Context(), policy)
+	var validationError validator.ValidationErrors
+	if errors.As(err, &validationError) {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "validation error", err))
+		return
+	} else if err != nil {
+		renderError(w, r, payloads.NewDAOError(r.Context(), "update launch policy", err))
+		return
+	}
+
+	if err := render.Render(w, r, payloads.NewPolicyResponse(policy)); err != nil {
+		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render launch policy", err))
+	}
+}
+
+func DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	err := dao.GetPolicyDao(r.Context()).Delete(r.Context())
+	if errors.Is(err, dao.ErrAffectedMismatch) {
+		renderError(w, r, payloads.NewNotFoundError(r.Context(), "launch policy", err))
+		return
+	} else if err != nil {
+		renderError(w, r, payloads.NewDAOError(r.Context(), "delete launch policy", err))
+		return
+	}
+
+	writeNoContent(w, r)
+}
@@ -0,0 +1,135 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/clients/http/rbac"
+	Clientstubs "github.com/RHEnVision/provisioning-backend/internal/clients/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/dao/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/RHEnVision/provisioning-backend/internal/services"
+	"github.com/RHEnVision/provisioning-backend/internal/testing/factories"
+	tidentity "github.com/RHEnVision/provisioning-backend/internal/testing/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func requestPolicy(t *testing.T, ctx context.Context, method, contentType, body string, handlerFunc http.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, method, "/api/provisioning/v1/policy", bytes.NewBufferString(body))
+	require.NoError(t, err, "failed to create request")
+	if contentType != "" {
+		req.Header.Add("Content-Type", contentType)
+	}
+
+	rr := httptest.NewRecorder()
+	handlerFunc.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestPolicy(t *testing.T) {
+	ctx := stubs.WithAccountDaoOne(context.Background())
+	ctx = tidentity.WithTenant(t, ctx)
+	ctx = stubs.WithPolicyDao(ctx)
+	ctx = rbac.WithAcl(ctx, clients.AllPermissionsRbacAcl)
+
+	t.Run("get missing policy", func(t *testing.T) {
+		rr := requestPolicy(t, ctx, "GET", "", "", services.GetPolicy)
+		require.Equal(t, http.StatusNotFound, rr.Code, "Wrong status code")
+	})
+
+	t.Run("put JSON policy", func(t *testing.T) {
+		body := `{"allowed_regions": {"aws": ["us-east-*"]}, "max_amount": 5}`
+		rr := requestPolicy(t, ctx, "PUT", "application/json", body, services.PutPolicy)
+		require.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
+
+		var result payloads.PolicyResponse
+		err := json.NewDecoder(rr.Body).Decode(&result)
+		require.NoError(t, err, "failed to decode response body")
+		assert.Equal(t, []string{"us-east-*"}, result.AllowedRegions.AWS)
+		assert.Equal(t, int64(5), result.MaxAmount)
+	})
+
+	t.Run("put YAML policy", func(t *testing.T) {
+		body := "allowed_instance_types:\n  - t3.*\nrequired_tags:\n  - cost-center\n"
+		rr := requestPolicy(t, ctx, "PUT", "application/yaml", body, services.PutPolicy)
+		require.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
+
+		rr = requestPolicy(t, ctx, "GET", "", "", services.GetPolicy)
+		require.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
+
+		var result payloads.PolicyResponse
+		err := json.NewDecoder(rr.Body).Decode(&result)
+		require.NoError(t, err, "failed to decode response body")
+		assert.Equal(t, []string{"t3.*"}, result.AllowedInstanceTypes)
+		assert.Equal(t, []string{"cost-center"}, result.RequiredTags)
+		assert.Empty(t, result.AllowedRegions.AWS, "policy should be replaced")
+	})
+
+	t.Run("put unknown rule", func(t *testing.T) {
+		rr := requestPolicy(t, ctx, "PUT", "application/json", `{"allowed_region": {"aws": ["us-east-1"]}}`, services.PutPolicy)
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Wrong status code")
+	})
+
+	t.Run("put invalid pattern", func(t *testing.T) {
+		rr := requestPolicy(t, ctx, "PUT", "application/json", `{"allowed_images": ["ami-["]}`, services.PutPolicy)
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Wrong status code")
+	})
+
+	t.Run("delete policy", func(t *testing.T) {
+		rr := requestPolicy(t, ctx, "DELETE", "", "", services.DeletePolicy)
+		require.Equal(t, http.StatusNoContent, rr.Code, "Wrong status code")
+
+		rr = requestPolicy(t, ctx, "DELETE", "", "", services.DeletePolicy)
+		require.Equal(t, http.StatusNotFound, rr.Code, "Wrong status code")
+	})
+}
+
+func TestCreateAWSReservationPolicyViolation(t *testing.T) {
+	ctx := stubs.WithAccountDaoOne(context.Background())
+	ctx = tidentity.WithTenant(t, ctx)
+	ctx = Clientstubs.WithSourcesClient(ctx)
+	ctx = Clientstubs.WithImageBuilderClient(ctx)
+	ctx = stubs.WithReservationDao(ctx)
+	ctx = stubs.WithApprovalDao(ctx)
+	ctx = stubs.WithPolicyDao(ctx)
+	ctx = stubs.WithPubkeyDao(ctx)
+	pk := factories.NewPubkeyRSA()
+	err := stubs.AddPubkey(ctx, pk)
+	require.NoError(t, err, "failed to generate pubkey")
+	err = stubs.AddPolicy(ctx, &models.PolicyDocument{
+		AllowedRegions: models.PolicyRegions{AWS: []string{"eu-*"}},
+	})
+	require.NoError(t, err, "failed to add launch policy")
+
+	values := map[string]interface{}{
+		"source_id":     "1",
+		"image_id":      "2bc640f6-927a-404a-9594-5b2da7e06608",
+		"amount":        1,
+		"instance_type": "t1.micro",
+		"region":        "us-east-1",
+		"pubkey_id":     pk.ID,
+	}
+	jsonData, err := json.Marshal(values)
+	require.NoError(t, err, "unable to marshal values to json")
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "/api/provisioning/reservations/aws", bytes.NewBuffer(jsonData))
+	require.NoError(t, err, "failed to create request")
+	req.Header.Add("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(services.CreateAWSReservation)
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusForbidden, rr.Code, "Handler returned wrong status code")
+	assert.Contains(t, rr.Body.String(), "allowed_regions")
+	assert.Equal(t, 0, stubs.AWSReservationStubCount(ctx), "Reservation should not be created")
+}
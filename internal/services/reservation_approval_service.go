@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/identity"
+	"github.com/RHEnVision/provisioning-backend/internal/jobs"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/notifications"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/RHEnVision/provisioning-backend/pkg/worker"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog"
+)
+
+// Status of reservations waiting for approval.
+const pendingApprovalStatus = "pending_approval"
+
+// approvalReason returns names of approval rules matching the reservation, or empty string when
+// the reservation does not need approval.
+func approvalReason(ctx context.Context, amount int64, instanceType string) (string, error) {
+	rules, err := dao.GetApprovalDao(ctx).ListRules(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to list approval rules: %w", err)
+	}
+
+	var names []string
+	for _, rule := range rules {
+		if rule.Matches(amount, instanceType) {
+			names = append(names, rule.Name)
+		}
+	}
+	return strings.Join(names, ", "), nil
+}
+
+// requestApproval stores the launch job with a new approval instead of enqueueing it, the job is
+// enqueued or scheduled when the reservation is approved.
+func requestApproval(ctx context.Context, reservationID int64, job *worker.Job, schedule *models.ReservationSchedule, reason string) error {
+	// authentication and image are resolved when the reservation is approved
+	args, err := json.Marshal(jobs.StorableArgs(job.Args))
+	if err != nil {
+		return fmt.Errorf("unable to encode job arguments: %w", err)
+	}
+
+	approval := &models.ReservationApproval{
+		ReservationID: reservationID,
+		Reason:        reason,
+		JobType:       job.Type.String(),
+		JobIdentity:   job.Identity,
+		JobEdgeID:     job.EdgeID,
+		JobArgs:       args,
+	}
+	if schedule != nil {
+		approval.ScheduleCron = schedule.Cron
+		approval.ScheduleNextRunAt = sql.NullTime{Time: schedule.NextRunAt, Valid: true}
+	}
+	err = dao.GetApprovalDao(ctx).Create(ctx, approval)
+	if err != nil {
+		return fmt.Errorf("unable to create approval: %w", err)
+	}
+
+	err = dao.GetReservationDao(ctx).UpdateStatus(ctx, reservationID, pendingApprovalStatus, 0)
+	if err != nil {
+		return fmt.Errorf("unable to update reservation status: %w", err)
+	}
+
+	notifications.GetNotificationClient(ctx).ApprovalRequested(ctx, reservationID, reason)
+	return nil
+}
+
+// pendingApproval loads a pending approval and prepares its decision by the current user, it
+// renders an error and returns nil when the approval does not exist, it was already decided or
+// the current user requested the launch.
+func pendingApproval(w http.ResponseWriter, r *http.Request) (*models.ReservationApproval, *models.ReservationApproval) {
+	id, err := ParseInt64(r, "ID")
+	if err != nil {
+		renderError(w, r, payloads.NewURLParsingError(r.Context(), "unable to parse ID parameter", err))
+		return nil, nil
+	}
+
+	payload := &payloads.ApprovalDecisionRequest{}
+	if r.ContentLength != 0 {
+		if err := render.Bind(r, payload); err != nil {
+			renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "approval decision", err))
+			return nil, nil
+		}
+	}
+
+	approval, err := dao.GetApprovalDao(r.Context()).GetByReservationId(r.Context(), id)
+	if err != nil {
+		renderNotFoundOrDAOError(w, r, err, "get reservation approval")
+		return nil, nil
+	}
+	if !approval.Pending() {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "reservation was already approved or rejected", ErrApprovalDecided))
+		return nil, nil
+	}
+
+	decidedBy := identity.Identity(r.Context()).Identity.User.Username
+	if decidedBy != "" && decidedBy == approval.JobIdentity.Identity.User.Username {
+		renderError(w, r, payloads.NewResponseError(r.Context(), http.StatusForbidden, "Access denied: reservation cannot be decided by its requester", ErrSelfApproval))
+		return nil, nil
+	}
+
+	decision := &models.ReservationApproval{
+		ReservationID: id,
+		DecidedBy:     decidedBy,
+		Comment:       payload.Comment,
+	}
+	return approval, decision
+}
+
+// renderDecideError renders an error of a decision which could not be stored.
+func renderDecideError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, dao.ErrAffectedMismatch) {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "reservation was already approved or rejected", ErrApprovalDecided))
+	} else {
+		renderError(w, r, payloads.NewDAOError(r.Context(), "decide reservation approval", err))
+	}
+}
+
+// applyDecision copies the stored decision to the approval.
+func applyDecision(approval, decision *models.ReservationApproval) {
+	approval.Approved = decision.Approved
+	approval.DecidedBy = decision.DecidedBy
+	approval.DecidedAt = decision.DecidedAt
+	approval.Comment = decision.Comment
+}
+
+// reopenApproval reverts the decision of an approved reservation whose launch could not be
+// scheduled or enqueued, the reservation waits for approval again.
+func reopenApproval(ctx context.Context, reservationID int64, scheduled bool) {
+	logger := zerolog.Ctx(ctx)
+
+	if scheduled {
+		err := dao.GetScheduleDao(ctx).DeleteByReservationId(ctx, reservationID)
+		if err != nil && !errors.Is(err, dao.ErrAffectedMismatch) {
+			logger.Error().Err(err).Msg("Unable to delete schedule of approved reservation")
+		}
+	}
+
+	err := dao.GetApprovalDao(ctx).Reopen(ctx, reservationID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to reopen approval of reservation")
+		return
+	}
+
+	err = dao.GetReservationDao(ctx).UpdateStatus(ctx, reservationID, pendingApprovalStatus, 0)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to update reservation status")
+	}
+}
+
+func GetReservationApproval(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseInt64(r, "ID")
+	if err != nil {
+		renderError(w, r, payloads.NewURLParsingError(r.Context(), "unable to parse ID parameter", err))
+		return
+	}
+
+	approval, err := dao.GetApprovalDao(r.Context()).GetByReservationId(r.Context(), id)
+	if err != nil {
+		renderNotFoundOrDAOError(w, r, err, "get reservation approval")
+		return
+	}
+
+	if err := render.Render(w, r, payloads.NewApprovalResponse(approval)); err != nil {
+		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render reservation approval", err))
+	}
+}
+
+// ApproveReservation enqueues the launch job of a reservation waiting for approval, or schedules
+// it when the reservation was created with a schedule. The job is prepared before the decision is
+// stored and the decision is reverted when the job cannot be enqueued or scheduled.
+func ApproveReservation(w http.ResponseWriter, r *http.Request) {
+	logger := zerolog.Ctx(r.Context())
+
+	approval, decision := pendingApproval(w, r)
+	if approval == nil {
+		return
+	}
+	decision.Approved = sql.NullBool{Bool: true, Valid: true}
+
+	jobType := worker.JobType(approval.JobType)
+	args, err := jobs.DecodeLaunchArgs(jobType, approval.JobArgs)
+	if err != nil {
+		renderError(w, r, payloads.NewEnqueueTaskError(r.Context(), "unable to decode launch job", err))
+		return
+	}
+
+	// requested launch time could have passed while waiting for approval
+	var schedule *models.ReservationSchedule
+	if approval.ScheduleNextRunAt.Valid {
+		schedule, err = newReservationSchedule(&approval.ScheduleNextRunAt.Time, approval.ScheduleCron)
+		if err != nil {
+			renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "Invalid schedule", err))
+			return
+		}
+	} else {
+		// authentication and image are not stored with the approval
+		args, err = jobs.ResolveLaunchArgs(r.Context(), args)
+		if err != nil {
+			renderError(w, r, payloads.NewEnqueueTaskError(r.Context(), "unable to prepare launch job", err))
+			return
+		}
+	}
+
+	launchJob := worker.Job{
+		Type:      jobType,
+		Identity:  approval.JobIdentity,
+		EdgeID:    approval.JobEdgeID,
+		AccountID: approval.AccountID,
+		Args:      args,
+	}
+
+	err = dao.GetApprovalDao(r.Context()).Decide(r.Context(), decision)
+	if err != nil {
+		renderDecideError(w, r, err)
+		return
+	}
+
+	if schedule != nil {
+		err = scheduleLaunch(r.Context(), approval.ReservationID, &launchJob, schedule)
+		if err != nil {
+			reopenApproval(r.Context(), approval.ReservationID, true)
+			renderError(w, r, payloads.NewDAOError(r.Context(), "schedule reservation", err))
+			return
+		}
+		logger.Debug().Msgf("Scheduled approved reservation launch at %s", schedule.NextRunAt)
+	} else {
+		err = jobs.EnqueueLaunch(r.Context(), &launchJob)
+		if err != nil {
+			reopenApproval(r.Context(), approval.ReservationID, false)
+			renderError(w, r, payloads.NewEnqueueTaskError(r.Context(), "job enqueue error", err))
+			return
+		}
+		logger.Debug().Msgf("Enqueued approved reservation job %s", launchJob.ID)
+	}
+
+	applyDecision(approval, decision)
+	if err := render.Render(w, r, payloads.NewApprovalResponse(approval)); err != nil {
+		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render reservation approval", err))
+	}
+}
+
+// RejectReservation finishes a reservation waiting for approval with an error, it is never launched.
+func RejectReservation(w http.ResponseWriter, r *http.Request) {
+	approval, decision := pendingApproval(w, r)
+	if approval == nil {
+		return
+	}
+
+	message := "Launch was rejected"
+	if decision.Comment != "" {
+		message = fmt.Sprintf("%s: %s", message, decision.Comment)
+	}
+
+	err := dao.GetApprovalDao(r.Context()).Reject(r.Context(), decision, message)
+	if err != nil {
+		renderDecideError(w, r, err)
+		return
+	}
+
+	applyDecision(approval, decision)
+	if err := render.Render(w, r, payloads.NewApprovalResponse(approval)); err != nil {
+		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render reservation approval", err))
+	}
+}
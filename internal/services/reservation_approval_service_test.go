@@ -0,0 +1,321 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/clients/http/rbac"
+	Clientstubs "github.com/RHEnVision/provisioning-backend/internal/clients/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/dao/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/identity"
+	"github.com/RHEnVision/provisioning-backend/internal/jobs"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/RHEnVision/provisioning-backend/internal/queue"
+	"github.com/RHEnVision/provisioning-backend/internal/queue/stub"
+	"github.com/RHEnVision/provisioning-backend/internal/services"
+	"github.com/RHEnVision/provisioning-backend/internal/testing/factories"
+	tidentity "github.com/RHEnVision/provisioning-backend/internal/testing/identity"
+	"github.com/RHEnVision/provisioning-backend/pkg/worker"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errQueueDown = errors.New("queue is down")
+
+func requestReservationDecision(t *testing.T, ctx context.Context, id int64, body string, handlerFunc http.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rctx := chi.NewRouteContext()
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+	rctx.URLParams.Add("ID", strconv.FormatInt(id, 10))
+	req, err := http.NewRequestWithContext(ctx, "POST", "/api/provisioning/v1/reservations/"+strconv.FormatInt(id, 10)+"/approve", bytes.NewBufferString(body))
+	require.NoError(t, err, "failed to create request")
+	if body != "" {
+		req.Header.Add("Content-Type", "application/json")
+	}
+
+	rr := httptest.NewRecorder()
+	handlerFunc.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestCreateAWSReservationWithApproval(t *testing.T) {
+	ctx := stubs.WithAccountDaoOne(context.Background())
+	ctx = tidentity.WithTenant(t, ctx)
+	ctx = Clientstubs.WithSourcesClient(ctx)
+	ctx = Clientstubs.WithImageBuilderClient(ctx)
+	ctx = stubs.WithReservationDao(ctx)
+	ctx = stubs.WithApprovalDao(ctx)
+	ctx = stubs.WithPolicyDao(ctx)
+	ctx = stubs.WithPubkeyDao(ctx)
+	pk := factories.NewPubkeyRSA()
+	err := stubs.AddPubkey(ctx, pk)
+	require.NoError(t, err, "failed to generate pubkey")
+	err = stubs.AddApprovalRule(ctx, &models.ApprovalRule{Name: "large", MaxAmount: 10})
+	require.NoError(t, err, "failed to add approval rule")
+
+	values := map[string]interface{}{
+		"source_id":     "1",
+		"image_id":      "2bc640f6-927a-404a-9594-5b2da7e06608",
+		"amount":        20,
+		"instance_type": "t1.micro",
+		"region":        "us-east-1",
+		"pubkey_id":     pk.ID,
+	}
+	jsonData, err := json.Marshal(values)
+	require.NoError(t, err, "unable to marshal values to json")
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "/api/provisioning/reservations/aws", bytes.NewBuffer(jsonData))
+	require.NoError(t, err, "failed to create request")
+	req.Header.Add("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(services.CreateAWSReservation)
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code, "Handler returned wrong status code")
+	assert.Equal(t, 1, stubs.ApprovalStubCount(ctx), "Approval has not been created through DAO")
+
+	reservation, err := dao.GetReservationDao(ctx).GetById(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "pending_approval", reservation.Status)
+
+	approval, err := dao.GetApprovalDao(ctx).GetByReservationId(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "large", approval.Reason)
+	assert.Equal(t, jobs.TypeLaunchInstanceAws.String(), approval.JobType)
+	assert.NotContains(t, string(approval.JobArgs), "arn:aws", "authentication must not be stored")
+}
+
+// the package imports the job queue which replaces the stubbed enqueuer
+type failingEnqueuer struct{}
+
+func (failingEnqueuer) Enqueue(_ context.Context, _ *worker.Job) error {
+	return errQueueDown
+}
+
+func (failingEnqueuer) EnqueueAt(_ context.Context, _ *worker.Job, _ time.Time) error {
+	return errQueueDown
+}
+
+func (failingEnqueuer) EnqueueAfter(_ context.Context, _ *worker.Job, _ time.Duration) error {
+	return errQueueDown
+}
+
+func TestReservationDecision(t *testing.T) {
+	ctx := stubs.WithAccountDaoOne(context.Background())
+	ctx = tidentity.WithTenant(t, ctx)
+	ctx = stubs.WithReservationDao(ctx)
+	ctx = stubs.WithApprovalDao(ctx)
+	ctx = stubs.WithScheduleDao(ctx)
+	ctx = Clientstubs.WithSourcesClient(ctx)
+	ctx = stub.WithEnqueuer(ctx)
+	ctx = rbac.WithAcl(ctx, clients.AllPermissionsRbacAcl)
+	getEnqueuer := queue.GetEnqueuer
+	queue.GetEnqueuer = stub.Enqueuer
+	t.Cleanup(func() {
+		queue.GetEnqueuer = getEnqueuer
+	})
+
+	addApproval := func(requester string, nextRunAt *time.Time) int64 {
+		reservation := &models.AWSReservation{
+			SourceID: "1",
+			ImageID:  "ami-random",
+			Detail: &models.AWSDetail{
+				Region:       "us-east-1",
+				InstanceType: "t1.micro",
+				Amount:       20,
+			},
+		}
+		reservation.AccountID = identity.AccountId(ctx)
+		reservation.Provider = models.ProviderTypeAWS
+		err := stubs.AddAWSReservation(ctx, reservation)
+		require.NoError(t, err, "failed to add stubbed reservation")
+
+		args, err := json.Marshal(jobs.LaunchInstanceAWSTaskArgs{ReservationID: reservation.ID, Region: "us-east-1"})
+		require.NoError(t, err)
+		approval := &models.ReservationApproval{
+			ReservationID: reservation.ID,
+			Reason:        "large",
+			JobType:       jobs.TypeLaunchInstanceAws.String(),
+			JobArgs:       args,
+		}
+		approval.JobIdentity.Identity.User.Username = requester
+		if nextRunAt != nil {
+			approval.ScheduleNextRunAt = sql.NullTime{Time: *nextRunAt, Valid: true}
+		}
+		err = dao.GetApprovalDao(ctx).Create(ctx, approval)
+		require.NoError(t, err, "failed to add stubbed approval")
+		return reservation.ID
+	}
+
+	var rejectedID int64
+	t.Run("reject", func(t *testing.T) {
+		rejectedID = addApproval("", nil)
+		rr := requestReservationDecision(t, ctx, rejectedID, `{"comment": "too expensive"}`, services.RejectReservation)
+		require.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
+
+		var result payloads.ApprovalResponse
+		err := json.NewDecoder(rr.Body).Decode(&result)
+		require.NoError(t, err, "failed to decode response body")
+		assert.Equal(t, "rejected", result.Status)
+		assert.Equal(t, "too expensive", result.Comment)
+		assert.NotNil(t, result.DecidedAt)
+	})
+
+	t.Run("decided twice", func(t *testing.T) {
+		rr := requestReservationDecision(t, ctx, rejectedID, "", services.ApproveReservation)
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Wrong status code")
+		assert.Contains(t, rr.Body.String(), "already approved or rejected")
+	})
+
+	t.Run("approve", func(t *testing.T) {
+		id := addApproval("", nil)
+		rr := requestReservationDecision(t, ctx, id, "", services.ApproveReservation)
+		require.Equal(t, http.StatusOK, rr.Code, "Wrong status code: %s", rr.Body.String())
+		assert.Contains(t, rr.Body.String(), `"status":"approved"`)
+
+		// authentication and image are resolved on approval
+		enqueued := stub.EnqueuedJobs(ctx)
+		require.Len(t, enqueued, 1)
+		args, ok := enqueued[0].Args.(jobs.LaunchInstanceAWSTaskArgs)
+		require.True(t, ok, "unexpected job args type %T", enqueued[0].Args)
+		assert.Equal(t, id, args.ReservationID)
+		require.NotNil(t, args.ARN)
+		assert.Equal(t, "arn:aws:iam::230214684733:role/Test", args.ARN.Payload)
+		assert.Equal(t, "ami-random", args.AMI)
+	})
+
+	t.Run("approve scheduled", func(t *testing.T) {
+		nextRunAt := time.Now().Add(time.Hour)
+		id := addApproval("", &nextRunAt)
+		rr := requestReservationDecision(t, ctx, id, "", services.ApproveReservation)
+		require.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
+		assert.Contains(t, rr.Body.String(), `"status":"approved"`)
+
+		schedule, err := dao.GetScheduleDao(ctx).GetByReservationId(ctx, id)
+		require.NoError(t, err, "schedule has not been created through DAO")
+		assert.True(t, nextRunAt.UTC().Equal(schedule.NextRunAt))
+	})
+
+	t.Run("decided by requester", func(t *testing.T) {
+		id := addApproval("jdoe", nil)
+		principal := identity.Identity(ctx)
+		principal.Identity.User.Username = "jdoe"
+		userCtx := identity.WithIdentity(ctx, principal)
+
+		rr := requestReservationDecision(t, userCtx, id, "", services.ApproveReservation)
+		require.Equal(t, http.StatusForbidden, rr.Code, "Wrong status code")
+
+		approval, err := dao.GetApprovalDao(ctx).GetByReservationId(ctx, id)
+		require.NoError(t, err)
+		assert.True(t, approval.Pending())
+	})
+
+	t.Run("failed enqueue", func(t *testing.T) {
+		id := addApproval("", nil)
+		queue.GetEnqueuer = func(_ context.Context) worker.JobEnqueuer {
+			return failingEnqueuer{}
+		}
+		t.Cleanup(func() {
+			queue.GetEnqueuer = stub.Enqueuer
+		})
+
+		rr := requestReservationDecision(t, ctx, id, "", services.ApproveReservation)
+		require.Equal(t, http.StatusInternalServerError, rr.Code, "Wrong status code")
+
+		// the reservation can be approved again
+		approval, err := dao.GetApprovalDao(ctx).GetByReservationId(ctx, id)
+		require.NoError(t, err)
+		assert.True(t, approval.Pending())
+		assert.Empty(t, approval.DecidedBy)
+	})
+
+	t.Run("missing approval", func(t *testing.T) {
+		rr := requestReservationDecision(t, ctx, 999, "", services.ApproveReservation)
+		require.Equal(t, http.StatusNotFound, rr.Code, "Wrong status code")
+	})
+}
+
+func TestApprovalRules(t *testing.T) {
+	ctx := stubs.WithAccountDaoOne(context.Background())
+	ctx = tidentity.WithTenant(t, ctx)
+	ctx = stubs.WithApprovalDao(ctx)
+	ctx = rbac.WithAcl(ctx, clients.AllPermissionsRbacAcl)
+
+	createRule := func(t *testing.T, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, "POST", "/api/provisioning/v1/approval_rules", bytes.NewBufferString(body))
+		require.NoError(t, err, "failed to create request")
+		req.Header.Add("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(services.CreateApprovalRule)
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("create", func(t *testing.T) {
+		rr := createRule(t, `{"name": "GPU", "instance_types": ["p3.*", "g5.*"]}`)
+		require.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
+		assert.Contains(t, rr.Body.String(), `"name":"GPU"`)
+	})
+
+	t.Run("create without conditions", func(t *testing.T) {
+		rr := createRule(t, `{"name": "empty"}`)
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Wrong status code")
+	})
+
+	t.Run("create with invalid pattern", func(t *testing.T) {
+		rr := createRule(t, `{"name": "invalid", "instance_types": ["p3.["]}`)
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Wrong status code")
+	})
+
+	t.Run("list", func(t *testing.T) {
+		req, err := http.NewRequestWithContext(ctx, "GET", "/api/provisioning/v1/approval_rules", nil)
+		require.NoError(t, err, "failed to create request")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(services.ListApprovalRules)
+		handler.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
+
+		var result payloads.ApprovalRuleListResponse
+		err = json.NewDecoder(rr.Body).Decode(&result)
+		require.NoError(t, err, "failed to decode response body")
+		require.Len(t, result.Data, 1)
+		assert.Equal(t, []string{"p3.*", "g5.*"}, result.Data[0].InstanceTypes)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		deleteRule := func(id int64) *httptest.ResponseRecorder {
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("ID", strconv.FormatInt(id, 10))
+			req, err := http.NewRequestWithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx), "DELETE", "/api/provisioning/v1/approval_rules/"+strconv.FormatInt(id, 10), nil)
+			require.NoError(t, err, "failed to create request")
+
+			rr := httptest.NewRecorder()
+			handler := http.HandlerFunc(services.DeleteApprovalRule)
+			handler.ServeHTTP(rr, req)
+			return rr
+		}
+
+		rr := deleteRule(1)
+		require.Equal(t, http.StatusNoContent, rr.Code, "Wrong status code")
+
+		rr = deleteRule(1)
+		require.Equal(t, http.StatusNotFound, rr.Code, "Wrong status code")
+	})
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/preload"
+)
+
+// awsPricePerHour returns price of reserved instance type from preloaded data, nil is returned
+// when the price or region is unknown.
+func awsPricePerHour(reservation *models.AWSReservation) *float64 {
+	return preload.EC2InstanceType.PricePerHour(reservation.Detail.Region, clients.InstanceTypeName(reservation.Detail.InstanceType))
+}
+
+// azurePricePerHour returns price of reserved instance size from preloaded data, nil is returned
+// when the price or location is unknown (e.g. location induced from resource group).
+func azurePricePerHour(reservation *models.AzureReservation) *float64 {
+	location := strings.ToLower(reservation.Detail.Location)
+	return preload.AzureInstanceType.PricePerHour(location, clients.InstanceTypeName(reservation.Detail.InstanceSize))
+}
+
+// gcpPricePerHour returns price of reserved machine type from preloaded data, nil is returned
+// when the price or zone is unknown.
+func gcpPricePerHour(reservation *models.GCPReservation) *float64 {
+	return preload.GCPInstanceType.PricePerHour(reservation.Detail.Zone, clients.InstanceTypeName(reservation.Detail.MachineType))
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/go-chi/render"
+)
+
+// ListReservationJobs returns all recorded attempts of background jobs of a reservation, so it is
+// possible to find out which attempt failed and on which worker.
+func ListReservationJobs(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseInt64(r, "ID")
+	if err != nil {
+		renderError(w, r, payloads.NewURLParsingError(r.Context(), "unable to parse ID parameter", err))
+		return
+	}
+
+	// reservation of a different account is reported as not found
+	_, err = dao.GetReservationDao(r.Context()).GetById(r.Context(), id)
+	if err != nil {
+		renderNotFoundOrDAOError(w, r, err, "get reservation")
+		return
+	}
+
+	executions, err := dao.GetJobExecutionDao(r.Context()).ListByReservation(r.Context(), id)
+	if err != nil {
+		renderError(w, r, payloads.NewDAOError(r.Context(), "list reservation jobs", err))
+		return
+	}
+
+	if err := render.Render(w, r, payloads.NewJobExecutionListResponse(executions)); err != nil {
+		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render reservation jobs", err))
+	}
+}
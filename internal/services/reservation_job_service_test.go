@@ -0,0 +1,82 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/clients/http/rbac"
+	"github.com/RHEnVision/provisioning-backend/internal/dao/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/RHEnVision/provisioning-backend/internal/services"
+	tidentity "github.com/RHEnVision/provisioning-backend/internal/testing/identity"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func requestReservationJobs(t *testing.T, ctx context.Context, id int64) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rctx := chi.NewRouteContext()
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+	rctx.URLParams.Add("ID", strconv.FormatInt(id, 10))
+	req, err := http.NewRequestWithContext(ctx, "GET", "/api/provisioning/v1/reservations/"+strconv.FormatInt(id, 10)+"/jobs", nil)
+	require.NoError(t, err, "failed to create request")
+
+	rr := httptest.NewRecorder()
+	services.ListReservationJobs(rr, req)
+	return rr
+}
+
+func TestListReservationJobs(t *testing.T) {
+	ctx := stubs.WithAccountDaoOne(context.Background())
+	ctx = tidentity.WithTenant(t, ctx)
+	ctx = stubs.WithReservationDao(ctx)
+	ctx = stubs.WithJobExecutionDao(ctx)
+	ctx = rbac.WithAcl(ctx, clients.AllPermissionsRbacAcl)
+
+	reservation := &models.AWSReservation{Detail: &models.AWSDetail{}}
+	reservation.AccountID = 1
+	err := stubs.AddAWSReservation(ctx, reservation)
+	require.NoError(t, err, "failed to add stubbed reservation")
+
+	jobID := uuid.New()
+	for _, result := range []string{models.JobResultInterrupted, models.JobResultFailure} {
+		err = stubs.AddJobExecution(ctx, &models.JobExecution{
+			JobID:         jobID,
+			JobType:       "launch_instances_aws",
+			ReservationID: sql.NullInt64{Int64: reservation.ID, Valid: true},
+			Hostname:      "worker-1",
+			Result:        result,
+			Error:         "timeout",
+		})
+		require.NoError(t, err, "failed to add stubbed job execution")
+	}
+
+	t.Run("list jobs", func(t *testing.T) {
+		rr := requestReservationJobs(t, ctx, reservation.ID)
+		require.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
+
+		var result payloads.JobExecutionListResponse
+		err := json.NewDecoder(rr.Body).Decode(&result)
+		require.NoError(t, err, "failed to decode response body")
+		require.Len(t, result.Data, 2)
+		assert.Equal(t, jobID.String(), result.Data[1].JobID)
+		assert.EqualValues(t, 2, result.Data[1].Attempt)
+		assert.Equal(t, "worker-1", result.Data[1].Hostname)
+		assert.Equal(t, models.JobResultFailure, result.Data[1].Result)
+	})
+
+	t.Run("missing reservation", func(t *testing.T) {
+		rr := requestReservationJobs(t, ctx, reservation.ID+1)
+		require.Equal(t, http.StatusNotFound, rr.Code, "Wrong status code")
+	})
+}
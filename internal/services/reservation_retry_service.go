@@ -0,0 +1,187 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/RHEnVision/provisioning-backend/internal/config"
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/go-chi/render"
+)
+
+// RetryReservation creates a new reservation with parameters of a failed reservation and enqueues
+// its launch job. Region, zone and instance type can be overridden, the new reservation is linked
+// to the failed one via parent ID.
+func RetryReservation(w http.ResponseWriter, r *http.Request) {
+	if !config.LaunchEnabled(r.Context()) {
+		writeUnauthorized(w, r)
+		return
+	}
+
+	id, err := ParseInt64(r, "ID")
+	if err != nil {
+		renderError(w, r, payloads.NewURLParsingError(r.Context(), "unable to parse ID parameter", err))
+		return
+	}
+
+	// Overrides are optional, empty body is accepted
+	retry := &payloads.ReservationRetryRequest{}
+	if r.ContentLength != 0 {
+		if err := render.Bind(r, retry); err != nil && !errors.Is(err, io.EOF) {
+			renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "reservation retry", err))
+			return
+		}
+	}
+
+	rDao := dao.GetReservationDao(r.Context())
+	reservation, err := rDao.GetById(r.Context(), id)
+	if err != nil {
+		renderNotFoundOrDAOError(w, r, err, "get reservation to retry")
+		return
+	}
+
+	if CheckPermissionAndRender(w, r, "write", "reservation", reservation.Provider.String()) != nil {
+		return
+	}
+
+	if !reservation.Success.Valid || reservation.Success.Bool {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "reservation has not failed", ErrReservationNotFailed))
+		return
+	}
+
+	switch reservation.Provider {
+	case models.ProviderTypeAWS:
+		retryAWSReservation(w, r, id, retry)
+	case models.ProviderTypeAzure:
+		if config.FeatureEnabled(r.Context(), "azure") {
+			retryAzureReservation(w, r, id, retry)
+		} else {
+			renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "azure reservation is not implemented", ErrProviderTypeNotImplemented))
+		}
+	case models.ProviderTypeGCP:
+		retryGCPReservation(w, r, id, retry)
+	case models.ProviderTypeUnknown, models.ProviderTypeNoop:
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "provider does not support retries", ErrProviderTypeNotImplemented))
+	default:
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "provider does not support retries", ErrProviderTypeNotImplemented))
+	}
+}
+
+func retryAWSReservation(w http.ResponseWriter, r *http.Request, id int64, retry *payloads.ReservationRetryRequest) {
+	reservation, err := dao.GetReservationDao(r.Context()).GetAWSById(r.Context(), id)
+	if err != nil {
+		renderNotFoundOrDAOError(w, r, err, fmt.Sprintf("get AWS reservation with id %d", id))
+		return
+	}
+	if reservation.PubkeyID == nil {
+		renderError(w, r, payloads.NewNotFoundError(r.Context(), "pubkey of the reservation was deleted", ErrPubkeyNotFound))
+		return
+	}
+
+	rootVolume, volumes := payloads.NewVolumeRequests(reservation.Detail.RootVolume, reservation.Detail.Volumes)
+	payload := &payloads.AWSReservationRequest{
+		PubkeyID:         *reservation.PubkeyID,
+		SourceID:         reservation.SourceID,
+		Region:           reservation.Detail.Region,
+		Name:             strings.TrimPrefix(reservation.Detail.Name, config.Application.InstancePrefix),
+		LaunchTemplateID: reservation.Detail.LaunchTemplateID,
+		InstanceType:     reservation.Detail.InstanceType,
+		Amount:           reservation.Detail.Amount,
+		ImageID:          reservation.ImageID,
+		PowerOff:         reservation.Detail.PowerOff,
+		RootVolume:       rootVolume,
+		Volumes:          volumes,
+		Tags:             payloads.NewTags(reservation.Detail.Tags),
+	}
+	if retry.Region != "" {
+		payload.Region = retry.Region
+	}
+	if retry.InstanceType != "" {
+		payload.InstanceType = retry.InstanceType
+	}
+
+	createAWSReservation(w, r, payload, &reservation.ID)
+}
+
+func retryAzureReservation(w http.ResponseWriter, r *http.Request, id int64, retry *payloads.ReservationRetryRequest) {
+	reservation, err := dao.GetReservationDao(r.Context()).GetAzureById(r.Context(), id)
+	if err != nil {
+		renderNotFoundOrDAOError(w, r, err, fmt.Sprintf("get Azure reservation with id %d", id))
+		return
+	}
+	if reservation.PubkeyID == nil {
+		renderError(w, r, payloads.NewNotFoundError(r.Context(), "pubkey of the reservation was deleted", ErrPubkeyNotFound))
+		return
+	}
+
+	rootVolume, volumes := payloads.NewVolumeRequests(reservation.Detail.RootVolume, reservation.Detail.Volumes)
+	payload := &payloads.AzureReservationRequest{
+		PubkeyID:      *reservation.PubkeyID,
+		SourceID:      reservation.SourceID,
+		ImageID:       reservation.ImageID,
+		ResourceGroup: reservation.Detail.ResourceGroup,
+		Location:      reservation.Detail.Location,
+		InstanceSize:  reservation.Detail.InstanceSize,
+		Amount:        reservation.Detail.Amount,
+		Name:          strings.TrimPrefix(reservation.Detail.Name, config.Application.InstancePrefix),
+		PowerOff:      reservation.Detail.PowerOff,
+		RootVolume:    rootVolume,
+		Volumes:       volumes,
+		Tags:          payloads.NewTags(reservation.Detail.Tags),
+	}
+	if retry.Location != "" {
+		payload.Location = retry.Location
+	}
+	if retry.InstanceSize != "" {
+		payload.InstanceSize = retry.InstanceSize
+	}
+
+	createAzureReservation(w, r, payload, &reservation.ID)
+}
+
+func retryGCPReservation(w http.ResponseWriter, r *http.Request, id int64, retry *payloads.ReservationRetryRequest) {
+	reservation, err := dao.GetReservationDao(r.Context()).GetGCPById(r.Context(), id)
+	if err != nil {
+		renderNotFoundOrDAOError(w, r, err, fmt.Sprintf("get GCP reservation with id %d", id))
+		return
+	}
+	if reservation.PubkeyID == nil {
+		renderError(w, r, payloads.NewNotFoundError(r.Context(), "pubkey of the reservation was deleted", ErrPubkeyNotFound))
+		return
+	}
+
+	// Stored pattern has the numeric suffix appended, the default pattern is not passed at all
+	var namePattern string
+	if reservation.Detail.NamePattern != nil && *reservation.Detail.NamePattern != defaultGCPNamePattern {
+		namePattern = strings.TrimSuffix(*reservation.Detail.NamePattern, "-#####")
+	}
+
+	rootVolume, volumes := payloads.NewVolumeRequests(reservation.Detail.RootVolume, reservation.Detail.Volumes)
+	payload := &payloads.GCPReservationRequest{
+		PubkeyID:         *reservation.PubkeyID,
+		SourceID:         reservation.SourceID,
+		LaunchTemplateID: reservation.Detail.LaunchTemplateID,
+		NamePattern:      namePattern,
+		Zone:             reservation.Detail.Zone,
+		MachineType:      reservation.Detail.MachineType,
+		Amount:           reservation.Detail.Amount,
+		ImageID:          reservation.ImageID,
+		PowerOff:         reservation.Detail.PowerOff,
+		RootVolume:       rootVolume,
+		Volumes:          volumes,
+		Tags:             payloads.NewTags(reservation.Detail.Tags),
+	}
+	if retry.Zone != "" {
+		payload.Zone = retry.Zone
+	}
+	if retry.MachineType != "" {
+		payload.MachineType = retry.MachineType
+	}
+
+	createGCPReservation(w, r, payload, &reservation.ID)
+}
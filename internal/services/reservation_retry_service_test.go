@@ -0,0 +1,143 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/clients/http/rbac"
+	clientStubs "github.com/RHEnVision/provisioning-backend/internal/clients/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/dao/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/identity"
+	"github.com/RHEnVision/provisioning-backend/internal/jobs"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/RHEnVision/provisioning-backend/internal/queue"
+	"github.com/RHEnVision/provisioning-backend/internal/queue/stub"
+	"github.com/RHEnVision/provisioning-backend/internal/services"
+	"github.com/RHEnVision/provisioning-backend/internal/testing/factories"
+	tidentity "github.com/RHEnVision/provisioning-backend/internal/testing/identity"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func requestReservationRetry(t *testing.T, ctx context.Context, id int64, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rctx := chi.NewRouteContext()
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+	rctx.URLParams.Add("ID", strconv.FormatInt(id, 10))
+	req, err := http.NewRequestWithContext(ctx, "POST", "/api/provisioning/v1/reservations/"+strconv.FormatInt(id, 10)+"/retry", strings.NewReader(body))
+	require.NoError(t, err, "failed to create request")
+	req.Header.Add("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(services.RetryReservation)
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestRetryReservation(t *testing.T) {
+	ctx := stubs.WithAccountDaoOne(context.Background())
+	ctx = tidentity.WithTenant(t, ctx)
+	ctx = stubs.WithPubkeyDao(ctx)
+	ctx = stubs.WithReservationDao(ctx)
+	ctx = stubs.WithApprovalDao(ctx)
+	ctx = stubs.WithPolicyDao(ctx)
+	ctx = clientStubs.WithSourcesClient(ctx)
+	ctx = stub.WithEnqueuer(ctx)
+	ctx = rbac.WithAcl(ctx, clients.AllPermissionsRbacAcl)
+	getEnqueuer := queue.GetEnqueuer
+	queue.GetEnqueuer = stub.Enqueuer
+	t.Cleanup(func() {
+		queue.GetEnqueuer = getEnqueuer
+	})
+	pk := factories.NewPubkeyRSA()
+	err := stubs.AddPubkey(ctx, pk)
+	require.NoError(t, err, "failed to add stubbed key")
+
+	addReservation := func(pubkeyID *int64, success sql.NullBool) int64 {
+		reservation := &models.AWSReservation{
+			PubkeyID: pubkeyID,
+			SourceID: "1",
+			ImageID:  "ami-random",
+			Detail: &models.AWSDetail{
+				Region:       "us-east-1",
+				InstanceType: "t1.micro",
+				Amount:       2,
+				Name:         "retried",
+				PowerOff:     true,
+				Tags:         map[string]string{"team": "provisioning"},
+			},
+		}
+		reservation.AccountID = identity.AccountId(ctx)
+		reservation.Provider = models.ProviderTypeAWS
+		reservation.Success = success
+		err := stubs.AddAWSReservation(ctx, reservation)
+		require.NoError(t, err, "failed to create stub reservation")
+		return reservation.ID
+	}
+
+	t.Run("pending reservation", func(t *testing.T) {
+		id := addReservation(&pk.ID, sql.NullBool{})
+		rr := requestReservationRetry(t, ctx, id, "")
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Wrong status code")
+		require.Contains(t, rr.Body.String(), "only failed reservations can be retried")
+	})
+
+	t.Run("successful reservation", func(t *testing.T) {
+		id := addReservation(&pk.ID, sql.NullBool{Bool: true, Valid: true})
+		rr := requestReservationRetry(t, ctx, id, "")
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Wrong status code")
+	})
+
+	t.Run("deleted pubkey", func(t *testing.T) {
+		id := addReservation(nil, sql.NullBool{Bool: false, Valid: true})
+		rr := requestReservationRetry(t, ctx, id, "")
+		require.Equal(t, http.StatusNotFound, rr.Code, "Wrong status code")
+	})
+
+	t.Run("failed reservation with overrides", func(t *testing.T) {
+		id := addReservation(&pk.ID, sql.NullBool{Bool: false, Valid: true})
+		rr := requestReservationRetry(t, ctx, id, `{"region": "us-east-2", "instance_type": "t2.micro"}`)
+		require.Equal(t, http.StatusOK, rr.Code, "Wrong status code: %s", rr.Body.String())
+
+		var response payloads.AWSReservationResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&response), "failed to decode response")
+		retried, err := dao.GetReservationDao(ctx).GetAWSById(ctx, response.ID)
+		require.NoError(t, err, "failed to get retried reservation")
+
+		require.NotNil(t, retried.ParentID)
+		require.Equal(t, id, *retried.ParentID)
+		require.Equal(t, "ami-random", retried.ImageID)
+		require.Equal(t, "us-east-2", retried.Detail.Region)
+		require.Equal(t, "t2.micro", retried.Detail.InstanceType)
+		require.Equal(t, int32(2), retried.Detail.Amount)
+		require.Equal(t, "retried", retried.Detail.Name)
+		require.True(t, retried.Detail.PowerOff)
+		require.Equal(t, map[string]string{"team": "provisioning"}, retried.Detail.Tags)
+
+		enqueued := stub.EnqueuedJobs(ctx)
+		require.Len(t, enqueued, 1)
+		require.Equal(t, jobs.TypeLaunchInstanceAws, enqueued[0].Type)
+		args, ok := enqueued[0].Args.(jobs.LaunchInstanceAWSTaskArgs)
+		require.True(t, ok, "unexpected job args type %T", enqueued[0].Args)
+		require.Equal(t, retried.ID, args.ReservationID)
+		require.Equal(t, "us-east-2", args.Region)
+		require.Equal(t, "ami-random", args.AMI)
+		require.Equal(t, pk.ID, args.PubkeyID)
+		require.Equal(t, "t2.micro", args.Detail.InstanceType)
+	})
+
+	t.Run("unknown reservation", func(t *testing.T) {
+		rr := requestReservationRetry(t, ctx, 999, "")
+		require.Equal(t, http.StatusNotFound, rr.Code, "Wrong status code")
+	})
+}
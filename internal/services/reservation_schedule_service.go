@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/cron"
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/jobs"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/RHEnVision/provisioning-backend/pkg/worker"
+	"github.com/go-chi/render"
+)
+
+// Status of reservations waiting for the scheduler.
+const scheduledStatus = "Scheduled"
+
+// newReservationSchedule returns a schedule for reservations launched at a later time or repeatedly,
+// or nil when the reservation is launched immediately.
+func newReservationSchedule(notBefore *time.Time, expression string) (*models.ReservationSchedule, error) {
+	now := time.Now().UTC()
+	start := now
+	if notBefore != nil && notBefore.After(now) {
+		start = notBefore.UTC()
+	}
+
+	if expression == "" {
+		if start.Equal(now) {
+			return nil, nil
+		}
+		return &models.ReservationSchedule{NextRunAt: start}, nil
+	}
+
+	schedule, err := cron.Parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("schedule: %w", err)
+	}
+
+	// the start minute itself is a valid launch time
+	next := schedule.Next(start.Add(-time.Nanosecond))
+	if next.IsZero() {
+		return nil, fmt.Errorf("schedule %q: %w", expression, ErrScheduleNeverRuns)
+	}
+	return &models.ReservationSchedule{Cron: expression, NextRunAt: next}, nil
+}
+
+// scheduleLaunch stores the launch job with the schedule instead of enqueueing it, the scheduler
+// resolves authentication and image and enqueues it once the schedule is due.
+func scheduleLaunch(ctx context.Context, reservationID int64, job *worker.Job, schedule *models.ReservationSchedule) error {
+	args, err := json.Marshal(jobs.StorableArgs(job.Args))
+	if err != nil {
+		return fmt.Errorf("unable to encode job arguments: %w", err)
+	}
+
+	schedule.ReservationID = reservationID
+	schedule.JobType = job.Type.String()
+	schedule.JobIdentity = job.Identity
+	schedule.JobEdgeID = job.EdgeID
+	schedule.JobArgs = args
+	err = dao.GetScheduleDao(ctx).Create(ctx, schedule)
+	if err != nil {
+		return fmt.Errorf("unable to create schedule: %w", err)
+	}
+
+	err = dao.GetReservationDao(ctx).UpdateStatus(ctx, reservationID, scheduledStatus, 0)
+	if err != nil {
+		return fmt.Errorf("unable to update reservation status: %w", err)
+	}
+	return nil
+}
+
+func GetReservationSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseInt64(r, "ID")
+	if err != nil {
+		renderError(w, r, payloads.NewURLParsingError(r.Context(), "unable to parse ID parameter", err))
+		return
+	}
+
+	schedule, err := dao.GetScheduleDao(r.Context()).GetByReservationId(r.Context(), id)
+	if err != nil {
+		renderNotFoundOrDAOError(w, r, err, "get reservation schedule")
+		return
+	}
+
+	if err := render.Render(w, r, payloads.NewScheduleResponse(schedule)); err != nil {
+		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render reservation schedule", err))
+	}
+}
+
+// DeleteReservationSchedule cancels all future launches of a reservation, reservations which were
+// already launched are not affected.
+func DeleteReservationSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseInt64(r, "ID")
+	if err != nil {
+		renderError(w, r, payloads.NewURLParsingError(r.Context(), "unable to parse ID parameter", err))
+		return
+	}
+
+	sDao := dao.GetScheduleDao(r.Context())
+	_, err = sDao.GetByReservationId(r.Context(), id)
+	if err != nil {
+		renderNotFoundOrDAOError(w, r, err, "get reservation schedule")
+		return
+	}
+
+	err = sDao.DeleteByReservationId(r.Context(), id)
+	if err != nil {
+		renderError(w, r, payloads.NewDAOError(r.Context(), "delete reservation schedule", err))
+		return
+	}
+
+	// scheduled reservation (or template of recurring launches) itself was never launched
+	rDao := dao.GetReservationDao(r.Context())
+	err = rDao.UpdateStatus(r.Context(), id, "Cancelled", 0)
+	if err != nil {
+		renderError(w, r, payloads.NewDAOError(r.Context(), "update reservation status", err))
+		return
+	}
+	err = rDao.FinishWithError(r.Context(), id, "Schedule was cancelled")
+	if err != nil {
+		renderError(w, r, payloads.NewDAOError(r.Context(), "finish reservation", err))
+		return
+	}
+
+	writeNoContent(w, r)
+}
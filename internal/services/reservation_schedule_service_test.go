@@ -0,0 +1,158 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/clients/http/rbac"
+	Clientstubs "github.com/RHEnVision/provisioning-backend/internal/clients/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/dao"
+	"github.com/RHEnVision/provisioning-backend/internal/dao/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/RHEnVision/provisioning-backend/internal/services"
+	"github.com/RHEnVision/provisioning-backend/internal/testing/factories"
+	tidentity "github.com/RHEnVision/provisioning-backend/internal/testing/identity"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateScheduledAWSReservation(t *testing.T) {
+	ctx := stubs.WithAccountDaoOne(context.Background())
+	ctx = tidentity.WithTenant(t, ctx)
+	ctx = Clientstubs.WithSourcesClient(ctx)
+	ctx = Clientstubs.WithImageBuilderClient(ctx)
+	ctx = stubs.WithReservationDao(ctx)
+	ctx = stubs.WithApprovalDao(ctx)
+	ctx = stubs.WithPolicyDao(ctx)
+	ctx = stubs.WithPubkeyDao(ctx)
+	ctx = stubs.WithScheduleDao(ctx)
+	pk := factories.NewPubkeyRSA()
+	err := stubs.AddPubkey(ctx, pk)
+	require.NoError(t, err, "failed to generate pubkey")
+
+	createReservation := func(t *testing.T, schedule map[string]interface{}) *httptest.ResponseRecorder {
+		t.Helper()
+		values := map[string]interface{}{
+			"source_id":     "1",
+			"image_id":      "2bc640f6-927a-404a-9594-5b2da7e06608",
+			"amount":        1,
+			"instance_type": "t1.micro",
+			"region":        "us-east-1",
+			"pubkey_id":     pk.ID,
+		}
+		for k, v := range schedule {
+			values[k] = v
+		}
+		jsonData, err := json.Marshal(values)
+		require.NoError(t, err, "unable to marshal values to json")
+
+		req, err := http.NewRequestWithContext(ctx, "POST", "/api/provisioning/reservations/aws", bytes.NewBuffer(jsonData))
+		require.NoError(t, err, "failed to create request")
+		req.Header.Add("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(services.CreateAWSReservation)
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("recurring reservation", func(t *testing.T) {
+		rr := createReservation(t, map[string]interface{}{"schedule": "0 2 * * *"})
+		require.Equal(t, http.StatusOK, rr.Code, "Handler returned wrong status code")
+
+		assert.Equal(t, 1, stubs.AWSReservationStubCount(ctx), "Reservation has not been created through DAO")
+		assert.Equal(t, 1, stubs.ScheduleStubCount(ctx), "Schedule has not been created through DAO")
+
+		// authentication and image are resolved when the schedule is due
+		schedule, err := dao.GetScheduleDao(ctx).GetByReservationId(ctx, 1)
+		require.NoError(t, err)
+		assert.NotContains(t, string(schedule.JobArgs), "arn:aws")
+		assert.NotContains(t, string(schedule.JobArgs), "ami-")
+	})
+
+	t.Run("delayed reservation", func(t *testing.T) {
+		notBefore := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		rr := createReservation(t, map[string]interface{}{"not_before": notBefore})
+		require.Equal(t, http.StatusOK, rr.Code, "Handler returned wrong status code")
+
+		assert.Equal(t, 2, stubs.ScheduleStubCount(ctx), "Schedule has not been created through DAO")
+	})
+
+	t.Run("invalid schedule", func(t *testing.T) {
+		rr := createReservation(t, map[string]interface{}{"schedule": "0 25 * * *"})
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Handler returned wrong status code")
+		assert.Contains(t, rr.Body.String(), "Invalid schedule")
+	})
+
+	t.Run("schedule which never runs", func(t *testing.T) {
+		rr := createReservation(t, map[string]interface{}{"schedule": "0 0 30 2 *"})
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Handler returned wrong status code")
+		assert.Equal(t, 2, stubs.ScheduleStubCount(ctx), "Schedule should not be created")
+	})
+}
+
+func requestReservationSchedule(t *testing.T, ctx context.Context, method string, id int64, handlerFunc http.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rctx := chi.NewRouteContext()
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+	rctx.URLParams.Add("ID", strconv.FormatInt(id, 10))
+	req, err := http.NewRequestWithContext(ctx, method, "/api/provisioning/v1/reservations/"+strconv.FormatInt(id, 10)+"/schedule", nil)
+	require.NoError(t, err, "failed to create request")
+
+	rr := httptest.NewRecorder()
+	handlerFunc.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestReservationSchedule(t *testing.T) {
+	ctx := stubs.WithAccountDaoOne(context.Background())
+	ctx = tidentity.WithTenant(t, ctx)
+	ctx = stubs.WithReservationDao(ctx)
+	ctx = stubs.WithScheduleDao(ctx)
+	ctx = rbac.WithAcl(ctx, clients.AllPermissionsRbacAcl)
+
+	nextRunAt := time.Date(2030, 1, 1, 2, 0, 0, 0, time.UTC)
+	err := stubs.AddSchedule(ctx, &models.ReservationSchedule{
+		ReservationID: 42,
+		Cron:          "0 2 * * *",
+		NextRunAt:     nextRunAt,
+	})
+	require.NoError(t, err, "failed to add stubbed schedule")
+
+	t.Run("get schedule", func(t *testing.T) {
+		rr := requestReservationSchedule(t, ctx, "GET", 42, services.GetReservationSchedule)
+		require.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
+
+		var result payloads.ScheduleResponse
+		err := json.NewDecoder(rr.Body).Decode(&result)
+		require.NoError(t, err, "failed to decode response body")
+		assert.Equal(t, int64(42), result.ReservationID)
+		assert.Equal(t, "0 2 * * *", result.Cron)
+		assert.True(t, nextRunAt.Equal(result.NextRunAt))
+		assert.Nil(t, result.LastRunAt)
+	})
+
+	t.Run("get missing schedule", func(t *testing.T) {
+		rr := requestReservationSchedule(t, ctx, "GET", 43, services.GetReservationSchedule)
+		require.Equal(t, http.StatusNotFound, rr.Code, "Wrong status code")
+	})
+
+	t.Run("delete schedule", func(t *testing.T) {
+		rr := requestReservationSchedule(t, ctx, "DELETE", 42, services.DeleteReservationSchedule)
+		require.Equal(t, http.StatusNoContent, rr.Code, "Wrong status code")
+		assert.Equal(t, 0, stubs.ScheduleStubCount(ctx))
+
+		rr = requestReservationSchedule(t, ctx, "DELETE", 42, services.DeleteReservationSchedule)
+		require.Equal(t, http.StatusNotFound, rr.Code, "Wrong status code")
+	})
+}
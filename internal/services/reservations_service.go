@@ -24,6 +24,12 @@ var (
 	ErrUnsupportedRegion          = errors.New("unknown region/location/zone")
 	ErrInvalidNamePattern         = errors.New("name pattern is not RFC-1035 compatible")
 	ErrPubkeyNotFound             = errors.New("no pubkey found")
+	ErrVolumesWithoutImage        = errors.New("root volume and volumes require an image")
+	ErrReservationNotFailed       = errors.New("only failed reservations can be retried")
+	ErrScheduleNeverRuns          = errors.New("schedule has no launch time in the next five years")
+	ErrApprovalRuleEmpty          = errors.New("approval rule needs max amount or instance types")
+	ErrApprovalDecided            = errors.New("approval was already decided")
+	ErrSelfApproval               = errors.New("approval cannot be decided by its requester")
 )
 
 // CreateReservation dispatches requests to type provider specific handlers
@@ -133,7 +139,7 @@ func GetReservationDetail(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if err := render.Render(w, r, payloads.NewAWSReservationResponse(reservationAws, instances)); err != nil {
+		if err := render.Render(w, r, payloads.NewAWSReservationResponse(reservationAws, instances, awsPricePerHour(reservationAws))); err != nil {
 			renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render reservation", err))
 		}
 	case models.ProviderTypeAzure:
@@ -151,7 +157,7 @@ func GetReservationDetail(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if err := render.Render(w, r, payloads.NewAzureReservationResponse(reservationAzure, instances)); err != nil {
+		if err := render.Render(w, r, payloads.NewAzureReservationResponse(reservationAzure, instances, azurePricePerHour(reservationAzure))); err != nil {
 			renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render reservation", err))
 		}
 	case models.ProviderTypeGCP:
@@ -169,7 +175,7 @@ func GetReservationDetail(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if err := render.Render(w, r, payloads.NewGCPReservationResponse(reservationGCP, instances)); err != nil {
+		if err := render.Render(w, r, payloads.NewGCPReservationResponse(reservationGCP, instances, gcpPricePerHour(reservationGCP))); err != nil {
 			renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render reservation", err))
 		}
 	default:
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/config"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads/validation"
+	"github.com/RHEnVision/provisioning-backend/internal/preload"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"golang.org/x/sync/errgroup"
+)
+
+// ListSourceImages merges Image Builder images with images available in the account of a source.
+func ListSourceImages(w http.ResponseWriter, r *http.Request) {
+	sourceId := chi.URLParam(r, "ID")
+	if err := validation.DigitsOnly(sourceId); err != nil {
+		renderError(w, r, payloads.NewURLParsingError(r.Context(), "id parameter invalid", err))
+		return
+	}
+
+	sourcesClient, err := clients.GetSourcesClient(r.Context())
+	if err != nil {
+		renderError(w, r, payloads.NewClientError(r.Context(), err))
+		return
+	}
+
+	authentication, err := sourcesClient.GetAuthentication(r.Context(), sourceId)
+	if err != nil {
+		renderError(w, r, payloads.NewClientError(r.Context(), err))
+		return
+	}
+
+	var instanceType *clients.InstanceType
+	if typeName := r.URL.Query().Get("instance_type"); typeName != "" {
+		instanceType = findInstanceType(authentication.ProviderType, clients.InstanceTypeName(typeName))
+		if instanceType == nil {
+			renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "unknown instance type", ErrUnknownInstanceTypeName))
+			return
+		}
+	}
+
+	// AWS images are regional, other providers list global images
+	region := r.URL.Query().Get("region")
+	if region == "" {
+		region = config.AWS.DefaultRegion
+	} else if authentication.ProviderType == models.ProviderTypeAWS && !preload.EC2InstanceType.ValidateRegion(region) {
+		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "Unsupported region", ErrUnsupportedRegion))
+		return
+	}
+
+	images, err := listSourceImages(r.Context(), authentication, region)
+	if err != nil {
+		renderError(w, r, payloads.NewClientError(r.Context(), err))
+		return
+	}
+
+	if err := render.Render(w, r, payloads.NewSourceImageListResponse(authentication.ProviderType.String(), images, instanceType)); err != nil {
+		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render images", err))
+		return
+	}
+}
+
+func findInstanceType(provider models.ProviderType, name clients.InstanceTypeName) *clients.InstanceType {
+	//nolint:exhaustive
+	switch provider {
+	case models.ProviderTypeAWS:
+		return preload.EC2InstanceType.FindInstanceType(name)
+	case models.ProviderTypeAzure:
+		return preload.AzureInstanceType.FindInstanceType(name)
+	case models.ProviderTypeGCP:
+		return preload.GCPInstanceType.FindInstanceType(name)
+	default:
+		return nil
+	}
+}
+
+// listSourceImages fetches Image Builder and account images concurrently, Image Builder
+// images are listed first. Account images of Image Builder images (e.g. a compose AMI shared
+// into the account) are only listed once as Image Builder images.
+func listSourceImages(ctx context.Context, authentication *clients.Authentication, region string) ([]*clients.Image, error) {
+	var builtImages, accountImages []*clients.Image
+	group, gctx := errgroup.WithContext(ctx)
+
+	group.Go(func() error {
+		ibClient, err := clients.GetImageBuilderClient(gctx)
+		if err != nil {
+			return fmt.Errorf("unable to get image builder client: %w", err)
+		}
+
+		images, err := ibClient.ListImages(gctx, authentication.ProviderType)
+		if err != nil {
+			return fmt.Errorf("unable to list image builder images: %w", err)
+		}
+
+		for _, image := range images {
+			if image.Region == "" || image.Region == region || authentication.ProviderType != models.ProviderTypeAWS {
+				builtImages = append(builtImages, image)
+			}
+		}
+		return nil
+	})
+
+	group.Go(func() error {
+		var err error
+		accountImages, err = listAccountImages(gctx, authentication, region)
+		return err
+	})
+
+	if err := group.Wait(); err != nil {
+		return nil, fmt.Errorf("source images: %w", err)
+	}
+
+	built := make(map[string]struct{}, len(builtImages))
+	for _, image := range builtImages {
+		if image.ProviderID != "" {
+			built[image.ProviderID] = struct{}{}
+		}
+	}
+	result := builtImages
+	for _, image := range accountImages {
+		if _, ok := built[image.ID]; !ok {
+			result = append(result, image)
+		}
+	}
+	return result, nil
+}
+
+func listAccountImages(ctx context.Context, authentication *clients.Authentication, region string) ([]*clients.Image, error) {
+	switch authentication.ProviderType {
+	case models.ProviderTypeAWS:
+		ec2Client, err := clients.GetEC2Client(ctx, authentication, region)
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize AWS client: %w", err)
+		}
+		images, err := ec2Client.ListImages(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list AWS images: %w", err)
+		}
+		return images, nil
+	case models.ProviderTypeAzure:
+		azureClient, err := clients.GetAzureClient(ctx, authentication)
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize Azure client: %w", err)
+		}
+		images, err := azureClient.ListImages(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list Azure gallery images: %w", err)
+		}
+		return images, nil
+	case models.ProviderTypeGCP:
+		gcpClient, err := clients.GetGCPClient(ctx, authentication)
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize GCP client: %w", err)
+		}
+		images, err := gcpClient.ListImages(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list GCP images: %w", err)
+		}
+		return images, nil
+	case models.ProviderTypeNoop, models.ProviderTypeUnknown:
+		return nil, ErrProviderTypeNotImplemented
+	}
+	return nil, ErrUnknownProviderType
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	_ "github.com/RHEnVision/provisioning-backend/internal/testing/initialization"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+
+	clientStub "github.com/RHEnVision/provisioning-backend/internal/clients/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/dao/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/testing/identity"
+)
+
+func requestSourceImages(t *testing.T, ctx context.Context, provider models.ProviderType, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	source, err := clientStub.AddSource(ctx, provider)
+	require.NoError(t, err, "failed to add stubbed source")
+
+	rctx := chi.NewRouteContext()
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+	rctx.URLParams.Add("ID", source.ID)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("/api/provisioning/sources/%s/images?%s", source.ID, query), nil)
+	require.NoError(t, err, "failed to create request")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ListSourceImages)
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestListSourceImages(t *testing.T) {
+	t.Run("AWS images with compatibility", func(t *testing.T) {
+		ctx := stubs.WithAccountDaoOne(context.Background())
+		ctx = identity.WithTenant(t, ctx)
+		ctx = clientStub.WithSourcesClient(ctx)
+		ctx = clientStub.WithEC2Client(ctx)
+		ctx = clientStub.WithImageBuilderClient(ctx)
+
+		rr := requestSourceImages(t, ctx, models.ProviderTypeAWS, "region=us-east-1&instance_type=t4g.nano")
+		require.Equal(t, http.StatusOK, rr.Code, "Handler returned wrong status code: %s", rr.Body.String())
+
+		var result payloads.SourceImageListResponse
+		err := json.NewDecoder(rr.Body).Decode(&result)
+		require.NoError(t, err, "failed to decode response body")
+		require.Equal(t, "aws", result.Provider)
+		require.Len(t, result.Data, 3, "shared AMI of the Image Builder image is listed once")
+
+		require.Equal(t, "image-builder", result.Data[0].Origin)
+		require.False(t, *result.Data[0].Compatible, "x86_64 image is not compatible with arm64 type")
+		require.Equal(t, "shared", result.Data[2].Origin)
+		require.True(t, *result.Data[2].Compatible, "arm64 image is compatible with arm64 type")
+	})
+
+	t.Run("AWS image builder images from other regions are filtered", func(t *testing.T) {
+		ctx := stubs.WithAccountDaoOne(context.Background())
+		ctx = identity.WithTenant(t, ctx)
+		ctx = clientStub.WithSourcesClient(ctx)
+		ctx = clientStub.WithEC2Client(ctx)
+		ctx = clientStub.WithImageBuilderClient(ctx)
+
+		rr := requestSourceImages(t, ctx, models.ProviderTypeAWS, "region=eu-central-1")
+		require.Equal(t, http.StatusOK, rr.Code, "Handler returned wrong status code: %s", rr.Body.String())
+
+		var result payloads.SourceImageListResponse
+		err := json.NewDecoder(rr.Body).Decode(&result)
+		require.NoError(t, err, "failed to decode response body")
+		require.Len(t, result.Data, 3)
+		require.Equal(t, "account", result.Data[0].Origin)
+		require.Nil(t, result.Data[0].Compatible, "compatibility is only present for instance type")
+	})
+
+	t.Run("Azure gallery images", func(t *testing.T) {
+		ctx := stubs.WithAccountDaoOne(context.Background())
+		ctx = identity.WithTenant(t, ctx)
+		ctx = clientStub.WithSourcesClient(ctx)
+		ctx = clientStub.WithAzureClient(ctx)
+		ctx = clientStub.WithImageBuilderClient(ctx)
+
+		rr := requestSourceImages(t, ctx, models.ProviderTypeAzure, "")
+		require.Equal(t, http.StatusOK, rr.Code, "Handler returned wrong status code: %s", rr.Body.String())
+
+		var result payloads.SourceImageListResponse
+		err := json.NewDecoder(rr.Body).Decode(&result)
+		require.NoError(t, err, "failed to decode response body")
+		require.Equal(t, "azure", result.Provider)
+		require.Len(t, result.Data, 2)
+	})
+
+	t.Run("fails on unknown region", func(t *testing.T) {
+		ctx := stubs.WithAccountDaoOne(context.Background())
+		ctx = identity.WithTenant(t, ctx)
+		ctx = clientStub.WithSourcesClient(ctx)
+		ctx = clientStub.WithEC2Client(ctx)
+		ctx = clientStub.WithImageBuilderClient(ctx)
+
+		rr := requestSourceImages(t, ctx, models.ProviderTypeAWS, "region=cz-olomouc-2")
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Handler returned wrong status code")
+	})
+
+	t.Run("fails on unknown instance type", func(t *testing.T) {
+		ctx := stubs.WithAccountDaoOne(context.Background())
+		ctx = identity.WithTenant(t, ctx)
+		ctx = clientStub.WithSourcesClient(ctx)
+		ctx = clientStub.WithEC2Client(ctx)
+		ctx = clientStub.WithImageBuilderClient(ctx)
+
+		rr := requestSourceImages(t, ctx, models.ProviderTypeAWS, "instance_type=x1.unknown")
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Handler returned wrong status code")
+	})
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/cache"
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/config"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads/validation"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/errgroup"
+)
+
+// sourceRegionsExpiration is how long regions of a source are cached, customers enable new
+// regions rarely
+const sourceRegionsExpiration = time.Hour
+
+// maximum concurrent requests when listing zones of AWS regions
+const awsZonesConcurrency = 8
+
+func ListSourceRegions(w http.ResponseWriter, r *http.Request) {
+	sourceId := chi.URLParam(r, "ID")
+	if err := validation.DigitsOnly(sourceId); err != nil {
+		renderError(w, r, payloads.NewURLParsingError(r.Context(), "id parameter invalid", err))
+		return
+	}
+
+	sourcesClient, err := clients.GetSourcesClient(r.Context())
+	if err != nil {
+		renderError(w, r, payloads.NewClientError(r.Context(), err))
+		return
+	}
+
+	authentication, err := sourcesClient.GetAuthentication(r.Context(), sourceId)
+	if err != nil {
+		renderError(w, r, payloads.NewClientError(r.Context(), err))
+		return
+	}
+
+	regions, err := getSourceRegions(r.Context(), sourceId, authentication)
+	if err != nil {
+		renderError(w, r, payloads.NewClientError(r.Context(), err))
+		return
+	}
+
+	if err := render.Render(w, r, payloads.NewSourceRegionListResponse(authentication.ProviderType.String(), regions)); err != nil {
+		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render regions", err))
+		return
+	}
+}
+
+// getSourceRegions returns sorted regions of a source from cache or from the provider.
+func getSourceRegions(ctx context.Context, sourceId string, authentication *clients.Authentication) (clients.SourceRegions, error) {
+	result, err := cache.GetOrLoad(ctx, sourceId, cache.LoadOptions{Expiration: sourceRegionsExpiration},
+		func(ctx context.Context) (clients.SourceRegions, error) {
+			var regions []clients.RegionDetail
+			var loadErr error
+			switch authentication.ProviderType {
+			case models.ProviderTypeAWS:
+				regions, loadErr = listAWSRegions(ctx, authentication)
+			case models.ProviderTypeAzure:
+				regions, loadErr = listAzureRegions(ctx, authentication)
+			case models.ProviderTypeGCP:
+				regions, loadErr = listGCPRegions(ctx, authentication)
+			case models.ProviderTypeNoop, models.ProviderTypeUnknown:
+				return nil, ErrProviderTypeNotImplemented
+			}
+			if loadErr != nil {
+				return nil, loadErr
+			}
+
+			sort.Slice(regions, func(i, j int) bool {
+				return regions[i].Name < regions[j].Name
+			})
+			return regions, nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("source regions load error: %w", err)
+	}
+
+	return result, nil
+}
+
+func listAWSRegions(ctx context.Context, authentication *clients.Authentication) ([]clients.RegionDetail, error) {
+	logger := zerolog.Ctx(ctx)
+	ec2Client, err := clients.GetEC2Client(ctx, authentication, config.AWS.DefaultRegion)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize AWS client: %w", err)
+	}
+
+	regions, err := ec2Client.ListRegionDetails(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list AWS regions: %w", err)
+	}
+
+	// zones can be only listed via regional endpoints of enabled regions
+	group := errgroup.Group{}
+	group.SetLimit(awsZonesConcurrency)
+	for i := range regions {
+		region := &regions[i]
+		if !region.Enabled {
+			continue
+		}
+		group.Go(func() error {
+			regionalClient, clientErr := clients.GetEC2Client(ctx, authentication, region.Name.String())
+			if clientErr != nil {
+				logger.Warn().Err(clientErr).Msgf("Unable to initialize AWS client for region %s", region.Name)
+				return nil
+			}
+			zones, zonesErr := regionalClient.ListAllZones(ctx, region.Name)
+			if zonesErr != nil {
+				logger.Warn().Err(zonesErr).Msgf("Unable to list AWS zones for region %s", region.Name)
+				return nil
+			}
+			region.Zones = zones
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	return regions, nil
+}
+
+func listAzureRegions(ctx context.Context, authentication *clients.Authentication) ([]clients.RegionDetail, error) {
+	azureClient, err := clients.GetAzureClient(ctx, authentication)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize Azure client: %w", err)
+	}
+
+	regions, err := azureClient.ListRegionDetails(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list Azure locations: %w", err)
+	}
+
+	return regions, nil
+}
+
+func listGCPRegions(ctx context.Context, authentication *clients.Authentication) ([]clients.RegionDetail, error) {
+	gcpClient, err := clients.GetGCPClient(ctx, authentication)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize GCP client: %w", err)
+	}
+
+	regions, err := gcpClient.ListRegionDetails(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list GCP regions: %w", err)
+	}
+
+	return regions, nil
+}
+
+// sourceRegionEnabled returns true when a region (or a zone for GCP) is enabled for the source
+// account. It is used for regions which are not preloaded, e.g. AWS opt-in regions. Only regions
+// cached by the source regions endpoint are checked, the cloud provider is never called when
+// creating a reservation.
+func sourceRegionEnabled(ctx context.Context, sourceId string, name string) bool {
+	var regions clients.SourceRegions
+	err := cache.Find(ctx, sourceId, &regions)
+	if err != nil {
+		if !errors.Is(err, cache.ErrNotFound) {
+			zerolog.Ctx(ctx).Warn().Err(err).Msg("Unable to find cached source regions")
+		}
+		return false
+	}
+
+	for _, region := range regions {
+		if !region.Enabled {
+			continue
+		}
+		if region.Name.String() == name {
+			return true
+		}
+		for _, zone := range region.Zones {
+			if zone.String() == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
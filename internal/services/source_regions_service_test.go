@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RHEnVision/provisioning-backend/internal/cache"
+	"github.com/RHEnVision/provisioning-backend/internal/clients"
+	"github.com/RHEnVision/provisioning-backend/internal/config"
+	"github.com/RHEnVision/provisioning-backend/internal/models"
+	"github.com/RHEnVision/provisioning-backend/internal/payloads"
+	_ "github.com/RHEnVision/provisioning-backend/internal/testing/initialization"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+
+	clientStub "github.com/RHEnVision/provisioning-backend/internal/clients/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/dao/stubs"
+	"github.com/RHEnVision/provisioning-backend/internal/testing/identity"
+)
+
+func listSourceRegions(t *testing.T, ctx context.Context, provider models.ProviderType) payloads.SourceRegionListResponse {
+	t.Helper()
+	source, err := clientStub.AddSource(ctx, provider)
+	require.NoError(t, err, "failed to add stubbed source")
+
+	rctx := chi.NewRouteContext()
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+	rctx.URLParams.Add("ID", source.ID)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("/api/provisioning/sources/%s/regions", source.ID), nil)
+	require.NoError(t, err, "failed to create request")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ListSourceRegions)
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, "Handler returned wrong status code: %s", rr.Body.String())
+
+	var result payloads.SourceRegionListResponse
+	err = json.NewDecoder(rr.Body).Decode(&result)
+	require.NoError(t, err, "failed to decode response body")
+	return result
+}
+
+func TestListSourceRegions(t *testing.T) {
+	t.Run("AWS regions with opt-in status", func(t *testing.T) {
+		ctx := stubs.WithAccountDaoOne(context.Background())
+		ctx = identity.WithTenant(t, ctx)
+		ctx = clientStub.WithSourcesClient(ctx)
+		ctx = clientStub.WithEC2Client(ctx)
+
+		result := listSourceRegions(t, ctx, models.ProviderTypeAWS)
+		require.Equal(t, "aws", result.Provider)
+		require.Len(t, result.Data, 3)
+		require.Equal(t, "af-south-1", result.Data[0].Name)
+		require.False(t, result.Data[0].Enabled)
+		require.Empty(t, result.Data[0].Zones)
+		require.Equal(t, "eu-central-1", result.Data[1].Name)
+		require.True(t, result.Data[1].Enabled)
+		require.NotEmpty(t, result.Data[1].Zones)
+	})
+
+	t.Run("Azure locations", func(t *testing.T) {
+		ctx := stubs.WithAccountDaoOne(context.Background())
+		ctx = identity.WithTenant(t, ctx)
+		ctx = clientStub.WithSourcesClient(ctx)
+		ctx = clientStub.WithAzureClient(ctx)
+
+		result := listSourceRegions(t, ctx, models.ProviderTypeAzure)
+		require.Equal(t, "azure", result.Provider)
+		require.Len(t, result.Data, 2)
+		require.Equal(t, "eastus", result.Data[0].Name)
+	})
+}
+
+func TestSourceRegionEnabled(t *testing.T) {
+	cacheType, cacheSize := config.Application.Cache.Type, config.Application.Cache.Memory.Size
+	config.Application.Cache.Type = "memory"
+	config.Application.Cache.Memory.Size = 100
+	cache.Initialize()
+	t.Cleanup(func() {
+		config.Application.Cache.Type, config.Application.Cache.Memory.Size = cacheType, cacheSize
+		cache.Initialize()
+	})
+
+	ctx := stubs.WithAccountDaoOne(context.Background())
+	ctx = identity.WithTenant(t, ctx)
+	ctx = clientStub.WithSourcesClient(ctx)
+	ctx = clientStub.WithEC2Client(ctx)
+	source, err := clientStub.AddSource(ctx, models.ProviderTypeAWS)
+	require.NoError(t, err, "failed to add stubbed source")
+
+	require.False(t, sourceRegionEnabled(ctx, source.ID, "eu-central-1"), "regions are not cached yet")
+
+	authentication := clients.NewAuthentication("arn:aws:iam::230214684733:role/Test", models.ProviderTypeAWS)
+	_, err = getSourceRegions(ctx, source.ID, authentication)
+	require.NoError(t, err)
+
+	require.True(t, sourceRegionEnabled(ctx, source.ID, "eu-central-1"))
+	require.False(t, sourceRegionEnabled(ctx, source.ID, "af-south-1"), "opt-in region is not enabled")
+	require.False(t, sourceRegionEnabled(ctx, source.ID, "cz-olomouc-1"))
+}
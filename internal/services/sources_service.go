@@ -2,7 +2,6 @@ package services
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net/http"
 
@@ -128,52 +127,43 @@ func GetSourceUploadInfo(w http.ResponseWriter, r *http.Request) {
 }
 
 func getAWSAccountDetails(ctx context.Context, sourceId string, authentication *clients.Authentication) (*clients.AccountDetailsAWS, error) {
-	result := &clients.AccountDetailsAWS{}
-
-	err := cache.Find(ctx, sourceId, result)
-	if errors.Is(err, cache.ErrNotFound) {
-		ec2Client, clientErr := clients.GetEC2Client(ctx, authentication, "")
-		if clientErr != nil {
-			return nil, fmt.Errorf("unable to initialize AWS client: %w", clientErr)
-		}
-
-		result.AccountID, clientErr = ec2Client.GetAccountId(ctx)
-		if clientErr != nil {
-			return nil, fmt.Errorf("unable to get account id: %w", clientErr)
-		}
-
-		clientErr = cache.SetForever(ctx, sourceId, result)
-		if clientErr != nil {
-			return nil, fmt.Errorf("cache set error: %w", clientErr)
-		}
-	} else if err != nil {
-		return nil, fmt.Errorf("cache find error: %w", err)
+	result, err := cache.GetOrLoad(ctx, sourceId, cache.LoadOptions{Expiration: cache.Forever},
+		func(ctx context.Context) (clients.AccountDetailsAWS, error) {
+			ec2Client, clientErr := clients.GetEC2Client(ctx, authentication, "")
+			if clientErr != nil {
+				return clients.AccountDetailsAWS{}, fmt.Errorf("unable to initialize AWS client: %w", clientErr)
+			}
+
+			accountId, clientErr := ec2Client.GetAccountId(ctx)
+			if clientErr != nil {
+				return clients.AccountDetailsAWS{}, fmt.Errorf("unable to get account id: %w", clientErr)
+			}
+
+			return clients.AccountDetailsAWS{AccountID: accountId}, nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("account details load error: %w", err)
 	}
 
-	return result, nil
+	return &result, nil
 }
 
 func getAzureAccountDetails(ctx context.Context, sourceId string, authentication *clients.Authentication) (*clients.AccountDetailsAzure, error) {
-	var tenantId clients.AzureTenantId
-
 	azureClient, err := clients.GetAzureClient(ctx, authentication)
 	if err != nil {
 		return nil, fmt.Errorf("unable to initialize Azure client: %w", err)
 	}
 
-	err = cache.Find(ctx, sourceId, &tenantId)
-	if errors.Is(err, cache.ErrNotFound) {
-		tenantId, err = azureClient.TenantId(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("unable to fetch Tenant ID: %w", err)
-		}
-
-		err = cache.SetForever(ctx, sourceId, &tenantId)
-		if err != nil {
-			return nil, fmt.Errorf("cache set error: %w", err)
-		}
-	} else if err != nil {
-		return nil, fmt.Errorf("cache find error: %w", err)
+	tenantId, err := cache.GetOrLoad(ctx, sourceId, cache.LoadOptions{Expiration: cache.Forever},
+		func(ctx context.Context) (clients.AzureTenantId, error) {
+			tenantId, tenantErr := azureClient.TenantId(ctx)
+			if tenantErr != nil {
+				return "", fmt.Errorf("unable to fetch Tenant ID: %w", tenantErr)
+			}
+			return tenantId, nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("tenant id load error: %w", err)
 	}
 
 	groupList, err := azureClient.ListResourceGroups(ctx)
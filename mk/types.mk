@@ -14,3 +14,13 @@ generate-gcp-types: ## Generate instance types for GCP
 
 .PHONY: generate-types
 generate-types: generate-ec2-types generate-azure-types generate-gcp-types ## Generate instance types for all providers
+
+.PHONY: diff-types
+diff-types: ## Compare live instance types for all providers with preloaded data
+	$(GO) run cmd/typesctl/main.go -provider azure -diff
+	$(GO) run cmd/typesctl/main.go -provider ec2 -diff
+	$(GO) run cmd/typesctl/main.go -provider gcp -diff
+
+.PHONY: validate-types
+validate-types: ## Check integrity of preloaded instance types
+	$(GO) run cmd/typesctl/main.go -validate
@@ -4,4 +4,10 @@ import (
 	"errors"
 )
 
-var ErrJobNotFound = errors.New("job not found")
+var (
+	ErrJobNotFound    = errors.New("job not found")
+	ErrJobNotInFlight = errors.New("job is not in flight")
+	ErrJobPanic       = errors.New("panic during job")
+	ErrJobInterrupted = errors.New("job interrupted by worker shutdown")
+	ErrUnexpectedArgs = errors.New("unexpected job arguments")
+)
@@ -3,16 +3,17 @@ package worker
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
 
 	"github.com/RHEnVision/provisioning-backend/internal/config"
 	"github.com/RHEnVision/provisioning-backend/internal/identity"
 	"github.com/RHEnVision/provisioning-backend/internal/logging"
-	"github.com/RHEnVision/provisioning-backend/internal/telemetry"
 	"github.com/google/uuid"
-	"github.com/rs/zerolog"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
-	"go.opentelemetry.io/otel/trace"
 )
 
 func init() {
@@ -22,6 +23,16 @@ func init() {
 
 type JobType string
 
+// JobPriority selects a lane of the Redis queue, jobs of higher priority lanes are dequeued more
+// often. The memory worker ignores priorities.
+type JobPriority string
+
+const (
+	PriorityHigh   JobPriority = "high"
+	PriorityNormal JobPriority = "normal"
+	PriorityLow    JobPriority = "low"
+)
+
 type JobHandler func(ctx context.Context, job *Job)
 
 type Job struct {
@@ -45,6 +56,22 @@ type Job struct {
 
 	// Job arguments.
 	Args any
+
+	// Queue lane, blank value is PriorityNormal.
+	Priority JobPriority
+
+	// Time when the job was first enqueued. It is set by Enqueue function when blank.
+	EnqueuedAt time.Time
+
+	// Last step finished by the handler, see SetProgress. Jobs interrupted by a worker shutdown
+	// are re-queued with it.
+	Progress string
+
+	// Number of times the job was interrupted by a worker shutdown and re-queued.
+	Interruptions int
+
+	// The handler stopped because of an interruption, see ReportInterrupted. Not stored.
+	stoppedInterrupted bool
 }
 
 var ErrHandlerNotFound = errors.New("handler not registered")
@@ -53,27 +80,67 @@ var ErrHandlerNotFound = errors.New("handler not registered")
 type JobEnqueuer interface {
 	// Enqueue delivers a job to one of the backend workers.
 	Enqueue(context.Context, *Job) error
+
+	// EnqueueAt delivers a job to one of the backend workers at the given time. Jobs with time in
+	// the past are delivered immediately.
+	EnqueueAt(context.Context, *Job, time.Time) error
+
+	// EnqueueAfter delivers a job to one of the backend workers after the given delay.
+	EnqueueAfter(context.Context, *Job, time.Duration) error
 }
 
 // JobWorker receives and handles Job messages.
 type JobWorker interface {
 	// RegisterHandler registers an event listener for a particular type with an associated handler.
+	// See RegisterTypedHandler for handlers of a single argument type.
 	RegisterHandler(JobType, JobHandler, any)
 
+	// Use adds middleware wrapping all handlers, the first middleware is the outermost one.
+	Use(...Middleware)
+
 	// DequeueLoop starts one or more goroutines to dispatch incoming jobs.
 	DequeueLoop(ctx context.Context)
 
 	// Stop let's background workers to finish all jobs and terminates them. It blocks until workers are done.
+	// The Redis worker interrupts and re-queues jobs which are still running when the context is done.
 	Stop(ctx context.Context)
 
 	// Stats returns statistics. Not all implementations supports stats, some may return zero values.
 	Stats(ctx context.Context) (Stats, error)
+
+	// ListJobs returns delayed, queued and in-flight jobs matching the filter, oldest first.
+	ListJobs(ctx context.Context, filter *JobFilter) ([]*JobInfo, error)
+
+	// GetJob returns a delayed, queued or in-flight job or ErrJobNotFound.
+	GetJob(ctx context.Context, id uuid.UUID) (*JobInfo, error)
+
+	// DeleteJob removes a delayed or queued job. For in-flight jobs it only removes the in-flight record, which
+	// is useful for jobs of workers which were killed. The job itself is not cancelled.
+	DeleteJob(ctx context.Context, id uuid.UUID) error
+
+	// RequeueJob enqueues an in-flight job again, for example a job of a killed worker. Returns
+	// ErrJobNotInFlight for queued jobs.
+	RequeueJob(ctx context.Context, id uuid.UUID) error
+
+	// DrainQueue removes all delayed and queued jobs and returns their count. In-flight jobs are
+	// not affected.
+	DrainQueue(ctx context.Context) (int64, error)
 }
 
 func (jt JobType) String() string {
 	return string(jt)
 }
 
+// Lane returns the priority, unknown and blank priorities are PriorityNormal.
+func (p JobPriority) Lane() JobPriority {
+	switch p {
+	case PriorityHigh, PriorityNormal, PriorityLow:
+		return p
+	default:
+		return PriorityNormal
+	}
+}
+
 // Stats provides monitoring statistics.
 type Stats struct {
 	// Number of jobs currently in the queue. This is a global value - all clients see the same value.
@@ -83,36 +150,137 @@ type Stats struct {
 	InFlight int64
 }
 
-func initJobContext(origCtx context.Context, job *Job) (context.Context, *zerolog.Logger, trace.Span) {
-	// init with invalid span from empty context
-	span := trace.SpanFromContext(context.Background())
+// prepareJob sets job ID, enqueue time and trace context before the job is enqueued.
+func prepareJob(ctx context.Context, job *Job) error {
+	var err error
+	if job.ID == uuid.Nil {
+		job.ID, err = uuid.NewRandom()
+		if err != nil {
+			return fmt.Errorf("unable to generate UUID: %w", err)
+		}
+	}
 
-	if job == nil {
-		zerolog.Ctx(origCtx).Warn().Err(ErrJobNotFound).Msg("No job, context not changed")
-		return origCtx, nil, span
+	if job.EnqueuedAt.IsZero() {
+		job.EnqueuedAt = time.Now()
 	}
 
-	ctx := logging.WithJobId(origCtx, job.ID.String())
+	if config.Telemetry.Enabled {
+		job.TraceContext = make(map[string]string)
+		otel.GetTextMapPropagator().Inject(ctx, job.TraceContext)
+	}
+	return nil
+}
+
+// JobState is a state of a job returned by JobWorker.ListJobs.
+type JobState string
+
+const (
+	// JobStateDelayed is a job enqueued with EnqueueAt or EnqueueAfter waiting for its time.
+	JobStateDelayed JobState = "delayed"
+
+	// JobStateQueued is a job waiting in the queue.
+	JobStateQueued JobState = "queued"
+
+	// JobStateInFlight is a job being processed by a worker.
+	JobStateInFlight JobState = "in_flight"
+)
+
+// JobInfo is a job in the queue or being processed.
+type JobInfo struct {
+	Job *Job
+
+	State JobState
+
+	// Time when a delayed job is delivered, zero for other jobs.
+	RunAt time.Time
+
+	// Time when a worker started processing the job, zero for queued jobs.
+	StartedAt time.Time
+}
+
+// JobFilter filters listed jobs, zero values do not filter.
+type JobFilter struct {
+	Type JobType
+
+	AccountID int64
+
+	// Only jobs enqueued at least this long ago.
+	OlderThan time.Duration
+}
+
+// Matches returns true when the job passes the filter.
+func (f *JobFilter) Matches(info *JobInfo, now time.Time) bool {
+	if f == nil {
+		return true
+	}
+	if f.Type != "" && info.Job.Type != f.Type {
+		return false
+	}
+	if f.AccountID != 0 && info.Job.AccountID != f.AccountID {
+		return false
+	}
+	if f.OlderThan > 0 && now.Sub(info.Job.EnqueuedAt) < f.OlderThan {
+		return false
+	}
+	return true
+}
+
+// sortJobs sorts jobs by the enqueue time, oldest first.
+func sortJobs(jobs []*JobInfo) {
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].Job.EnqueuedAt.Before(jobs[j].Job.EnqueuedAt)
+	})
+}
+
+type jobCtxKeyType int
+
+const (
+	jobCtxKey jobCtxKeyType = iota
+	interruptCtxKey
+)
+
+// SetProgress records the last finished step of the job being processed. A job interrupted by a
+// worker shutdown is re-queued with the marker, so the next worker can tell where it stopped.
+func SetProgress(ctx context.Context, marker string) {
+	if job, ok := ctx.Value(jobCtxKey).(*Job); ok {
+		job.Progress = marker
+	}
+}
+
+// Interrupted returns true when the job was interrupted by a worker shutdown. Handlers should stop
+// at the next step boundary without finishing the job and report it with ReportInterrupted, the
+// job is then re-queued and continued by another worker. The job context is only cancelled when the handler does not stop in time.
+func Interrupted(ctx context.Context) bool {
+	if flag, ok := ctx.Value(interruptCtxKey).(*atomic.Bool); ok && flag.Load() {
+		return true
+	}
+	return errors.Is(context.Cause(ctx), ErrJobInterrupted)
+}
+
+// ReportInterrupted records that the handler of the job being processed stopped early with
+// ErrJobInterrupted. Only such jobs are re-queued on shutdown, jobs which finished or failed while
+// interrupted are not.
+func ReportInterrupted(ctx context.Context) {
+	if job, ok := ctx.Value(jobCtxKey).(*Job); ok {
+		job.stoppedInterrupted = true
+	}
+}
+
+// WithInterrupt returns context with a flag which is set by the returned function. The job is
+// interrupted without cancelling the context, see Interrupted.
+func WithInterrupt(ctx context.Context) (context.Context, func()) {
+	flag := &atomic.Bool{}
+	return context.WithValue(ctx, interruptCtxKey, flag), func() { flag.Store(true) }
+}
+
+// initJobContext returns context with identity, account and job keys. Logger fields and tracing
+// are added by middleware.
+func initJobContext(origCtx context.Context, job *Job) context.Context {
+	ctx := context.WithValue(origCtx, jobCtxKey, job)
 	ctx = identity.WithIdentity(ctx, job.Identity)
 	ctx = logging.WithEdgeRequestId(ctx, job.EdgeID)
 	ctx = identity.WithAccountId(ctx, job.AccountID)
 	ctx = logging.WithJobId(ctx, job.ID.String())
 	ctx = logging.WithJobType(ctx, job.Type.String())
-
-	logCtx := zerolog.Ctx(ctx).With().
-		Int64("account_id", job.AccountID).
-		Str("org_id", job.Identity.Identity.OrgID).
-		Str("account_number", job.Identity.Identity.AccountNumber).
-		Str("request_id", job.EdgeID).
-		Str("job_id", job.ID.String()).
-		Str("job_type", job.Type.String())
-
-	if config.Telemetry.Enabled {
-		ctx = otel.GetTextMapPropagator().Extract(ctx, job.TraceContext)
-		ctx, span = telemetry.StartSpan(ctx, job.Type.String())
-		logCtx = logCtx.Str("trace_id", span.SpanContext().TraceID().String())
-	}
-	logger := logCtx.Logger()
-
-	return logger.WithContext(ctx), &logger, span
+	return ctx
 }
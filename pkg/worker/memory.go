@@ -1,34 +1,54 @@
 package worker
 
 import (
+	"container/heap"
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
-	"github.com/RHEnVision/provisioning-backend/internal/config"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
-	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/codes"
 )
 
 type MemoryWorker struct {
-	handlers map[JobType]JobHandler
+	handlers *handlerRegistry
 	todo     chan *Job
+
+	// queued and in-flight jobs for inspection, deleted jobs are skipped by the dequeue loop
+	mu       sync.Mutex
+	queued   map[uuid.UUID]*JobInfo
+	inFlight map[uuid.UUID]*JobInfo
+
+	// timer heap of delayed jobs, the mover goroutine is woken up when a job is added
+	delayed delayedJobs
+	wake    chan struct{}
+	closeCh chan struct{}
+	moverWG sync.WaitGroup
 }
 
+var _ JobWorker = &MemoryWorker{}
+
 func NewMemoryClient() *MemoryWorker {
 	return &MemoryWorker{
-		handlers: make(map[JobType]JobHandler),
+		handlers: newHandlerRegistry(),
 		todo:     make(chan *Job),
+		queued:   make(map[uuid.UUID]*JobInfo),
+		inFlight: make(map[uuid.UUID]*JobInfo),
+		wake:     make(chan struct{}, 1),
+		closeCh:  make(chan struct{}),
 	}
 }
 
 func (w *MemoryWorker) RegisterHandler(jtype JobType, handler JobHandler, _ any) {
-	w.handlers[jtype] = handler
+	w.handlers.register(jtype, handler)
+}
+
+func (w *MemoryWorker) Use(middlewares ...Middleware) {
+	w.handlers.use(middlewares...)
 }
 
 func (w *MemoryWorker) Enqueue(ctx context.Context, job *Job) error {
-	var err error
 	if job == nil {
 		return fmt.Errorf("unable to enqueue job: %w", ErrJobNotFound)
 	}
@@ -39,58 +59,298 @@ func (w *MemoryWorker) Enqueue(ctx context.Context, job *Job) error {
 		Logger()
 	logger.Info().Interface("job_args", job.Args).Msg("Enqueuing job via memory")
 
-	if job.ID == uuid.Nil {
-		job.ID, err = uuid.NewRandom()
-		if err != nil {
-			logger.Error().Err(err).Msg("Unable to generate a job id")
-			return fmt.Errorf("unable to generate UUID: %w", err)
-		}
+	err := prepareJob(ctx, job)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to prepare the job")
+		return err
 	}
 
-	if config.Telemetry.Enabled {
-		job.TraceContext = make(map[string]string)
-		otel.GetTextMapPropagator().Inject(ctx, job.TraceContext)
-	}
+	w.mu.Lock()
+	w.queued[job.ID] = &JobInfo{Job: job, State: JobStateQueued}
+	w.mu.Unlock()
 
 	w.todo <- job
 	return nil
 }
 
+func (w *MemoryWorker) EnqueueAt(ctx context.Context, job *Job, at time.Time) error {
+	if !at.After(time.Now()) {
+		return w.Enqueue(ctx, job)
+	}
+	if job == nil {
+		return fmt.Errorf("unable to enqueue job: %w", ErrJobNotFound)
+	}
+
+	logger := zerolog.Ctx(ctx).With().
+		Str("job_id", job.ID.String()).
+		Str("job_type", job.Type.String()).
+		Time("run_at", at).
+		Logger()
+	logger.Info().Interface("job_args", job.Args).Msg("Enqueuing delayed job via memory")
+
+	err := prepareJob(ctx, job)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to prepare the job")
+		return err
+	}
+
+	w.mu.Lock()
+	heap.Push(&w.delayed, &JobInfo{Job: job, State: JobStateDelayed, RunAt: at})
+	w.mu.Unlock()
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (w *MemoryWorker) EnqueueAfter(ctx context.Context, job *Job, delay time.Duration) error {
+	return w.EnqueueAt(ctx, job, time.Now().Add(delay))
+}
+
 func (w *MemoryWorker) Stop(_ context.Context) {
+	close(w.closeCh)
+	w.moverWG.Wait()
 	close(w.todo)
 }
 
 func (w *MemoryWorker) DequeueLoop(ctx context.Context) {
 	zerolog.Ctx(ctx).Info().Msg("Starting memory dequeuer")
 	go w.dequeueLoop(ctx)
+
+	w.moverWG.Add(1)
+	go w.moveLoop(ctx)
+}
+
+// moveLoop delivers delayed jobs when their time comes.
+func (w *MemoryWorker) moveLoop(ctx context.Context) {
+	defer w.moverWG.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-w.closeCh:
+			return
+		case <-ctx.Done():
+			return
+		case <-w.wake:
+		case <-timer.C:
+		}
+
+		for _, job := range w.dueJobs(time.Now()) {
+			w.mu.Lock()
+			w.queued[job.ID] = &JobInfo{Job: job, State: JobStateQueued}
+			w.mu.Unlock()
+
+			select {
+			case w.todo <- job:
+			case <-w.closeCh:
+				return
+			}
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(w.nextDelay(time.Now()))
+	}
+}
+
+// dueJobs pops delayed jobs with time before now.
+func (w *MemoryWorker) dueJobs(now time.Time) []*Job {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var due []*Job
+	for w.delayed.Len() > 0 && !w.delayed[0].RunAt.After(now) {
+		info, _ := heap.Pop(&w.delayed).(*JobInfo)
+		due = append(due, info.Job)
+	}
+	return due
+}
+
+// nextDelay returns time until the next delayed job, the mover is woken up by new jobs.
+func (w *MemoryWorker) nextDelay(now time.Time) time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.delayed.Len() == 0 {
+		return time.Hour
+	}
+	return w.delayed[0].RunAt.Sub(now)
 }
 
 func (w *MemoryWorker) dequeueLoop(ctx context.Context) {
 	for job := range w.todo {
+		if !w.start(job) {
+			zerolog.Ctx(ctx).Info().Str("job_id", job.ID.String()).Msg("Skipping deleted job")
+			continue
+		}
 		w.processJob(ctx, job)
+		w.finish(job)
 	}
 }
 
+// start moves the job from queued to in-flight jobs, returns false when the job was deleted.
+func (w *MemoryWorker) start(job *Job) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.queued[job.ID]; !ok {
+		return false
+	}
+	delete(w.queued, job.ID)
+	w.inFlight[job.ID] = &JobInfo{Job: job, State: JobStateInFlight, StartedAt: time.Now()}
+	return true
+}
+
+func (w *MemoryWorker) finish(job *Job) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.inFlight, job.ID)
+}
+
 func (w *MemoryWorker) processJob(origCtx context.Context, job *Job) {
 	if job == nil {
 		zerolog.Ctx(origCtx).Error().Err(ErrJobNotFound).Msg("No job to process")
 		return
 	}
 
-	ctx, logger, span := initJobContext(origCtx, job)
-	defer span.End()
-	logger.Info().Interface("job_args", job.Args).Msgf("Dequeued job from memory")
-
-	if h, ok := w.handlers[job.Type]; ok {
-		cCtx, cFunc := context.WithTimeout(ctx, config.Worker.Timeout)
-		defer cFunc()
-		h(cCtx, job)
+	ctx := initJobContext(origCtx, job)
+	if h, ok := w.handlers.handler(job.Type); ok {
+		h(ctx, job)
 	} else {
-		span.SetStatus(codes.Error, "worker has not found handler for a job type")
-		logger.Warn().Msgf("Memory worker handler not found for job type: %s", job.Type)
+		zerolog.Ctx(ctx).Warn().Str("job_id", job.ID.String()).Msgf("Memory worker handler not found for job type: %s", job.Type)
 	}
 }
 
 func (w *MemoryWorker) Stats(_ context.Context) (Stats, error) {
 	return Stats{}, nil
 }
+
+func (w *MemoryWorker) ListJobs(_ context.Context, filter *JobFilter) ([]*JobInfo, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	result := make([]*JobInfo, 0, len(w.delayed)+len(w.queued)+len(w.inFlight))
+	for _, info := range w.delayed {
+		if filter.Matches(info, now) {
+			result = append(result, info)
+		}
+	}
+	for _, jobs := range []map[uuid.UUID]*JobInfo{w.queued, w.inFlight} {
+		for _, info := range jobs {
+			if filter.Matches(info, now) {
+				result = append(result, info)
+			}
+		}
+	}
+	sortJobs(result)
+	return result, nil
+}
+
+func (w *MemoryWorker) GetJob(_ context.Context, id uuid.UUID) (*JobInfo, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if info, ok := w.queued[id]; ok {
+		return info, nil
+	}
+	if info, ok := w.inFlight[id]; ok {
+		return info, nil
+	}
+	if i := w.delayed.index(id); i >= 0 {
+		return w.delayed[i], nil
+	}
+	return nil, fmt.Errorf("job %s: %w", id, ErrJobNotFound)
+}
+
+func (w *MemoryWorker) DeleteJob(_ context.Context, id uuid.UUID) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.queued[id]; ok {
+		delete(w.queued, id)
+		return nil
+	}
+	if _, ok := w.inFlight[id]; ok {
+		delete(w.inFlight, id)
+		return nil
+	}
+	if i := w.delayed.index(id); i >= 0 {
+		heap.Remove(&w.delayed, i)
+		return nil
+	}
+	return fmt.Errorf("job %s: %w", id, ErrJobNotFound)
+}
+
+func (w *MemoryWorker) RequeueJob(ctx context.Context, id uuid.UUID) error {
+	w.mu.Lock()
+	info, inFlight := w.inFlight[id]
+	_, queued := w.queued[id]
+	queued = queued || w.delayed.index(id) >= 0
+	delete(w.inFlight, id)
+	w.mu.Unlock()
+
+	if queued {
+		return fmt.Errorf("job %s: %w", id, ErrJobNotInFlight)
+	}
+	if !inFlight {
+		return fmt.Errorf("job %s: %w", id, ErrJobNotFound)
+	}
+
+	// the only dequeue goroutine may be busy, do not block the caller
+	go func() {
+		_ = w.Enqueue(ctx, info.Job)
+	}()
+	return nil
+}
+
+func (w *MemoryWorker) DrainQueue(_ context.Context) (int64, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	count := int64(len(w.queued) + len(w.delayed))
+	w.queued = make(map[uuid.UUID]*JobInfo)
+	w.delayed = nil
+	return count, nil
+}
+
+// delayedJobs is a heap of delayed jobs ordered by delivery time.
+type delayedJobs []*JobInfo
+
+func (h delayedJobs) Len() int           { return len(h) }
+func (h delayedJobs) Less(i, j int) bool { return h[i].RunAt.Before(h[j].RunAt) }
+func (h delayedJobs) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *delayedJobs) Push(x any) {
+	if info, ok := x.(*JobInfo); ok {
+		*h = append(*h, info)
+	}
+}
+
+func (h *delayedJobs) Pop() any {
+	old := *h
+	n := len(old)
+	info := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return info
+}
+
+func (h delayedJobs) index(id uuid.UUID) int {
+	for i, info := range h {
+		if info.Job.ID == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,56 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryEnqueueAfter(t *testing.T) {
+	ctx := context.Background()
+	w := NewMemoryClient()
+
+	processed := make(chan string, 3)
+	w.RegisterHandler("test", func(_ context.Context, job *Job) {
+		name, _ := job.Args.(string)
+		processed <- name
+	}, "")
+	w.DequeueLoop(ctx)
+	defer w.Stop(ctx)
+
+	require.NoError(t, w.EnqueueAfter(ctx, &Job{Type: "test", Args: "second"}, 100*time.Millisecond))
+	require.NoError(t, w.EnqueueAfter(ctx, &Job{Type: "test", Args: "first"}, 50*time.Millisecond))
+	deleted := &Job{Type: "test", Args: "deleted"}
+	require.NoError(t, w.EnqueueAfter(ctx, deleted, 20*time.Millisecond))
+
+	info, err := w.GetJob(ctx, deleted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, JobStateDelayed, info.State)
+	require.NoError(t, w.DeleteJob(ctx, deleted.ID))
+
+	assert.Equal(t, "first", <-processed)
+	assert.Equal(t, "second", <-processed)
+	assert.Empty(t, processed, "deleted job was processed")
+}
+
+func TestMemoryEnqueueAtPast(t *testing.T) {
+	ctx := context.Background()
+	w := NewMemoryClient()
+
+	processed := make(chan struct{})
+	w.RegisterHandler("test", func(_ context.Context, _ *Job) {
+		close(processed)
+	}, nil)
+	w.DequeueLoop(ctx)
+	defer w.Stop(ctx)
+
+	require.NoError(t, w.EnqueueAt(ctx, &Job{Type: "test"}, time.Now().Add(-time.Minute)))
+	select {
+	case <-processed:
+	case <-time.After(time.Second):
+		t.Fatal("job was not processed immediately")
+	}
+}
@@ -0,0 +1,185 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/config"
+	"github.com/RHEnVision/provisioning-backend/internal/metrics"
+	"github.com/RHEnVision/provisioning-backend/internal/telemetry"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware wraps a job handler, for example to recover from panics or to measure the duration.
+type Middleware func(next JobHandler) JobHandler
+
+// PanicHook is called by the Recover middleware after a handler panicked, the error wraps
+// ErrJobPanic.
+type PanicHook func(ctx context.Context, job *Job, err error)
+
+// TypedJobHandler handles jobs with arguments of type T.
+type TypedJobHandler[T any] func(ctx context.Context, args T)
+
+// RegisterTypedHandler registers a handler of jobs with arguments of type T. The argument type is
+// registered for encoding, jobs with arguments of a different type are logged and dropped.
+func RegisterTypedHandler[T any](w JobWorker, jtype JobType, handler TypedJobHandler[T]) {
+	var zero T
+	w.RegisterHandler(jtype, typedHandler(handler), zero)
+}
+
+func typedHandler[T any](handler TypedJobHandler[T]) JobHandler {
+	return func(ctx context.Context, job *Job) {
+		args, ok := job.Args.(T)
+		if !ok {
+			err := fmt.Errorf("%w: expected %T, got %T", ErrUnexpectedArgs, args, job.Args)
+			zerolog.Ctx(ctx).Error().Err(err).Interface("job_args", job.Args).Msg("Unable to handle job, skipping")
+			return
+		}
+		handler(ctx, args)
+	}
+}
+
+// handlerRegistry keeps handlers and middleware of a worker. Middleware is applied when a handler
+// is looked up, so it can be added before or after handlers are registered.
+type handlerRegistry struct {
+	mu          sync.RWMutex
+	handlers    map[JobType]JobHandler
+	middlewares []Middleware
+}
+
+func newHandlerRegistry() *handlerRegistry {
+	return &handlerRegistry{handlers: make(map[JobType]JobHandler)}
+}
+
+func (r *handlerRegistry) register(jtype JobType, handler JobHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[jtype] = handler
+}
+
+func (r *handlerRegistry) use(middlewares ...Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// handler returns the handler of the job type wrapped in middleware, the first middleware is
+// the outermost one.
+func (r *handlerRegistry) handler(jtype JobType) (JobHandler, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	h, ok := r.handlers[jtype]
+	if !ok {
+		return nil, false
+	}
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		h = r.middlewares[i](h)
+	}
+	return h, true
+}
+
+// Logging adds job fields to the context logger and logs start and end of each job.
+func Logging() Middleware {
+	return func(next JobHandler) JobHandler {
+		return func(ctx context.Context, job *Job) {
+			logger := zerolog.Ctx(ctx).With().
+				Int64("account_id", job.AccountID).
+				Str("org_id", job.Identity.Identity.OrgID).
+				Str("account_number", job.Identity.Identity.AccountNumber).
+				Str("request_id", job.EdgeID).
+				Str("job_id", job.ID.String()).
+				Str("job_type", job.Type.String()).
+				Logger()
+			ctx = logger.WithContext(ctx)
+
+			start := time.Now()
+			logger.Info().Interface("job_args", job.Args).Msg("Started job")
+			defer func() {
+				logger.Info().Dur("job_duration", time.Since(start)).Msg("Finished job")
+			}()
+
+			next(ctx, job)
+		}
+	}
+}
+
+// Tracing starts a span of the job linked to the trace of the enqueuer. It does nothing when
+// telemetry is disabled.
+func Tracing() Middleware {
+	return func(next JobHandler) JobHandler {
+		return func(ctx context.Context, job *Job) {
+			if !config.Telemetry.Enabled {
+				next(ctx, job)
+				return
+			}
+
+			ctx = otel.GetTextMapPropagator().Extract(ctx, job.TraceContext)
+			ctx, span := telemetry.StartSpan(ctx, job.Type.String())
+			defer span.End()
+			logger := zerolog.Ctx(ctx).With().Str("trace_id", span.SpanContext().TraceID().String()).Logger()
+
+			next(logger.WithContext(ctx), job)
+		}
+	}
+}
+
+// Recover logs panics of the handler and calls hooks, so the job can be finished properly.
+func Recover(hooks ...PanicHook) Middleware {
+	return func(next JobHandler) JobHandler {
+		return func(ctx context.Context, job *Job) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				zerolog.Ctx(ctx).Error().
+					Bool("panic", true).
+					Bytes("stacktrace", debug.Stack()).
+					Msgf("Unhandled panic in job: %s", rec)
+				trace.SpanFromContext(ctx).SetStatus(codes.Error, "panic in job")
+
+				err := fmt.Errorf("%w: %s", ErrJobPanic, rec)
+				for _, hook := range hooks {
+					hook(ctx, job, err)
+				}
+			}()
+
+			next(ctx, job)
+		}
+	}
+}
+
+// Metrics observes duration of jobs by type.
+func Metrics() Middleware {
+	return func(next JobHandler) JobHandler {
+		return func(ctx context.Context, job *Job) {
+			metrics.ObserveBackgroundJobDuration(job.Type.String(), func() {
+				next(ctx, job)
+			})
+		}
+	}
+}
+
+// Timeout cancels the handler context after the duration.
+func Timeout(d time.Duration) Middleware {
+	return func(next JobHandler) JobHandler {
+		return func(ctx context.Context, job *Job) {
+			cCtx, cFunc := context.WithTimeout(ctx, d)
+			defer func() {
+				if c := cCtx.Err(); c != nil {
+					zerolog.Ctx(ctx).Error().Err(c).Msg("Job was either cancelled or timeout occurred")
+				}
+				cFunc()
+			}()
+
+			next(cCtx, job)
+		}
+	}
+}
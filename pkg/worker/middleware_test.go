@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerRegistryOrder(t *testing.T) {
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next JobHandler) JobHandler {
+			return func(ctx context.Context, job *Job) {
+				calls = append(calls, name)
+				next(ctx, job)
+			}
+		}
+	}
+
+	r := newHandlerRegistry()
+	r.use(record("outer"))
+	r.register("test", func(_ context.Context, _ *Job) {
+		calls = append(calls, "handler")
+	})
+	r.use(record("inner"))
+
+	h, ok := r.handler("test")
+	require.True(t, ok)
+	h(context.Background(), &Job{Type: "test"})
+	assert.Equal(t, []string{"outer", "inner", "handler"}, calls)
+
+	_, ok = r.handler("missing")
+	assert.False(t, ok)
+}
+
+func TestRecover(t *testing.T) {
+	var hookErr error
+	h := Recover(func(_ context.Context, _ *Job, err error) {
+		hookErr = err
+	})(func(_ context.Context, _ *Job) {
+		panic("boom")
+	})
+
+	require.NotPanics(t, func() {
+		h(context.Background(), &Job{Type: "test"})
+	})
+	require.ErrorIs(t, hookErr, ErrJobPanic)
+	assert.Contains(t, hookErr.Error(), "boom")
+}
+
+func TestTimeout(t *testing.T) {
+	var deadline time.Time
+	h := Timeout(time.Minute)(func(ctx context.Context, _ *Job) {
+		deadline, _ = ctx.Deadline()
+	})
+
+	h(context.Background(), &Job{Type: "test"})
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+}
+
+func TestTypedHandler(t *testing.T) {
+	var handled []string
+	h := typedHandler(func(_ context.Context, args string) {
+		handled = append(handled, args)
+	})
+
+	h(context.Background(), &Job{Type: "test", Args: "first"})
+	h(context.Background(), &Job{Type: "test", Args: 42})
+	assert.Equal(t, []string{"first"}, handled, "job with unexpected arguments was handled")
+}
+
+func TestInterruptedProgress(t *testing.T) {
+	job := &Job{Type: "test"}
+	ctx, cancel := context.WithCancelCause(initJobContext(context.Background(), job))
+	SetProgress(ctx, "first")
+	assert.Equal(t, "first", job.Progress)
+	assert.False(t, Interrupted(ctx))
+
+	cancel(ErrJobInterrupted)
+	timeoutCtx, timeoutCancel := context.WithTimeout(ctx, time.Minute)
+	defer timeoutCancel()
+	assert.True(t, Interrupted(timeoutCtx), "interruption is not visible in child context")
+
+	assert.False(t, job.stoppedInterrupted)
+	ReportInterrupted(timeoutCtx)
+	assert.True(t, job.stoppedInterrupted, "interruption was not reported")
+}
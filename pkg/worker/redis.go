@@ -7,18 +7,16 @@ import (
 	"errors"
 	"fmt"
 	"runtime/debug"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/RHEnVision/provisioning-backend/internal/config"
 	"github.com/RHEnVision/provisioning-backend/internal/metrics"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
-	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/codes"
 )
 
 type RedisWorker struct {
@@ -26,11 +24,26 @@ type RedisWorker struct {
 	client *redis.Client
 
 	// handler functions
-	handlers map[JobType]JobHandler
+	handlers *handlerRegistry
 
-	// queue for all jobs
+	// prefix of all keys and the list of jobs enqueued by previous versions (see redis_lanes.go)
 	queueName string
 
+	// hash of jobs being processed by all workers (job ID to inFlightRecord, see decodeInFlightRecord)
+	inFlightName string
+
+	// hash of in-flight job counts per account
+	accountsName string
+
+	// sorted set of in-flight job IDs scored by lease expiration (unix milliseconds)
+	leasesName string
+
+	// sorted set of delayed jobs scored by delivery time (unix milliseconds)
+	delayedName string
+
+	// list which wakes up idle polling goroutines
+	signalName string
+
 	// close channel
 	closeCh chan interface{}
 
@@ -39,93 +52,290 @@ type RedisWorker struct {
 	concurrency  int
 	loopWG       sync.WaitGroup
 
+	// maximum of in-flight jobs of a single account, zero disables the limit
+	accountConcurrency int
+
+	// in-flight jobs without a heartbeat for this long are considered lost and re-queued
+	leaseTimeout time.Duration
+
+	// weighted choice of lanes
+	lanes *laneScheduler
+
 	// number of in-flight jobs (must be used via atomic functions)
 	inFlight int64
+
+	// how long interrupted jobs may run until their contexts are cancelled
+	interruptTimeout time.Duration
+
+	// jobs processed by this worker, used to interrupt them on shutdown
+	runningMu    sync.Mutex
+	running      map[uuid.UUID]*runningJob
+	interrupting bool
+	cancelling   bool
+}
+
+// runningJob is a job processed by this worker. The interrupt flag stops the job at the next step
+// boundary, the cancel function aborts it.
+type runningJob struct {
+	interrupt func()
+	cancel    context.CancelCauseFunc
 }
 
 var _ JobWorker = &RedisWorker{}
 
-// NewRedisWorker creates new worker that keeps all jobs in a single queue (list), starts N polling
-// goroutines which fetch jobs from the queue and process them in the same goroutine. Use the
-// Stats function to track number of in-flight jobs.
-func NewRedisWorker(address, username, password string, db int, queueName string, pollInterval time.Duration, concurrency int) (*RedisWorker, error) {
+// NewRedisWorker creates new worker that keeps jobs in priority lanes. Each lane is a round-robin
+// list of accounts with a list of jobs per account, so a single account cannot starve the others.
+// The worker starts N polling goroutines which pick a lane by weight, fetch a job of the next
+// account in the lane and process it in the same goroutine. Accounts with accountConcurrency
+// in-flight jobs are skipped, zero disables the limit. Use the Stats function to track number of
+// in-flight jobs. Delayed jobs are kept in a sorted set and moved into lanes by a mover goroutine
+// started together with the polling goroutines. In-flight jobs hold a lease which is extended by
+// a heartbeat while they run, the mover re-queues jobs with an expired lease so jobs of crashed
+// workers are delivered again and their accounts are not blocked by the in-flight limit. Jobs
+// interrupted on shutdown are cancelled when they do not stop within interruptTimeout.
+func NewRedisWorker(address, username, password string, db int, queueName string, pollInterval time.Duration, concurrency, accountConcurrency int, interruptTimeout time.Duration) (*RedisWorker, error) {
 	rdb := redis.NewClient(&redis.Options{
 		Addr:     address,
 		Username: username,
 		Password: password,
 		DB:       db,
-		PoolSize: concurrency + 2, // number of polling goroutines + room for Stats call
+		PoolSize: 2*concurrency + 3, // number of polling goroutines + heartbeats + mover goroutine + room for Stats call
 	})
 	return &RedisWorker{
-		handlers:     make(map[JobType]JobHandler),
-		client:       rdb,
-		queueName:    queueName,
-		pollInterval: pollInterval,
-		concurrency:  concurrency,
-		closeCh:      make(chan interface{}),
+		handlers:           newHandlerRegistry(),
+		client:             rdb,
+		queueName:          queueName,
+		inFlightName:       queueName + ":in-flight",
+		accountsName:       queueName + ":in-flight-accounts",
+		leasesName:         queueName + ":in-flight-leases",
+		delayedName:        queueName + ":delayed",
+		signalName:         queueName + ":signal",
+		pollInterval:       pollInterval,
+		concurrency:        concurrency,
+		accountConcurrency: accountConcurrency,
+		leaseTimeout:       leaseIntervals * pollInterval,
+		interruptTimeout:   interruptTimeout,
+		lanes:              newLaneScheduler(),
+		closeCh:            make(chan interface{}),
+		running:            make(map[uuid.UUID]*runningJob),
 	}, nil
 }
 
 func (w *RedisWorker) RegisterHandler(jtype JobType, handler JobHandler, args any) {
-	w.handlers[jtype] = handler
+	w.handlers.register(jtype, handler)
 	gob.Register(args)
 }
 
+func (w *RedisWorker) Use(middlewares ...Middleware) {
+	w.handlers.use(middlewares...)
+}
+
+func (w *RedisWorker) laneName(lane JobPriority) string {
+	return w.queueName + ":" + string(lane)
+}
+
+func (w *RedisWorker) accountListName(lane JobPriority, account string) string {
+	return w.laneName(lane) + ":" + account
+}
+
+func accountKey(job *Job) string {
+	return strconv.FormatInt(job.AccountID, 10)
+}
+
+// encodeJob prepares and encodes the job for Redis.
+func encodeJob(ctx context.Context, job *Job) ([]byte, error) {
+	err := prepareJob(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	return newJobEntry(job)
+}
+
+// newJobEntry encodes a queued job as "ID:JOB" with the gob encoded job, so scripts can track
+// dequeued jobs by ID without decoding them. Jobs enqueued by previous versions have no ID.
+func newJobEntry(job *Job) ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(job.ID.String() + ":")
+	enc := gob.NewEncoder(&buffer)
+	err := enc.Encode(job)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode args: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// splitJobEntry returns the job ID and the gob encoded job of a queued job, the ID is empty for
+// jobs enqueued by previous versions.
+func splitJobEntry(entry string) (string, string) {
+	id, data, found := strings.Cut(entry, ":")
+	if !found || len(id) != 36 {
+		return "", entry
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return "", entry
+	}
+	return id, data
+}
+
+// decodeJobEntry decodes a queued job, see newJobEntry.
+func decodeJobEntry(entry string) (*Job, error) {
+	_, data := splitJobEntry(entry)
+	var job Job
+	err := gob.NewDecoder(strings.NewReader(data)).Decode(&job)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode job: %w", err)
+	}
+	return &job, nil
+}
+
 func (w *RedisWorker) Enqueue(ctx context.Context, job *Job) error {
-	var err error
 	if job == nil {
 		return fmt.Errorf("unable to enqueue job: %w", ErrJobNotFound)
 	}
 
+	lane := job.Priority.Lane()
 	logger := zerolog.Ctx(ctx).With().
 		Str("job_id", job.ID.String()).
 		Str("job_type", job.Type.String()).
+		Str("job_lane", string(lane)).
 		Logger()
 	logger.Info().Interface("job_args", job.Args).Msg("Enqueuing job via Redis")
 
-	if job.ID == uuid.Nil {
-		job.ID, err = uuid.NewRandom()
-		if err != nil {
-			logger.Error().Err(err).Msg("Unable to generate a job id")
-			return fmt.Errorf("unable to generate UUID: %w", err)
-		}
+	data, err := encodeJob(ctx, job)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to encode the job")
+		return err
 	}
 
-	var buffer bytes.Buffer
-	enc := gob.NewEncoder(&buffer)
+	account := accountKey(job)
+	keys := []string{w.accountListName(lane, account), w.laneName(lane), w.signalName}
+	err = pushScript.Run(ctx, w.client, keys, data, account).Err()
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to push job into Redis")
+		return fmt.Errorf("unable to push job into Redis: %w", err)
+	}
 
-	if config.Telemetry.Enabled {
-		job.TraceContext = make(map[string]string)
-		otel.GetTextMapPropagator().Inject(ctx, job.TraceContext)
+	logger.Info().Msg("Pushed job successfully")
+	return nil
+}
+
+func (w *RedisWorker) EnqueueAt(ctx context.Context, job *Job, at time.Time) error {
+	if !at.After(time.Now()) {
+		return w.Enqueue(ctx, job)
+	}
+	if job == nil {
+		return fmt.Errorf("unable to enqueue job: %w", ErrJobNotFound)
 	}
 
-	err = enc.Encode(&job)
+	lane := job.Priority.Lane()
+	logger := zerolog.Ctx(ctx).With().
+		Str("job_id", job.ID.String()).
+		Str("job_type", job.Type.String()).
+		Str("job_lane", string(lane)).
+		Time("run_at", at).
+		Logger()
+	logger.Info().Interface("job_args", job.Args).Msg("Enqueuing delayed job via Redis")
+
+	data, err := encodeJob(ctx, job)
 	if err != nil {
 		logger.Error().Err(err).Msg("Unable to encode the job")
-		return fmt.Errorf("unable to encode args: %w", err)
-	}
-
-	cmd := w.client.LPush(ctx, w.queueName, buffer.Bytes())
-	if cmd.Err() != nil {
-		logger.Error().Err(cmd.Err()).Msg("Unable to push job into Redis")
-		return fmt.Errorf("unable to push job into Redis: %w", cmd.Err())
+		return err
 	}
 
-	result, err := cmd.Result()
+	// the mover needs the lane and the account of the job
+	member := string(lane) + ":" + accountKey(job) + ":" + string(data)
+	err = w.client.ZAdd(ctx, w.delayedName, redis.Z{Score: float64(at.UnixMilli()), Member: member}).Err()
 	if err != nil {
-		logger.Error().Err(err).Msg("Unable to process redis push result")
-		return fmt.Errorf("unable to process result: %w", err)
+		logger.Error().Err(err).Msg("Unable to add delayed job into Redis")
+		return fmt.Errorf("unable to add delayed job into Redis: %w", err)
 	}
-	logger.Info().Int64("redis_push_result", result).Msg("Pushed job successfully")
 	return nil
 }
 
+func (w *RedisWorker) EnqueueAfter(ctx context.Context, job *Job, delay time.Duration) error {
+	return w.EnqueueAt(ctx, job, time.Now().Add(delay))
+}
+
+// Stop waits for in-flight jobs until the context is done. Jobs which are still running are then
+// interrupted (see Interrupted) and re-queued when their handlers stop early and report it (see
+// ReportInterrupted), so another worker continues them. Interrupted jobs stop at the next step boundary, their contexts are cancelled
+// when they do not stop within the interrupt timeout. Context without a deadline waits for all jobs.
 func (w *RedisWorker) Stop(ctx context.Context) {
 	logger := zerolog.Ctx(ctx)
+	start := time.Now()
 	close(w.closeCh)
-	logger.Info().Msg("Waiting for all workers to finish")
-	w.loopWG.Wait()
-	logger.Info().Msg("Done waiting for all workers to finish")
+
+	done := make(chan struct{})
+	go func() {
+		w.loopWG.Wait()
+		close(done)
+	}()
+
+	logger.Info().Int64("in_flight", atomic.LoadInt64(&w.inFlight)).Msg("Waiting for all workers to finish")
+	select {
+	case <-done:
+	case <-ctx.Done():
+		interrupted := w.interruptJobs(false)
+		logger.Warn().Int("interrupted_jobs", interrupted).Msg("Shutdown deadline expired, interrupting in-flight jobs")
+		timer := time.NewTimer(w.interruptTimeout)
+		select {
+		case <-done:
+		case <-timer.C:
+			cancelled := w.interruptJobs(true)
+			logger.Warn().Int("cancelled_jobs", cancelled).Msg("Interrupt deadline expired, cancelling in-flight jobs")
+			<-done
+		}
+		timer.Stop()
+	}
+
+	metrics.ObserveWorkerShutdown(time.Since(start))
+	logger.Info().Dur("shutdown_duration", time.Since(start)).Msg("Done waiting for all workers to finish")
+}
+
+// interruptJobs interrupts all running jobs and returns their count, their contexts are cancelled
+// too when cancel is true. Jobs started later are interrupted immediately.
+func (w *RedisWorker) interruptJobs(cancel bool) int {
+	w.runningMu.Lock()
+	defer w.runningMu.Unlock()
+
+	w.interrupting = true
+	w.cancelling = w.cancelling || cancel
+	for _, job := range w.running {
+		w.interruptJob(job)
+	}
+	return len(w.running)
+}
+
+func (w *RedisWorker) interruptJob(job *runningJob) {
+	if w.interrupting {
+		job.interrupt()
+	}
+	if w.cancelling {
+		job.cancel(ErrJobInterrupted)
+	}
+}
+
+func (w *RedisWorker) startRunning(id uuid.UUID, job *runningJob) {
+	w.runningMu.Lock()
+	defer w.runningMu.Unlock()
+	w.running[id] = job
+	w.interruptJob(job)
+}
+
+func (w *RedisWorker) stopRunning(id uuid.UUID) {
+	w.runningMu.Lock()
+	defer w.runningMu.Unlock()
+	delete(w.running, id)
+}
+
+// stopping returns true after Stop was called.
+func (w *RedisWorker) stopping() bool {
+	select {
+	case <-w.closeCh:
+		return true
+	default:
+		return false
+	}
 }
 
 func (w *RedisWorker) DequeueLoop(ctx context.Context) {
@@ -135,6 +345,71 @@ func (w *RedisWorker) DequeueLoop(ctx context.Context) {
 		w.loopWG.Add(1)
 		go w.dequeueLoop(ctx, i, w.concurrency)
 	}
+
+	w.loopWG.Add(1)
+	go w.moveLoop(ctx)
+}
+
+// number of delayed jobs moved by a single script call
+const moveBatchSize = 100
+
+// lease of in-flight jobs in poll intervals, the lease is extended three times per period
+const leaseIntervals = 6
+
+func (w *RedisWorker) moveLoop(ctx context.Context) {
+	defer w.loopWG.Done()
+	logger := zerolog.Ctx(ctx)
+
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.closeCh:
+			logger.Info().Msg("Shutting down a Redis delayed job mover (stop)")
+			return
+		case <-ctx.Done():
+			logger.Info().Msg("Shutting down a Redis delayed job mover (cancel)")
+			return
+		case <-ticker.C:
+			w.moveDelayed(ctx)
+			w.moveLegacy(ctx)
+			w.reclaimExpired(ctx)
+		}
+	}
+}
+
+func (w *RedisWorker) moveDelayed(ctx context.Context) {
+	defer recoverAndLog(ctx)
+
+	for {
+		moved, err := moveDelayedScript.Run(ctx, w.client, nil, w.queueName, time.Now().UnixMilli(), moveBatchSize).Int64()
+		if err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Unable to move delayed jobs into the queue")
+			return
+		}
+		if moved > 0 {
+			zerolog.Ctx(ctx).Debug().Int64("moved_jobs", moved).Msg("Moved delayed jobs into the queue")
+		}
+		if moved < moveBatchSize {
+			return
+		}
+	}
+}
+
+// moveLegacy moves jobs pushed by previous versions, which used a single list, into the normal lane.
+func (w *RedisWorker) moveLegacy(ctx context.Context) {
+	defer recoverAndLog(ctx)
+
+	keys := []string{w.queueName, w.accountListName(PriorityNormal, legacyAccount), w.laneName(PriorityNormal), w.signalName}
+	moved, err := moveLegacyScript.Run(ctx, w.client, keys, legacyAccount).Int64()
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("Unable to move legacy jobs into the queue")
+		return
+	}
+	if moved > 0 {
+		zerolog.Ctx(ctx).Info().Int64("moved_jobs", moved).Msg("Moved legacy jobs into the normal lane")
+	}
 }
 
 func (w *RedisWorker) dequeueLoop(ctx context.Context, i, total int) {
@@ -172,37 +447,116 @@ func recoverAndLog(ctx context.Context) {
 	}
 }
 
+// dequeue pops the next job, returns empty job when there is no job or all accounts with jobs are
+// at the in-flight limit. The job is stored in the in-flight hash with its lease by the same script,
+// except jobs enqueued by previous versions which are returned untracked.
+func (w *RedisWorker) dequeue(ctx context.Context) (lane JobPriority, account, raw string, tracked bool, err error) {
+	args := []any{w.queueName, w.accountConcurrency, time.Now().UnixMilli(), w.leaseTimeout.Milliseconds()}
+	for _, l := range w.lanes.next() {
+		args = append(args, string(l))
+	}
+
+	res, err := dequeueScript.Run(ctx, w.client, nil, args...).StringSlice()
+	if errors.Is(err, redis.Nil) {
+		return "", "", "", false, nil
+	} else if err != nil {
+		return "", "", "", false, fmt.Errorf("unable to dequeue job: %w", err)
+	}
+	return JobPriority(res[0]), res[1], res[2], res[3] == "1", nil
+}
+
 func (w *RedisWorker) fetchJob(ctx context.Context) {
 	defer recoverAndLog(ctx)
 
-	res, err := w.client.BLPop(ctx, w.pollInterval, w.queueName).Result()
-
-	if errors.Is(err, redis.Nil) {
-		// timeout occurred
+	lane, account, raw, tracked, err := w.dequeue(ctx)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("Error consuming from Redis queue")
 		return
-	} else if err != nil {
-		logger := zerolog.Ctx(ctx)
-		logger.Error().Err(err).Msg("Error consuming from Redis queue")
+	}
+	if raw == "" {
+		// wait for a new job, a finished job or a timeout
+		_, err = w.client.BLPop(ctx, w.pollInterval, w.signalName).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error waiting for Redis queue signal")
+		}
 		return
 	}
 
+	id, data := splitJobEntry(raw)
 	var job Job
-	dec := gob.NewDecoder(strings.NewReader(res[1]))
+	dec := gob.NewDecoder(strings.NewReader(data))
 	err = dec.Decode(&job)
 	if err != nil {
+		job.ID, _ = uuid.Parse(id)
 		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("job_id", job.ID.String()).
 			Str("job_type", job.Type.String()).
 			Interface("job_args", job.Args).
 			Msg("Unable to unmarshal job payload, skipping")
+		w.release(ctx, &job, account, !tracked)
 		return
 	}
 
+	if !job.EnqueuedAt.IsZero() {
+		metrics.ObserveJobQueueWait(string(lane), time.Since(job.EnqueuedAt))
+	}
+
 	atomic.AddInt64(&w.inFlight, 1)
 	defer atomic.AddInt64(&w.inFlight, -1)
 
-	w.processJob(ctx, &job)
+	if !tracked {
+		tracked = w.trackInFlight(ctx, &job, account)
+	}
+	stopHeartbeat := w.heartbeat(ctx, job.ID)
+	jobCtx, interrupt := WithInterrupt(ctx)
+	jobCtx, cancel := context.WithCancelCause(jobCtx)
+	w.startRunning(job.ID, &runningJob{interrupt: interrupt, cancel: cancel})
+	defer func() {
+		stopHeartbeat()
+		w.stopRunning(job.ID)
+		cancel(nil)
+		w.finishJob(ctx, &job, account, tracked)
+	}()
+
+	w.processJob(jobCtx, &job)
+}
+
+// finishJob releases the in-flight job and re-queues it when the handler stopped because it was
+// interrupted by shutdown, see ReportInterrupted.
+func (w *RedisWorker) finishJob(ctx context.Context, job *Job, account string, tracked bool) {
+	interrupted := job.stoppedInterrupted
+	released := w.release(ctx, job, account, !tracked)
+	if !w.stopping() {
+		return
+	}
+
+	logger := zerolog.Ctx(ctx).With().
+		Str("job_id", job.ID.String()).
+		Str("job_type", job.Type.String()).
+		Str("job_progress", job.Progress).
+		Logger()
+	if !interrupted {
+		logger.Info().Msg("In-flight job finished during shutdown")
+		metrics.IncJobShutdown("finished")
+		return
+	}
+	if !released {
+		// deleted or re-queued by an operator meanwhile
+		logger.Warn().Msg("Interrupted job is no longer in flight, not re-queuing")
+		metrics.IncJobShutdown("error")
+		return
+	}
+
+	job.Interruptions++
+	err := w.Enqueue(ctx, job)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to re-queue interrupted job, job is lost")
+		metrics.IncJobShutdown("error")
+		return
+	}
+	logger.Warn().Int("job_interruptions", job.Interruptions).Msg("Re-queued job interrupted by shutdown")
+	metrics.IncJobShutdown("requeued")
 }
 
 func (w *RedisWorker) processJob(origCtx context.Context, job *Job) {
@@ -210,37 +564,423 @@ func (w *RedisWorker) processJob(origCtx context.Context, job *Job) {
 		return
 	}
 
-	ctx, logger, span := initJobContext(origCtx, job)
-	defer span.End()
-	defer recoverAndLog(ctx)
-	logger.Info().Interface("job_args", job.Args).Msgf("Dequeued job from Redis")
+	ctx := initJobContext(origCtx, job)
+	if h, ok := w.handlers.handler(job.Type); ok {
+		h(ctx, job)
+	} else {
+		zerolog.Ctx(ctx).Warn().Str("job_id", job.ID.String()).Msgf("Redis worker handler not found for job type: %s", job.Type)
+	}
+}
 
-	if h, ok := w.handlers[job.Type]; ok {
-		cCtx, cFunc := context.WithTimeout(ctx, config.Worker.Timeout)
-		defer func() {
-			if c := cCtx.Err(); c != nil {
-				zerolog.Ctx(ctx).Error().Err(c).Msg("Job was either cancelled or timeout occurred")
+// accountList is a list of queued jobs of an account in a lane.
+type accountList struct {
+	lane    JobPriority
+	account string
+	key     string
+}
+
+// accountLists returns lists of all accounts with queued jobs.
+func (w *RedisWorker) accountLists(ctx context.Context) ([]accountList, error) {
+	var result []accountList
+	for _, lane := range lanes {
+		accounts, err := w.client.LRange(ctx, w.laneName(lane), 0, -1).Result()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read lane %s: %w", lane, err)
+		}
+
+		seen := make(map[string]bool, len(accounts))
+		for _, account := range accounts {
+			if seen[account] {
+				continue
 			}
-			cFunc()
-		}()
-		metrics.ObserveBackgroundJobDuration(job.Type.String(), func() {
-			h(cCtx, job)
-		})
-	} else {
-		// handler not found
-		span.SetStatus(codes.Error, "worker has not found handler for a job type")
-		zerolog.Ctx(ctx).Warn().Msgf("Redis worker handler not found for job type: %s", job.Type)
+			seen[account] = true
+			result = append(result, accountList{lane: lane, account: account, key: w.accountListName(lane, account)})
+		}
 	}
+	return result, nil
 }
 
 func (w *RedisWorker) Stats(ctx context.Context) (Stats, error) {
-	count, err := w.client.LLen(ctx, w.queueName).Result()
+	lists, err := w.accountLists(ctx)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	cmds := make([]*redis.IntCmd, 0, len(lists)+1)
+	_, err = w.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		cmds = append(cmds, pipe.LLen(ctx, w.queueName))
+		for _, list := range lists {
+			cmds = append(cmds, pipe.LLen(ctx, list.key))
+		}
+		return nil
+	})
 	if err != nil {
 		return Stats{}, fmt.Errorf("unable to get queue len: %w", err)
 	}
 
+	var count int64
+	for _, cmd := range cmds {
+		count += cmd.Val()
+	}
 	return Stats{
 		EnqueuedJobs: uint64(count),
 		InFlight:     atomic.LoadInt64(&w.inFlight),
 	}, nil
 }
+
+// inFlightRecord is stored in the in-flight hash while a job is processed.
+type inFlightRecord struct {
+	Job       *Job
+	StartedAt time.Time
+
+	// Account key of the in-flight count, it is "legacy" for jobs of previous versions.
+	Account string
+}
+
+// decodeInFlightRecord decodes an in-flight record stored as "ACCOUNT:STARTED:JOB" with the start
+// time in unix milliseconds and the gob encoded job, or a gob encoded record of previous versions.
+func decodeInFlightRecord(raw string) (*inFlightRecord, error) {
+	parts := strings.SplitN(raw, ":", 3)
+	if len(parts) == 3 {
+		if startedAt, err := strconv.ParseInt(parts[1], 10, 64); err == nil {
+			var job Job
+			if err = gob.NewDecoder(strings.NewReader(parts[2])).Decode(&job); err == nil {
+				return &inFlightRecord{Job: &job, StartedAt: time.UnixMilli(startedAt), Account: parts[0]}, nil
+			}
+		}
+	}
+
+	var record inFlightRecord
+	err := gob.NewDecoder(strings.NewReader(raw)).Decode(&record)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode in-flight job: %w", err)
+	}
+	if record.Job == nil {
+		return nil, fmt.Errorf("in-flight job: %w", ErrJobNotFound)
+	}
+	return &record, nil
+}
+
+// trackInFlight stores a job enqueued by a previous version in the in-flight hash, so it is visible
+// to all clients, together with its lease. Other jobs are stored by dequeueScript. Errors are only
+// logged, tracking must not prevent job processing. Returns false when the job was not stored.
+func (w *RedisWorker) trackInFlight(ctx context.Context, job *Job, account string) bool {
+	now := time.Now()
+	var buffer bytes.Buffer
+	buffer.WriteString(account + ":" + strconv.FormatInt(now.UnixMilli(), 10) + ":")
+	err := gob.NewEncoder(&buffer).Encode(job)
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Str("job_id", job.ID.String()).Msg("Unable to encode in-flight job")
+		return false
+	}
+
+	_, err = w.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, w.inFlightName, job.ID.String(), buffer.Bytes())
+		pipe.ZAdd(ctx, w.leasesName, redis.Z{Score: float64(now.Add(w.leaseTimeout).UnixMilli()), Member: job.ID.String()})
+		return nil
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Str("job_id", job.ID.String()).Msg("Unable to store in-flight job")
+		return false
+	}
+	return true
+}
+
+// heartbeat extends the lease of an in-flight job until the returned function is called.
+func (w *RedisWorker) heartbeat(ctx context.Context, id uuid.UUID) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(w.leaseTimeout / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				expiresAt := time.Now().Add(w.leaseTimeout).UnixMilli()
+				keys := []string{w.inFlightName, w.leasesName}
+				err := heartbeatScript.Run(ctx, w.client, keys, id.String(), expiresAt).Err()
+				if err != nil {
+					zerolog.Ctx(ctx).Warn().Err(err).Str("job_id", id.String()).Msg("Unable to extend lease of in-flight job")
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// reclaimExpired re-queues in-flight jobs whose lease expired, their worker crashed or lost the
+// connection to Redis. Jobs which cannot be decoded are released without re-queuing.
+func (w *RedisWorker) reclaimExpired(ctx context.Context) {
+	defer recoverAndLog(ctx)
+	logger := zerolog.Ctx(ctx)
+
+	now := time.Now().UnixMilli()
+	ids, err := w.client.ZRangeByScore(ctx, w.leasesName, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now, 10),
+		Count: moveBatchSize,
+	}).Result()
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to list expired in-flight jobs")
+		return
+	}
+
+	for _, id := range ids {
+		raw, err := w.client.HGet(ctx, w.inFlightName, id).Result()
+		if errors.Is(err, redis.Nil) {
+			// released meanwhile
+			w.client.ZRem(ctx, w.leasesName, id)
+			continue
+		} else if err != nil {
+			logger.Error().Err(err).Str("job_id", id).Msg("Unable to read expired in-flight job")
+			continue
+		}
+
+		var data []byte
+		lane := PriorityNormal
+		record, err := decodeInFlightRecord(raw)
+		if err == nil {
+			record.Job.Interruptions++
+			lane = record.Job.Priority.Lane()
+			data, err = newJobEntry(record.Job)
+		} else {
+			record = &inFlightRecord{}
+		}
+		if err != nil {
+			logger.Error().Err(err).Str("job_id", id).Msg("Unable to decode expired in-flight job, job is lost")
+			data = nil
+		}
+
+		keys := []string{w.inFlightName, w.leasesName, w.accountsName, w.accountListName(lane, record.Account), w.laneName(lane), w.signalName}
+		reclaimed, err := reclaimScript.Run(ctx, w.client, keys, id, now, record.Account, data).Int64()
+		if err != nil {
+			logger.Error().Err(err).Str("job_id", id).Msg("Unable to re-queue expired in-flight job")
+			continue
+		}
+		if reclaimed == 1 && data != nil {
+			logger.Warn().Str("job_id", id).
+				Str("job_type", record.Job.Type.String()).
+				Int("job_interruptions", record.Job.Interruptions).
+				Msg("Re-queued in-flight job with expired lease")
+		}
+	}
+}
+
+// release removes the in-flight record and decrements the in-flight count of the account. The
+// count is decremented only once, even when an operator deleted or requeued the job meanwhile.
+// Returns false when the record did not exist.
+func (w *RedisWorker) release(ctx context.Context, job *Job, account string, force bool) bool {
+	forceArg := "0"
+	if force {
+		forceArg = "1"
+	}
+
+	keys := []string{w.inFlightName, w.accountsName, w.signalName, w.leasesName}
+	released, err := releaseScript.Run(ctx, w.client, keys, job.ID.String(), account, forceArg).Int64()
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Str("job_id", job.ID.String()).Msg("Unable to release in-flight job")
+		return false
+	}
+	return released == 1
+}
+
+// redisJob is a decoded job with the raw item and the list needed to remove it from the queue.
+type redisJob struct {
+	info *JobInfo
+	raw  string
+
+	// account list of queued jobs, account key of in-flight jobs
+	list    accountList
+	account string
+}
+
+// scanJobs decodes all delayed, queued and in-flight jobs. Jobs which cannot be decoded, for example
+// because their argument type is not registered, are logged and skipped.
+func (w *RedisWorker) scanJobs(ctx context.Context) ([]redisJob, error) {
+	logger := zerolog.Ctx(ctx)
+
+	lists, err := w.accountLists(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	inFlight, err := w.client.HGetAll(ctx, w.inFlightName).Result()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read in-flight jobs: %w", err)
+	}
+
+	delayed, err := w.client.ZRangeWithScores(ctx, w.delayedName, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read delayed jobs: %w", err)
+	}
+
+	result := make([]redisJob, 0, len(lists)+len(inFlight)+len(delayed))
+	for _, z := range delayed {
+		member, ok := z.Member.(string)
+		if !ok {
+			continue
+		}
+		// member is LANE:ACCOUNT:JOB
+		parts := strings.SplitN(member, ":", 3)
+		if len(parts) != 3 {
+			logger.Warn().Msg("Unable to parse delayed job, skipping")
+			continue
+		}
+		job, err := decodeJobEntry(parts[2])
+		if err != nil {
+			logger.Warn().Err(err).Msg("Unable to decode delayed job, skipping")
+			continue
+		}
+		runAt := time.UnixMilli(int64(z.Score))
+		result = append(result, redisJob{info: &JobInfo{Job: job, State: JobStateDelayed, RunAt: runAt}, raw: member})
+	}
+	for _, list := range lists {
+		queued, err := w.client.LRange(ctx, list.key, 0, -1).Result()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read queue: %w", err)
+		}
+		for _, raw := range queued {
+			job, err := decodeJobEntry(raw)
+			if err != nil {
+				logger.Warn().Err(err).Msg("Unable to decode queued job, skipping")
+				continue
+			}
+			result = append(result, redisJob{info: &JobInfo{Job: job, State: JobStateQueued}, raw: raw, list: list})
+		}
+	}
+	for id, raw := range inFlight {
+		record, err := decodeInFlightRecord(raw)
+		if err != nil {
+			logger.Warn().Err(err).Str("job_id", id).Msg("Unable to decode in-flight job, skipping")
+			continue
+		}
+		info := &JobInfo{Job: record.Job, State: JobStateInFlight, StartedAt: record.StartedAt}
+		result = append(result, redisJob{info: info, raw: raw, account: record.Account})
+	}
+
+	return result, nil
+}
+
+func (w *RedisWorker) findJob(ctx context.Context, id uuid.UUID) (*redisJob, error) {
+	jobs, err := w.scanJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range jobs {
+		if jobs[i].info.Job.ID == id {
+			return &jobs[i], nil
+		}
+	}
+	return nil, fmt.Errorf("job %s: %w", id, ErrJobNotFound)
+}
+
+func (w *RedisWorker) ListJobs(ctx context.Context, filter *JobFilter) ([]*JobInfo, error) {
+	jobs, err := w.scanJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := make([]*JobInfo, 0, len(jobs))
+	for _, job := range jobs {
+		if filter.Matches(job.info, now) {
+			result = append(result, job.info)
+		}
+	}
+	sortJobs(result)
+	return result, nil
+}
+
+func (w *RedisWorker) GetJob(ctx context.Context, id uuid.UUID) (*JobInfo, error) {
+	job, err := w.findJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return job.info, nil
+}
+
+func (w *RedisWorker) DeleteJob(ctx context.Context, id uuid.UUID) error {
+	job, err := w.findJob(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	removed := true
+	switch job.info.State {
+	case JobStateInFlight:
+		removed = w.release(ctx, job.info.Job, job.account, false)
+	case JobStateDelayed:
+		var count int64
+		count, err = w.client.ZRem(ctx, w.delayedName, job.raw).Result()
+		removed = count > 0
+	case JobStateQueued:
+		var count int64
+		keys := []string{job.list.key, w.laneName(job.list.lane)}
+		count, err = deleteQueuedScript.Run(ctx, w.client, keys, job.raw, job.list.account).Int64()
+		removed = count > 0
+	default:
+		return fmt.Errorf("job %s: %w", id, ErrJobNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to delete job %s: %w", id, err)
+	}
+	if !removed {
+		// dequeued or finished in the meantime
+		return fmt.Errorf("job %s: %w", id, ErrJobNotFound)
+	}
+
+	zerolog.Ctx(ctx).Info().Str("job_id", id.String()).Str("job_state", string(job.info.State)).Msg("Deleted job")
+	return nil
+}
+
+func (w *RedisWorker) RequeueJob(ctx context.Context, id uuid.UUID) error {
+	job, err := w.findJob(ctx, id)
+	if err != nil {
+		return err
+	}
+	if job.info.State != JobStateInFlight {
+		return fmt.Errorf("job %s: %w", id, ErrJobNotInFlight)
+	}
+
+	if !w.release(ctx, job.info.Job, job.account, false) {
+		// finished in the meantime
+		return fmt.Errorf("job %s: %w", id, ErrJobNotInFlight)
+	}
+
+	return w.Enqueue(ctx, job.info.Job)
+}
+
+func (w *RedisWorker) DrainQueue(ctx context.Context) (int64, error) {
+	lists, err := w.accountLists(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	keys := []string{w.queueName, w.delayedName}
+	for _, lane := range lanes {
+		keys = append(keys, w.laneName(lane))
+	}
+	cmds := make([]*redis.IntCmd, 0, len(lists)+2)
+	_, err = w.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		cmds = append(cmds, pipe.LLen(ctx, w.queueName), pipe.ZCard(ctx, w.delayedName))
+		for _, list := range lists {
+			cmds = append(cmds, pipe.LLen(ctx, list.key))
+			keys = append(keys, list.key)
+		}
+		pipe.Del(ctx, keys...)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("unable to drain queue: %w", err)
+	}
+
+	var count int64
+	for _, cmd := range cmds {
+		count += cmd.Val()
+	}
+	zerolog.Ctx(ctx).Warn().Int64("drained_jobs", count).Msg("Drained job queue")
+	return count, nil
+}
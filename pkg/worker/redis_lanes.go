@@ -0,0 +1,251 @@
+package worker
+
+import (
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis keys are derived from the queue name (prefix):
+//
+//	prefix                       list of jobs enqueued before priority lanes were introduced
+//	prefix:LANE                  round-robin list of accounts with queued jobs in the lane
+//	prefix:LANE:ACCOUNT          list of queued jobs of the account in the lane ("ID:JOB")
+//	prefix:delayed               sorted set of delayed jobs ("LANE:ACCOUNT:ID:JOB" scored by time)
+//	prefix:in-flight             hash of in-flight jobs (job ID to "ACCOUNT:STARTED:JOB")
+//	prefix:in-flight-accounts    hash of in-flight job counts (account to count)
+//	prefix:in-flight-leases      sorted set of in-flight job IDs scored by lease expiration
+//	prefix:signal                list used to wake up idle polling goroutines
+//
+// Scripts build some keys from the prefix, the queue requires a single Redis instance (no cluster).
+
+// laneWeights are relative shares of dequeue attempts which start with the lane. A lane without
+// jobs passes its turn to the other lanes in priority order.
+var laneWeights = map[JobPriority]int{
+	PriorityHigh:   6,
+	PriorityNormal: 3,
+	PriorityLow:    1,
+}
+
+// lanes in priority order
+var lanes = []JobPriority{PriorityHigh, PriorityNormal, PriorityLow}
+
+// account of jobs moved from the list used before priority lanes were introduced
+const legacyAccount = "legacy"
+
+// laneScheduler picks lanes by smooth weighted round-robin.
+type laneScheduler struct {
+	mu      sync.Mutex
+	current map[JobPriority]int
+}
+
+func newLaneScheduler() *laneScheduler {
+	return &laneScheduler{current: make(map[JobPriority]int)}
+}
+
+// next returns lanes in the order they should be tried, the first lane is picked by weight and
+// the rest follow in priority order.
+func (s *laneScheduler) next() []JobPriority {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	total := 0
+	best := lanes[0]
+	for _, lane := range lanes {
+		s.current[lane] += laneWeights[lane]
+		total += laneWeights[lane]
+		if s.current[lane] > s.current[best] {
+			best = lane
+		}
+	}
+	s.current[best] -= total
+
+	order := make([]JobPriority, 0, len(lanes))
+	order = append(order, best)
+	for _, lane := range lanes {
+		if lane != best {
+			order = append(order, lane)
+		}
+	}
+	return order
+}
+
+// pushScript appends a job to the account list and adds the account to the lane when the list
+// was empty.
+//
+// KEYS: account list, lane, signal
+// ARGV: job, account
+var pushScript = redis.NewScript(`
+if redis.call('RPUSH', KEYS[1], ARGV[1]) == 1 then
+	redis.call('RPUSH', KEYS[2], ARGV[2])
+end
+redis.call('LPUSH', KEYS[3], 1)
+redis.call('LTRIM', KEYS[3], 0, 99)
+return 1
+`)
+
+// dequeueScript pops the first account of a lane and returns its first job. Accounts at the
+// in-flight limit are rotated to the end of the lane and skipped, accounts with more jobs are
+// rotated to the end after the pop. The job is stored in the in-flight hash with its lease together
+// with the in-flight count increment, so a crashed worker cannot leave a count without a record.
+// Jobs enqueued by previous versions have no ID and are tracked by the worker. Returns lane,
+// account, job and whether the job was tracked (1 or 0), or nil when no job is available.
+//
+// ARGV: prefix, in-flight limit per account (zero disables the limit), current time and lease
+// timeout (milliseconds), lanes in order
+var dequeueScript = redis.NewScript(`
+local prefix = ARGV[1]
+local limit = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local lease = tonumber(ARGV[4])
+local counts = prefix .. ':in-flight-accounts'
+for i = 5, #ARGV do
+	local lane = prefix .. ':' .. ARGV[i]
+	for _ = 1, redis.call('LLEN', lane) do
+		local account = redis.call('LPOP', lane)
+		local list = lane .. ':' .. account
+		local running = tonumber(redis.call('HGET', counts, account)) or 0
+		if limit > 0 and running >= limit then
+			redis.call('RPUSH', lane, account)
+		else
+			local job = redis.call('LPOP', list)
+			if job then
+				if redis.call('LLEN', list) > 0 then
+					redis.call('RPUSH', lane, account)
+				end
+				redis.call('HINCRBY', counts, account, 1)
+				local id = string.sub(job, 1, 36)
+				if string.sub(job, 37, 37) == ':' and string.find(id, '^%x+%-%x+%-%x+%-%x+%-%x+$') then
+					local record = account .. ':' .. string.format('%d', now) .. ':' .. string.sub(job, 38)
+					redis.call('HSET', prefix .. ':in-flight', id, record)
+					redis.call('ZADD', prefix .. ':in-flight-leases', now + lease, id)
+					return {ARGV[i], account, job, '1'}
+				end
+				return {ARGV[i], account, job, '0'}
+			end
+		end
+	end
+end
+return false
+`)
+
+// releaseScript removes the in-flight record and its lease and decrements the in-flight count of
+// the account. The count is only decremented when the record existed or when forced (the record
+// was never stored). Returns 1 when the count was decremented.
+//
+// KEYS: in-flight hash, in-flight counts, signal, leases
+// ARGV: job ID, account, force (1 or 0)
+var releaseScript = redis.NewScript(`
+redis.call('ZREM', KEYS[4], ARGV[1])
+if redis.call('HDEL', KEYS[1], ARGV[1]) == 1 or ARGV[3] == '1' then
+	if redis.call('HINCRBY', KEYS[2], ARGV[2], -1) <= 0 then
+		redis.call('HDEL', KEYS[2], ARGV[2])
+	end
+	redis.call('LPUSH', KEYS[3], 1)
+	redis.call('LTRIM', KEYS[3], 0, 99)
+	return 1
+end
+return 0
+`)
+
+// heartbeatScript extends the lease of an in-flight job, released jobs get no new lease.
+//
+// KEYS: in-flight hash, leases
+// ARGV: job ID, lease expiration (unix milliseconds)
+var heartbeatScript = redis.NewScript(`
+if redis.call('HEXISTS', KEYS[1], ARGV[1]) == 1 then
+	redis.call('ZADD', KEYS[2], ARGV[2], ARGV[1])
+	return 1
+end
+return 0
+`)
+
+// reclaimScript re-queues an in-flight job whose lease expired and decrements the in-flight count
+// of the account. Nothing is done when the job was released or its lease extended meanwhile, so
+// concurrent movers and a late worker cannot deliver or release the job twice. An empty job only
+// removes the record, an empty account (undecodable record) keeps the counts. Returns 1 when the
+// job was reclaimed.
+//
+// KEYS: in-flight hash, leases, in-flight counts, account list, lane, signal
+// ARGV: job ID, current time (unix milliseconds), account, job
+var reclaimScript = redis.NewScript(`
+local expiresAt = redis.call('ZSCORE', KEYS[2], ARGV[1])
+if not expiresAt or tonumber(expiresAt) > tonumber(ARGV[2]) then
+	return 0
+end
+redis.call('ZREM', KEYS[2], ARGV[1])
+if redis.call('HDEL', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+if ARGV[3] ~= '' and redis.call('HINCRBY', KEYS[3], ARGV[3], -1) <= 0 then
+	redis.call('HDEL', KEYS[3], ARGV[3])
+end
+if ARGV[4] ~= '' then
+	if redis.call('RPUSH', KEYS[4], ARGV[4]) == 1 then
+		redis.call('RPUSH', KEYS[5], ARGV[3])
+	end
+end
+redis.call('LPUSH', KEYS[6], 1)
+redis.call('LTRIM', KEYS[6], 0, 99)
+return 1
+`)
+
+// deleteQueuedScript removes a queued job and removes the account from the lane when it has no
+// more jobs.
+//
+// KEYS: account list, lane
+// ARGV: job, account
+var deleteQueuedScript = redis.NewScript(`
+local removed = redis.call('LREM', KEYS[1], 1, ARGV[1])
+if redis.call('LLEN', KEYS[1]) == 0 then
+	redis.call('LREM', KEYS[2], 0, ARGV[2])
+end
+return removed
+`)
+
+// moveDelayedScript moves due delayed jobs into their lanes. It runs atomically, movers of all
+// workers can run concurrently without delivering a job twice.
+//
+// ARGV: prefix, current time (unix milliseconds), batch size
+var moveDelayedScript = redis.NewScript(`
+local prefix = ARGV[1]
+local delayed = prefix .. ':delayed'
+local members = redis.call('ZRANGEBYSCORE', delayed, '-inf', ARGV[2], 'LIMIT', 0, ARGV[3])
+for _, member in ipairs(members) do
+	redis.call('ZREM', delayed, member)
+	local lane, account, job = string.match(member, '^([^:]*):([^:]*):(.*)$')
+	if job then
+		lane = prefix .. ':' .. lane
+		if redis.call('RPUSH', lane .. ':' .. account, job) == 1 then
+			redis.call('RPUSH', lane, account)
+		end
+	end
+end
+if #members > 0 then
+	redis.call('LPUSH', prefix .. ':signal', 1)
+	redis.call('LTRIM', prefix .. ':signal', 0, 99)
+end
+return #members
+`)
+
+// moveLegacyScript moves jobs enqueued by previous versions into the normal lane under a single
+// account, so they are processed during rolling upgrades.
+//
+// KEYS: legacy list, account list, lane, signal
+// ARGV: account
+var moveLegacyScript = redis.NewScript(`
+local moved = 0
+local job = redis.call('RPOP', KEYS[1])
+while job do
+	if redis.call('RPUSH', KEYS[2], job) == 1 then
+		redis.call('RPUSH', KEYS[3], ARGV[1])
+	end
+	moved = moved + 1
+	job = redis.call('RPOP', KEYS[1])
+end
+if moved > 0 then
+	redis.call('LPUSH', KEYS[4], 1)
+	redis.call('LTRIM', KEYS[4], 0, 99)
+end
+return moved
+`)
@@ -0,0 +1,91 @@
+package worker
+
+import (
+	"bytes"
+	"encoding/gob"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLaneSchedulerWeights(t *testing.T) {
+	s := newLaneScheduler()
+
+	counts := make(map[JobPriority]int)
+	for i := 0; i < 10; i++ {
+		order := s.next()
+		assert.Len(t, order, len(lanes))
+		counts[order[0]]++
+	}
+
+	assert.Equal(t, 6, counts[PriorityHigh])
+	assert.Equal(t, 3, counts[PriorityNormal])
+	assert.Equal(t, 1, counts[PriorityLow])
+}
+
+func TestLaneSchedulerFallbackOrder(t *testing.T) {
+	s := newLaneScheduler()
+
+	for i := 0; i < 10; i++ {
+		order := s.next()
+		if order[0] == PriorityLow {
+			assert.Equal(t, []JobPriority{PriorityLow, PriorityHigh, PriorityNormal}, order)
+			return
+		}
+	}
+	t.Fatal("low lane was never picked first")
+}
+
+func TestJobPriorityLane(t *testing.T) {
+	assert.Equal(t, PriorityHigh, PriorityHigh.Lane())
+	assert.Equal(t, PriorityLow, PriorityLow.Lane())
+	assert.Equal(t, PriorityNormal, JobPriority("").Lane())
+	assert.Equal(t, PriorityNormal, JobPriority("urgent").Lane())
+}
+
+func TestJobEntry(t *testing.T) {
+	job := &Job{ID: uuid.New(), AccountID: 1, Type: "test", Args: "entry"}
+	entry, err := newJobEntry(job)
+	require.NoError(t, err)
+
+	id, data := splitJobEntry(string(entry))
+	assert.Equal(t, job.ID.String(), id)
+	decoded, err := decodeJobEntry(string(entry))
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, decoded.ID)
+
+	// jobs enqueued by previous versions are gob encoded only
+	id, previous := splitJobEntry(data)
+	assert.Empty(t, id)
+	assert.Equal(t, data, previous)
+	decoded, err = decodeJobEntry(data)
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, decoded.ID)
+}
+
+func TestDecodeInFlightRecord(t *testing.T) {
+	job := &Job{ID: uuid.New(), AccountID: 1, Type: "test", Args: "record"}
+	startedAt := time.UnixMilli(time.Now().UnixMilli())
+	entry, err := newJobEntry(job)
+	require.NoError(t, err)
+	_, data := splitJobEntry(string(entry))
+
+	// record stored by the dequeue script
+	record, err := decodeInFlightRecord("1:" + strconv.FormatInt(startedAt.UnixMilli(), 10) + ":" + data)
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, record.Job.ID)
+	assert.Equal(t, "1", record.Account)
+	assert.True(t, startedAt.Equal(record.StartedAt))
+
+	// record stored by previous versions
+	var buffer bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buffer).Encode(&inFlightRecord{Job: job, StartedAt: startedAt, Account: "legacy"}))
+	record, err = decodeInFlightRecord(buffer.String())
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, record.Job.ID)
+	assert.Equal(t, "legacy", record.Account)
+}
@@ -0,0 +1,202 @@
+//go:build integration
+// +build integration
+
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/RHEnVision/provisioning-backend/internal/config"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	config.Initialize("config/test.env", "../../config/test.env")
+	os.Exit(m.Run())
+}
+
+func newTestRedisWorker(t *testing.T, accountConcurrency int) *RedisWorker {
+	t.Helper()
+	ctx := context.Background()
+
+	w, err := NewRedisWorker(config.RedisHostAndPort(),
+		config.Application.Cache.Redis.User, config.Application.Cache.Redis.Password,
+		config.Application.Cache.Redis.DB, "provisioning-worker-test", 50*time.Millisecond, 1, accountConcurrency, time.Second)
+	require.NoError(t, err)
+	w.RegisterHandler("test", func(_ context.Context, _ *Job) {}, "")
+
+	cleanup := func() {
+		keys, err := w.client.Keys(ctx, w.queueName+"*").Result()
+		require.NoError(t, err)
+		if len(keys) > 0 {
+			require.NoError(t, w.client.Del(ctx, keys...).Err())
+		}
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+	return w
+}
+
+// dequeueJob pops the next job like a polling goroutine, returns nil when no job is available.
+func dequeueJob(t *testing.T, w *RedisWorker) (*Job, string) {
+	t.Helper()
+	_, account, raw, tracked, err := w.dequeue(context.Background())
+	require.NoError(t, err)
+	if raw == "" {
+		return nil, ""
+	}
+	require.True(t, tracked, "dequeued job must be tracked by the script")
+
+	job, err := decodeJobEntry(raw)
+	require.NoError(t, err)
+	return job, account
+}
+
+func TestRedisAccountFairness(t *testing.T) {
+	ctx := context.Background()
+	w := newTestRedisWorker(t, 0)
+
+	for _, accountID := range []int64{1, 1, 1, 2} {
+		require.NoError(t, w.Enqueue(ctx, &Job{AccountID: accountID, Type: "test", Args: "fairness"}))
+	}
+
+	// the account with a single job does not wait for all jobs of the first account
+	var order []int64
+	for job, _ := dequeueJob(t, w); job != nil; job, _ = dequeueJob(t, w) {
+		order = append(order, job.AccountID)
+	}
+	assert.Equal(t, []int64{1, 2, 1, 1}, order)
+}
+
+func TestRedisAccountConcurrency(t *testing.T) {
+	ctx := context.Background()
+	w := newTestRedisWorker(t, 1)
+
+	for _, accountID := range []int64{1, 1, 2} {
+		require.NoError(t, w.Enqueue(ctx, &Job{AccountID: accountID, Type: "test", Args: "limit"}))
+	}
+
+	first, account := dequeueJob(t, w)
+	require.NotNil(t, first)
+	assert.Equal(t, int64(1), first.AccountID)
+
+	// the first account is at the limit, the other account is not blocked
+	second, _ := dequeueJob(t, w)
+	require.NotNil(t, second)
+	assert.Equal(t, int64(2), second.AccountID)
+
+	blocked, _ := dequeueJob(t, w)
+	require.Nil(t, blocked)
+
+	require.True(t, w.release(ctx, first, account, true))
+	third, _ := dequeueJob(t, w)
+	require.NotNil(t, third)
+	assert.Equal(t, int64(1), third.AccountID)
+}
+
+func TestRedisReclaimExpired(t *testing.T) {
+	ctx := context.Background()
+	w := newTestRedisWorker(t, 1)
+	w.leaseTimeout = 100 * time.Millisecond
+
+	require.NoError(t, w.Enqueue(ctx, &Job{AccountID: 1, Type: "test", Args: "crashed"}))
+	require.NoError(t, w.Enqueue(ctx, &Job{AccountID: 1, Type: "test", Args: "alive"}))
+
+	// a worker which crashed while processing the job never releases it
+	crashed, account := dequeueJob(t, w)
+	require.NotNil(t, crashed)
+
+	blocked, _ := dequeueJob(t, w)
+	require.Nil(t, blocked, "account must be at the in-flight limit")
+
+	time.Sleep(150 * time.Millisecond)
+	w.reclaimExpired(ctx)
+
+	info, err := w.GetJob(ctx, crashed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, JobStateQueued, info.State)
+	assert.Equal(t, 1, info.Job.Interruptions)
+
+	_, err = w.client.HGet(ctx, w.accountsName, account).Result()
+	require.ErrorIs(t, err, redis.Nil, "in-flight count must be released")
+
+	// the account is not blocked anymore
+	next, _ := dequeueJob(t, w)
+	require.NotNil(t, next)
+}
+
+func TestRedisHeartbeatKeepsLease(t *testing.T) {
+	ctx := context.Background()
+	w := newTestRedisWorker(t, 0)
+	w.leaseTimeout = 100 * time.Millisecond
+
+	require.NoError(t, w.Enqueue(ctx, &Job{AccountID: 1, Type: "test", Args: "running"}))
+	job, account := dequeueJob(t, w)
+	require.NotNil(t, job)
+	stopHeartbeat := w.heartbeat(ctx, job.ID)
+
+	time.Sleep(250 * time.Millisecond)
+	w.reclaimExpired(ctx)
+
+	info, err := w.GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, JobStateInFlight, info.State)
+
+	stopHeartbeat()
+	require.True(t, w.release(ctx, job, account, false))
+	count, err := w.client.ZCard(ctx, w.leasesName).Result()
+	require.NoError(t, err)
+	assert.Zero(t, count, "lease must be removed on release")
+}
+
+func TestRedisDequeueTracksInFlight(t *testing.T) {
+	ctx := context.Background()
+	w := newTestRedisWorker(t, 0)
+
+	job := &Job{AccountID: 1, Type: "test", Args: "tracked"}
+	require.NoError(t, w.Enqueue(ctx, job))
+	dequeued, account := dequeueJob(t, w)
+	require.NotNil(t, dequeued)
+	assert.Equal(t, "1", account)
+
+	info, err := w.GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, JobStateInFlight, info.State)
+	assert.WithinDuration(t, time.Now(), info.StartedAt, time.Minute)
+	_, err = w.client.ZScore(ctx, w.leasesName, job.ID.String()).Result()
+	require.NoError(t, err, "lease must be stored with the in-flight job")
+	require.True(t, w.release(ctx, dequeued, account, false))
+}
+
+func TestRedisDequeuePreviousVersionJob(t *testing.T) {
+	ctx := context.Background()
+	w := newTestRedisWorker(t, 0)
+
+	// jobs enqueued by previous versions have no ID prefix
+	job := &Job{ID: uuid.New(), AccountID: 1, Type: "test", Args: "previous"}
+	var buffer bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buffer).Encode(job))
+	keys := []string{w.accountListName(PriorityNormal, "1"), w.laneName(PriorityNormal), w.signalName}
+	require.NoError(t, pushScript.Run(ctx, w.client, keys, buffer.String(), "1").Err())
+
+	_, account, raw, tracked, err := w.dequeue(ctx)
+	require.NoError(t, err)
+	require.False(t, tracked)
+	dequeued, err := decodeJobEntry(raw)
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, dequeued.ID)
+
+	require.True(t, w.trackInFlight(ctx, dequeued, account))
+	info, err := w.GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, JobStateInFlight, info.State)
+	require.True(t, w.release(ctx, dequeued, account, false))
+}